@@ -0,0 +1,138 @@
+// Package app holds the dependencies shared by routes, controllers and
+// middlewares. main builds one App and passes it down; tests build their own
+// with fakes, so several instances can run side by side.
+package app
+
+import (
+	"flower-backend/cache"
+	"flower-backend/config"
+	"flower-backend/health"
+	"flower-backend/jobs"
+	"flower-backend/libs"
+	"flower-backend/metrics"
+	"flower-backend/migrations"
+	post_repository "flower-backend/repositories/v1/post"
+	user_repository "flower-backend/repositories/v1/user"
+	"flower-backend/scheduler"
+	post_services "flower-backend/services/v1/post"
+	"flower-backend/tasks"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// App is the wiring for one running instance of the API.
+type App struct {
+	Config *config.Config
+	Logger *zap.Logger
+	DB     *gorm.DB
+	JWT    *libs.JWT
+	// Cache serves post and user reads; nil when caching is disabled. main
+	// closes it on shutdown.
+	Cache cache.Cache
+	// Storage keeps uploaded post images and avatars
+	Storage libs.Storage
+	// Images signs URLs for images kept in a private bucket; nil when
+	// Storage serves them itself
+	Images libs.URLSigner
+	// OAuthProvider returns the named login provider ("google" or "github")
+	OAuthProvider func(name string) (libs.OAuthProvider, error)
+	// Users reads accounts; Authenticate checks suspension through it so the
+	// lookup is served from cache
+	Users user_repository.UserRepository
+	// Posts is the post repository every service and task shares
+	Posts post_repository.PostRepository
+	// Now is the clock used for token expiry
+	Now func() time.Time
+	// Health runs the readiness checks behind /readyz
+	Health *health.Checker
+	// Jobs runs background work; main starts and drains it
+	Jobs *jobs.Queue
+	// Scheduler runs the periodic tasks while this instance holds the lease;
+	// main starts and stops it
+	Scheduler *scheduler.Scheduler
+	// Metrics holds the collectors served on /metrics
+	Metrics *metrics.Metrics
+}
+
+// New wires the production implementations around an open database,
+// including the configured cache.
+func New(cfg *config.Config, logger *zap.Logger, db *gorm.DB) (*App, error) {
+	storage, images, err := newStorage(cfg)
+	if err != nil {
+		return nil, err
+	}
+	migrator, err := migrations.NewMigrator(db, logger)
+	if err != nil {
+		return nil, err
+	}
+	m := metrics.New()
+	// expose connection pool saturation
+	if sqlDB, err := db.DB(); err == nil {
+		if err := m.RegisterDB(sqlDB, cfg.DBName); err != nil {
+			logger.Warn("failed to register database metrics", zap.Error(err))
+		}
+	}
+	storage = libs.NewInstrumentedStorage(storage, m)
+	queue := jobs.New(db, cfg, logger, m)
+	if err := jobs.RegisterDefaults(queue, cfg, storage); err != nil {
+		return nil, err
+	}
+	store := cache.New(cfg, logger)
+	postRepo := post_repository.NewPostRepository(db, cfg, store, logger.Sugar())
+	userRepo := user_repository.NewUserRepository(db, cfg, store, logger.Sugar())
+	post_services.RegisterJobs(queue, db, cfg, postRepo, logger.Sugar(), storage)
+	sched := scheduler.New(db, cfg, logger, m)
+	if err := tasks.Schedule(sched, cfg, m, postRepo, userRepo); err != nil {
+		cache.Close(store)
+		return nil, err
+	}
+	return &App{
+		Config:  cfg,
+		Logger:  logger,
+		DB:      db,
+		Cache:   store,
+		JWT:     libs.NewJWT(cfg, logger.Sugar(), time.Now),
+		Storage: storage,
+		Images:  images,
+		OAuthProvider: func(name string) (libs.OAuthProvider, error) {
+			return libs.NewOAuthProvider(name, cfg)
+		},
+		Users: userRepo,
+		Posts: postRepo,
+		Now:   time.Now,
+		Health: health.NewChecker(
+			health.Database(db),
+			health.Pool(db),
+			// Cloudinary's admin API is rate limited per hour
+			health.Storage(storage, time.Minute),
+			health.Migrations(migrator),
+		),
+		Jobs:      queue,
+		Scheduler: sched,
+		Metrics:   m,
+	}, nil
+}
+
+// newStorage builds the configured image storage, and its URL signer when
+// images are served from a private bucket.
+func newStorage(cfg *config.Config) (libs.Storage, libs.URLSigner, error) {
+	switch cfg.StorageDriver {
+	case "s3":
+		storage, err := libs.NewS3Storage(cfg)
+		if err != nil {
+			return nil, nil, err
+		}
+		if cfg.S3PublicURL != "" {
+			return storage, nil, nil
+		}
+		return storage, storage, nil
+	case "", "cloudinary":
+		storage, err := libs.NewCloudinaryStorage(cfg)
+		return storage, nil, err
+	default:
+		return nil, nil, fmt.Errorf("unknown storage driver %q", cfg.StorageDriver)
+	}
+}
@@ -0,0 +1,104 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/gob"
+	"flower-backend/config"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	DriverMemory = "memory"
+	DriverRedis  = "redis"
+	DriverNone   = "none"
+)
+
+// Cache is a byte-oriented key/value store with per-entry expiry. Lookup and
+// write failures are logged by the implementation and reported as misses, so
+// a broken cache only costs the extra database round trip.
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte, ttl time.Duration)
+	Delete(keys ...string)
+	Close() error
+}
+
+// New creates the cache for the configured driver. It returns nil when
+// caching is disabled; repositories given a nil Cache read straight through.
+func New(cfg *config.Config, logger *zap.Logger) Cache {
+	var store Cache
+	switch cfg.CacheDriver {
+	case DriverNone:
+		logger.Info("cache disabled")
+		return nil
+	case DriverRedis:
+		redisStore, err := NewRedisCache(cfg.RedisURL, logger)
+		if err != nil {
+			// Serving uncached is better than refusing to start
+			logger.Error("failed to connect to redis, continuing without cache", zap.Error(err))
+			return nil
+		}
+		store = redisStore
+	default:
+		store = NewMemoryCache(cfg.CacheMaxEntries)
+	}
+	logger.Info("cache configured",
+		zap.String("driver", cfg.CacheDriver),
+		zap.Duration("ttl", cfg.CacheTTL),
+	)
+	return NewInstrumentedCache(store)
+}
+
+// Close releases the connections held by c, which may be nil.
+func Close(c Cache) error {
+	if c == nil {
+		return nil
+	}
+	return c.Close()
+}
+
+// Load decodes the value cached under key into v, reporting whether it was found.
+func Load(c Cache, key string, v any) bool {
+	data, ok := c.Get(key)
+	if !ok {
+		return false
+	}
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(v); err != nil {
+		// Entries written by an older build may not decode; drop them
+		c.Delete(key)
+		return false
+	}
+	return true
+}
+
+// Save encodes v and caches it under key for ttl.
+func Save(c Cache, key string, v any, ttl time.Duration) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return
+	}
+	c.Set(key, buf.Bytes(), ttl)
+}
+
+const PostsAllKey = "posts:all"
+
+func PostKey(id uint) string {
+	return fmt.Sprintf("post:%d", id)
+}
+
+func UserKey(id uint) string {
+	return fmt.Sprintf("user:%d", id)
+}
+
+// UserAuthKey holds the fields Authenticate checks. It is kept apart from
+// UserKey, which every like and follow invalidates.
+func UserAuthKey(id uint) string {
+	return fmt.Sprintf("user:auth:%d", id)
+}
+
+func UsernameKey(username string) string {
+	return "user:username:" + username
+}
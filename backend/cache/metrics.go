@@ -0,0 +1,43 @@
+package cache
+
+import "sync/atomic"
+
+// Stats is a snapshot of the cache hit and miss counters.
+type Stats struct {
+	Hits     uint64  `json:"hits"`
+	Misses   uint64  `json:"misses"`
+	HitRatio float64 `json:"hit_ratio"`
+}
+
+var (
+	hits   atomic.Uint64
+	misses atomic.Uint64
+)
+
+// GetStats returns the counters accumulated since startup
+func GetStats() Stats {
+	stats := Stats{Hits: hits.Load(), Misses: misses.Load()}
+	if total := stats.Hits + stats.Misses; total > 0 {
+		stats.HitRatio = float64(stats.Hits) / float64(total)
+	}
+	return stats
+}
+
+// instrumentedCache counts hits and misses around another Cache.
+type instrumentedCache struct {
+	Cache
+}
+
+func NewInstrumentedCache(c Cache) Cache {
+	return &instrumentedCache{Cache: c}
+}
+
+func (c *instrumentedCache) Get(key string) ([]byte, bool) {
+	value, ok := c.Cache.Get(key)
+	if ok {
+		hits.Add(1)
+	} else {
+		misses.Add(1)
+	}
+	return value, ok
+}
@@ -0,0 +1,77 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const redisTimeout = 500 * time.Millisecond
+
+// redisCache stores entries in Redis or any server speaking its protocol
+// (Valkey, KeyDB, Dragonfly), so all replicas share one cache.
+type redisCache struct {
+	client *redis.Client
+	logger *zap.Logger
+}
+
+func NewRedisCache(url string, logger *zap.Logger) (Cache, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, err
+	}
+	client := redis.NewClient(opts)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, err
+	}
+	return &redisCache{client: client, logger: logger}, nil
+}
+
+func (c *redisCache) Get(key string) ([]byte, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	value, err := c.client.Get(ctx, key).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			c.logger.Warn("failed to read from cache", zap.String("key", key), zap.Error(err))
+		}
+		return nil, false
+	}
+	return value, true
+}
+
+func (c *redisCache) Set(key string, value []byte, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	if err := c.client.Set(ctx, key, value, ttl).Err(); err != nil {
+		c.logger.Warn("failed to write to cache", zap.String("key", key), zap.Error(err))
+	}
+}
+
+func (c *redisCache) Delete(keys ...string) {
+	if len(keys) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	if err := c.client.Del(ctx, keys...).Err(); err != nil {
+		c.logger.Warn("failed to invalidate cache", zap.Strings("keys", keys), zap.Error(err))
+	}
+}
+
+func (c *redisCache) Close() error {
+	return c.client.Close()
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"flower-backend/config"
+	post_repository "flower-backend/repositories/v1/post"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type exportedPost struct {
+	ID         uint      `json:"id"`
+	Title      string    `json:"title"`
+	Content    string    `json:"content"`
+	ImageURL   string    `json:"image_url"`
+	Hidden     bool      `json:"hidden"`
+	LikesCount int64     `json:"likes_count"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// userExport is everything stored about one user, for data access requests.
+type userExport struct {
+	ExportedAt   time.Time      `json:"exported_at"`
+	ID           uint           `json:"id"`
+	Username     string         `json:"username"`
+	Email        string         `json:"email"`
+	Avatar       string         `json:"avatar"`
+	Role         string         `json:"role"`
+	Provider     string         `json:"provider"`
+	Suspended    bool           `json:"suspended"`
+	CreatedAt    time.Time      `json:"created_at"`
+	Posts        []exportedPost `json:"posts"`
+	LikedPostIDs []uint         `json:"liked_post_ids"`
+	Followers    []string       `json:"followers"`
+	Following    []string       `json:"following"`
+}
+
+// runExportUser writes a user's profile, posts, likes and follows as JSON.
+func runExportUser(cfg *config.Config, logger *zap.Logger, args []string) int {
+	flags := flag.NewFlagSet("export-user", flag.ContinueOnError)
+	email := flags.String("email", "", "email of the user")
+	username := flags.String("username", "", "username of the user")
+	out := flags.String("out", "", "file to write to (default stdout)")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if (*email == "") == (*username == "") {
+		fmt.Fprintln(os.Stderr, "export-user: exactly one of -email or -username is required")
+		return 2
+	}
+
+	userRepo, postRepo, closeRepos, err := openRepositories(cfg, logger)
+	if err != nil {
+		return 1
+	}
+	defer closeRepos()
+
+	user, err := lookupUser(userRepo, *email, *username)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			fmt.Fprintln(os.Stderr, "export-user: user not found")
+		}
+		return 1
+	}
+
+	export := userExport{
+		ExportedAt:   time.Now().UTC(),
+		ID:           user.ID,
+		Username:     user.Username,
+		Email:        user.Email,
+		Avatar:       user.Avatar,
+		Role:         user.Role,
+		Provider:     user.Provider,
+		Suspended:    user.Suspended,
+		CreatedAt:    user.CreatedAt,
+		Posts:        make([]exportedPost, 0),
+		LikedPostIDs: make([]uint, 0, len(user.Likes)),
+		Followers:    make([]string, 0),
+		Following:    make([]string, 0),
+	}
+	for _, post := range user.Likes {
+		export.LikedPostIDs = append(export.LikedPostIDs, post.ID)
+	}
+
+	// Hidden posts are included, so page through the admin listing
+	filter := post_repository.PostFilter{AuthorID: &user.ID}
+	for page := 1; ; page++ {
+		posts, total, err := postRepo.GetAllWithFilter(filter, page, 100)
+		if err != nil {
+			return 1
+		}
+		for _, post := range posts {
+			export.Posts = append(export.Posts, exportedPost{
+				ID:         post.ID,
+				Title:      post.Title,
+				Content:    post.Content,
+				ImageURL:   post.ImageURL,
+				Hidden:     post.Hidden,
+				LikesCount: post.LikesCount,
+				CreatedAt:  post.CreatedAt,
+				UpdatedAt:  post.UpdatedAt,
+			})
+		}
+		if len(posts) == 0 || int64(len(export.Posts)) >= total {
+			break
+		}
+	}
+
+	followers, err := userRepo.GetFollowers(user.ID)
+	if err != nil {
+		return 1
+	}
+	for _, follower := range followers {
+		export.Followers = append(export.Followers, follower.Username)
+	}
+	following, err := userRepo.GetFollowing(user.ID)
+	if err != nil {
+		return 1
+	}
+	for _, followed := range following {
+		export.Following = append(export.Following, followed.Username)
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		file, err := os.OpenFile(*out, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+		if err != nil {
+			logger.Error("failed to open export file", zap.Error(err))
+			return 1
+		}
+		defer file.Close()
+		w = file
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(export); err != nil {
+		logger.Error("failed to write export", zap.Error(err))
+		return 1
+	}
+	logger.Info("user exported", zap.Uint("user_id", user.ID), zap.Int("posts", len(export.Posts)))
+	return 0
+}
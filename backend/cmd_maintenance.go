@@ -0,0 +1,61 @@
+package main
+
+import (
+	"flag"
+	"flower-backend/config"
+	"flower-backend/tasks"
+	"fmt"
+	"os"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// runCleanupTokens deletes expired refresh tokens once, as the hourly
+// background task does while serving.
+func runCleanupTokens(cfg *config.Config, logger *zap.Logger, args []string) int {
+	flags := flag.NewFlagSet("cleanup-tokens", flag.ContinueOnError)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() > 0 {
+		fmt.Fprintln(os.Stderr, "cleanup-tokens: takes no arguments")
+		return 2
+	}
+
+	userRepo, _, closeRepos, err := openRepositories(cfg, logger)
+	if err != nil {
+		return 1
+	}
+	defer closeRepos()
+
+	deleted, err := userRepo.DeleteExpiredTokens(time.Now())
+	if err != nil {
+		return 1
+	}
+	logger.Info("expired tokens deleted", zap.Int64("count", deleted))
+	return 0
+}
+
+// runRecount repairs the denormalized counters.
+func runRecount(cfg *config.Config, logger *zap.Logger, args []string) int {
+	flags := flag.NewFlagSet("recount", flag.ContinueOnError)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() > 0 {
+		fmt.Fprintln(os.Stderr, "recount: takes no arguments")
+		return 2
+	}
+
+	userRepo, postRepo, closeRepos, err := openRepositories(cfg, logger)
+	if err != nil {
+		return 1
+	}
+	defer closeRepos()
+
+	if err := tasks.RecountCounters(postRepo, userRepo, logger); err != nil {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"flag"
+	"flower-backend/config"
+	"flower-backend/database"
+	"flower-backend/migrations"
+	"fmt"
+	"os"
+	"path"
+	"text/tabwriter"
+
+	"go.uber.org/zap"
+)
+
+const migrateUsage = `usage: flower-backend migrate <command>
+
+commands:
+  up                 apply all pending migrations
+  down [-steps N]    roll back the latest N migrations (default 1)
+  status             list migrations and whether they are applied
+  create <name>      write an empty up/down pair to -dir`
+
+// runMigrate implements the `migrate` subcommand and returns the exit code.
+func runMigrate(cfg *config.Config, logger *zap.Logger, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	switch args[0] {
+	case "create":
+		flags := flag.NewFlagSet("migrate create", flag.ContinueOnError)
+		dir := flags.String("dir", path.Join("migrations", cfg.DBDriver), "directory to write the migration files to")
+		if err := flags.Parse(args[1:]); err != nil || flags.NArg() != 1 {
+			fmt.Fprintln(os.Stderr, migrateUsage)
+			return 2
+		}
+		upPath, downPath, err := migrations.Create(*dir, flags.Arg(0))
+		if err != nil {
+			logger.Error("failed to create migration", zap.Error(err))
+			return 1
+		}
+		fmt.Println(upPath)
+		fmt.Println(downPath)
+		return 0
+	case "up", "down", "status":
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	db, err := database.ConnectDB(cfg, logger)
+	if err != nil {
+		logger.Error("failed to connect to database", zap.Error(err))
+		return 1
+	}
+	defer database.DisconnectDB(db, logger)
+	migrator, err := migrations.NewMigrator(db, logger)
+	if err != nil {
+		logger.Error("failed to load migrations", zap.Error(err))
+		return 1
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up()
+		if err != nil {
+			return 1
+		}
+		logger.Info("migrations up to date", zap.Int("applied", applied))
+	case "down":
+		flags := flag.NewFlagSet("migrate down", flag.ContinueOnError)
+		steps := flags.Int("steps", 1, "number of migrations to roll back")
+		if err := flags.Parse(args[1:]); err != nil || *steps < 1 {
+			fmt.Fprintln(os.Stderr, migrateUsage)
+			return 2
+		}
+		reverted, err := migrator.Down(*steps)
+		if err != nil {
+			return 1
+		}
+		logger.Info("migrations rolled back", zap.Int("reverted", reverted))
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			logger.Error("failed to get migration status", zap.Error(err))
+			return 1
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.AppliedAt != nil {
+				appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\n", status.Version, status.Name, appliedAt)
+		}
+		w.Flush()
+	}
+	return 0
+}
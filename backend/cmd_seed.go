@@ -0,0 +1,119 @@
+package main
+
+import (
+	"flag"
+	"flower-backend/config"
+	"flower-backend/models"
+	"flower-backend/utils"
+	"fmt"
+	"os"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const seedPassword = "Seed-passw0rd"
+
+var seedTags = []string{"roses", "tulips", "orchids", "garden", "wildflowers"}
+
+// runSeed fills a development database with users who post, follow each
+// other and like each other's posts. It refuses to run twice or in
+// production unless forced.
+func runSeed(cfg *config.Config, logger *zap.Logger, args []string) int {
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	users := flags.Int("users", 10, "number of users to create")
+	posts := flags.Int("posts", 5, "number of posts per user")
+	force := flags.Bool("force", false, "allow seeding when GO_ENV is production")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *users < 2 || *posts < 0 {
+		fmt.Fprintln(os.Stderr, "seed: -users must be at least 2 and -posts not negative")
+		return 2
+	}
+	if cfg.GO_ENV == "production" && !*force {
+		fmt.Fprintln(os.Stderr, "seed: refusing to seed a production database without -force")
+		return 2
+	}
+
+	userRepo, postRepo, closeRepos, err := openRepositories(cfg, logger)
+	if err != nil {
+		return 1
+	}
+	defer closeRepos()
+
+	if _, err := userRepo.GetByUsername("seeduser1"); err == nil {
+		fmt.Fprintln(os.Stderr, "seed: the database is already seeded")
+		return 1
+	} else if err != gorm.ErrRecordNotFound {
+		return 1
+	}
+
+	hash, err := utils.HashPassword(seedPassword)
+	if err != nil {
+		return 1
+	}
+	tags, err := postRepo.FindOrCreateTags(seedTags)
+	if err != nil {
+		return 1
+	}
+
+	created := make([]models.User, 0, *users)
+	postIDs := make([][]uint, 0, *users)
+	for i := 1; i <= *users; i++ {
+		username := fmt.Sprintf("seeduser%d", i)
+		user := models.User{
+			Username: username,
+			Email:    username + "@example.com",
+			Password: hash,
+			Avatar:   "https://ui-avatars.com/api/?name=S&background=random&size=200",
+			Role:     "user",
+			Provider: "local",
+		}
+		if err := userRepo.Create(&user); err != nil {
+			return 1
+		}
+		created = append(created, user)
+
+		ids := make([]uint, 0, *posts)
+		for j := 1; j <= *posts; j++ {
+			post := models.Post{
+				Title:   fmt.Sprintf("Flower %d from %s", j, username),
+				Content: fmt.Sprintf("Sample post %d by %s, created by the seed command.", j, username),
+				UserID:  user.ID,
+			}
+			if err := postRepo.Create(&post); err != nil {
+				return 1
+			}
+			if err := postRepo.ReplaceTags(post.ID, []models.Tag{tags[(i+j)%len(tags)]}); err != nil {
+				return 1
+			}
+			ids = append(ids, post.ID)
+		}
+		postIDs = append(postIDs, ids)
+	}
+
+	// Each user follows the next two and likes the posts of the next one
+	for i, user := range created {
+		for _, offset := range []int{1, 2} {
+			other := created[(i+offset)%len(created)]
+			if other.ID == user.ID {
+				continue
+			}
+			if err := userRepo.Follow(user.ID, other.ID); err != nil {
+				return 1
+			}
+		}
+		for _, postID := range postIDs[(i+1)%len(created)] {
+			if err := postRepo.Like(postID, user.ID); err != nil {
+				return 1
+			}
+		}
+	}
+
+	logger.Info("database seeded",
+		zap.Int("users", len(created)),
+		zap.Int("posts_per_user", *posts),
+		zap.String("password", seedPassword))
+	return 0
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"flag"
+	"flower-backend/config"
+	"flower-backend/models"
+	user_repository "flower-backend/repositories/v1/user"
+	"flower-backend/utils"
+	"fmt"
+	"os"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// runCreateAdmin creates an admin account, or promotes the user that already
+// owns the email, without needing the WHITE_LIST_ADMIN_EMAILS variable.
+func runCreateAdmin(cfg *config.Config, logger *zap.Logger, args []string) int {
+	flags := flag.NewFlagSet("create-admin", flag.ContinueOnError)
+	email := flags.String("email", "", "email of the admin account (required)")
+	username := flags.String("username", "", "username for a new account")
+	password := flags.String("password", "", "password for a new account; read from stdin if omitted")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if !utils.ValidateEmail(*email) {
+		fmt.Fprintln(os.Stderr, "create-admin: a valid -email is required")
+		return 2
+	}
+
+	userRepo, _, closeRepos, err := openRepositories(cfg, logger)
+	if err != nil {
+		return 1
+	}
+	defer closeRepos()
+
+	existing, err := userRepo.GetByEmail(*email)
+	if err == nil {
+		if existing.Role == "admin" {
+			logger.Info("user is already an admin", zap.Uint("user_id", existing.ID))
+			return 0
+		}
+		if _, err := userRepo.UpdateRoleByIDs([]uint{existing.ID}, "admin"); err != nil {
+			return 1
+		}
+		logger.Info("user promoted to admin", zap.Uint("user_id", existing.ID), zap.String("email", *email))
+		return 0
+	}
+	if err != gorm.ErrRecordNotFound {
+		return 1
+	}
+
+	if !utils.ValidateUsername(*username) {
+		fmt.Fprintln(os.Stderr, "create-admin: a valid -username is required for a new account")
+		return 2
+	}
+	hash, ok := readNewPassword(*password)
+	if !ok {
+		return 2
+	}
+	user := models.User{
+		Username: *username,
+		Email:    *email,
+		Password: hash,
+		Avatar:   fmt.Sprintf("https://ui-avatars.com/api/?name=%s&background=random&size=200", string([]rune(*username)[0])),
+		Role:     "admin",
+		Provider: "local",
+	}
+	if err := userRepo.Create(&user); err != nil {
+		return 1
+	}
+	logger.Info("admin created", zap.Uint("user_id", user.ID), zap.String("email", *email))
+	return 0
+}
+
+// runResetPassword sets a new password and revokes the user's refresh tokens.
+func runResetPassword(cfg *config.Config, logger *zap.Logger, args []string) int {
+	flags := flag.NewFlagSet("reset-password", flag.ContinueOnError)
+	email := flags.String("email", "", "email of the user")
+	username := flags.String("username", "", "username of the user")
+	password := flags.String("password", "", "new password; read from stdin if omitted")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if (*email == "") == (*username == "") {
+		fmt.Fprintln(os.Stderr, "reset-password: exactly one of -email or -username is required")
+		return 2
+	}
+	hash, ok := readNewPassword(*password)
+	if !ok {
+		return 2
+	}
+
+	userRepo, _, closeRepos, err := openRepositories(cfg, logger)
+	if err != nil {
+		return 1
+	}
+	defer closeRepos()
+
+	user, err := lookupUser(userRepo, *email, *username)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			fmt.Fprintln(os.Stderr, "reset-password: user not found")
+		}
+		return 1
+	}
+	if err := userRepo.UpdatePassword(user.ID, hash); err != nil {
+		return 1
+	}
+	logger.Info("password reset", zap.Uint("user_id", user.ID))
+	return 0
+}
+
+// readNewPassword takes the password from the flag or stdin, validates it
+// against the signup rules and returns its hash.
+func readNewPassword(password string) (string, bool) {
+	if password == "" {
+		var err error
+		if password, err = readSecret("password: "); err != nil {
+			fmt.Fprintln(os.Stderr, "failed to read password:", err)
+			return "", false
+		}
+	}
+	if !utils.ValidatePassword(password) {
+		fmt.Fprintln(os.Stderr, "password must be at least 8 characters with upper and lower case letters, a digit and a special character")
+		return "", false
+	}
+	hash, err := utils.HashPassword(password)
+	if err != nil {
+		return "", false
+	}
+	return hash, true
+}
+
+// lookupUser finds a user by email when given, otherwise by username.
+func lookupUser(userRepo user_repository.UserRepository, email, username string) (*models.User, error) {
+	if email != "" {
+		return userRepo.GetByEmail(email)
+	}
+	return userRepo.GetByUsername(username)
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bufio"
+	"flower-backend/cache"
+	"flower-backend/config"
+	"flower-backend/database"
+	post_repository "flower-backend/repositories/v1/post"
+	user_repository "flower-backend/repositories/v1/user"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// command is one subcommand of the binary. run receives the arguments after
+// the command name and returns the process exit code.
+type command struct {
+	name    string
+	summary string
+	run     func(cfg *config.Config, logger *zap.Logger, args []string) int
+}
+
+var commands []command
+
+func init() {
+	commands = []command{
+		{"serve", "run the HTTP server (default)", runServe},
+		{"migrate", "apply, roll back, list or create schema migrations", runMigrate},
+		{"create-admin", "create an admin account or promote an existing user", runCreateAdmin},
+		{"reset-password", "set a user's password and sign out their sessions", runResetPassword},
+		{"cleanup-tokens", "delete expired refresh tokens once", runCleanupTokens},
+		{"recount", "recompute the like, follower, following and post counters", runRecount},
+		{"seed", "fill a development database with sample users and posts", runSeed},
+		{"export-user", "write everything stored about a user as JSON", runExportUser},
+	}
+}
+
+// runCommand dispatches to the named subcommand; no arguments means serve.
+func runCommand(cfg *config.Config, logger *zap.Logger, args []string) int {
+	if len(args) == 0 {
+		return runServe(cfg, logger, nil)
+	}
+	if args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		printUsage()
+		return 0
+	}
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			return cmd.run(cfg, logger, args[1:])
+		}
+	}
+	fmt.Fprintf(os.Stderr, "unknown command %q\n\n", args[0])
+	printUsage()
+	return 2
+}
+
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "usage: flower-backend <command> [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-16s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, `run "flower-backend <command> -h" for the flags of a command`)
+}
+
+// openRepositories connects to the database and cache for a one-off command.
+// The cache is needed so writes drop entries a shared cache still holds.
+// The returned func releases both.
+func openRepositories(cfg *config.Config, logger *zap.Logger) (user_repository.UserRepository, post_repository.PostRepository, func(), error) {
+	db, err := database.ConnectDB(cfg, logger)
+	if err != nil {
+		logger.Error("failed to connect to database", zap.Error(err))
+		return nil, nil, nil, err
+	}
+	store := cache.New(cfg, logger)
+	userRepo := user_repository.NewUserRepository(db, cfg, store, logger.Sugar())
+	postRepo := post_repository.NewPostRepository(db, cfg, store, logger.Sugar())
+	return userRepo, postRepo, func() { closeRepositories(db, store, logger) }, nil
+}
+
+// closeRepositories releases what openRepositories acquired.
+func closeRepositories(db *gorm.DB, store cache.Cache, logger *zap.Logger) {
+	if err := cache.Close(store); err != nil {
+		logger.Error("failed to close cache", zap.Error(err))
+	}
+	if err := database.DisconnectDB(db, logger); err != nil {
+		logger.Error("failed to disconnect from database", zap.Error(err))
+	}
+}
+
+// readSecret prompts on stderr and reads one line from stdin, so passwords
+// can be piped in instead of appearing in the process list.
+func readSecret(prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
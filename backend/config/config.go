@@ -9,11 +9,14 @@ import (
 type Config struct {
 	Port                 string
 	APIBaseURL           string
+	DBDriver             string
 	DBHost               string
 	DBPort               string
 	DBUser               string
 	DBPassword           string
 	DBName               string
+	DBSSLMode            string
+	DBPath               string
 	GO_ENV               string
 	JWTSecret            string
 	JWTRefreshSecret     string
@@ -31,6 +34,8 @@ type Config struct {
 	ReadTimeout          time.Duration
 	WriteTimeout         time.Duration
 	IdleTimeout          time.Duration
+	// ShutdownDrainDelay is how long /readyz fails before the listener closes
+	ShutdownDrainDelay time.Duration
 	// Database connection pool settings
 	DBMaxOpenConns    int
 	DBMaxIdleConns    int
@@ -44,17 +49,88 @@ type Config struct {
 	GithubClientSecret string
 	GithubRedirectURL  string
 	FrontendURL        string
+	// Admin dashboard configuration
+	StatsCacheTTL time.Duration
+	// Feed ranking configuration
+	FeedWeightRecency   float64
+	FeedWeightVelocity  float64
+	FeedWeightAffinity  float64
+	FeedWeightFollowed  float64
+	FeedSeenFactor      float64
+	FeedRecencyHalfLife time.Duration
+	FeedCandidateWindow time.Duration
+	FeedVelocityWindow  time.Duration
+	FeedAffinityWindow  time.Duration
+	FeedMaxCandidates   int
+	// Trending and popular configuration
+	LikeAggregateInterval time.Duration
+	// SMTP configuration for outgoing mail (invites)
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	SMTPFrom     string
+	// InviteExpiry is how long the set-password link in an invite works
+	InviteExpiry time.Duration
+	// Cache configuration for hot reads
+	CacheDriver     string
+	CacheTTL        time.Duration
+	CacheMaxEntries int
+	RedisURL        string
+	HTTPCacheMaxAge time.Duration
+	// Metrics configuration; /metrics is served only when one is set
+	MetricsAddr  string
+	MetricsToken string
+	// Tracing configuration
+	TracingExporter    string
+	TracingServiceName string
+	TracingSampleRatio float64
+	// Background job queue
+	JobWorkers      int
+	JobPollInterval time.Duration
+	JobTimeout      time.Duration
+	// SchedulerLeaseTTL is how long a replica leads the scheduled tasks
+	// without renewing its lease
+	SchedulerLeaseTTL time.Duration
+	// DirectUploadExpiry is how long signed direct-upload parameters stay
+	// valid, and DirectUploadMaxSize the largest image they accept in bytes
+	DirectUploadExpiry  time.Duration
+	DirectUploadMaxSize int64
+	// Image storage: "cloudinary" or "s3"
+	StorageDriver string
+	// S3-compatible storage. Without S3PublicURL the bucket stays private and
+	// images are served through signed URLs valid for S3URLExpiry; images
+	// larger than S3PartSize are uploaded in parts of that size.
+	S3Endpoint        string
+	S3Bucket          string
+	S3Region          string
+	S3AccessKeyID     string
+	S3SecretAccessKey string
+	S3UsePathStyle    bool
+	S3PublicURL       string
+	S3URLExpiry       time.Duration
+	S3PartSize        int64
+	// Logging configuration
+	LogLevel         string
+	LogDebugSampling bool
 }
 
 func LoadConfig() *Config {
 	port := utils.GetEnv("PORT", "8080")
 	goEnv := utils.GetEnv("GO_ENV", "development")
 	apiBaseURL := utils.GetEnv("API_BASE_URL", "http://localhost:8080")
+	dbDriver := utils.GetEnv("DB_DRIVER", "mysql") // mysql, postgres or sqlite
 	dbHost := utils.GetEnv("DB_HOST", "localhost")
-	dbPort := utils.GetEnv("DB_PORT", "3306")
+	defaultDBPort := "3306"
+	if dbDriver == "postgres" {
+		defaultDBPort = "5432"
+	}
+	dbPort := utils.GetEnv("DB_PORT", defaultDBPort)
 	dbUser := utils.GetEnv("DB_USER", "root")
 	dbPassword := utils.GetEnv("DB_PASSWORD", "root")
 	dbName := utils.GetEnv("DB_NAME", "flower_sharing")
+	dbSSLMode := utils.GetEnv("DB_SSL_MODE", "disable")    // postgres only
+	dbPath := utils.GetEnv("DB_PATH", "flower_sharing.db") // sqlite only
 
 	jwtSecret := utils.MustGetEnv("JWT_SECRET")
 	jwtRefreshSecret := utils.MustGetEnv("JWT_REFRESH_SECRET")
@@ -64,10 +140,29 @@ func LoadConfig() *Config {
 	defaultResLimit := 20
 	defaultResOffset := 0
 
-	cloudinaryCloudName := utils.MustGetEnv("CLOUDINARY_CLOUD_NAME")
-	cloudinaryAPIKey := utils.MustGetEnv("CLOUDINARY_API_KEY")
-	cloudinaryAPISecret := utils.MustGetEnv("CLOUDINARY_API_SECRET")
-	cloudinaryFolder := utils.MustGetEnv("CLOUDINARY_FOLDER")
+	storageDriver := utils.GetEnv("STORAGE_DRIVER", "cloudinary") // cloudinary or s3
+	// a storage driver's settings are only required when it is selected
+	requiredFor := func(driver, key string) string {
+		if storageDriver == driver {
+			return utils.MustGetEnv(key)
+		}
+		return utils.GetEnv(key, "")
+	}
+
+	cloudinaryCloudName := requiredFor("cloudinary", "CLOUDINARY_CLOUD_NAME")
+	cloudinaryAPIKey := requiredFor("cloudinary", "CLOUDINARY_API_KEY")
+	cloudinaryAPISecret := requiredFor("cloudinary", "CLOUDINARY_API_SECRET")
+	cloudinaryFolder := requiredFor("cloudinary", "CLOUDINARY_FOLDER")
+
+	s3Endpoint := utils.GetEnv("S3_ENDPOINT", "") // empty for AWS itself
+	s3Bucket := requiredFor("s3", "S3_BUCKET")
+	s3Region := utils.GetEnv("S3_REGION", "us-east-1")
+	s3AccessKeyID := requiredFor("s3", "S3_ACCESS_KEY_ID")
+	s3SecretAccessKey := requiredFor("s3", "S3_SECRET_ACCESS_KEY")
+	s3UsePathStyle := utils.GetEnv("S3_USE_PATH_STYLE", "false") == "true"
+	s3PublicURL := utils.GetEnv("S3_PUBLIC_URL", "") // empty serves images through signed URLs
+	s3URLExpiry := utils.ParseDuration(utils.GetEnv("S3_URL_EXPIRY", "1h"))
+	s3PartSize := int64(utils.ParseInt(utils.GetEnv("S3_PART_SIZE", "8388608")))
 
 	whiteListAdminEmails := strings.Split(utils.MustGetEnv("WHITE_LIST_ADMIN_EMAILS"), ",")
 
@@ -78,6 +173,13 @@ func LoadConfig() *Config {
 	readTimeout := utils.ParseDuration(utils.GetEnv("READ_TIMEOUT", "15s"))
 	writeTimeout := utils.ParseDuration(utils.GetEnv("WRITE_TIMEOUT", "15s"))
 	idleTimeout := utils.ParseDuration(utils.GetEnv("IDLE_TIMEOUT", "60s"))
+	// give load balancers a few probe intervals to stop routing to a
+	// stopping instance; locally there is nothing to drain
+	defaultDrainDelay := "0s"
+	if goEnv == "production" {
+		defaultDrainDelay = "5s"
+	}
+	shutdownDrainDelay := utils.ParseDuration(utils.GetEnv("SHUTDOWN_DRAIN_DELAY", defaultDrainDelay))
 
 	// Database connection pool configurations
 	dbMaxOpenConns := utils.ParseInt(utils.GetEnv("DB_MAX_OPEN_CONNS", "100"))
@@ -94,41 +196,147 @@ func LoadConfig() *Config {
 	githubRedirectURL := utils.GetEnv("GITHUB_REDIRECT_URL", "http://localhost:8080/api/v1/auth/github/callback")
 	frontendURL := utils.GetEnv("FRONTEND_URL", "http://localhost:3000")
 
+	// Admin dashboard configurations
+	statsCacheTTL := utils.ParseDuration(utils.GetEnv("STATS_CACHE_TTL", "1m"))
+
+	// Feed ranking configurations
+	feedWeightRecency := utils.ParseFloat(utils.GetEnv("FEED_WEIGHT_RECENCY", "1.0"))
+	feedWeightVelocity := utils.ParseFloat(utils.GetEnv("FEED_WEIGHT_VELOCITY", "0.5"))
+	feedWeightAffinity := utils.ParseFloat(utils.GetEnv("FEED_WEIGHT_AFFINITY", "0.3"))
+	feedWeightFollowed := utils.ParseFloat(utils.GetEnv("FEED_WEIGHT_FOLLOWED", "0.5"))
+	feedSeenFactor := utils.ParseFloat(utils.GetEnv("FEED_SEEN_FACTOR", "0.3"))
+	feedRecencyHalfLife := utils.ParseDuration(utils.GetEnv("FEED_RECENCY_HALF_LIFE", "24h"))
+	feedCandidateWindow := utils.ParseDuration(utils.GetEnv("FEED_CANDIDATE_WINDOW", "168h"))
+	feedVelocityWindow := utils.ParseDuration(utils.GetEnv("FEED_VELOCITY_WINDOW", "24h"))
+	feedAffinityWindow := utils.ParseDuration(utils.GetEnv("FEED_AFFINITY_WINDOW", "720h"))
+	feedMaxCandidates := utils.ParseInt(utils.GetEnv("FEED_MAX_CANDIDATES", "300"))
+
+	// Trending and popular configurations
+	likeAggregateInterval := utils.ParseDuration(utils.GetEnv("LIKE_AGGREGATE_INTERVAL", "5m"))
+
+	// SMTP configurations
+	smtpHost := utils.GetEnv("SMTP_HOST", "")
+	smtpPort := utils.GetEnv("SMTP_PORT", "587")
+	smtpUsername := utils.GetEnv("SMTP_USERNAME", "")
+	smtpPassword := utils.GetEnv("SMTP_PASSWORD", "")
+	smtpFrom := utils.GetEnv("SMTP_FROM", "")
+	inviteExpiry := utils.ParseDuration(utils.GetEnv("INVITE_EXPIRY", "72h"))
+
+	// Cache configurations
+	cacheDriver := utils.GetEnv("CACHE_DRIVER", "memory") // memory, redis or none
+	cacheTTL := utils.ParseDuration(utils.GetEnv("CACHE_TTL", "1m"))
+	cacheMaxEntries := utils.ParseInt(utils.GetEnv("CACHE_MAX_ENTRIES", "10000"))
+	redisURL := utils.GetEnv("REDIS_URL", "redis://localhost:6379/0")
+	httpCacheMaxAge := utils.ParseDuration(utils.GetEnv("HTTP_CACHE_MAX_AGE", "30s"))
+
+	// Metrics configurations
+	metricsAddr := utils.GetEnv("METRICS_ADDR", "")   // separate listener, e.g. ":9090"
+	metricsToken := utils.GetEnv("METRICS_TOKEN", "") // bearer token required to scrape
+
+	// Tracing configurations
+	tracingExporter := utils.GetEnv("TRACING_EXPORTER", "none") // none, stdout or otlp
+	tracingServiceName := utils.GetEnv("TRACING_SERVICE_NAME", "flower-backend")
+	tracingSampleRatio := utils.ParseFloat(utils.GetEnv("TRACING_SAMPLE_RATIO", "1.0"))
+
+	// Background job configurations
+	jobWorkers := utils.ParseInt(utils.GetEnv("JOB_WORKERS", "4"))
+	jobPollInterval := utils.ParseDuration(utils.GetEnv("JOB_POLL_INTERVAL", "1s"))
+	jobTimeout := utils.ParseDuration(utils.GetEnv("JOB_TIMEOUT", "5m"))
+	schedulerLeaseTTL := utils.ParseDuration(utils.GetEnv("SCHEDULER_LEASE_TTL", "30s"))
+
+	// Direct upload configurations
+	directUploadExpiry := utils.ParseDuration(utils.GetEnv("DIRECT_UPLOAD_EXPIRY", "15m"))
+	directUploadMaxSize := int64(utils.ParseInt(utils.GetEnv("DIRECT_UPLOAD_MAX_SIZE", "10485760")))
+
+	// Logging configurations
+	logLevel := utils.GetEnv("LOG_LEVEL", "info") // debug, info, warn or error
+	logDebugSampling := utils.GetEnv("LOG_DEBUG_SAMPLING", "false") == "true"
+
 	return &Config{
-		Port:                 port,
-		APIBaseURL:           apiBaseURL,
-		DBHost:               dbHost,
-		DBPort:               dbPort,
-		DBUser:               dbUser,
-		DBPassword:           dbPassword,
-		DBName:               dbName,
-		GO_ENV:               goEnv,
-		JWTSecret:            jwtSecret,
-		JWTRefreshSecret:     jwtRefreshSecret,
-		JWTExpiry:            jwtExpiry,
-		JWTRefreshExpiry:     jwtRefreshExpiry,
-		DefaultResLimit:      defaultResLimit,
-		DefaultResOffset:     defaultResOffset,
-		CloudinaryCloudName:  cloudinaryCloudName,
-		CloudinaryAPIKey:     cloudinaryAPIKey,
-		CloudinaryAPISecret:  cloudinaryAPISecret,
-		CloudinaryFolder:     cloudinaryFolder,
-		WhiteListAdminEmails: whiteListAdminEmails,
-		AllowOrigins:         allowOrigins,
-		RequestTimeout:       requestTimeout,
-		ReadTimeout:          readTimeout,
-		WriteTimeout:         writeTimeout,
-		IdleTimeout:          idleTimeout,
-		DBMaxOpenConns:       dbMaxOpenConns,
-		DBMaxIdleConns:       dbMaxIdleConns,
-		DBConnMaxLifetime:    dbConnMaxLifetime,
-		DBConnMaxIdleTime:    dbConnMaxIdleTime,
-		GoogleClientID:       googleClientID,
-		GoogleClientSecret:   googleClientSecret,
-		GoogleRedirectURL:    googleRedirectURL,
-		GithubClientID:       githubClientID,
-		GithubClientSecret:   githubClientSecret,
-		GithubRedirectURL:    githubRedirectURL,
-		FrontendURL:          frontendURL,
+		Port:                  port,
+		APIBaseURL:            apiBaseURL,
+		DBDriver:              dbDriver,
+		DBHost:                dbHost,
+		DBPort:                dbPort,
+		DBUser:                dbUser,
+		DBPassword:            dbPassword,
+		DBName:                dbName,
+		DBSSLMode:             dbSSLMode,
+		DBPath:                dbPath,
+		GO_ENV:                goEnv,
+		JWTSecret:             jwtSecret,
+		JWTRefreshSecret:      jwtRefreshSecret,
+		JWTExpiry:             jwtExpiry,
+		JWTRefreshExpiry:      jwtRefreshExpiry,
+		DefaultResLimit:       defaultResLimit,
+		DefaultResOffset:      defaultResOffset,
+		CloudinaryCloudName:   cloudinaryCloudName,
+		CloudinaryAPIKey:      cloudinaryAPIKey,
+		CloudinaryAPISecret:   cloudinaryAPISecret,
+		CloudinaryFolder:      cloudinaryFolder,
+		WhiteListAdminEmails:  whiteListAdminEmails,
+		AllowOrigins:          allowOrigins,
+		RequestTimeout:        requestTimeout,
+		ReadTimeout:           readTimeout,
+		WriteTimeout:          writeTimeout,
+		IdleTimeout:           idleTimeout,
+		ShutdownDrainDelay:    shutdownDrainDelay,
+		DBMaxOpenConns:        dbMaxOpenConns,
+		DBMaxIdleConns:        dbMaxIdleConns,
+		DBConnMaxLifetime:     dbConnMaxLifetime,
+		DBConnMaxIdleTime:     dbConnMaxIdleTime,
+		GoogleClientID:        googleClientID,
+		GoogleClientSecret:    googleClientSecret,
+		GoogleRedirectURL:     googleRedirectURL,
+		GithubClientID:        githubClientID,
+		GithubClientSecret:    githubClientSecret,
+		GithubRedirectURL:     githubRedirectURL,
+		FrontendURL:           frontendURL,
+		StatsCacheTTL:         statsCacheTTL,
+		FeedWeightRecency:     feedWeightRecency,
+		FeedWeightVelocity:    feedWeightVelocity,
+		FeedWeightAffinity:    feedWeightAffinity,
+		FeedWeightFollowed:    feedWeightFollowed,
+		FeedSeenFactor:        feedSeenFactor,
+		FeedRecencyHalfLife:   feedRecencyHalfLife,
+		FeedCandidateWindow:   feedCandidateWindow,
+		FeedVelocityWindow:    feedVelocityWindow,
+		FeedAffinityWindow:    feedAffinityWindow,
+		FeedMaxCandidates:     feedMaxCandidates,
+		LikeAggregateInterval: likeAggregateInterval,
+		SMTPHost:              smtpHost,
+		SMTPPort:              smtpPort,
+		SMTPUsername:          smtpUsername,
+		SMTPPassword:          smtpPassword,
+		SMTPFrom:              smtpFrom,
+		InviteExpiry:          inviteExpiry,
+		CacheDriver:           cacheDriver,
+		CacheTTL:              cacheTTL,
+		CacheMaxEntries:       cacheMaxEntries,
+		RedisURL:              redisURL,
+		HTTPCacheMaxAge:       httpCacheMaxAge,
+		MetricsAddr:           metricsAddr,
+		MetricsToken:          metricsToken,
+		TracingExporter:       tracingExporter,
+		TracingServiceName:    tracingServiceName,
+		TracingSampleRatio:    tracingSampleRatio,
+		JobWorkers:            jobWorkers,
+		JobPollInterval:       jobPollInterval,
+		JobTimeout:            jobTimeout,
+		SchedulerLeaseTTL:     schedulerLeaseTTL,
+		DirectUploadExpiry:    directUploadExpiry,
+		DirectUploadMaxSize:   directUploadMaxSize,
+		StorageDriver:         storageDriver,
+		S3Endpoint:            s3Endpoint,
+		S3Bucket:              s3Bucket,
+		S3Region:              s3Region,
+		S3AccessKeyID:         s3AccessKeyID,
+		S3SecretAccessKey:     s3SecretAccessKey,
+		S3UsePathStyle:        s3UsePathStyle,
+		S3PublicURL:           s3PublicURL,
+		S3URLExpiry:           s3URLExpiry,
+		S3PartSize:            s3PartSize,
+		LogLevel:              logLevel,
+		LogDebugSampling:      logDebugSampling,
 	}
 }
@@ -1,12 +1,15 @@
 package auth_controller
 
 import (
+	"flower-backend/app"
 	"flower-backend/config"
+	"flower-backend/libs"
+	"flower-backend/log"
 	user_services "flower-backend/services/v1/user"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
-	"gorm.io/gorm"
 )
 
 type AuthController interface {
@@ -14,6 +17,7 @@ type AuthController interface {
 	Login(c *gin.Context)
 	Logout(c *gin.Context)
 	RefreshToken(c *gin.Context)
+	SetPassword(c *gin.Context)
 	Me(c *gin.Context)
 	GoogleLogin(c *gin.Context)
 	GoogleCallback(c *gin.Context)
@@ -22,12 +26,28 @@ type AuthController interface {
 }
 
 type authController struct {
-	svc    user_services.UserService
-	cfg    *config.Config
-	logger *zap.SugaredLogger
+	svc           user_services.UserService
+	cfg           *config.Config
+	logger        *zap.SugaredLogger
+	tokens        *libs.JWT
+	oauthProvider func(name string) (libs.OAuthProvider, error)
+	now           func() time.Time
 }
 
-func NewAuthController(db *gorm.DB, cfg *config.Config, logger *zap.SugaredLogger) AuthController {
-	svc := user_services.NewUserService(db, cfg, logger)
-	return &authController{svc: svc, logger: logger, cfg: cfg}
+func NewAuthController(a *app.App) AuthController {
+	logger := a.Logger.Sugar()
+	svc := user_services.NewUserService(a.DB, a.Config, a.Users, logger, a.Storage)
+	return &authController{
+		svc:           svc,
+		logger:        logger,
+		cfg:           a.Config,
+		tokens:        a.JWT,
+		oauthProvider: a.OAuthProvider,
+		now:           a.Now,
+	}
+}
+
+// log returns the request's logger, which carries its request and user ids.
+func (ac *authController) log(c *gin.Context) *zap.SugaredLogger {
+	return log.SugarFromContext(c.Request.Context(), ac.logger)
 }
@@ -1,12 +1,9 @@
 package auth_controller
 
 import (
-	"flower-backend/database"
-	"flower-backend/libs"
 	"flower-backend/models"
 	"flower-backend/utils"
 	"net/http"
-	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -30,6 +27,7 @@ type LoginRequest struct {
 //	@Success		200			{object}	map[string]interface{}	"Login successful, returns tokens"
 //	@Failure		400			{object}	map[string]interface{}	"Bad request - invalid input"
 //	@Failure		401			{object}	map[string]interface{}	"Unauthorized - invalid credentials"
+//	@Failure		403			{object}	map[string]interface{}	"Forbidden - account suspended"
 //	@Failure		500			{object}	map[string]interface{}	"Internal server error"
 //	@Security		BearerAuth
 //	@Router			/auth/login [post]
@@ -43,14 +41,14 @@ func (ac *authController) Login(c *gin.Context) {
 	email := req.Email
 	password := req.Password
 
-	user, err := ac.svc.GetUserByEmail(email)
+	user, err := ac.svc.WithContext(c.Request.Context()).GetUserByEmail(email)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			ac.logger.Error("user not found", zap.String("email", email))
+			ac.log(c).Error("user not found", zap.String("email", email))
 			utils.JSONError(c, http.StatusUnauthorized, "InvalidCredentials", "Invalid email or password")
 			return
 		}
-		ac.logger.Error("failed to get user", zap.Error(err))
+		ac.log(c).Error("failed to get user", zap.Error(err))
 		utils.JSONError(c, http.StatusInternalServerError, "", "Internal server error")
 		return
 	}
@@ -61,21 +59,25 @@ func (ac *authController) Login(c *gin.Context) {
 		return
 	}
 
+	if user.Suspended {
+		utils.JSONError(c, http.StatusForbidden, "AccountSuspended", "Account is suspended")
+		return
+	}
+
 	// generate access token
-	accessToken := libs.GenerateAccessToken(user.ID)
-	refreshToken := libs.GenerateRefreshToken(user.ID)
-	expiresAt := time.Now().Add(ac.cfg.JWTRefreshExpiry)
+	accessToken := ac.tokens.GenerateAccessToken(user.ID)
+	refreshToken := ac.tokens.GenerateRefreshToken(user.ID)
+	expiresAt := ac.now().Add(ac.cfg.JWTRefreshExpiry)
 
-	// create token
-	database.DB.Where("user_id = ?", user.ID).Delete(&models.Token{})
+	// create token; older sessions of the user are replaced
 	token := models.Token{
 		Token:     refreshToken,
 		UserID:    user.ID,
 		ExpiresAt: expiresAt,
 	}
 
-	if err := database.DB.Create(&token).Error; err != nil {
-		ac.logger.Error("failed to create token", zap.Error(err))
+	if err := ac.svc.WithContext(c.Request.Context()).CreateToken(&token); err != nil {
+		ac.log(c).Error("failed to create token", zap.Error(err))
 		utils.JSONError(c, http.StatusInternalServerError, "", "Failed to create token")
 		return
 	}
@@ -97,5 +99,5 @@ func (ac *authController) Login(c *gin.Context) {
 		},
 	})
 
-	ac.logger.Info("Login successful", zap.Any("user", user))
+	ac.log(c).Infow("Login successful", "user_id", user.ID)
 }
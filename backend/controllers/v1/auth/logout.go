@@ -1,9 +1,6 @@
 package auth_controller
 
 import (
-	"flower-backend/database"
-	"flower-backend/libs"
-	"flower-backend/models"
 	"flower-backend/utils"
 	"net/http"
 
@@ -25,20 +22,20 @@ import (
 func (ac *authController) Logout(c *gin.Context) {
 	refreshToken, err := c.Cookie("refreshToken")
 	if err != nil {
-		ac.logger.Error("failed to get refresh token", zap.Error(err))
+		ac.log(c).Error("failed to get refresh token", zap.Error(err))
 		utils.JSONError(c, http.StatusUnauthorized, "AuthenticationError", "Refresh token is required")
 		return
 	}
 
-	userId, err := libs.VerifyRefreshToken(refreshToken)
+	userId, err := ac.tokens.VerifyRefreshToken(refreshToken)
 	if err != nil {
-		ac.logger.Error("failed to verify refresh token", zap.Error(err))
+		ac.log(c).Error("failed to verify refresh token", zap.Error(err))
 		utils.JSONError(c, http.StatusUnauthorized, "AuthenticationError", "Invalid refresh token")
 		return
 	}
 
-	if err := database.DB.Where("token = ?", refreshToken).Delete(&models.Token{}).Error; err != nil {
-		ac.logger.Error("failed to logout", zap.Error(err))
+	if err := ac.svc.WithContext(c.Request.Context()).DeleteToken(refreshToken); err != nil {
+		ac.log(c).Error("failed to logout", zap.Error(err))
 		utils.JSONError(c, http.StatusInternalServerError, "ServerError", "Internal server error")
 		return
 	}
@@ -49,5 +46,5 @@ func (ac *authController) Logout(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully", "userId": userId})
 
-	ac.logger.Info("Logged out successfully", zap.Uint("user_id", userId))
+	ac.log(c).Info("Logged out successfully", zap.Uint("user_id", userId))
 }
@@ -28,14 +28,14 @@ func (ac *authController) Me(c *gin.Context) {
 		return
 	}
 
-	user, err := ac.svc.GetUserByID(userID.(uint))
+	user, err := ac.svc.WithContext(c.Request.Context()).GetUserByID(userID.(uint))
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			ac.logger.Error("user not found", zap.Any("user_id", userID))
+			ac.log(c).Error("user not found", zap.Any("user_id", userID))
 			utils.JSONError(c, http.StatusNotFound, "NotFound", "User not found")
 			return
 		}
-		ac.logger.Error("failed to get user", zap.Error(err))
+		ac.log(c).Error("failed to get user", zap.Error(err))
 		utils.JSONError(c, http.StatusInternalServerError, "ServerError", "Internal server error")
 		return
 	}
@@ -52,5 +52,5 @@ func (ac *authController) Me(c *gin.Context) {
 		},
 	})
 
-	ac.logger.Info("User fetched successfully", zap.Uint("user_id", user.ID))
+	ac.log(c).Info("User fetched successfully", zap.Uint("user_id", user.ID))
 }
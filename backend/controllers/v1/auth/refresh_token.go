@@ -2,12 +2,8 @@ package auth_controller
 
 import (
 	"errors"
-	"flower-backend/database"
-	"flower-backend/libs"
-	"flower-backend/models"
 	"flower-backend/utils"
 	"net/http"
-	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
@@ -31,21 +27,21 @@ func (ac *authController) RefreshToken(c *gin.Context) {
 	}
 
 	// Check if token exists in database
-	var token models.Token
-	if err := database.DB.Where("token = ?", refreshToken).First(&token).Error; err != nil {
+	token, err := ac.svc.WithContext(c.Request.Context()).GetToken(refreshToken)
+	if err != nil {
 		utils.JSONError(c, http.StatusUnauthorized, "AuthenticationError", "Invalid refresh token")
 		return
 	}
 
-	if time.Now().After(token.ExpiresAt) {
+	if ac.now().After(token.ExpiresAt) {
 		// remove expired token eagerly
-		database.DB.Delete(&token)
+		ac.svc.WithContext(c.Request.Context()).DeleteToken(refreshToken)
 		utils.JSONError(c, http.StatusUnauthorized, "AuthenticationError", "Refresh token expired, please login again")
 		return
 	}
 
 	// Verify refresh token
-	userId, err := libs.VerifyRefreshToken(refreshToken)
+	userId, err := ac.tokens.VerifyRefreshToken(refreshToken)
 	if err != nil {
 		// Check if it's a token expiration error
 		if errors.Is(err, jwt.ErrTokenExpired) {
@@ -59,9 +55,9 @@ func (ac *authController) RefreshToken(c *gin.Context) {
 	}
 
 	// Generate new access token
-	accessToken := libs.GenerateAccessToken(userId)
+	accessToken := ac.tokens.GenerateAccessToken(userId)
 	if accessToken == "" {
-		ac.logger.Error("Error during refresh token: failed to generate access token")
+		ac.log(c).Error("Error during refresh token: failed to generate access token")
 		utils.JSONError(c, http.StatusInternalServerError, "ServerError", "Internal server error")
 		return
 	}
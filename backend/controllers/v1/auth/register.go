@@ -1,11 +1,9 @@
 package auth_controller
 
 import (
-	"flower-backend/libs"
 	"flower-backend/models"
 	"flower-backend/utils"
 	"net/http"
-	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -59,21 +57,21 @@ func (ac *authController) Register(c *gin.Context) {
 
 	hashedPassword, err := utils.HashPassword(password)
 	if err != nil {
-		ac.logger.Error("failed to hash password", zap.Error(err))
+		ac.log(c).Error("failed to hash password", zap.Error(err))
 		utils.JSONError(c, http.StatusInternalServerError, "", "Failed to hash password")
 		return
 	}
 
-	user, err := ac.svc.RegisterUser(username, email, hashedPassword, nil)
+	user, err := ac.svc.WithContext(c.Request.Context()).RegisterUser(username, email, hashedPassword, nil)
 	if err != nil {
 		utils.JSONError(c, http.StatusInternalServerError, "", "Failed to register user")
 		return
 	}
 
 	// generate refresh token
-	accessToken := libs.GenerateAccessToken(user.ID)
-	refreshToken := libs.GenerateRefreshToken(user.ID)
-	expiresAt := time.Now().Add(ac.cfg.JWTRefreshExpiry)
+	accessToken := ac.tokens.GenerateAccessToken(user.ID)
+	refreshToken := ac.tokens.GenerateRefreshToken(user.ID)
+	expiresAt := ac.now().Add(ac.cfg.JWTRefreshExpiry)
 
 	// create token
 	token := models.Token{
@@ -82,8 +80,8 @@ func (ac *authController) Register(c *gin.Context) {
 		ExpiresAt: expiresAt,
 	}
 
-	if err := ac.svc.CreateToken(&token); err != nil {
-		ac.logger.Error("failed to create token", zap.Error(err))
+	if err := ac.svc.WithContext(c.Request.Context()).CreateToken(&token); err != nil {
+		ac.log(c).Error("failed to create token", zap.Error(err))
 		utils.JSONError(c, http.StatusInternalServerError, "", "Failed to create token")
 		return
 	}
@@ -0,0 +1,58 @@
+package auth_controller
+
+import (
+	user_services "flower-backend/services/v1/user"
+	"flower-backend/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type SetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=8"`
+}
+
+// SetPassword godoc
+//
+//	@Summary		Set password from an invite
+//	@Description	Choose a password with the single-use token from an invite email. Existing sessions are signed out.
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		SetPasswordRequest		true	"Token and new password"
+//	@Success		200		{object}	map[string]interface{}	"Password set"
+//	@Failure		400		{object}	map[string]interface{}	"Bad request - invalid password, or token invalid, expired or used"
+//	@Failure		500		{object}	map[string]interface{}	"Internal server error"
+//	@Router			/auth/set-password [post]
+func (ac *authController) SetPassword(c *gin.Context) {
+	var req SetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "ValidationError", "Token and password are required")
+		return
+	}
+
+	if !utils.ValidatePassword(req.Password) {
+		utils.JSONError(c, http.StatusBadRequest, "ValidationError", "Invalid password")
+		return
+	}
+
+	hashedPassword, err := utils.HashPassword(req.Password)
+	if err != nil {
+		ac.log(c).Error("failed to hash password", zap.Error(err))
+		utils.JSONError(c, http.StatusInternalServerError, "", "Failed to hash password")
+		return
+	}
+
+	if err := ac.svc.WithContext(c.Request.Context()).SetPassword(req.Token, hashedPassword); err != nil {
+		if err == user_services.ErrInvalidPasswordToken {
+			utils.JSONError(c, http.StatusBadRequest, "ValidationError", "Link is invalid or has expired")
+			return
+		}
+		utils.JSONError(c, http.StatusInternalServerError, "", "Failed to set password")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password set successfully"})
+}
@@ -0,0 +1,35 @@
+package feed_controller
+
+import (
+	"flower-backend/app"
+	"flower-backend/config"
+	"flower-backend/log"
+	feed_services "flower-backend/services/v1/feed"
+	viewer_services "flower-backend/services/v1/viewer"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type FeedController interface {
+	GetFeed(c *gin.Context)
+}
+
+type feedController struct {
+	svc    feed_services.FeedService
+	viewer viewer_services.ViewerService
+	logger *zap.SugaredLogger
+	cfg    *config.Config
+}
+
+func NewFeedController(a *app.App) FeedController {
+	logger := a.Logger.Sugar()
+	svc := feed_services.NewFeedService(a.DB, a.Config, logger)
+	viewer := viewer_services.NewViewerService(a.Config, a.Posts, a.Users, logger)
+	return &feedController{svc: svc, viewer: viewer, logger: logger, cfg: a.Config}
+}
+
+// log returns the request's logger, which carries its request and user ids.
+func (fc *feedController) log(c *gin.Context) *zap.SugaredLogger {
+	return log.SugarFromContext(c.Request.Context(), fc.logger)
+}
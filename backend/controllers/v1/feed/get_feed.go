@@ -0,0 +1,60 @@
+package feed_controller
+
+import (
+	public_dto "flower-backend/dto/public"
+	feed_services "flower-backend/services/v1/feed"
+	"flower-backend/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// GetFeed godoc
+//
+//	@Summary		Get personalized feed
+//	@Description	Get a ranked feed blending posts from followed users with popular posts
+//	@Tags			feed
+//	@Produce		json
+//	@Param			cursor	query		string					false	"Cursor returned by the previous page"
+//	@Param			limit	query		int						false	"Items per page (default 20, max 50)"
+//	@Success		200		{object}	map[string]interface{}	"Feed fetched successfully"
+//	@Failure		400		{object}	map[string]interface{}	"Bad request - invalid input"
+//	@Failure		401		{object}	map[string]interface{}	"Unauthorized"
+//	@Failure		500		{object}	map[string]interface{}	"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/feed [get]
+func (fc *feedController) GetFeed(c *gin.Context) {
+	userId := c.GetUint("user_id")
+	if userId == 0 {
+		fc.log(c).Error("user_id not found in context")
+		utils.JSONError(c, http.StatusUnauthorized, "Unauthorized", "Unauthorized")
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 50 {
+		utils.JSONError(c, http.StatusBadRequest, "ValidationError", "Invalid limit")
+		return
+	}
+
+	posts, nextCursor, err := fc.svc.WithContext(c.Request.Context()).GetFeed(userId, c.Query("cursor"), limit)
+	if err != nil {
+		if err == feed_services.ErrInvalidCursor {
+			utils.JSONError(c, http.StatusBadRequest, "ValidationError", "Invalid cursor")
+			return
+		}
+		utils.JSONError(c, http.StatusInternalServerError, "ServerError", "Failed to get feed")
+		return
+	}
+	postsDTO := public_dto.ToPublicPosts(posts)
+	// Flags are left out if the lookup fails; the feed itself is still served.
+	if viewer, err := fc.viewer.WithContext(c.Request.Context()).ForPosts(userId, posts); err == nil {
+		viewer.ApplyToPosts(postsDTO)
+	} else {
+		fc.log(c).Warn("failed to load viewer flags for posts", zap.Error(err))
+	}
+	c.JSON(http.StatusOK, gin.H{"posts": postsDTO, "nextCursor": nextCursor})
+	fc.log(c).Info("feed fetched successfully", zap.Uint("user_id", userId), zap.Int("posts_count", len(posts)))
+}
@@ -0,0 +1,30 @@
+package health_controller
+
+import (
+	"flower-backend/app"
+	"flower-backend/health"
+	"flower-backend/log"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type HealthController interface {
+	Livez(c *gin.Context)
+	Readyz(c *gin.Context)
+	Version(c *gin.Context)
+}
+
+type healthController struct {
+	checker *health.Checker
+	logger  *zap.SugaredLogger
+}
+
+func NewHealthController(a *app.App) HealthController {
+	return &healthController{checker: a.Health, logger: a.Logger.Sugar()}
+}
+
+// log returns the request's logger, which carries its request id.
+func (hc *healthController) log(c *gin.Context) *zap.SugaredLogger {
+	return log.SugarFromContext(c.Request.Context(), hc.logger)
+}
@@ -0,0 +1,35 @@
+package health_controller
+
+import (
+	"flower-backend/health"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /livez
+// The process is up and serving; dependencies are not consulted, so a
+// database outage does not get every instance restarted.
+func (hc *healthController) Livez(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": health.StatusOK})
+}
+
+// GET /readyz
+func (hc *healthController) Readyz(c *gin.Context) {
+	report := hc.checker.Ready(c.Request.Context())
+	if !report.Ready() {
+		for name, result := range report.Checks {
+			if result.Status != health.StatusOK {
+				hc.log(c).Warnf("Readiness check %s failed: %s", name, result.Error)
+			}
+		}
+		c.JSON(http.StatusServiceUnavailable, report)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// GET /version
+func (hc *healthController) Version(c *gin.Context) {
+	c.JSON(http.StatusOK, health.Build())
+}
@@ -0,0 +1,41 @@
+package image_controller
+
+import (
+	"flower-backend/utils"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// GetImage godoc
+//
+//	@Summary		Get an image
+//	@Description	Redirect to a short-lived signed URL of an image kept in private storage. The stored image
+//	@Description	URLs point here, so they stay valid while the signed ones expire.
+//	@Tags			images
+//	@Param			key	path	string	true	"Image key, e.g. flower-sharing/post_image_1_1700000000"
+//	@Success		302
+//	@Failure		404	{object}	map[string]interface{}
+//	@Router			/images/{key} [get]
+func (ic *imageController) GetImage(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	// only uploaded images are served, not whatever else the bucket holds
+	if !strings.HasPrefix(key, "flower-sharing/") || strings.Contains(key, "..") {
+		utils.JSONError(c, http.StatusNotFound, "NotFound", "Image not found")
+		return
+	}
+	url, err := ic.signer.SignURL(c.Request.Context(), key, ic.expiry)
+	if err != nil {
+		ic.log(c).Error("failed to sign image URL", zap.String("key", key), zap.Error(err))
+		utils.JSONError(c, http.StatusInternalServerError, "", "Failed to get image")
+		return
+	}
+	// browsers may reuse the redirect, but not past the signed URL's expiry
+	c.Header("Cache-Control", fmt.Sprintf("private, max-age=%d", int(ic.expiry.Seconds()/2)))
+	c.Writer.Header().Del("Pragma")
+	c.Writer.Header().Del("Expires")
+	c.Redirect(http.StatusFound, url)
+}
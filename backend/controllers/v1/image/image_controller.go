@@ -0,0 +1,34 @@
+package image_controller
+
+import (
+	"flower-backend/app"
+	"flower-backend/libs"
+	"flower-backend/log"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type ImageController interface {
+	GetImage(c *gin.Context)
+}
+
+type imageController struct {
+	signer libs.URLSigner
+	expiry time.Duration
+	logger *zap.SugaredLogger
+}
+
+func NewImageController(a *app.App) ImageController {
+	expiry := a.Config.S3URLExpiry
+	if expiry <= 0 {
+		expiry = time.Hour
+	}
+	return &imageController{signer: a.Images, expiry: expiry, logger: a.Logger.Sugar()}
+}
+
+// log returns the request's logger, which carries its request id.
+func (ic *imageController) log(c *gin.Context) *zap.SugaredLogger {
+	return log.SugarFromContext(c.Request.Context(), ic.logger)
+}
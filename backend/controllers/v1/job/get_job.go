@@ -0,0 +1,72 @@
+package job_controller
+
+import (
+	admin_dto "flower-backend/dto/admin"
+	"flower-backend/models"
+	job_repository "flower-backend/repositories/v1/job"
+	"flower-backend/utils"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var validStatuses = map[string]bool{
+	models.JobQueued:    true,
+	models.JobRunning:   true,
+	models.JobSucceeded: true,
+	models.JobDead:      true,
+}
+
+// GET /api/v1/admin/jobs?status=dead&type=storage.delete&page=1&limit=20
+func (jc *jobController) GetJobs(c *gin.Context) {
+	filter := job_repository.JobFilter{Status: c.Query("status"), Type: c.Query("type")}
+	if filter.Status != "" && !validStatuses[filter.Status] {
+		utils.JSONError(c, http.StatusBadRequest, "ValidationError", "Invalid status, expected queued, running, succeeded or dead")
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		utils.JSONError(c, http.StatusBadRequest, "ValidationError", "Invalid page")
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		utils.JSONError(c, http.StatusBadRequest, "ValidationError", "Invalid limit")
+		return
+	}
+
+	jobs, total, err := jc.svc.WithContext(c.Request.Context()).GetJobs(filter, page, limit)
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "ServerError", "Internal server error")
+		return
+	}
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+	c.JSON(http.StatusOK, gin.H{"jobs": admin_dto.ToJobAdminDTOs(jobs), "total": total, "totalPages": totalPages, "page": page})
+	jc.log(c).Info("jobs fetched successfully", zap.Int("jobs_count", len(jobs)))
+}
+
+// GET /api/v1/admin/jobs/:id
+func (jc *jobController) GetJobByID(c *gin.Context) {
+	jobId := c.Param("id")
+	jobIdUint, err := utils.ParseUint(jobId, jc.log(c))
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "ValidationError", err.Error())
+		return
+	}
+
+	job, err := jc.svc.WithContext(c.Request.Context()).GetJobByID(jobIdUint)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.JSONError(c, http.StatusNotFound, "NotFound", "Job not found")
+			return
+		}
+		utils.JSONError(c, http.StatusInternalServerError, "ServerError", "Internal server error")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"job": admin_dto.ToJobAdminDTO(job)})
+}
@@ -0,0 +1,35 @@
+package job_controller
+
+import (
+	"flower-backend/app"
+	"flower-backend/config"
+	"flower-backend/log"
+	job_services "flower-backend/services/v1/job"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type JobController interface {
+	GetJobs(c *gin.Context)
+	GetJobByID(c *gin.Context)
+	RetryJob(c *gin.Context)
+	RetryDeadJobs(c *gin.Context)
+}
+
+type jobController struct {
+	svc    job_services.JobService
+	cfg    *config.Config
+	logger *zap.SugaredLogger
+}
+
+func NewJobController(a *app.App) JobController {
+	logger := a.Logger.Sugar()
+	svc := job_services.NewJobService(a.DB, a.Config, logger)
+	return &jobController{svc: svc, logger: logger, cfg: a.Config}
+}
+
+// log returns the request's logger, which carries its request and user ids.
+func (jc *jobController) log(c *gin.Context) *zap.SugaredLogger {
+	return log.SugarFromContext(c.Request.Context(), jc.logger)
+}
@@ -0,0 +1,50 @@
+package job_controller
+
+import (
+	"errors"
+	admin_dto "flower-backend/dto/admin"
+	job_services "flower-backend/services/v1/job"
+	"flower-backend/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// POST /api/v1/admin/jobs/:id/retry
+func (jc *jobController) RetryJob(c *gin.Context) {
+	jobId := c.Param("id")
+	jobIdUint, err := utils.ParseUint(jobId, jc.log(c))
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "ValidationError", err.Error())
+		return
+	}
+
+	job, err := jc.svc.WithContext(c.Request.Context()).RetryJob(jobIdUint)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			utils.JSONError(c, http.StatusNotFound, "NotFound", "Job not found")
+		case errors.Is(err, job_services.ErrJobNotDead):
+			utils.JSONError(c, http.StatusConflict, "Conflict", "Only dead jobs can be retried")
+		default:
+			utils.JSONError(c, http.StatusInternalServerError, "ServerError", "Failed to retry job")
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"job": admin_dto.ToJobAdminDTO(job)})
+	jc.log(c).Info("job retried by admin", zap.String("job_id", jobId))
+}
+
+// POST /api/v1/admin/jobs/retry?type=storage.delete
+// Requeues every dead job, or only those of the given type.
+func (jc *jobController) RetryDeadJobs(c *gin.Context) {
+	retried, err := jc.svc.WithContext(c.Request.Context()).RetryDeadJobs(c.Query("type"))
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "ServerError", "Failed to retry jobs")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"retried": retried})
+	jc.log(c).Info("dead jobs retried by admin", zap.Int64("count", retried))
+}
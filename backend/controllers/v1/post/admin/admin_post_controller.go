@@ -0,0 +1,40 @@
+package admin_post_controller
+
+import (
+	"flower-backend/app"
+	"flower-backend/config"
+	"flower-backend/log"
+	post_services "flower-backend/services/v1/post"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type AdminPostController interface {
+	// Get post operations
+	GetPostAll(c *gin.Context)
+	// Update post operations
+	UpdatePostByID(c *gin.Context)
+	TransferPostOwnership(c *gin.Context)
+	HidePosts(c *gin.Context)
+	UnhidePosts(c *gin.Context)
+	// Delete post operations
+	DeletePosts(c *gin.Context)
+}
+
+type adminPostController struct {
+	svc    post_services.PostService
+	cfg    *config.Config
+	logger *zap.SugaredLogger
+}
+
+func NewAdminPostController(a *app.App) AdminPostController {
+	logger := a.Logger.Sugar()
+	svc := post_services.NewPostService(a.DB, a.Config, a.Posts, logger, a.Storage)
+	return &adminPostController{svc: svc, logger: logger, cfg: a.Config}
+}
+
+// log returns the request's logger, which carries its request and user ids.
+func (pc *adminPostController) log(c *gin.Context) *zap.SugaredLogger {
+	return log.SugarFromContext(c.Request.Context(), pc.logger)
+}
@@ -0,0 +1,22 @@
+package admin_post_controller
+
+import (
+	"flower-backend/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// POST /api/v1/admin/post/bulk/delete
+func (pc *adminPostController) DeletePosts(c *gin.Context) {
+	var req BulkPostIDsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "ValidationError", "ids must contain between 1 and 100 post IDs")
+		return
+	}
+
+	deleted, failed := pc.svc.WithContext(c.Request.Context()).AdminDeletePosts(req.IDs)
+	c.JSON(http.StatusOK, gin.H{"message": "Posts deleted", "deleted": deleted, "failed": failed})
+	pc.log(c).Info("posts deleted by admin", zap.Int("deleted", len(deleted)), zap.Int("failed", len(failed)))
+}
@@ -0,0 +1,84 @@
+package admin_post_controller
+
+import (
+	admin_dto "flower-backend/dto/admin"
+	post_repository "flower-backend/repositories/v1/post"
+	"flower-backend/utils"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const dateLayout = "2006-01-02"
+
+// GET /api/v1/admin/post/all?author=1&from=2025-01-01&to=2025-01-31&reported=true&hidden=false&page=1&limit=20
+func (pc *adminPostController) GetPostAll(c *gin.Context) {
+	var filter post_repository.PostFilter
+
+	if author := c.Query("author"); author != "" {
+		authorID, err := utils.ParseUint(author, pc.log(c))
+		if err != nil {
+			utils.JSONError(c, http.StatusBadRequest, "ValidationError", "Invalid author")
+			return
+		}
+		filter.AuthorID = &authorID
+	}
+	if from := c.Query("from"); from != "" {
+		parsed, err := time.ParseInLocation(dateLayout, from, time.Local)
+		if err != nil {
+			utils.JSONError(c, http.StatusBadRequest, "ValidationError", "Invalid from date, expected YYYY-MM-DD")
+			return
+		}
+		filter.From = &parsed
+	}
+	if to := c.Query("to"); to != "" {
+		parsed, err := time.ParseInLocation(dateLayout, to, time.Local)
+		if err != nil {
+			utils.JSONError(c, http.StatusBadRequest, "ValidationError", "Invalid to date, expected YYYY-MM-DD")
+			return
+		}
+		// to is inclusive, so filter up to the start of the next day
+		end := parsed.AddDate(0, 0, 1)
+		filter.To = &end
+	}
+	if reported := c.Query("reported"); reported != "" {
+		value, err := strconv.ParseBool(reported)
+		if err != nil {
+			utils.JSONError(c, http.StatusBadRequest, "ValidationError", "Invalid reported")
+			return
+		}
+		filter.Reported = &value
+	}
+	if hidden := c.Query("hidden"); hidden != "" {
+		value, err := strconv.ParseBool(hidden)
+		if err != nil {
+			utils.JSONError(c, http.StatusBadRequest, "ValidationError", "Invalid hidden")
+			return
+		}
+		filter.Hidden = &value
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		utils.JSONError(c, http.StatusBadRequest, "ValidationError", "Invalid page")
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		utils.JSONError(c, http.StatusBadRequest, "ValidationError", "Invalid limit")
+		return
+	}
+
+	posts, total, err := pc.svc.WithContext(c.Request.Context()).AdminGetPosts(filter, page, limit)
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "ServerError", "Internal server error")
+		return
+	}
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+	c.JSON(http.StatusOK, gin.H{"posts": admin_dto.ToPostAdminDTOs(posts), "total": total, "totalPages": totalPages, "page": page})
+	pc.log(c).Info("admin posts fetched successfully", zap.Int("posts_count", len(posts)))
+}
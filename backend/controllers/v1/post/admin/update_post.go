@@ -0,0 +1,104 @@
+package admin_post_controller
+
+import (
+	admin_dto "flower-backend/dto/admin"
+	"flower-backend/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type BulkPostIDsRequest struct {
+	IDs []uint `json:"ids" binding:"required,min=1,max=100"`
+}
+
+// PUT /api/v1/admin/post/:id
+func (pc *adminPostController) UpdatePostByID(c *gin.Context) {
+	postId := c.Param("id")
+	postIdUint, err := utils.ParseUint(postId, pc.log(c))
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "ValidationError", err.Error())
+		return
+	}
+
+	updates := make(map[string]any)
+	if title := c.PostForm("title"); title != "" {
+		updates["title"] = title
+	}
+	if content := c.PostForm("content"); content != "" {
+		updates["content"] = content
+	}
+	if len(updates) == 0 {
+		utils.JSONError(c, http.StatusBadRequest, "ValidationError", "Title or content is required")
+		return
+	}
+
+	post, err := pc.svc.WithContext(c.Request.Context()).AdminUpdatePost(postIdUint, updates)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			pc.log(c).Error("post not found", zap.String("post_id", postId))
+			utils.JSONError(c, http.StatusNotFound, "NotFound", "Post not found")
+			return
+		}
+		utils.JSONError(c, http.StatusInternalServerError, "ServerError", "Failed to update post")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"post": admin_dto.ToPostAdminDTO(post)})
+	pc.log(c).Info("post updated by admin", zap.String("post_id", postId))
+}
+
+// PUT /api/v1/admin/post/:id/owner
+func (pc *adminPostController) TransferPostOwnership(c *gin.Context) {
+	postId := c.Param("id")
+	postIdUint, err := utils.ParseUint(postId, pc.log(c))
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "ValidationError", err.Error())
+		return
+	}
+	userId := c.PostForm("user_id")
+	userIdUint, err := utils.ParseUint(userId, pc.log(c))
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "ValidationError", "Invalid user_id")
+		return
+	}
+
+	post, err := pc.svc.WithContext(c.Request.Context()).TransferPostOwnership(postIdUint, userIdUint)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.JSONError(c, http.StatusNotFound, "NotFound", "Post or user not found")
+			return
+		}
+		utils.JSONError(c, http.StatusInternalServerError, "ServerError", "Failed to transfer post ownership")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"post": admin_dto.ToPostAdminDTO(post)})
+	pc.log(c).Info("post ownership transferred", zap.String("post_id", postId), zap.String("user_id", userId))
+}
+
+// POST /api/v1/admin/post/bulk/hide
+func (pc *adminPostController) HidePosts(c *gin.Context) {
+	pc.setPostsHidden(c, true)
+}
+
+// POST /api/v1/admin/post/bulk/unhide
+func (pc *adminPostController) UnhidePosts(c *gin.Context) {
+	pc.setPostsHidden(c, false)
+}
+
+func (pc *adminPostController) setPostsHidden(c *gin.Context, hidden bool) {
+	var req BulkPostIDsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "ValidationError", "ids must contain between 1 and 100 post IDs")
+		return
+	}
+
+	affected, err := pc.svc.WithContext(c.Request.Context()).AdminSetPostsHidden(req.IDs, hidden)
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "ServerError", "Failed to update posts")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Posts updated successfully", "updated": affected})
+	pc.log(c).Info("post visibility updated by admin", zap.Bool("hidden", hidden), zap.Int64("count", affected))
+}
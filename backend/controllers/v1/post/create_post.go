@@ -13,15 +13,26 @@ import (
 // CreatePost godoc
 //
 //	@Summary		Create a new post
-//	@Description	Create a post with title, content, and image
+//	@Description	Create a post with title, content, and image. With "Prefer: respond-async" the image is
+//	@Description	uploaded in the background: the post is created as processing and 202 is returned with
+//	@Description	the URL of its upload status. Instead of an image, asset_id names one the client uploaded
+//	@Description	directly with parameters from /post/upload/sign. Status "draft" keeps the post private and
+//	@Description	a future publish_at schedules it; either way it is managed under /post/drafts.
 //	@Tags			posts
 //	@Accept			multipart/form-data
 //	@Produce		json
-//	@Param			title	formData	string	true	"Post title"
-//	@Param			content	formData	string	true	"Post content"
-//	@Param			image	formData	file	true	"Post image"
-//	@Success		201		{object}	map[string]interface{}
-//	@Failure		400		{object}	map[string]interface{}
+//	@Param			title		formData	string	true	"Post title"
+//	@Param			content		formData	string	true	"Post content"
+//	@Param			image		formData	file	false	"Post image; required unless asset_id is given"
+//	@Param			asset_id	formData	string	false	"ID of a directly uploaded image"
+//	@Param			tags		formData	string	false	"Comma-separated tags"
+//	@Param			status		formData	string	false	"draft or published (default)"
+//	@Param			publish_at	formData	string	false	"RFC 3339 time to publish at"
+//	@Param			Prefer		header		string	false	"respond-async to upload the image in the background"
+//	@Success		200			{object}	map[string]interface{}
+//	@Success		202			{object}	map[string]interface{}
+//	@Failure		400			{object}	map[string]interface{}
+//	@Failure		409			{object}	map[string]interface{}
 //	@Security		BearerAuth
 //	@Router			/post [post]
 func (pc *postController) CreatePost(c *gin.Context) {
@@ -29,15 +40,41 @@ func (pc *postController) CreatePost(c *gin.Context) {
 	userId := c.GetUint("user_id")
 	title := c.PostForm("title")
 	content := c.PostForm("content")
-	imageFile, err := c.FormFile("image")
-	if err != nil {
-		pc.logger.Error("failed to get image file", zap.Error(err))
-		utils.JSONError(c, http.StatusBadRequest, "", "Failed to get image file")
+	if title == "" || content == "" {
+		utils.JSONError(c, http.StatusBadRequest, "", "Title and content are required")
 		return
 	}
 
-	if title == "" || content == "" {
-		utils.JSONError(c, http.StatusBadRequest, "", "Title and content are required")
+	tagNames, ok := utils.NormalizeTags(c.PostForm("tags"))
+	if !ok {
+		utils.JSONError(c, http.StatusBadRequest, "ValidationError", "Invalid tags")
+		return
+	}
+	tags := make([]models.Tag, 0, len(tagNames))
+	for _, name := range tagNames {
+		tags = append(tags, models.Tag{Name: name})
+	}
+	status, publishAt, ok := pc.parsePublishing(c)
+	if !ok {
+		return
+	}
+
+	if assetID := c.PostForm("asset_id"); assetID != "" {
+		pc.createPostFromAsset(c, models.Post{
+			Title:     title,
+			Content:   content,
+			UserID:    userId,
+			Tags:      tags,
+			Status:    status,
+			PublishAt: publishAt,
+		}, assetID)
+		return
+	}
+
+	imageFile, err := c.FormFile("image")
+	if err != nil {
+		pc.log(c).Error("failed to get image file", zap.Error(err))
+		utils.JSONError(c, http.StatusBadRequest, "", "Failed to get image file")
 		return
 	}
 
@@ -46,7 +83,7 @@ func (pc *postController) CreatePost(c *gin.Context) {
 	if imageFile != nil {
 		src, err := imageFile.Open()
 		if err != nil {
-			pc.logger.Error("failed to open image file", zap.Error(err))
+			pc.log(c).Error("failed to open image file", zap.Error(err))
 			utils.JSONError(c, http.StatusInternalServerError, "", "Failed to open image file")
 			return
 		}
@@ -54,30 +91,44 @@ func (pc *postController) CreatePost(c *gin.Context) {
 
 		buffer, err := io.ReadAll(src)
 		if err != nil {
-			pc.logger.Error("failed to read image file", zap.Error(err))
+			pc.log(c).Error("failed to read image file", zap.Error(err))
 			utils.JSONError(c, http.StatusInternalServerError, "", "Failed to read image file")
 			return
 		}
 
-		imageURL, err = pc.svc.UploadImage(buffer, userId)
+		// a finished background upload publishes its post, so drafts upload inline
+		if respondAsync(c) && status == models.PostPublished {
+			pc.createPostAsync(c, models.Post{
+				Title:   title,
+				Content: content,
+				UserID:  userId,
+				Tags:    tags,
+			}, buffer)
+			return
+		}
+
+		imageURL, err = pc.svc.WithContext(c.Request.Context()).UploadImage(buffer, userId)
 		if err != nil {
-			pc.logger.Error("failed to upload image", zap.Error(err))
+			pc.log(c).Error("failed to upload image", zap.Error(err))
 			utils.JSONError(c, http.StatusInternalServerError, "", "Failed to upload image")
 			return
 		}
 	}
 
-	post, err := pc.svc.CreatePost(models.Post{
-		Title:    title,
-		Content:  content,
-		ImageURL: imageURL,
-		UserID:   userId,
+	post, err := pc.svc.WithContext(c.Request.Context()).CreatePost(models.Post{
+		Title:     title,
+		Content:   content,
+		ImageURL:  imageURL,
+		UserID:    userId,
+		Tags:      tags,
+		Status:    status,
+		PublishAt: publishAt,
 	})
 	if err != nil {
-		pc.logger.Error("failed to create post", zap.Error(err))
+		pc.log(c).Error("failed to create post", zap.Error(err))
 		utils.JSONError(c, http.StatusInternalServerError, "", "Failed to create post")
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"post": post})
-	pc.logger.Info("post created successfully", zap.String("title", post.Title))
+	pc.log(c).Info("post created successfully", zap.String("title", post.Title))
 }
@@ -26,13 +26,13 @@ import (
 func (pc *postController) DeletePostByID(c *gin.Context) {
 
 	postId := c.Param("id")
-	postIdUint, err := utils.ParseUint(postId, pc.logger)
+	postIdUint, err := utils.ParseUint(postId, pc.log(c))
 	if err != nil {
 		utils.JSONError(c, http.StatusBadRequest, "ValidationError", err.Error())
 		return
 	}
 	userId := c.GetUint("user_id")
-	ownership, err := pc.svc.CheckPostOwnership(uint(postIdUint), userId)
+	ownership, err := pc.svc.WithContext(c.Request.Context()).CheckPostOwnership(uint(postIdUint), userId)
 	if err != nil {
 		utils.JSONError(c, http.StatusInternalServerError, "", "Failed to check post ownership")
 		return
@@ -41,9 +41,9 @@ func (pc *postController) DeletePostByID(c *gin.Context) {
 		utils.JSONError(c, http.StatusForbidden, "Forbidden", "You are not the owner of this post")
 		return
 	}
-	if err := pc.svc.DeletePostByID(uint(postIdUint), userId); err != nil {
+	if err := pc.svc.WithContext(c.Request.Context()).DeletePostByID(uint(postIdUint), userId); err != nil {
 		if err == gorm.ErrRecordNotFound {
-			pc.logger.Error("post not found", zap.String("post_id", postId))
+			pc.log(c).Error("post not found", zap.String("post_id", postId))
 			utils.JSONError(c, http.StatusNotFound, "NotFound", "Post not found")
 			return
 		}
@@ -51,5 +51,5 @@ func (pc *postController) DeletePostByID(c *gin.Context) {
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "Post deleted successfully"})
-	pc.logger.Info("post deleted successfully", zap.String("post_id", postId), zap.Uint("user_id", userId))
+	pc.log(c).Info("post deleted successfully", zap.String("post_id", postId), zap.Uint("user_id", userId))
 }
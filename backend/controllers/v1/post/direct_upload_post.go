@@ -0,0 +1,56 @@
+package post_controller
+
+import (
+	"errors"
+	"flower-backend/models"
+	post_services "flower-backend/services/v1/post"
+	"flower-backend/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// SignImageUpload godoc
+//
+//	@Summary		Sign a direct image upload
+//	@Description	Issue short-lived parameters for uploading an image straight to storage. Send the image
+//	@Description	as described by upload, then create the post with the returned asset_id.
+//	@Tags			posts
+//	@Produce		json
+//	@Success		200	{object}	map[string]interface{}
+//	@Security		BearerAuth
+//	@Router			/post/upload/sign [post]
+func (pc *postController) SignImageUpload(c *gin.Context) {
+	upload, signed, err := pc.svc.WithContext(c.Request.Context()).SignImageUpload(c.GetUint("user_id"))
+	if err != nil {
+		pc.log(c).Error("failed to sign image upload", zap.Error(err))
+		utils.JSONError(c, http.StatusInternalServerError, "", "Failed to sign image upload")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"asset_id": upload.AssetID, "upload": signed})
+}
+
+// createPostFromAsset creates a post with an image the author uploaded
+// directly to storage.
+func (pc *postController) createPostFromAsset(c *gin.Context, post models.Post, assetID string) {
+	created, err := pc.svc.WithContext(c.Request.Context()).CreatePostFromAsset(post, assetID)
+	if err != nil {
+		switch {
+		case errors.Is(err, post_services.ErrAssetNotFound):
+			utils.JSONError(c, http.StatusBadRequest, "ValidationError", "Unknown asset")
+		case errors.Is(err, post_services.ErrAssetNotUploaded):
+			utils.JSONError(c, http.StatusBadRequest, "ValidationError", "The image has not been uploaded")
+		case errors.Is(err, post_services.ErrAssetInvalid):
+			utils.JSONError(c, http.StatusBadRequest, "ValidationError", "The upload must be a PNG, JPEG or WebP image within the size limit")
+		case errors.Is(err, post_services.ErrAssetInUse):
+			utils.JSONError(c, http.StatusConflict, "Conflict", "The image is already used by another post")
+		default:
+			pc.log(c).Error("failed to create post", zap.Error(err))
+			utils.JSONError(c, http.StatusInternalServerError, "", "Failed to create post")
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"post": created})
+	pc.log(c).Info("post created successfully", zap.String("title", created.Title))
+}
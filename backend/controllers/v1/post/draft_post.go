@@ -0,0 +1,217 @@
+package post_controller
+
+import (
+	"errors"
+	public_dto "flower-backend/dto/public"
+	"flower-backend/models"
+	post_services "flower-backend/services/v1/post"
+	"flower-backend/utils"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// parsePublishing reads when a new post goes public: status "draft" keeps it
+// unpublished, a future publish_at (RFC 3339) schedules it, and otherwise it
+// is published at once.
+func (pc *postController) parsePublishing(c *gin.Context) (string, *time.Time, bool) {
+	status := c.PostForm("status")
+	if raw := c.PostForm("publish_at"); raw != "" {
+		publishAt, ok := pc.parsePublishAt(c, raw)
+		if !ok {
+			return "", nil, false
+		}
+		if status != "" && status != models.PostScheduled {
+			utils.JSONError(c, http.StatusBadRequest, "ValidationError", "Only scheduled posts take publish_at")
+			return "", nil, false
+		}
+		return models.PostScheduled, publishAt, true
+	}
+	switch status {
+	case "", models.PostPublished:
+		return models.PostPublished, nil, true
+	case models.PostDraft:
+		return models.PostDraft, nil, true
+	case models.PostScheduled:
+		utils.JSONError(c, http.StatusBadRequest, "ValidationError", "publish_at is required to schedule a post")
+	default:
+		utils.JSONError(c, http.StatusBadRequest, "ValidationError", "Invalid status")
+	}
+	return "", nil, false
+}
+
+// parsePublishAt parses a publish time, which must be in the future.
+func (pc *postController) parsePublishAt(c *gin.Context, raw string) (*time.Time, bool) {
+	publishAt, err := time.Parse(time.RFC3339, raw)
+	if err != nil || !publishAt.After(pc.now()) {
+		utils.JSONError(c, http.StatusBadRequest, "ValidationError", "publish_at must be a future RFC 3339 time")
+		return nil, false
+	}
+	publishAt = publishAt.UTC()
+	return &publishAt, true
+}
+
+// GetDrafts godoc
+//
+//	@Summary		Get draft posts
+//	@Description	List the caller's drafts and scheduled posts, most recently edited first
+//	@Tags			posts
+//	@Produce		json
+//	@Param			page	query		int	false	"Page number"		default(1)
+//	@Param			limit	query		int	false	"Items per page"	default(20)
+//	@Success		200		{object}	map[string]interface{}
+//	@Failure		400		{object}	map[string]interface{}
+//	@Security		BearerAuth
+//	@Router			/post/drafts [get]
+func (pc *postController) GetDrafts(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		utils.JSONError(c, http.StatusBadRequest, "ValidationError", "Invalid page")
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		utils.JSONError(c, http.StatusBadRequest, "ValidationError", "Invalid limit")
+		return
+	}
+	posts, total, err := pc.svc.WithContext(c.Request.Context()).GetDrafts(c.GetUint("user_id"), page, limit)
+	if err != nil {
+		pc.log(c).Error("failed to get drafts", zap.Error(err))
+		utils.JSONError(c, http.StatusInternalServerError, "", "Failed to get drafts")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"posts": public_dto.ToDraftPosts(posts), "total": total})
+}
+
+// GetDraft godoc
+//
+//	@Summary		Get a draft post
+//	@Description	Get one of the caller's drafts or scheduled posts
+//	@Tags			posts
+//	@Produce		json
+//	@Param			id	path		int	true	"Post ID"
+//	@Success		200	{object}	map[string]interface{}
+//	@Failure		404	{object}	map[string]interface{}
+//	@Security		BearerAuth
+//	@Router			/post/drafts/{id} [get]
+func (pc *postController) GetDraft(c *gin.Context) {
+	postId, err := utils.ParseUint(c.Param("id"), pc.log(c))
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "ValidationError", err.Error())
+		return
+	}
+	post, err := pc.svc.WithContext(c.Request.Context()).GetDraft(uint(postId), c.GetUint("user_id"))
+	if err != nil {
+		pc.draftError(c, err, "Failed to get draft")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"post": public_dto.ToDraftPost(post)})
+}
+
+// UpdateDraft godoc
+//
+//	@Summary		Update a draft post
+//	@Description	Edit one of the caller's drafts or scheduled posts. Only the fields sent change. A future
+//	@Description	publish_at schedules the post; an empty one turns it back into a draft.
+//	@Tags			posts
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Param			id			path		int		true	"Post ID"
+//	@Param			title		formData	string	false	"Post title"
+//	@Param			content		formData	string	false	"Post content"
+//	@Param			tags		formData	string	false	"Comma-separated tags"
+//	@Param			publish_at	formData	string	false	"RFC 3339 time to publish at"
+//	@Success		200			{object}	map[string]interface{}
+//	@Failure		400			{object}	map[string]interface{}
+//	@Failure		404			{object}	map[string]interface{}
+//	@Failure		409			{object}	map[string]interface{}
+//	@Security		BearerAuth
+//	@Router			/post/drafts/{id} [put]
+func (pc *postController) UpdateDraft(c *gin.Context) {
+	postId, err := utils.ParseUint(c.Param("id"), pc.log(c))
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "ValidationError", err.Error())
+		return
+	}
+
+	updates := make(map[string]any)
+	for _, field := range []string{"title", "content"} {
+		if value, ok := c.GetPostForm(field); ok {
+			if value == "" {
+				utils.JSONError(c, http.StatusBadRequest, "ValidationError", "Title and content cannot be empty")
+				return
+			}
+			updates[field] = value
+		}
+	}
+	if raw, ok := c.GetPostForm("tags"); ok {
+		tagNames, ok := utils.NormalizeTags(raw)
+		if !ok {
+			utils.JSONError(c, http.StatusBadRequest, "ValidationError", "Invalid tags")
+			return
+		}
+		updates["tags"] = tagNames
+	}
+	if raw, ok := c.GetPostForm("publish_at"); ok {
+		var publishAt *time.Time
+		if raw != "" {
+			if publishAt, ok = pc.parsePublishAt(c, raw); !ok {
+				return
+			}
+		}
+		updates["publish_at"] = publishAt
+	}
+
+	post, err := pc.svc.WithContext(c.Request.Context()).UpdateDraft(uint(postId), c.GetUint("user_id"), updates)
+	if err != nil {
+		pc.draftError(c, err, "Failed to update draft")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"post": public_dto.ToDraftPost(post)})
+	pc.log(c).Info("draft updated successfully", zap.Uint("post_id", post.ID))
+}
+
+// PublishDraft godoc
+//
+//	@Summary		Publish a draft post
+//	@Description	Publish one of the caller's drafts or scheduled posts now and notify their followers
+//	@Tags			posts
+//	@Produce		json
+//	@Param			id	path		int	true	"Post ID"
+//	@Success		200	{object}	map[string]interface{}
+//	@Failure		404	{object}	map[string]interface{}
+//	@Failure		409	{object}	map[string]interface{}
+//	@Security		BearerAuth
+//	@Router			/post/drafts/{id}/publish [post]
+func (pc *postController) PublishDraft(c *gin.Context) {
+	postId, err := utils.ParseUint(c.Param("id"), pc.log(c))
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "ValidationError", err.Error())
+		return
+	}
+	post, err := pc.svc.WithContext(c.Request.Context()).PublishDraft(uint(postId), c.GetUint("user_id"))
+	if err != nil {
+		pc.draftError(c, err, "Failed to publish draft")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"post": public_dto.ToDraftPost(post)})
+	pc.log(c).Info("draft published successfully", zap.Uint("post_id", post.ID))
+}
+
+// draftError answers a failed draft operation; message is used for
+// unexpected errors.
+func (pc *postController) draftError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		utils.JSONError(c, http.StatusNotFound, "NotFound", "Draft not found")
+	case errors.Is(err, post_services.ErrPostNotDraft):
+		utils.JSONError(c, http.StatusConflict, "Conflict", "The post has already been published")
+	default:
+		pc.log(c).Error(message, zap.Error(err))
+		utils.JSONError(c, http.StatusInternalServerError, "", message)
+	}
+}
@@ -2,10 +2,12 @@ package post_controller
 
 import (
 	public_dto "flower-backend/dto/public"
+	"flower-backend/models"
 	"flower-backend/utils"
 	"math"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -26,25 +28,36 @@ import (
 func (pc *postController) GetPostByID(c *gin.Context) {
 
 	postId := c.Param("id")
-	postIdUint, err := utils.ParseUint(postId, pc.logger)
+	postIdUint, err := utils.ParseUint(postId, pc.log(c))
 	if err != nil {
 		utils.JSONError(c, http.StatusBadRequest, "ValidationError", err.Error())
 		return
 	}
-	post, err := pc.svc.GetPostByID(uint(postIdUint))
+	post, err := pc.svc.WithContext(c.Request.Context()).GetPostByID(uint(postIdUint))
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			pc.logger.Error("post not found", zap.String("post_id", postId))
+			pc.log(c).Error("post not found", zap.String("post_id", postId))
 			utils.JSONError(c, http.StatusNotFound, "NotFound", "Post not found")
 			return
 		}
-		pc.logger.Error("failed to get post", zap.Error(err))
+		pc.log(c).Error("failed to get post", zap.Error(err))
 		utils.JSONError(c, http.StatusInternalServerError, "", "Failed to get post")
 		return
 	}
+	if post.Hidden || post.Status != models.PostPublished {
+		utils.JSONError(c, http.StatusNotFound, "NotFound", "Post not found")
+		return
+	}
 	postDTO := public_dto.ToPublicPost(post)
-	c.JSON(http.StatusOK, gin.H{"post": postDTO})
-	pc.logger.Info("post fetched successfully", zap.String("post_id", postId))
+	pc.viewerForPosts(c, []models.Post{*post}).ApplyToPost(&postDTO)
+	// like counts, liked_by_me and the author's counters do not move
+	// UpdatedAt, so Last-Modified would go stale; only the ETag validates
+	response := gin.H{"post": postDTO}
+	if utils.NotModified(c, utils.ETag(response), time.Time{}) {
+		return
+	}
+	c.JSON(http.StatusOK, response)
+	pc.log(c).Info("post fetched successfully", zap.String("post_id", postId))
 }
 
 // GetPostAllByUserID godoc
@@ -62,25 +75,30 @@ func (pc *postController) GetPostByID(c *gin.Context) {
 func (pc *postController) GetPostAllByUserID(c *gin.Context) {
 
 	userId := c.Param("user_id")
-	userIdUint, err := utils.ParseUint(userId, pc.logger)
+	userIdUint, err := utils.ParseUint(userId, pc.log(c))
 	if err != nil {
 		utils.JSONError(c, http.StatusBadRequest, "ValidationError", err.Error())
 		return
 	}
-	posts, err := pc.svc.GetPostAllByUserID(uint(userIdUint))
+	posts, err := pc.svc.WithContext(c.Request.Context()).GetPostAllByUserID(uint(userIdUint))
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			pc.logger.Error("posts not found", zap.String("user_id", userId))
+			pc.log(c).Error("posts not found", zap.String("user_id", userId))
 			utils.JSONError(c, http.StatusNotFound, "NotFound", "Posts not found")
 			return
 		}
-		pc.logger.Error("failed to get posts", zap.Error(err))
+		pc.log(c).Error("failed to get posts", zap.Error(err))
 		utils.JSONError(c, http.StatusInternalServerError, "", "Failed to get posts")
 		return
 	}
 	postsDTO := public_dto.ToPublicPosts(posts)
-	c.JSON(http.StatusOK, gin.H{"posts": postsDTO})
-	pc.logger.Info("posts fetched successfully", zap.String("user_id", userId))
+	pc.viewerForPosts(c, posts).ApplyToPosts(postsDTO)
+	response := gin.H{"posts": postsDTO}
+	if utils.NotModified(c, utils.WeakETag(response), time.Time{}) {
+		return
+	}
+	c.JSON(http.StatusOK, response)
+	pc.log(c).Info("posts fetched successfully", zap.String("user_id", userId))
 }
 
 // GetPostAll godoc
@@ -95,20 +113,25 @@ func (pc *postController) GetPostAllByUserID(c *gin.Context) {
 //	@Securuty		BearerAuth
 //	@Router			/post/all [get]
 func (pc *postController) GetPostAll(c *gin.Context) {
-	posts, err := pc.svc.GetPostAll()
+	posts, err := pc.svc.WithContext(c.Request.Context()).GetPostAll()
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			pc.logger.Error("posts not found", zap.Error(err))
+			pc.log(c).Error("posts not found", zap.Error(err))
 			utils.JSONError(c, http.StatusNotFound, "NotFound", "Posts not found")
 			return
 		}
-		pc.logger.Error("failed to get posts", zap.Error(err))
+		pc.log(c).Error("failed to get posts", zap.Error(err))
 		utils.JSONError(c, http.StatusInternalServerError, "", "Failed to get posts")
 		return
 	}
 	postsDTO := public_dto.ToPublicPosts(posts)
-	c.JSON(http.StatusOK, gin.H{"posts": postsDTO})
-	pc.logger.Info("posts fetched successfully")
+	pc.viewerForPosts(c, posts).ApplyToPosts(postsDTO)
+	response := gin.H{"posts": postsDTO}
+	if utils.NotModified(c, utils.WeakETag(response), time.Time{}) {
+		return
+	}
+	c.JSON(http.StatusOK, response)
+	pc.log(c).Info("posts fetched successfully")
 }
 
 // SearchPosts godoc
@@ -129,14 +152,19 @@ func (pc *postController) SearchPosts(c *gin.Context) {
 		utils.JSONError(c, http.StatusBadRequest, "ValidationError", "Query is required")
 		return
 	}
-	posts, err := pc.svc.SearchPosts(query)
+	posts, err := pc.svc.WithContext(c.Request.Context()).SearchPosts(query)
 	if err != nil {
 		utils.JSONError(c, http.StatusInternalServerError, "", "Failed to search posts")
 		return
 	}
 	postsDTO := public_dto.ToPublicPosts(posts)
-	c.JSON(http.StatusOK, gin.H{"posts": postsDTO})
-	pc.logger.Info("posts searched successfully", zap.String("query", query))
+	pc.viewerForPosts(c, posts).ApplyToPosts(postsDTO)
+	response := gin.H{"posts": postsDTO}
+	if utils.NotModified(c, utils.WeakETag(response), time.Time{}) {
+		return
+	}
+	c.JSON(http.StatusOK, response)
+	pc.log(c).Info("posts searched successfully", zap.String("query", query))
 }
 
 // GetPostWithPagination godoc
@@ -169,14 +197,19 @@ func (pc *postController) GetPostWithPagination(c *gin.Context) {
 		utils.JSONError(c, http.StatusBadRequest, "ValidationError", "Invalid limit")
 		return
 	}
-	posts, total, err := pc.svc.GetPostWithPagination(pageInt, limitInt)
+	posts, total, err := pc.svc.WithContext(c.Request.Context()).GetPostWithPagination(pageInt, limitInt)
 	if err != nil {
 		utils.JSONError(c, http.StatusInternalServerError, "", "Failed to get posts with pagination")
 		return
 	}
 	postsDTO := public_dto.ToPublicPosts(posts)
+	pc.viewerForPosts(c, posts).ApplyToPosts(postsDTO)
 	// Calculate totalPages from total count and limit (ceiling division)
 	totalPages := int(math.Ceil(float64(total) / float64(limitInt)))
-	c.JSON(http.StatusOK, gin.H{"posts": postsDTO, "totalPages": totalPages, "page": pageInt})
-	pc.logger.Info("posts fetched successfully with pagination", zap.Int("page", pageInt), zap.Int("limit", limitInt))
+	response := gin.H{"posts": postsDTO, "totalPages": totalPages, "page": pageInt}
+	if utils.NotModified(c, utils.WeakETag(response), time.Time{}) {
+		return
+	}
+	c.JSON(http.StatusOK, response)
+	pc.log(c).Info("posts fetched successfully with pagination", zap.Int("page", pageInt), zap.Int("limit", limitInt))
 }
@@ -1,12 +1,15 @@
 package post_controller
 
 import (
+	"errors"
 	public_dto "flower-backend/dto/public"
 	"flower-backend/utils"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 // LikePost godoc
@@ -18,37 +21,42 @@ import (
 //	@Param			id	path		int						true	"Post ID"
 //	@Success		200	{object}	map[string]interface{}	"Post liked successfully"
 //	@Failure		400	{object}	map[string]interface{}	"Bad request - invalid input"
+//	@Failure		404	{object}	map[string]interface{}	"Post not found"
 //	@Failure		500	{object}	map[string]interface{}	"Internal server error"
 //	@Security		BearerAuth
 //	@Router			/post/{id}/like [post]
 func (pc *postController) LikePost(c *gin.Context) {
 
 	postId := c.Param("id")
-	postIdUint, err := utils.ParseUint(postId, pc.logger)
+	postIdUint, err := utils.ParseUint(postId, pc.log(c))
 	if err != nil {
 		utils.JSONError(c, http.StatusBadRequest, "ValidationError", err.Error())
 		return
 	}
 	userId := c.GetUint("user_id")
 	if userId == 0 {
-		pc.logger.Error("user_id not found in context")
+		pc.log(c).Error("user_id not found in context")
 		utils.JSONError(c, http.StatusUnauthorized, "Unauthorized", "Unauthorized")
 		return
 	}
 
-	if err := pc.svc.LikePost(uint(postIdUint), userId); err != nil {
+	if err := pc.svc.WithContext(c.Request.Context()).LikePost(uint(postIdUint), userId); err != nil {
 		// Handle specific error cases
 		if err.Error() == "post already liked" {
 			// This is expected validation, log as info instead of error
-			pc.logger.Info("post already liked",
+			pc.log(c).Info("post already liked",
 				zap.Uint("post_id", uint(postIdUint)),
 				zap.Uint("user_id", userId))
 			utils.JSONError(c, http.StatusBadRequest, "ValidationError", err.Error())
 			return
 		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.JSONError(c, http.StatusNotFound, "NotFound", "Post not found")
+			return
+		}
 
 		// Log actual errors
-		pc.logger.Error("failed to like post",
+		pc.log(c).Error("failed to like post",
 			zap.Uint("post_id", uint(postIdUint)),
 			zap.Uint("user_id", userId),
 			zap.Error(err))
@@ -57,7 +65,7 @@ func (pc *postController) LikePost(c *gin.Context) {
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "Post liked successfully"})
-	pc.logger.Info("post liked successfully", zap.Uint("post_id", uint(postIdUint)))
+	pc.log(c).Info("post liked successfully", zap.Uint("post_id", uint(postIdUint)))
 }
 
 // DislikePost godoc
@@ -75,18 +83,18 @@ func (pc *postController) LikePost(c *gin.Context) {
 func (pc *postController) DislikePost(c *gin.Context) {
 
 	postId := c.Param("id")
-	postIdUint, err := utils.ParseUint(postId, pc.logger)
+	postIdUint, err := utils.ParseUint(postId, pc.log(c))
 	if err != nil {
 		utils.JSONError(c, http.StatusBadRequest, "ValidationError", err.Error())
 		return
 	}
 	userId := c.GetUint("user_id")
-	if err := pc.svc.DislikePost(uint(postIdUint), userId); err != nil {
+	if err := pc.svc.WithContext(c.Request.Context()).DislikePost(uint(postIdUint), userId); err != nil {
 		utils.JSONError(c, http.StatusInternalServerError, "", "Failed to dislike post")
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "Post disliked successfully"})
-	pc.logger.Info("post disliked successfully", zap.Uint("post_id", uint(postIdUint)))
+	pc.log(c).Info("post disliked successfully", zap.Uint("post_id", uint(postIdUint)))
 }
 
 // GetPostLikes godoc
@@ -103,18 +111,22 @@ func (pc *postController) DislikePost(c *gin.Context) {
 //	@Router			/post/{id}/likes [get]
 func (pc *postController) GetPostLikes(c *gin.Context) {
 	postId := c.Param("id")
-	postIdUint, err := utils.ParseUint(postId, pc.logger)
+	postIdUint, err := utils.ParseUint(postId, pc.log(c))
 	if err != nil {
 		utils.JSONError(c, http.StatusBadRequest, "ValidationError", err.Error())
 		return
 	}
-	likes, err := pc.svc.GetPostLikes(uint(postIdUint))
+	likes, err := pc.svc.WithContext(c.Request.Context()).GetPostLikes(uint(postIdUint))
 	if err != nil {
 		utils.JSONError(c, http.StatusInternalServerError, "", "Failed to get post likes")
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"likes": likes})
-	pc.logger.Info("post likes fetched successfully", zap.Uint("post_id", uint(postIdUint)))
+	response := gin.H{"likes": likes}
+	if utils.NotModified(c, utils.ETag(response), time.Time{}) {
+		return
+	}
+	c.JSON(http.StatusOK, response)
+	pc.log(c).Info("post likes fetched successfully", zap.Uint("post_id", uint(postIdUint)))
 }
 
 // GetUserLikedPosts godoc
@@ -131,29 +143,34 @@ func (pc *postController) GetPostLikes(c *gin.Context) {
 //	@Router			/post/user/{user_id}/liked [get]
 func (pc *postController) GetUserLikedPosts(c *gin.Context) {
 	userId := c.Param("user_id")
-	userIdUint, err := utils.ParseUint(userId, pc.logger)
+	userIdUint, err := utils.ParseUint(userId, pc.log(c))
 	if err != nil {
 		utils.JSONError(c, http.StatusBadRequest, "ValidationError", err.Error())
 		return
 	}
 	page := c.Query("page")
 	limit := c.Query("limit")
-	pageUint, err := utils.ParseUint(page, pc.logger)
+	pageUint, err := utils.ParseUint(page, pc.log(c))
 	if err != nil {
 		utils.JSONError(c, http.StatusBadRequest, "ValidationError", err.Error())
 		return
 	}
-	limitUint, err := utils.ParseUint(limit, pc.logger)
+	limitUint, err := utils.ParseUint(limit, pc.log(c))
 	if err != nil {
 		utils.JSONError(c, http.StatusBadRequest, "ValidationError", err.Error())
 		return
 	}
-	posts, total, err := pc.svc.GetUserLikedPosts(uint(userIdUint), int(pageUint), int(limitUint))
+	posts, total, err := pc.svc.WithContext(c.Request.Context()).GetUserLikedPosts(uint(userIdUint), int(pageUint), int(limitUint))
 	if err != nil {
 		utils.JSONError(c, http.StatusInternalServerError, "", "Failed to get user liked posts")
 		return
 	}
 	postsDTO := public_dto.ToPublicPosts(posts)
-	c.JSON(http.StatusOK, gin.H{"posts": postsDTO, "total": total})
-	pc.logger.Info("user liked posts fetched successfully", zap.Uint("user_id", uint(userIdUint)))
+	pc.viewerForPosts(c, posts).ApplyToPosts(postsDTO)
+	response := gin.H{"posts": postsDTO, "total": total}
+	if utils.NotModified(c, utils.WeakETag(response), time.Time{}) {
+		return
+	}
+	c.JSON(http.StatusOK, response)
+	pc.log(c).Info("user liked posts fetched successfully", zap.Uint("user_id", uint(userIdUint)))
 }
@@ -1,12 +1,15 @@
 package post_controller
 
 import (
+	"flower-backend/app"
 	"flower-backend/config"
+	"flower-backend/log"
 	post_services "flower-backend/services/v1/post"
+	viewer_services "flower-backend/services/v1/viewer"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
-	"gorm.io/gorm"
 )
 
 type PostController interface {
@@ -16,21 +19,41 @@ type PostController interface {
 	GetPostAll(c *gin.Context)
 	SearchPosts(c *gin.Context)
 	GetPostWithPagination(c *gin.Context)
+	GetTrendingPosts(c *gin.Context)
+	GetPopularPosts(c *gin.Context)
 	UpdatePostByIDWithSelect(c *gin.Context)
 	DeletePostByID(c *gin.Context)
 	LikePost(c *gin.Context)
 	DislikePost(c *gin.Context)
 	GetPostLikes(c *gin.Context)
 	GetUserLikedPosts(c *gin.Context)
+	ReportPost(c *gin.Context)
+	GetPostStatus(c *gin.Context)
+	RetryPostUpload(c *gin.Context)
+	SignImageUpload(c *gin.Context)
+	GetDrafts(c *gin.Context)
+	GetDraft(c *gin.Context)
+	UpdateDraft(c *gin.Context)
+	PublishDraft(c *gin.Context)
 }
 
 type postController struct {
 	svc    post_services.PostService
+	viewer viewer_services.ViewerService
 	logger *zap.SugaredLogger
 	cfg    *config.Config
+	// now is the app's clock, which publish times must be ahead of
+	now func() time.Time
 }
 
-func NewPostController(db *gorm.DB, cfg *config.Config, logger *zap.SugaredLogger) PostController {
-	svc := post_services.NewPostService(db, cfg, logger)
-	return &postController{svc: svc, logger: logger, cfg: cfg}
+func NewPostController(a *app.App) PostController {
+	logger := a.Logger.Sugar()
+	svc := post_services.NewPostService(a.DB, a.Config, a.Posts, logger, a.Storage)
+	viewer := viewer_services.NewViewerService(a.Config, a.Posts, a.Users, logger)
+	return &postController{svc: svc, viewer: viewer, logger: logger, cfg: a.Config, now: a.Now}
+}
+
+// log returns the request's logger, which carries its request and user ids.
+func (pc *postController) log(c *gin.Context) *zap.SugaredLogger {
+	return log.SugarFromContext(c.Request.Context(), pc.logger)
 }
@@ -0,0 +1,58 @@
+package post_controller
+
+import (
+	"flower-backend/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ReportPost godoc
+//
+//	@Summary		Report a post
+//	@Description	Flag a post for review by an administrator
+//	@Tags			posts
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Param			id		path		int						true	"Post ID"
+//	@Param			reason	formData	string					false	"Reason for the report"
+//	@Success		200		{object}	map[string]interface{}	"Post reported successfully"
+//	@Failure		400		{object}	map[string]interface{}	"Bad request - invalid input"
+//	@Failure		404		{object}	map[string]interface{}	"Post not found"
+//	@Failure		500		{object}	map[string]interface{}	"Internal server error"
+//	@Security		BearerAuth
+//	@Router			/post/{id}/report [post]
+func (pc *postController) ReportPost(c *gin.Context) {
+
+	postId := c.Param("id")
+	postIdUint, err := utils.ParseUint(postId, pc.log(c))
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "ValidationError", err.Error())
+		return
+	}
+	userId := c.GetUint("user_id")
+	if userId == 0 {
+		pc.log(c).Error("user_id not found in context")
+		utils.JSONError(c, http.StatusUnauthorized, "Unauthorized", "Unauthorized")
+		return
+	}
+
+	reason := c.PostForm("reason")
+	if len(reason) > 500 {
+		utils.JSONError(c, http.StatusBadRequest, "ValidationError", "Reason must be at most 500 characters")
+		return
+	}
+
+	if err := pc.svc.WithContext(c.Request.Context()).ReportPost(postIdUint, userId, reason); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			utils.JSONError(c, http.StatusNotFound, "NotFound", "Post not found")
+			return
+		}
+		utils.JSONError(c, http.StatusInternalServerError, "", "Failed to report post")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Post reported successfully"})
+	pc.log(c).Info("post reported successfully", zap.Uint("post_id", postIdUint))
+}
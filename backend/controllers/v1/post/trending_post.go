@@ -0,0 +1,121 @@
+package post_controller
+
+import (
+	public_dto "flower-backend/dto/public"
+	post_services "flower-backend/services/v1/post"
+	"flower-backend/utils"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// GetTrendingPosts godoc
+//
+//	@Summary		Get trending posts
+//	@Description	Retrieve posts ranked by likes received in the last N hours
+//	@Tags			posts
+//	@Produce		json
+//	@Param			hours	query		int						false	"Window in hours (default 24, max 168)"
+//	@Param			tag		query		string					false	"Only posts with this tag"
+//	@Param			page	query		int						false	"Page number (default 1)"
+//	@Param			limit	query		int						false	"Items per page (default 20, max 100)"
+//	@Success		200		{object}	map[string]interface{}	"Posts fetched successfully"
+//	@Failure		400		{object}	map[string]interface{}	"Bad request - invalid input"
+//	@Failure		500		{object}	map[string]interface{}	"Internal server error"
+//	@Router			/post/trending [get]
+func (pc *postController) GetTrendingPosts(c *gin.Context) {
+	hours, err := strconv.Atoi(c.DefaultQuery("hours", "24"))
+	if err != nil || hours < 1 || hours > 168 {
+		utils.JSONError(c, http.StatusBadRequest, "ValidationError", "Invalid hours")
+		return
+	}
+	tag, page, limit, ok := parseRankedQuery(c)
+	if !ok {
+		return
+	}
+
+	posts, likes, total, err := pc.svc.WithContext(c.Request.Context()).GetTrendingPosts(hours, tag, page, limit)
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "", "Failed to get trending posts")
+		return
+	}
+	postsDTO := public_dto.ToRankedPosts(posts, likes)
+	pc.viewerForPosts(c, posts).ApplyToRankedPosts(postsDTO)
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+	response := gin.H{"posts": postsDTO, "totalPages": totalPages, "page": page}
+	if utils.NotModified(c, utils.WeakETag(response), time.Time{}) {
+		return
+	}
+	c.JSON(http.StatusOK, response)
+	pc.log(c).Info("trending posts fetched successfully", zap.Int("hours", hours), zap.Int("page", page))
+}
+
+// GetPopularPosts godoc
+//
+//	@Summary		Get popular posts
+//	@Description	Retrieve posts ranked by likes received in a day, week, month or all time
+//	@Tags			posts
+//	@Produce		json
+//	@Param			window	query		string					false	"day, week, month or all (default week)"
+//	@Param			tag		query		string					false	"Only posts with this tag"
+//	@Param			page	query		int						false	"Page number (default 1)"
+//	@Param			limit	query		int						false	"Items per page (default 20, max 100)"
+//	@Success		200		{object}	map[string]interface{}	"Posts fetched successfully"
+//	@Failure		400		{object}	map[string]interface{}	"Bad request - invalid input"
+//	@Failure		500		{object}	map[string]interface{}	"Internal server error"
+//	@Router			/post/popular [get]
+func (pc *postController) GetPopularPosts(c *gin.Context) {
+	window := c.DefaultQuery("window", "week")
+	tag, page, limit, ok := parseRankedQuery(c)
+	if !ok {
+		return
+	}
+
+	posts, likes, total, err := pc.svc.WithContext(c.Request.Context()).GetPopularPosts(window, tag, page, limit)
+	if err != nil {
+		if err == post_services.ErrInvalidWindow {
+			utils.JSONError(c, http.StatusBadRequest, "ValidationError", "Window must be day, week, month or all")
+			return
+		}
+		utils.JSONError(c, http.StatusInternalServerError, "", "Failed to get popular posts")
+		return
+	}
+	postsDTO := public_dto.ToRankedPosts(posts, likes)
+	pc.viewerForPosts(c, posts).ApplyToRankedPosts(postsDTO)
+	totalPages := int(math.Ceil(float64(total) / float64(limit)))
+	response := gin.H{"posts": postsDTO, "totalPages": totalPages, "page": page}
+	if utils.NotModified(c, utils.WeakETag(response), time.Time{}) {
+		return
+	}
+	c.JSON(http.StatusOK, response)
+	pc.log(c).Info("popular posts fetched successfully", zap.String("window", window), zap.Int("page", page))
+}
+
+// parseRankedQuery reads the tag and pagination parameters shared by the
+// ranked listings, writing a validation error and returning false if invalid.
+func parseRankedQuery(c *gin.Context) (string, int, int, bool) {
+	tag := ""
+	if raw := c.Query("tag"); raw != "" {
+		tags, ok := utils.NormalizeTags(raw)
+		if !ok || len(tags) != 1 {
+			utils.JSONError(c, http.StatusBadRequest, "ValidationError", "Invalid tag")
+			return "", 0, 0, false
+		}
+		tag = tags[0]
+	}
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		utils.JSONError(c, http.StatusBadRequest, "ValidationError", "Invalid page")
+		return "", 0, 0, false
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		utils.JSONError(c, http.StatusBadRequest, "ValidationError", "Invalid limit")
+		return "", 0, 0, false
+	}
+	return tag, page, limit, true
+}
@@ -22,6 +22,7 @@ import (
 //	@Param			title	formData	string					false	"Post title"
 //	@Param			content	formData	string					false	"Post content"
 //	@Param			image	formData	file					false	"Post image"
+//	@Param			tags	formData	string					false	"Comma-separated tags"
 //	@Success		200		{object}	map[string]interface{}	"Post updated successfully"
 //	@Failure		400		{object}	map[string]interface{}	"Bad request - invalid input"
 //	@Failure		403		{object}	map[string]interface{}	"Forbidden - you are not the owner of this post"
@@ -31,14 +32,14 @@ import (
 func (pc *postController) UpdatePostByIDWithSelect(c *gin.Context) {
 
 	postId := c.Param("id")
-	postIdUint, err := utils.ParseUint(postId, pc.logger)
+	postIdUint, err := utils.ParseUint(postId, pc.log(c))
 	if err != nil {
 		utils.JSONError(c, http.StatusBadRequest, "ValidationError", err.Error())
 		return
 	}
 
 	userId := c.GetUint("user_id")
-	ownership, err := pc.svc.CheckPostOwnership(uint(postIdUint), userId)
+	ownership, err := pc.svc.WithContext(c.Request.Context()).CheckPostOwnership(uint(postIdUint), userId)
 	if err != nil {
 		utils.JSONError(c, http.StatusInternalServerError, "", "Failed to check post ownership")
 		return
@@ -64,7 +65,7 @@ func (pc *postController) UpdatePostByIDWithSelect(c *gin.Context) {
 		if field == "image_url" {
 			imageFile, err = c.FormFile("image")
 			if err != nil {
-				pc.logger.Error("failed to get image file", zap.Error(err))
+				pc.log(c).Error("failed to get image file", zap.Error(err))
 				utils.JSONError(c, http.StatusBadRequest, "", "Failed to get image file")
 				return
 			}
@@ -79,12 +80,23 @@ func (pc *postController) UpdatePostByIDWithSelect(c *gin.Context) {
 	if content != "" {
 		updates["content"] = content
 	}
+	for _, field := range selectFields {
+		if field == "tags" {
+			tagNames, ok := utils.NormalizeTags(c.PostForm("tags"))
+			if !ok {
+				utils.JSONError(c, http.StatusBadRequest, "ValidationError", "Invalid tags")
+				return
+			}
+			updates["tags"] = tagNames
+			break
+		}
+	}
 
-	updatedPost, err := pc.svc.UpdatePostByID(uint(postIdUint), userId, imageFile, updates, selectFields)
+	updatedPost, err := pc.svc.WithContext(c.Request.Context()).UpdatePostByID(uint(postIdUint), userId, imageFile, updates, selectFields)
 	if err != nil {
 		utils.JSONError(c, http.StatusInternalServerError, "", "Failed to update post")
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"post": updatedPost})
-	pc.logger.Info("post updated successfully", zap.Uint("post_id", uint(postIdUint)))
+	pc.log(c).Info("post updated successfully", zap.Uint("post_id", uint(postIdUint)))
 }
@@ -0,0 +1,129 @@
+package post_controller
+
+import (
+	"errors"
+	public_dto "flower-backend/dto/public"
+	"flower-backend/models"
+	post_services "flower-backend/services/v1/post"
+	"flower-backend/utils"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// respondAsync reports whether the client asked for the work to finish in
+// the background (RFC 7240).
+func respondAsync(c *gin.Context) bool {
+	for _, preference := range strings.Split(c.GetHeader("Prefer"), ",") {
+		if strings.EqualFold(strings.TrimSpace(preference), "respond-async") {
+			return true
+		}
+	}
+	return false
+}
+
+// createPostAsync creates a processing post and answers 202 with the URL
+// where the author can follow its upload.
+func (pc *postController) createPostAsync(c *gin.Context, post models.Post, image []byte) {
+	created, err := pc.svc.WithContext(c.Request.Context()).CreatePostAsync(post, image)
+	if err != nil {
+		pc.log(c).Error("failed to create post", zap.Error(err))
+		utils.JSONError(c, http.StatusInternalServerError, "", "Failed to create post")
+		return
+	}
+	statusURL := fmt.Sprintf("/api/v1/post/%d/status", created.ID)
+	c.Header("Preference-Applied", "respond-async")
+	c.Header("Location", statusURL)
+	c.JSON(http.StatusAccepted, gin.H{"post": created, "status_url": statusURL})
+	pc.log(c).Info("post created, image upload queued", zap.Uint("post_id", created.ID))
+}
+
+// GetPostStatus godoc
+//
+//	@Summary		Get post upload status
+//	@Description	Report whether the background image upload of the caller's post is processing, published or failed
+//	@Tags			posts
+//	@Produce		json
+//	@Param			id	path		int	true	"Post ID"
+//	@Success		200	{object}	map[string]interface{}
+//	@Failure		404	{object}	map[string]interface{}
+//	@Security		BearerAuth
+//	@Router			/post/{id}/status [get]
+func (pc *postController) GetPostStatus(c *gin.Context) {
+	postId, err := utils.ParseUint(c.Param("id"), pc.log(c))
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "ValidationError", err.Error())
+		return
+	}
+	post, err := pc.svc.WithContext(c.Request.Context()).GetUploadStatus(uint(postId), c.GetUint("user_id"))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.JSONError(c, http.StatusNotFound, "NotFound", "Post not found")
+			return
+		}
+		pc.log(c).Error("failed to get post status", zap.Error(err))
+		utils.JSONError(c, http.StatusInternalServerError, "", "Failed to get post status")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"post": public_dto.ToPostStatus(post)})
+}
+
+// RetryPostUpload godoc
+//
+//	@Summary		Retry a failed image upload
+//	@Description	Queue the image upload of a failed post again, with a new image or the one sent originally
+//	@Tags			posts
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Param			id		path		int		true	"Post ID"
+//	@Param			image	formData	file	false	"Replacement image"
+//	@Success		202		{object}	map[string]interface{}
+//	@Failure		404		{object}	map[string]interface{}
+//	@Failure		409		{object}	map[string]interface{}
+//	@Security		BearerAuth
+//	@Router			/post/{id}/upload/retry [post]
+func (pc *postController) RetryPostUpload(c *gin.Context) {
+	postId, err := utils.ParseUint(c.Param("id"), pc.log(c))
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "ValidationError", err.Error())
+		return
+	}
+
+	var image []byte
+	if imageFile, err := c.FormFile("image"); err == nil {
+		src, err := imageFile.Open()
+		if err != nil {
+			pc.log(c).Error("failed to open image file", zap.Error(err))
+			utils.JSONError(c, http.StatusInternalServerError, "", "Failed to open image file")
+			return
+		}
+		defer src.Close()
+		if image, err = io.ReadAll(src); err != nil {
+			pc.log(c).Error("failed to read image file", zap.Error(err))
+			utils.JSONError(c, http.StatusInternalServerError, "", "Failed to read image file")
+			return
+		}
+	}
+
+	post, err := pc.svc.WithContext(c.Request.Context()).RetryUpload(uint(postId), c.GetUint("user_id"), image)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			utils.JSONError(c, http.StatusNotFound, "NotFound", "Post not found")
+		case errors.Is(err, post_services.ErrUploadNotFailed):
+			utils.JSONError(c, http.StatusConflict, "Conflict", "Only a failed upload can be retried")
+		default:
+			pc.log(c).Error("failed to retry post upload", zap.Error(err))
+			utils.JSONError(c, http.StatusInternalServerError, "", "Failed to retry upload")
+		}
+		return
+	}
+	c.Header("Location", fmt.Sprintf("/api/v1/post/%d/status", post.ID))
+	c.JSON(http.StatusAccepted, gin.H{"post": public_dto.ToPostStatus(post)})
+	pc.log(c).Info("post image upload retried", zap.Uint("post_id", post.ID))
+}
@@ -0,0 +1,21 @@
+package post_controller
+
+import (
+	public_dto "flower-backend/dto/public"
+	"flower-backend/models"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// viewerForPosts loads the viewer flags for a page of posts. Anonymous
+// requests and lookup failures yield nil, which leaves the flags out; a
+// failure is logged, as the page itself is still served.
+func (pc *postController) viewerForPosts(c *gin.Context, posts []models.Post) *public_dto.ViewerState {
+	state, err := pc.viewer.WithContext(c.Request.Context()).ForPosts(c.GetUint("user_id"), posts)
+	if err != nil {
+		pc.log(c).Warn("failed to load viewer flags for posts", zap.Error(err))
+		return nil
+	}
+	return state
+}
@@ -0,0 +1,161 @@
+package stats_controller
+
+import (
+	"errors"
+	"flower-backend/cache"
+	stats_repository "flower-backend/repositories/v1/stats"
+	stats_services "flower-backend/services/v1/stats"
+	"flower-backend/utils"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	dateLayout       = "2006-01-02"
+	defaultRangeDays = 30
+	maxRangeDays     = 366
+	defaultTopLimit  = 10
+	maxTopLimit      = 100
+)
+
+var validMetrics = map[string]bool{
+	stats_repository.MetricSignups:     true,
+	stats_repository.MetricPosts:       true,
+	stats_repository.MetricLikes:       true,
+	stats_repository.MetricFollows:     true,
+	stats_repository.MetricActiveUsers: true,
+}
+
+// GET /api/v1/admin/stats/overview
+func (sc *statsController) GetOverview(c *gin.Context) {
+	overview, err := sc.svc.WithContext(c.Request.Context()).GetOverview()
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "ServerError", "Internal server error")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"overview": overview})
+	sc.log(c).Info("stats overview fetched successfully")
+}
+
+// GET /api/v1/admin/stats/timeseries?metric=signups&interval=day&from=2025-01-01&to=2025-01-31
+func (sc *statsController) GetTimeSeries(c *gin.Context) {
+	metric := c.Query("metric")
+	if !validMetrics[metric] {
+		utils.JSONError(c, http.StatusBadRequest, "ValidationError", "Invalid metric")
+		return
+	}
+	interval := c.DefaultQuery("interval", stats_services.IntervalDay)
+	if interval != stats_services.IntervalDay && interval != stats_services.IntervalWeek {
+		utils.JSONError(c, http.StatusBadRequest, "ValidationError", "Invalid interval")
+		return
+	}
+	from, to, err := parseDateRange(c)
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "ValidationError", err.Error())
+		return
+	}
+	series, err := sc.svc.WithContext(c.Request.Context()).GetTimeSeries(metric, interval, from, to)
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "ServerError", "Internal server error")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"series": series})
+	sc.log(c).Info("stats time series fetched successfully", zap.String("metric", metric), zap.String("interval", interval))
+}
+
+// GET /api/v1/admin/stats/top-posts?from=2025-01-01&to=2025-01-31&limit=10
+func (sc *statsController) GetTopPosts(c *gin.Context) {
+	from, to, err := parseDateRange(c)
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "ValidationError", err.Error())
+		return
+	}
+	limit, err := parseTopLimit(c)
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "ValidationError", err.Error())
+		return
+	}
+	posts, err := sc.svc.WithContext(c.Request.Context()).GetTopPosts(from, to, limit)
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "ServerError", "Internal server error")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"posts": posts})
+	sc.log(c).Info("top posts fetched successfully", zap.Int("count", len(posts)))
+}
+
+// GET /api/v1/admin/stats/top-creators?from=2025-01-01&to=2025-01-31&limit=10
+func (sc *statsController) GetTopCreators(c *gin.Context) {
+	from, to, err := parseDateRange(c)
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "ValidationError", err.Error())
+		return
+	}
+	limit, err := parseTopLimit(c)
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "ValidationError", err.Error())
+		return
+	}
+	creators, err := sc.svc.WithContext(c.Request.Context()).GetTopCreators(from, to, limit)
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "ServerError", "Internal server error")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"creators": creators})
+	sc.log(c).Info("top creators fetched successfully", zap.Int("count", len(creators)))
+}
+
+// parseDateRange reads inclusive from/to dates and returns a half-open [from, to) range.
+// Defaults to the last 30 days including today.
+func parseDateRange(c *gin.Context) (time.Time, time.Time, error) {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+
+	to := today
+	if raw := c.Query("to"); raw != "" {
+		parsed, err := time.ParseInLocation(dateLayout, raw, time.Local)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("Invalid to date, expected YYYY-MM-DD")
+		}
+		to = parsed
+	}
+
+	from := to.AddDate(0, 0, -(defaultRangeDays - 1))
+	if raw := c.Query("from"); raw != "" {
+		parsed, err := time.ParseInLocation(dateLayout, raw, time.Local)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("Invalid from date, expected YYYY-MM-DD")
+		}
+		from = parsed
+	}
+
+	if from.After(to) {
+		return time.Time{}, time.Time{}, errors.New("from must not be after to")
+	}
+	if to.Sub(from) > maxRangeDays*24*time.Hour {
+		return time.Time{}, time.Time{}, errors.New("Date range must not exceed 366 days")
+	}
+	return from, to.AddDate(0, 0, 1), nil
+}
+
+func parseTopLimit(c *gin.Context) (int, error) {
+	raw := c.Query("limit")
+	if raw == "" {
+		return defaultTopLimit, nil
+	}
+	limit, err := strconv.Atoi(raw)
+	if err != nil || limit < 1 || limit > maxTopLimit {
+		return 0, errors.New("Invalid limit")
+	}
+	return limit, nil
+}
+
+// GET /api/v1/admin/stats/cache
+func (sc *statsController) GetCacheStats(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"enabled": sc.cache != nil, "driver": sc.cfg.CacheDriver, "cache": cache.GetStats()})
+	sc.log(c).Info("cache stats fetched successfully")
+}
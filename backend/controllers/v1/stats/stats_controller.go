@@ -0,0 +1,38 @@
+package stats_controller
+
+import (
+	"flower-backend/app"
+	"flower-backend/cache"
+	"flower-backend/config"
+	"flower-backend/log"
+	stats_services "flower-backend/services/v1/stats"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type StatsController interface {
+	GetOverview(c *gin.Context)
+	GetTimeSeries(c *gin.Context)
+	GetTopPosts(c *gin.Context)
+	GetTopCreators(c *gin.Context)
+	GetCacheStats(c *gin.Context)
+}
+
+type statsController struct {
+	svc    stats_services.StatsService
+	cfg    *config.Config
+	cache  cache.Cache
+	logger *zap.SugaredLogger
+}
+
+func NewStatsController(a *app.App) StatsController {
+	logger := a.Logger.Sugar()
+	svc := stats_services.NewStatsService(a.DB, a.Config, a.Cache, logger)
+	return &statsController{svc: svc, logger: logger, cfg: a.Config, cache: a.Cache}
+}
+
+// log returns the request's logger, which carries its request and user ids.
+func (sc *statsController) log(c *gin.Context) *zap.SugaredLogger {
+	return log.SugarFromContext(c.Request.Context(), sc.logger)
+}
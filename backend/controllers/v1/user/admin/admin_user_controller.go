@@ -1,12 +1,13 @@
 package admin_user_controller
 
 import (
+	"flower-backend/app"
 	"flower-backend/config"
+	"flower-backend/log"
 	user_services "flower-backend/services/v1/user"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
-	"gorm.io/gorm"
 )
 
 type AdminUserController interface {
@@ -16,8 +17,14 @@ type AdminUserController interface {
 	GetUserByUsername(c *gin.Context)
 	GetUserAll(c *gin.Context)
 	GetUserByIDWithSelect(c *gin.Context)
+	ExportUsers(c *gin.Context)
+	// Create user operations
+	ImportUsers(c *gin.Context)
 	// Update user operations
 	UpdateUserByIDWithSelect(c *gin.Context)
+	BulkUpdateUserRole(c *gin.Context)
+	SuspendUsers(c *gin.Context)
+	UnsuspendUsers(c *gin.Context)
 	// Delete user operations
 	DeleteUserByID(c *gin.Context)
 }
@@ -28,7 +35,13 @@ type adminUserController struct {
 	logger *zap.SugaredLogger
 }
 
-func NewAdminUserController(db *gorm.DB, cfg *config.Config, logger *zap.SugaredLogger) AdminUserController {
-	svc := user_services.NewUserService(db, cfg, logger)
-	return &adminUserController{svc: svc, logger: logger, cfg: cfg}
+func NewAdminUserController(a *app.App) AdminUserController {
+	logger := a.Logger.Sugar()
+	svc := user_services.NewUserService(a.DB, a.Config, a.Users, logger, a.Storage)
+	return &adminUserController{svc: svc, logger: logger, cfg: a.Config}
+}
+
+// log returns the request's logger, which carries its request and user ids.
+func (uc *adminUserController) log(c *gin.Context) *zap.SugaredLogger {
+	return log.SugarFromContext(c.Request.Context(), uc.logger)
 }
@@ -0,0 +1,73 @@
+package admin_user_controller
+
+import (
+	user_services "flower-backend/services/v1/user"
+	"flower-backend/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type BulkUserIDsRequest struct {
+	IDs []uint `json:"ids" binding:"required,min=1,max=100"`
+}
+
+type BulkUserRoleRequest struct {
+	IDs  []uint `json:"ids" binding:"required,min=1,max=100"`
+	Role string `json:"role" binding:"required"`
+}
+
+// POST /api/v1/admin/user/bulk/role
+func (uc *adminUserController) BulkUpdateUserRole(c *gin.Context) {
+	var req BulkUserRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "ValidationError", "ids must contain between 1 and 100 user IDs and role is required")
+		return
+	}
+
+	affected, err := uc.svc.WithContext(c.Request.Context()).BulkUpdateUserRole(req.IDs, req.Role, c.GetUint("user_id"))
+	if err != nil {
+		switch err {
+		case user_services.ErrInvalidRole:
+			utils.JSONError(c, http.StatusBadRequest, "ValidationError", "Invalid role")
+		case user_services.ErrSelfModifyAdmin:
+			utils.JSONError(c, http.StatusBadRequest, "ValidationError", "You cannot change your own role")
+		default:
+			utils.JSONError(c, http.StatusInternalServerError, "ServerError", "Failed to update user roles")
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "User roles updated successfully", "updated": affected})
+	uc.log(c).Info("user roles updated by admin", zap.String("role", req.Role), zap.Int64("count", affected))
+}
+
+// POST /api/v1/admin/user/bulk/suspend
+func (uc *adminUserController) SuspendUsers(c *gin.Context) {
+	uc.setUsersSuspended(c, true)
+}
+
+// POST /api/v1/admin/user/bulk/unsuspend
+func (uc *adminUserController) UnsuspendUsers(c *gin.Context) {
+	uc.setUsersSuspended(c, false)
+}
+
+func (uc *adminUserController) setUsersSuspended(c *gin.Context, suspended bool) {
+	var req BulkUserIDsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "ValidationError", "ids must contain between 1 and 100 user IDs")
+		return
+	}
+
+	affected, err := uc.svc.WithContext(c.Request.Context()).BulkSetUsersSuspended(req.IDs, suspended, c.GetUint("user_id"))
+	if err != nil {
+		if err == user_services.ErrSelfModifyAdmin {
+			utils.JSONError(c, http.StatusBadRequest, "ValidationError", "You cannot suspend yourself")
+			return
+		}
+		utils.JSONError(c, http.StatusInternalServerError, "ServerError", "Failed to update users")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Users updated successfully", "updated": affected})
+	uc.log(c).Info("user suspension updated by admin", zap.Bool("suspended", suspended), zap.Int64("count", affected))
+}
@@ -12,14 +12,14 @@ import (
 // DELETE /api/v1/admin/user/:id
 func (uc *adminUserController) DeleteUserByID(c *gin.Context) {
 	userId := c.Param("id")
-	userIdUint, err := utils.ParseUint(userId, uc.logger)
+	userIdUint, err := utils.ParseUint(userId, uc.log(c))
 	if err != nil {
 		utils.JSONError(c, http.StatusBadRequest, "ValidationError", err.Error())
 		return
 	}
-	if err := uc.svc.DeleteUserByID(uint(userIdUint)); err != nil {
+	if err := uc.svc.WithContext(c.Request.Context()).DeleteUserByID(uint(userIdUint)); err != nil {
 		if err == gorm.ErrRecordNotFound {
-			uc.logger.Error("user not found", zap.String("user_id", userId))
+			uc.log(c).Error("user not found", zap.String("user_id", userId))
 			utils.JSONError(c, http.StatusNotFound, "NotFound", "User not found")
 			return
 		}
@@ -27,5 +27,5 @@ func (uc *adminUserController) DeleteUserByID(c *gin.Context) {
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "User deleted successfully"})
-	uc.logger.Info("user deleted successfully", zap.String("user_id", userId))
+	uc.log(c).Info("user deleted successfully", zap.String("user_id", userId))
 }
@@ -0,0 +1,61 @@
+package admin_user_controller
+
+import (
+	"encoding/csv"
+	user_repository "flower-backend/repositories/v1/user"
+	"flower-backend/utils"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// GET /api/v1/admin/user/export?role=admin&provider=google
+func (uc *adminUserController) ExportUsers(c *gin.Context) {
+	filter := user_repository.UserFilter{
+		Role:     c.Query("role"),
+		Provider: c.Query("provider"),
+	}
+	if filter.Provider != "" && filter.Provider != "local" && filter.Provider != "google" && filter.Provider != "github" {
+		utils.JSONError(c, http.StatusBadRequest, "ValidationError", "Invalid provider")
+		return
+	}
+
+	users, err := uc.svc.WithContext(c.Request.Context()).ExportUsers(filter)
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "ServerError", "Failed to export users")
+		return
+	}
+
+	filename := fmt.Sprintf("users_%s.csv", time.Now().Format("20060102_150405"))
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Status(http.StatusOK)
+
+	writer := csv.NewWriter(c.Writer)
+	_ = writer.Write([]string{"id", "username", "email", "role", "provider", "suspended", "created_at"})
+	for _, user := range users {
+		provider := user.Provider
+		if provider == "" {
+			provider = "local"
+		}
+		_ = writer.Write([]string{
+			strconv.FormatUint(uint64(user.ID), 10),
+			user.Username,
+			user.Email,
+			user.Role,
+			provider,
+			strconv.FormatBool(user.Suspended),
+			user.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		uc.log(c).Error("failed to write users csv", zap.Error(err))
+		return
+	}
+	uc.log(c).Info("users exported successfully", zap.Int("count", len(users)))
+}
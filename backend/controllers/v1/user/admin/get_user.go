@@ -14,15 +14,15 @@ import (
 // GET /api/v1/admin/user/:id
 func (uc *adminUserController) GetUserByID(c *gin.Context) {
 	userId := c.Param("id")
-	userIdUint, err := utils.ParseUint(userId, uc.logger)
+	userIdUint, err := utils.ParseUint(userId, uc.log(c))
 	if err != nil {
 		utils.JSONError(c, http.StatusBadRequest, "ValidationError", err.Error())
 		return
 	}
-	user, err := uc.svc.GetUserByID(uint(userIdUint))
+	user, err := uc.svc.WithContext(c.Request.Context()).GetUserByID(uint(userIdUint))
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			uc.logger.Error("user not found", zap.String("user_id", userId))
+			uc.log(c).Error("user not found", zap.String("user_id", userId))
 			utils.JSONError(c, http.StatusNotFound, "NotFound", "User not found")
 			return
 		}
@@ -30,7 +30,7 @@ func (uc *adminUserController) GetUserByID(c *gin.Context) {
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"user": admin_user_dto.ToUserAdminDTO(user)})
-	uc.logger.Info("user fetched successfully", zap.Uint("user_id", uint(userIdUint)))
+	uc.log(c).Info("user fetched successfully", zap.Uint("user_id", uint(userIdUint)))
 }
 
 // GET /api/v1/admin/user/:email
@@ -40,10 +40,10 @@ func (uc *adminUserController) GetUserByEmail(c *gin.Context) {
 		utils.JSONError(c, http.StatusBadRequest, "ValidationError", "Email is required")
 		return
 	}
-	user, err := uc.svc.GetUserByEmail(email)
+	user, err := uc.svc.WithContext(c.Request.Context()).GetUserByEmail(email)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			uc.logger.Error("user not found", zap.String("email", email))
+			uc.log(c).Error("user not found", zap.String("email", email))
 			utils.JSONError(c, http.StatusNotFound, "NotFound", "User not found")
 			return
 		}
@@ -51,7 +51,7 @@ func (uc *adminUserController) GetUserByEmail(c *gin.Context) {
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"user": admin_user_dto.ToUserAdminDTO(user)})
-	uc.logger.Info("user fetched successfully", zap.String("email", email))
+	uc.log(c).Info("user fetched successfully", zap.String("email", email))
 }
 
 // GET /api/v1/admin/user/:username
@@ -61,10 +61,10 @@ func (uc *adminUserController) GetUserByUsername(c *gin.Context) {
 		utils.JSONError(c, http.StatusBadRequest, "ValidationError", "Invalid username")
 		return
 	}
-	user, err := uc.svc.GetUserByUsername(username)
+	user, err := uc.svc.WithContext(c.Request.Context()).GetUserByUsername(username)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			uc.logger.Error("user not found", zap.String("username", username))
+			uc.log(c).Error("user not found", zap.String("username", username))
 			utils.JSONError(c, http.StatusNotFound, "NotFound", "User not found")
 			return
 		}
@@ -72,24 +72,24 @@ func (uc *adminUserController) GetUserByUsername(c *gin.Context) {
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"user": admin_user_dto.ToUserAdminDTO(user)})
-	uc.logger.Info("user fetched successfully", zap.String("username", username))
+	uc.log(c).Info("user fetched successfully", zap.String("username", username))
 }
 
 // GET /api/v1/admin/user/all
 func (uc *adminUserController) GetUserAll(c *gin.Context) {
-	users, err := uc.svc.GetUserAll()
+	users, err := uc.svc.WithContext(c.Request.Context()).GetUserAll()
 	if err != nil {
 		utils.JSONError(c, http.StatusInternalServerError, "ServerError", "Internal server error")
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"users": admin_user_dto.ToUserAdminDTOs(users)})
-	uc.logger.Info("all users fetched successfully", zap.Int("users_count", len(users)))
+	uc.log(c).Info("all users fetched successfully", zap.Int("users_count", len(users)))
 }
 
 // GET /api/v1/admin/user/id/:id/select?select=field1,field2,field3
 func (uc *adminUserController) GetUserByIDWithSelect(c *gin.Context) {
 	userId := c.Param("id")
-	userIdUint, err := utils.ParseUint(userId, uc.logger)
+	userIdUint, err := utils.ParseUint(userId, uc.log(c))
 	if err != nil {
 		utils.JSONError(c, http.StatusBadRequest, "ValidationError", err.Error())
 		return
@@ -100,10 +100,10 @@ func (uc *adminUserController) GetUserByIDWithSelect(c *gin.Context) {
 		return
 	}
 	selectFields := admin_user_dto.EnsureUserAdminSelectFields(strings.Split(selectFieldsString, ","))
-	user, err := uc.svc.GetUserByIDWithSelect(uint(userIdUint), selectFields)
+	user, err := uc.svc.WithContext(c.Request.Context()).GetUserByIDWithSelect(uint(userIdUint), selectFields)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			uc.logger.Error("user not found", zap.Uint("user_id", uint(userIdUint)))
+			uc.log(c).Error("user not found", zap.Uint("user_id", uint(userIdUint)))
 			utils.JSONError(c, http.StatusNotFound, "NotFound", "User not found")
 			return
 		}
@@ -111,5 +111,5 @@ func (uc *adminUserController) GetUserByIDWithSelect(c *gin.Context) {
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"user": admin_user_dto.ToUserAdminDTO(user)})
-	uc.logger.Info("user fetched successfully", zap.Uint("user_id", uint(userIdUint)))
+	uc.log(c).Info("user fetched successfully", zap.Uint("user_id", uint(userIdUint)))
 }
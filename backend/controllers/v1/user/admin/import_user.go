@@ -0,0 +1,168 @@
+package admin_user_controller
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	user_services "flower-backend/services/v1/user"
+	"flower-backend/utils"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	maxImportRows     = 200
+	maxImportFileSize = 2 << 20 // 2 MB
+)
+
+type importUserRecord struct {
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
+	Role     string `json:"role"`
+}
+
+// POST /api/v1/admin/user/import?dry_run=true&send_invites=true
+// multipart/form-data with a "file" field holding a .csv (header: username,email,password,role)
+// or .json (array of {"username","email","password","role"}) document
+func (uc *adminUserController) ImportUsers(c *gin.Context) {
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "ValidationError", "Invalid dry_run")
+		return
+	}
+	sendInvites, err := strconv.ParseBool(c.DefaultQuery("send_invites", "false"))
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "ValidationError", "Invalid send_invites")
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "ValidationError", "File is required")
+		return
+	}
+	if fileHeader.Size > maxImportFileSize {
+		utils.JSONError(c, http.StatusBadRequest, "ValidationError", "File must be at most 2MB")
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		uc.log(c).Error("failed to open import file", zap.Error(err))
+		utils.JSONError(c, http.StatusInternalServerError, "ServerError", "Failed to read file")
+		return
+	}
+	defer file.Close()
+
+	var rows []user_services.ImportUserRow
+	switch strings.ToLower(filepath.Ext(fileHeader.Filename)) {
+	case ".csv":
+		rows, err = parseImportCSV(file)
+	case ".json":
+		rows, err = parseImportJSON(file)
+	default:
+		utils.JSONError(c, http.StatusBadRequest, "ValidationError", "File must be .csv or .json")
+		return
+	}
+	if err != nil {
+		utils.JSONError(c, http.StatusBadRequest, "ValidationError", err.Error())
+		return
+	}
+	if len(rows) == 0 {
+		utils.JSONError(c, http.StatusBadRequest, "ValidationError", "File contains no users")
+		return
+	}
+	if len(rows) > maxImportRows {
+		utils.JSONError(c, http.StatusBadRequest, "ValidationError", fmt.Sprintf("File must contain at most %d users", maxImportRows))
+		return
+	}
+
+	result, err := uc.svc.WithContext(c.Request.Context()).ImportUsers(rows, dryRun, sendInvites)
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "ServerError", "Failed to import users")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"result": result})
+	uc.log(c).Info("users import processed", zap.Bool("dry_run", dryRun), zap.Int("total", result.Total), zap.Int("created", result.Created))
+}
+
+func parseImportCSV(r io.Reader) ([]user_services.ImportUserRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("invalid CSV header: %v", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		// Spreadsheet exports often prefix the first column with a UTF-8 BOM
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	if _, ok := columns["username"]; !ok {
+		return nil, errors.New("CSV header must contain a username column")
+	}
+	if _, ok := columns["email"]; !ok {
+		return nil, errors.New("CSV header must contain an email column")
+	}
+
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return record[i]
+		}
+		return ""
+	}
+
+	var rows []user_services.ImportUserRow
+	// The header is row 1, so data starts at row 2
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV on row %d: %v", line, err)
+		}
+		rows = append(rows, user_services.ImportUserRow{
+			Row:      line,
+			Username: field(record, "username"),
+			Email:    field(record, "email"),
+			Password: field(record, "password"),
+			Role:     field(record, "role"),
+		})
+		if len(rows) > maxImportRows {
+			break
+		}
+	}
+	return rows, nil
+}
+
+func parseImportJSON(r io.Reader) ([]user_services.ImportUserRow, error) {
+	var records []importUserRecord
+	if err := json.NewDecoder(r).Decode(&records); err != nil {
+		return nil, errors.New("invalid JSON, expected an array of users")
+	}
+
+	rows := make([]user_services.ImportUserRow, 0, len(records))
+	for i, record := range records {
+		rows = append(rows, user_services.ImportUserRow{
+			Row:      i + 1,
+			Username: record.Username,
+			Email:    record.Email,
+			Password: record.Password,
+			Role:     record.Role,
+		})
+	}
+	return rows, nil
+}
@@ -14,7 +14,7 @@ import (
 // PUT /api/v1/admin/user/id/:id/select?select=field1,field2,field3
 func (uc *adminUserController) UpdateUserByIDWithSelect(c *gin.Context) {
 	userId := c.Param("id")
-	userIdUint, err := utils.ParseUint(userId, uc.logger)
+	userIdUint, err := utils.ParseUint(userId, uc.log(c))
 	if err != nil {
 		utils.JSONError(c, http.StatusBadRequest, "ValidationError", err.Error())
 		return
@@ -46,7 +46,7 @@ func (uc *adminUserController) UpdateUserByIDWithSelect(c *gin.Context) {
 			}
 			updates["avatar"] = avatarURL
 
-			updatedUser, err := uc.svc.UpdateUserByIDWithSelect(uint(userIdUint), updates, nil, selectFields)
+			updatedUser, err := uc.svc.WithContext(c.Request.Context()).UpdateUserByIDWithSelect(uint(userIdUint), updates, nil, selectFields)
 			if err != nil {
 				utils.JSONError(c, http.StatusInternalServerError, "ServerError", "Failed to update user")
 				return
@@ -65,9 +65,9 @@ func (uc *adminUserController) UpdateUserByIDWithSelect(c *gin.Context) {
 		updates["email"] = email
 	}
 
-	updatedUser, err := uc.svc.UpdateUserByIDWithSelect(uint(userIdUint), updates, imageFile, selectFields)
+	updatedUser, err := uc.svc.WithContext(c.Request.Context()).UpdateUserByIDWithSelect(uint(userIdUint), updates, imageFile, selectFields)
 	if err != nil {
-		uc.logger.Error("failed to update user", zap.Error(err))
+		uc.log(c).Error("failed to update user", zap.Error(err))
 		utils.JSONError(c, http.StatusInternalServerError, "ServerError", "Failed to update user")
 		return
 	}
@@ -25,12 +25,12 @@ import (
 //	@Router			/user/{id} [delete]
 func (uc *userController) DeleteUserByID(c *gin.Context) {
 	userId := c.Param("id")
-	userIdUint, err := utils.ParseUint(userId, uc.logger)
+	userIdUint, err := utils.ParseUint(userId, uc.log(c))
 	if err != nil {
 		utils.JSONError(c, http.StatusBadRequest, "ValidationError", err.Error())
 		return
 	}
-	ownership, err := uc.svc.CheckUserOwnership(uint(userIdUint), c.GetUint("user_id"))
+	ownership, err := uc.svc.WithContext(c.Request.Context()).CheckUserOwnership(uint(userIdUint), c.GetUint("user_id"))
 	if err != nil {
 		utils.JSONError(c, http.StatusInternalServerError, "ServerError", "Failed to check user ownership")
 		return
@@ -39,9 +39,9 @@ func (uc *userController) DeleteUserByID(c *gin.Context) {
 		utils.JSONError(c, http.StatusForbidden, "Forbidden", "You are not the owner of this user")
 		return
 	}
-	if err := uc.svc.DeleteUserByID(uint(userIdUint)); err != nil {
+	if err := uc.svc.WithContext(c.Request.Context()).DeleteUserByID(uint(userIdUint)); err != nil {
 		if err == gorm.ErrRecordNotFound {
-			uc.logger.Error("user not found", zap.String("user_id", userId))
+			uc.log(c).Error("user not found", zap.String("user_id", userId))
 			utils.JSONError(c, http.StatusNotFound, "NotFound", "User not found")
 			return
 		}
@@ -49,5 +49,5 @@ func (uc *userController) DeleteUserByID(c *gin.Context) {
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "User deleted successfully"})
-	uc.logger.Info("user deleted successfully", zap.String("user_id", userId))
+	uc.log(c).Info("user deleted successfully", zap.String("user_id", userId))
 }
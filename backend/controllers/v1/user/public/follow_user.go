@@ -2,8 +2,10 @@ package public_user_controller
 
 import (
 	publicuserdto "flower-backend/dto/public"
+	user_services "flower-backend/services/v1/user"
 	"flower-backend/utils"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -28,19 +30,23 @@ import (
 func (uc *userController) FollowUser(c *gin.Context) {
 	followerID := c.Param("follower_id")
 	followingID := c.Param("following_id")
-	followerIDUint, err := utils.ParseUint(followerID, uc.logger)
+	followerIDUint, err := utils.ParseUint(followerID, uc.log(c))
 	if err != nil {
 		utils.JSONError(c, http.StatusBadRequest, "ValidationError", err.Error())
 		return
 	}
-	followingIDUint, err := utils.ParseUint(followingID, uc.logger)
+	followingIDUint, err := utils.ParseUint(followingID, uc.log(c))
 	if err != nil {
 		utils.JSONError(c, http.StatusBadRequest, "ValidationError", err.Error())
 		return
 	}
-	if err := uc.svc.FollowUser(uint(followerIDUint), uint(followingIDUint)); err != nil {
+	if err := uc.svc.WithContext(c.Request.Context()).FollowUser(uint(followerIDUint), uint(followingIDUint)); err != nil {
+		if err == user_services.ErrCannotFollowSelf {
+			utils.JSONError(c, http.StatusBadRequest, "ValidationError", "You cannot follow yourself")
+			return
+		}
 		if err == gorm.ErrRecordNotFound {
-			uc.logger.Error("user not found", zap.String("follower_id", followerID), zap.String("following_id", followingID))
+			uc.log(c).Error("user not found", zap.String("follower_id", followerID), zap.String("following_id", followingID))
 			utils.JSONError(c, http.StatusNotFound, "NotFound", "User not found")
 			return
 		}
@@ -48,7 +54,7 @@ func (uc *userController) FollowUser(c *gin.Context) {
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "User followed successfully"})
-	uc.logger.Info("user followed successfully", zap.String("follower_id", followerID), zap.String("following_id", followingID))
+	uc.log(c).Info("user followed successfully", zap.String("follower_id", followerID), zap.String("following_id", followingID))
 }
 
 // UnfollowUser godoc
@@ -69,19 +75,19 @@ func (uc *userController) FollowUser(c *gin.Context) {
 func (uc *userController) UnfollowUser(c *gin.Context) {
 	followerID := c.Param("follower_id")
 	followingID := c.Param("following_id")
-	followerIDUint, err := utils.ParseUint(followerID, uc.logger)
+	followerIDUint, err := utils.ParseUint(followerID, uc.log(c))
 	if err != nil {
 		utils.JSONError(c, http.StatusBadRequest, "ValidationError", err.Error())
 		return
 	}
-	followingIDUint, err := utils.ParseUint(followingID, uc.logger)
+	followingIDUint, err := utils.ParseUint(followingID, uc.log(c))
 	if err != nil {
 		utils.JSONError(c, http.StatusBadRequest, "ValidationError", err.Error())
 		return
 	}
-	if err := uc.svc.UnfollowUser(uint(followerIDUint), uint(followingIDUint)); err != nil {
+	if err := uc.svc.WithContext(c.Request.Context()).UnfollowUser(uint(followerIDUint), uint(followingIDUint)); err != nil {
 		if err == gorm.ErrRecordNotFound {
-			uc.logger.Error("user not found", zap.String("follower_id", followerID), zap.String("following_id", followingID))
+			uc.log(c).Error("user not found", zap.String("follower_id", followerID), zap.String("following_id", followingID))
 			utils.JSONError(c, http.StatusNotFound, "NotFound", "User not found")
 			return
 		}
@@ -89,7 +95,7 @@ func (uc *userController) UnfollowUser(c *gin.Context) {
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "User unfollowed successfully"})
-	uc.logger.Info("user unfollowed successfully", zap.String("follower_id", followerID), zap.String("following_id", followingID))
+	uc.log(c).Info("user unfollowed successfully", zap.String("follower_id", followerID), zap.String("following_id", followingID))
 }
 
 // GetUserFollowers godoc
@@ -106,18 +112,24 @@ func (uc *userController) UnfollowUser(c *gin.Context) {
 //	@Router			/user/followers/{user_id} [get]
 func (uc *userController) GetUserFollowers(c *gin.Context) {
 	userID := c.Param("user_id")
-	userIDUint, err := utils.ParseUint(userID, uc.logger)
+	userIDUint, err := utils.ParseUint(userID, uc.log(c))
 	if err != nil {
 		utils.JSONError(c, http.StatusBadRequest, "ValidationError", err.Error())
 		return
 	}
-	followers, err := uc.svc.GetUserFollowers(uint(userIDUint))
+	followers, err := uc.svc.WithContext(c.Request.Context()).GetUserFollowers(uint(userIDUint))
 	if err != nil {
 		utils.JSONError(c, http.StatusInternalServerError, "ServerError", "Internal server error")
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"followers": publicuserdto.ToPublicUsers(followers)})
-	uc.logger.Info("user followers fetched successfully", zap.String("user_id", userID))
+	followersDTO := publicuserdto.ToPublicUsers(followers)
+	uc.viewerForUsers(c, followers).ApplyToUsers(followersDTO)
+	response := gin.H{"followers": followersDTO}
+	if utils.NotModified(c, utils.WeakETag(response), time.Time{}) {
+		return
+	}
+	c.JSON(http.StatusOK, response)
+	uc.log(c).Info("user followers fetched successfully", zap.String("user_id", userID))
 }
 
 // GetUserFollowing godoc
@@ -134,18 +146,24 @@ func (uc *userController) GetUserFollowers(c *gin.Context) {
 //	@Router			/user/following/{user_id} [get]
 func (uc *userController) GetUserFollowing(c *gin.Context) {
 	userID := c.Param("user_id")
-	userIDUint, err := utils.ParseUint(userID, uc.logger)
+	userIDUint, err := utils.ParseUint(userID, uc.log(c))
 	if err != nil {
 		utils.JSONError(c, http.StatusBadRequest, "ValidationError", err.Error())
 		return
 	}
-	following, err := uc.svc.GetUserFollowing(uint(userIDUint))
+	following, err := uc.svc.WithContext(c.Request.Context()).GetUserFollowing(uint(userIDUint))
 	if err != nil {
 		utils.JSONError(c, http.StatusInternalServerError, "ServerError", "Internal server error")
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"following": publicuserdto.ToPublicUsers(following)})
-	uc.logger.Info("user following fetched successfully", zap.String("user_id", userID))
+	followingDTO := publicuserdto.ToPublicUsers(following)
+	uc.viewerForUsers(c, following).ApplyToUsers(followingDTO)
+	response := gin.H{"following": followingDTO}
+	if utils.NotModified(c, utils.WeakETag(response), time.Time{}) {
+		return
+	}
+	c.JSON(http.StatusOK, response)
+	uc.log(c).Info("user following fetched successfully", zap.String("user_id", userID))
 }
 
 // GetUserFollowersCount godoc
@@ -162,18 +180,22 @@ func (uc *userController) GetUserFollowing(c *gin.Context) {
 //	@Router			/user/followers-count/{user_id} [get]
 func (uc *userController) GetUserFollowersCount(c *gin.Context) {
 	userID := c.Param("user_id")
-	userIDUint, err := utils.ParseUint(userID, uc.logger)
+	userIDUint, err := utils.ParseUint(userID, uc.log(c))
 	if err != nil {
 		utils.JSONError(c, http.StatusBadRequest, "ValidationError", err.Error())
 		return
 	}
-	count, err := uc.svc.GetUserFollowersCount(uint(userIDUint))
+	count, err := uc.svc.WithContext(c.Request.Context()).GetUserFollowersCount(uint(userIDUint))
 	if err != nil {
 		utils.JSONError(c, http.StatusInternalServerError, "ServerError", "Internal server error")
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"followers_count": count})
-	uc.logger.Info("user followers count fetched successfully", zap.String("user_id", userID))
+	response := gin.H{"followers_count": count}
+	if utils.NotModified(c, utils.ETag(response), time.Time{}) {
+		return
+	}
+	c.JSON(http.StatusOK, response)
+	uc.log(c).Info("user followers count fetched successfully", zap.String("user_id", userID))
 }
 
 // GetUserFollowingCount godoc
@@ -190,18 +212,22 @@ func (uc *userController) GetUserFollowersCount(c *gin.Context) {
 //	@Router			/user/following-count/{user_id} [get]
 func (uc *userController) GetUserFollowingCount(c *gin.Context) {
 	userID := c.Param("user_id")
-	userIDUint, err := utils.ParseUint(userID, uc.logger)
+	userIDUint, err := utils.ParseUint(userID, uc.log(c))
 	if err != nil {
 		utils.JSONError(c, http.StatusBadRequest, "ValidationError", err.Error())
 		return
 	}
-	count, err := uc.svc.GetUserFollowingCount(uint(userIDUint))
+	count, err := uc.svc.WithContext(c.Request.Context()).GetUserFollowingCount(uint(userIDUint))
 	if err != nil {
 		utils.JSONError(c, http.StatusInternalServerError, "ServerError", "Internal server error")
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"following_count": count})
-	uc.logger.Info("user following count fetched successfully", zap.String("user_id", userID))
+	response := gin.H{"following_count": count}
+	if utils.NotModified(c, utils.ETag(response), time.Time{}) {
+		return
+	}
+	c.JSON(http.StatusOK, response)
+	uc.log(c).Info("user following count fetched successfully", zap.String("user_id", userID))
 }
 
 // GetUserFollowingPosts godoc
@@ -220,28 +246,34 @@ func (uc *userController) GetUserFollowingCount(c *gin.Context) {
 //	@Router			/user/following-posts/{user_id} [get]
 func (uc *userController) GetUserFollowingPosts(c *gin.Context) {
 	userID := c.Param("user_id")
-	userIDUint, err := utils.ParseUint(userID, uc.logger)
+	userIDUint, err := utils.ParseUint(userID, uc.log(c))
 	if err != nil {
 		utils.JSONError(c, http.StatusBadRequest, "ValidationError", err.Error())
 		return
 	}
 	page := c.Query("page")
 	limit := c.Query("limit")
-	pageUint, err := utils.ParseUint(page, uc.logger)
+	pageUint, err := utils.ParseUint(page, uc.log(c))
 	if err != nil {
 		utils.JSONError(c, http.StatusBadRequest, "ValidationError", err.Error())
 		return
 	}
-	limitUint, err := utils.ParseUint(limit, uc.logger)
+	limitUint, err := utils.ParseUint(limit, uc.log(c))
 	if err != nil {
 		utils.JSONError(c, http.StatusBadRequest, "ValidationError", err.Error())
 		return
 	}
-	posts, total, err := uc.svc.GetUserFollowingPosts(uint(userIDUint), int(pageUint), int(limitUint))
+	posts, total, err := uc.svc.WithContext(c.Request.Context()).GetUserFollowingPosts(uint(userIDUint), int(pageUint), int(limitUint))
 	if err != nil {
 		utils.JSONError(c, http.StatusInternalServerError, "ServerError", "Internal server error")
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"posts": publicuserdto.ToPublicPosts(posts), "total": total})
-	uc.logger.Info("user following posts fetched successfully", zap.String("user_id", userID))
+	postsDTO := publicuserdto.ToPublicPosts(posts)
+	uc.viewerForPosts(c, posts).ApplyToPosts(postsDTO)
+	response := gin.H{"posts": postsDTO, "total": total}
+	if utils.NotModified(c, utils.WeakETag(response), time.Time{}) {
+		return
+	}
+	c.JSON(http.StatusOK, response)
+	uc.log(c).Info("user following posts fetched successfully", zap.String("user_id", userID))
 }
@@ -2,9 +2,11 @@ package public_user_controller
 
 import (
 	publicuserdto "flower-backend/dto/public"
+	"flower-backend/models"
 	"flower-backend/utils"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -26,15 +28,15 @@ import (
 //	@Router			/user/{id} [get]
 func (uc *userController) GetUserByID(c *gin.Context) {
 	userId := c.Param("id")
-	userIdUint, err := utils.ParseUint(userId, uc.logger)
+	userIdUint, err := utils.ParseUint(userId, uc.log(c))
 	if err != nil {
 		utils.JSONError(c, http.StatusBadRequest, "ValidationError", err.Error())
 		return
 	}
-	user, err := uc.svc.GetUserByID(uint(userIdUint))
+	user, err := uc.svc.WithContext(c.Request.Context()).GetUserByID(uint(userIdUint))
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			uc.logger.Error("user not found", zap.String("user_id", userId))
+			uc.log(c).Error("user not found", zap.String("user_id", userId))
 			utils.JSONError(c, http.StatusNotFound, "NotFound", "User not found")
 			return
 		}
@@ -46,17 +48,24 @@ func (uc *userController) GetUserByID(c *gin.Context) {
 	authenticatedUserID, exists := c.Get("user_id")
 	isOwnProfile := exists && authenticatedUserID.(uint) == uint(userIdUint)
 
+	var response gin.H
 	if isOwnProfile {
 		// Return full user data for own profile
-		c.JSON(http.StatusOK, gin.H{
+		response = gin.H{
 			"user": publicuserdto.ToAuthOwnerUser(user),
-		})
+		}
 	} else {
 		// Return public user data for other profiles
-		c.JSON(http.StatusOK, gin.H{"user": publicuserdto.ToPublicUser(user)})
+		userDTO := publicuserdto.ToPublicUser(user)
+		uc.viewerForUsers(c, []models.User{*user}).ApplyToUser(&userDTO)
+		response = gin.H{"user": userDTO}
+	}
+	if utils.NotModified(c, utils.ETag(response), time.Time{}) {
+		return
 	}
+	c.JSON(http.StatusOK, response)
 
-	uc.logger.Info("user fetched successfully", zap.Uint("user_id", uint(userIdUint)))
+	uc.log(c).Info("user fetched successfully", zap.Uint("user_id", uint(userIdUint)))
 }
 
 // GetUserByEmail godoc
@@ -78,18 +87,24 @@ func (uc *userController) GetUserByEmail(c *gin.Context) {
 		utils.JSONError(c, http.StatusBadRequest, "ValidationError", "Invalid email")
 		return
 	}
-	user, err := uc.svc.GetUserByEmail(email)
+	user, err := uc.svc.WithContext(c.Request.Context()).GetUserByEmail(email)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			uc.logger.Error("user not found", zap.String("email", email))
+			uc.log(c).Error("user not found", zap.String("email", email))
 			utils.JSONError(c, http.StatusNotFound, "NotFound", "User not found")
 			return
 		}
 		utils.JSONError(c, http.StatusInternalServerError, "ServerError", "Internal server error")
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"user": publicuserdto.ToPublicUser(user)})
-	uc.logger.Info("user fetched successfully", zap.String("email", email))
+	userDTO := publicuserdto.ToPublicUser(user)
+	uc.viewerForUsers(c, []models.User{*user}).ApplyToUser(&userDTO)
+	response := gin.H{"user": userDTO}
+	if utils.NotModified(c, utils.ETag(response), time.Time{}) {
+		return
+	}
+	c.JSON(http.StatusOK, response)
+	uc.log(c).Info("user fetched successfully", zap.String("email", email))
 }
 
 // GetUserByUsername godoc
@@ -111,18 +126,24 @@ func (uc *userController) GetUserByUsername(c *gin.Context) {
 		utils.JSONError(c, http.StatusBadRequest, "ValidationError", "Invalid username")
 		return
 	}
-	user, err := uc.svc.GetUserByUsername(username)
+	user, err := uc.svc.WithContext(c.Request.Context()).GetUserByUsername(username)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			uc.logger.Error("user not found", zap.String("username", username))
+			uc.log(c).Error("user not found", zap.String("username", username))
 			utils.JSONError(c, http.StatusNotFound, "NotFound", "User not found")
 			return
 		}
 		utils.JSONError(c, http.StatusInternalServerError, "ServerError", "Internal server error")
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"user": publicuserdto.ToPublicUser(user)})
-	uc.logger.Info("user fetched successfully", zap.String("username", username))
+	userDTO := publicuserdto.ToPublicUser(user)
+	uc.viewerForUsers(c, []models.User{*user}).ApplyToUser(&userDTO)
+	response := gin.H{"user": userDTO}
+	if utils.NotModified(c, utils.ETag(response), time.Time{}) {
+		return
+	}
+	c.JSON(http.StatusOK, response)
+	uc.log(c).Info("user fetched successfully", zap.String("username", username))
 }
 
 // GetUserAll godoc
@@ -136,13 +157,19 @@ func (uc *userController) GetUserByUsername(c *gin.Context) {
 //	@Securuty		BearerAuth
 //	@Router			/user/all [get]
 func (uc *userController) GetUserAll(c *gin.Context) {
-	users, err := uc.svc.GetUserAll()
+	users, err := uc.svc.WithContext(c.Request.Context()).GetUserAll()
 	if err != nil {
 		utils.JSONError(c, http.StatusInternalServerError, "ServerError", "Internal server error")
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"users": publicuserdto.ToPublicUsers(users)})
-	uc.logger.Info("all users fetched successfully", zap.Int("users_count", len(users)))
+	usersDTO := publicuserdto.ToPublicUsers(users)
+	uc.viewerForUsers(c, users).ApplyToUsers(usersDTO)
+	response := gin.H{"users": usersDTO}
+	if utils.NotModified(c, utils.WeakETag(response), time.Time{}) {
+		return
+	}
+	c.JSON(http.StatusOK, response)
+	uc.log(c).Info("all users fetched successfully", zap.Int("users_count", len(users)))
 }
 
 // GetUserByIDWithSelect godoc
@@ -161,7 +188,7 @@ func (uc *userController) GetUserAll(c *gin.Context) {
 //	@Router			/user/id/{id}/select [get]
 func (uc *userController) GetUserByIDWithSelect(c *gin.Context) {
 	userId := c.Param("id")
-	userIdUint, err := utils.ParseUint(userId, uc.logger)
+	userIdUint, err := utils.ParseUint(userId, uc.log(c))
 	if err != nil {
 		utils.JSONError(c, http.StatusBadRequest, "ValidationError", err.Error())
 		return
@@ -172,16 +199,20 @@ func (uc *userController) GetUserByIDWithSelect(c *gin.Context) {
 		return
 	}
 	selectFields := publicuserdto.EnsurePublicUserSelectFields(strings.Split(selectFieldsString, ","))
-	user, err := uc.svc.GetUserByIDWithSelect(uint(userIdUint), selectFields)
+	user, err := uc.svc.WithContext(c.Request.Context()).GetUserByIDWithSelect(uint(userIdUint), selectFields)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			uc.logger.Error("user not found", zap.Uint("user_id", uint(userIdUint)))
+			uc.log(c).Error("user not found", zap.Uint("user_id", uint(userIdUint)))
 			utils.JSONError(c, http.StatusNotFound, "NotFound", "User not found")
 			return
 		}
 		utils.JSONError(c, http.StatusInternalServerError, "ServerError", "Internal server error")
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"user": publicuserdto.ToPublicUser(user)})
-	uc.logger.Info("user fetched successfully", zap.Uint("user_id", uint(userIdUint)))
+	response := gin.H{"user": publicuserdto.ToPublicUser(user)}
+	if utils.NotModified(c, utils.ETag(response), time.Time{}) {
+		return
+	}
+	c.JSON(http.StatusOK, response)
+	uc.log(c).Info("user fetched successfully", zap.Uint("user_id", uint(userIdUint)))
 }
@@ -0,0 +1,40 @@
+package public_user_controller
+
+import (
+	"flower-backend/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetNotifications godoc
+//
+//	@Summary		Get notifications
+//	@Description	List the caller's notifications, newest first, such as posts published by users they follow
+//	@Tags			users
+//	@Produce		json
+//	@Param			page	query		int	false	"Page number"		default(1)
+//	@Param			limit	query		int	false	"Items per page"	default(20)
+//	@Success		200		{object}	map[string]interface{}
+//	@Failure		400		{object}	map[string]interface{}
+//	@Security		BearerAuth
+//	@Router			/user/notifications [get]
+func (uc *userController) GetNotifications(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		utils.JSONError(c, http.StatusBadRequest, "ValidationError", "Invalid page")
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		utils.JSONError(c, http.StatusBadRequest, "ValidationError", "Invalid limit")
+		return
+	}
+	notifications, total, err := uc.svc.WithContext(c.Request.Context()).GetNotifications(c.GetUint("user_id"), page, limit)
+	if err != nil {
+		utils.JSONError(c, http.StatusInternalServerError, "ServerError", "Internal server error")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"notifications": notifications, "total": total})
+}
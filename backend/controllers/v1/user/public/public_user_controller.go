@@ -1,12 +1,14 @@
 package public_user_controller
 
 import (
+	"flower-backend/app"
 	"flower-backend/config"
+	"flower-backend/log"
 	user_services "flower-backend/services/v1/user"
+	viewer_services "flower-backend/services/v1/viewer"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
-	"gorm.io/gorm"
 )
 
 type UserController interface {
@@ -24,15 +26,24 @@ type UserController interface {
 	GetUserFollowersCount(c *gin.Context)
 	GetUserFollowingCount(c *gin.Context)
 	GetUserFollowingPosts(c *gin.Context)
+	GetNotifications(c *gin.Context)
 }
 
 type userController struct {
 	svc    user_services.UserService
+	viewer viewer_services.ViewerService
 	cfg    *config.Config
 	logger *zap.SugaredLogger
 }
 
-func NewUserController(db *gorm.DB, cfg *config.Config, logger *zap.SugaredLogger) UserController {
-	svc := user_services.NewUserService(db, cfg, logger)
-	return &userController{svc: svc, logger: logger, cfg: cfg}
+func NewUserController(a *app.App) UserController {
+	logger := a.Logger.Sugar()
+	svc := user_services.NewUserService(a.DB, a.Config, a.Users, logger, a.Storage)
+	viewer := viewer_services.NewViewerService(a.Config, a.Posts, a.Users, logger)
+	return &userController{svc: svc, viewer: viewer, logger: logger, cfg: a.Config}
+}
+
+// log returns the request's logger, which carries its request and user ids.
+func (uc *userController) log(c *gin.Context) *zap.SugaredLogger {
+	return log.SugarFromContext(c.Request.Context(), uc.logger)
 }
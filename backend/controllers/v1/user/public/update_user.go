@@ -32,13 +32,13 @@ import (
 func (uc *userController) UpdateUserByIDWithSelect(c *gin.Context) {
 
 	userId := c.Param("id")
-	userIdUint, err := utils.ParseUint(userId, uc.logger)
+	userIdUint, err := utils.ParseUint(userId, uc.log(c))
 	if err != nil {
 		utils.JSONError(c, http.StatusBadRequest, "ValidationError", err.Error())
 		return
 	}
 
-	ownership, err := uc.svc.CheckUserOwnership(uint(userIdUint), c.GetUint("user_id"))
+	ownership, err := uc.svc.WithContext(c.Request.Context()).CheckUserOwnership(uint(userIdUint), c.GetUint("user_id"))
 	if err != nil {
 		utils.JSONError(c, http.StatusInternalServerError, "ServerError", "Failed to check user ownership")
 		return
@@ -67,11 +67,11 @@ func (uc *userController) UpdateUserByIDWithSelect(c *gin.Context) {
 		updates["email"] = email
 	}
 
-	updatedUser, err := uc.svc.UpdateUserByIDWithSelect(uint(userIdUint), updates, imageFile, selectFields)
+	updatedUser, err := uc.svc.WithContext(c.Request.Context()).UpdateUserByIDWithSelect(uint(userIdUint), updates, imageFile, selectFields)
 	if err != nil {
 		utils.JSONError(c, http.StatusInternalServerError, "ServerError", "Failed to update user")
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"user": public_user_dto.ToAuthOwnerUser(updatedUser)})
-	uc.logger.Info("user updated successfully", zap.Uint("user_id", uint(userIdUint)))
+	uc.log(c).Info("user updated successfully", zap.Uint("user_id", uint(userIdUint)))
 }
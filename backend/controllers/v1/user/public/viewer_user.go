@@ -0,0 +1,31 @@
+package public_user_controller
+
+import (
+	publicuserdto "flower-backend/dto/public"
+	"flower-backend/models"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// viewerForUsers loads the viewer flags for a page of users. Anonymous
+// requests and lookup failures yield nil, which leaves the flags out; a
+// failure is logged, as the page itself is still served.
+func (uc *userController) viewerForUsers(c *gin.Context, users []models.User) *publicuserdto.ViewerState {
+	state, err := uc.viewer.WithContext(c.Request.Context()).ForUsers(c.GetUint("user_id"), users)
+	if err != nil {
+		uc.log(c).Warn("failed to load viewer flags for users", zap.Error(err))
+		return nil
+	}
+	return state
+}
+
+// viewerForPosts is viewerForUsers for a page of posts.
+func (uc *userController) viewerForPosts(c *gin.Context, posts []models.Post) *publicuserdto.ViewerState {
+	state, err := uc.viewer.WithContext(c.Request.Context()).ForPosts(c.GetUint("user_id"), posts)
+	if err != nil {
+		uc.log(c).Warn("failed to load viewer flags for posts", zap.Error(err))
+		return nil
+	}
+	return state
+}
@@ -3,20 +3,51 @@ package database
 import (
 	"flower-backend/config"
 	"fmt"
-	"os"
 
+	"github.com/glebarez/sqlite"
 	"go.uber.org/zap"
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	gormlogger "gorm.io/gorm/logger"
 )
 
-var DB *gorm.DB
+const (
+	DriverMySQL    = "mysql"
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
 
-// ConnectDB connects to the database with connection pooling
-func ConnectDB(cfg *config.Config, logger *zap.Logger) {
+// openDialector builds the GORM dialector for the configured DB_DRIVER
+func openDialector(cfg *config.Config) (gorm.Dialector, error) {
+	switch cfg.DBDriver {
+	case DriverMySQL, "":
+		dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local", cfg.DBUser, cfg.DBPassword, cfg.DBHost, cfg.DBPort, cfg.DBName)
+		return mysql.Open(dsn), nil
+	case DriverPostgres:
+		dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s", cfg.DBHost, cfg.DBPort, cfg.DBUser, cfg.DBPassword, cfg.DBName, cfg.DBSSLMode)
+		return postgres.Open(dsn), nil
+	case DriverSQLite:
+		return sqlite.Open(SQLiteDSN(cfg.DBPath)), nil
+	default:
+		return nil, fmt.Errorf("unsupported DB_DRIVER %q (want mysql, postgres or sqlite)", cfg.DBDriver)
+	}
+}
+
+// SQLiteDSN enables foreign keys, which SQLite leaves off by default, waits
+// on a busy database instead of failing straight away, and takes the write
+// lock when a transaction begins so concurrent transactions queue rather
+// than fail when upgrading from a read lock.
+func SQLiteDSN(path string) string {
+	return path + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_txlock=immediate"
+}
 
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local", cfg.DBUser, cfg.DBPassword, cfg.DBHost, cfg.DBPort, cfg.DBName)
+// ConnectDB connects to the database with connection pooling
+func ConnectDB(cfg *config.Config, logger *zap.Logger) (*gorm.DB, error) {
+	dialector, err := openDialector(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("configure database driver: %w", err)
+	}
 	// Configure GORM with custom logger settings
 	gormConfig := &gorm.Config{}
 
@@ -27,17 +58,15 @@ func ConnectDB(cfg *config.Config, logger *zap.Logger) {
 		gormConfig.Logger = gormlogger.Default.LogMode(gormlogger.Warn)
 	}
 
-	db, err := gorm.Open(mysql.Open(dsn), gormConfig)
+	db, err := gorm.Open(dialector, gormConfig)
 	if err != nil {
-		logger.Error("failed to connect to database", zap.Error(err))
-		os.Exit(1)
+		return nil, fmt.Errorf("connect to database: %w", err)
 	}
 
 	// Get the underlying sql.DB to configure connection pooling
 	sqlDB, err := db.DB()
 	if err != nil {
-		logger.Error("failed to get database instance", zap.Error(err))
-		os.Exit(1)
+		return nil, fmt.Errorf("get database instance: %w", err)
 	}
 
 	// Configure connection pool settings
@@ -55,25 +84,24 @@ func ConnectDB(cfg *config.Config, logger *zap.Logger) {
 
 	// Verify the connection
 	if err := sqlDB.Ping(); err != nil {
-		logger.Error("failed to ping database", zap.Error(err))
-		os.Exit(1)
+		return nil, fmt.Errorf("ping database: %w", err)
 	}
 
-	DB = db
-
 	// Log connection pool configuration
 	logger.Info("database connection pool configured",
+		zap.String("driver", cfg.DBDriver),
 		zap.Int("max_open_connections", cfg.DBMaxOpenConns),
 		zap.Int("max_idle_connections", cfg.DBMaxIdleConns),
 		zap.Duration("connection_max_lifetime", cfg.DBConnMaxLifetime),
 		zap.Duration("connection_max_idle_time", cfg.DBConnMaxIdleTime),
 	)
+	return db, nil
 }
 
 // DisconnectDB closes the database connection gracefully
-func DisconnectDB(logger *zap.Logger) error {
-	if DB != nil {
-		sqlDB, err := DB.DB()
+func DisconnectDB(db *gorm.DB, logger *zap.Logger) error {
+	if db != nil {
+		sqlDB, err := db.DB()
 		if err != nil {
 			return err
 		}
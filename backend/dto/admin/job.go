@@ -0,0 +1,57 @@
+package admin_dto
+
+import (
+	"encoding/json"
+	"flower-backend/models"
+	"time"
+)
+
+type JobAdminDTO struct {
+	ID          uint            `json:"id"`
+	Type        string          `json:"type"`
+	Payload     json.RawMessage `json:"payload"`
+	Status      string          `json:"status"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"max_attempts"`
+	LastError   string          `json:"last_error,omitempty"`
+	RunAt       time.Time       `json:"run_at"`
+	LockedBy    string          `json:"locked_by,omitempty"`
+	FinishedAt  *time.Time      `json:"finished_at,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+func ToJobAdminDTO(job *models.Job) JobAdminDTO {
+	if job == nil {
+		return JobAdminDTO{}
+	}
+
+	// payloads are stored as JSON; show them as objects rather than strings
+	payload := json.RawMessage(job.Payload)
+	if !json.Valid(payload) {
+		payload, _ = json.Marshal(job.Payload)
+	}
+
+	return JobAdminDTO{
+		ID:          job.ID,
+		Type:        job.Type,
+		Payload:     payload,
+		Status:      job.Status,
+		Attempts:    job.Attempts,
+		MaxAttempts: job.MaxAttempts,
+		LastError:   job.LastError,
+		RunAt:       job.RunAt,
+		LockedBy:    job.LockedBy,
+		FinishedAt:  job.FinishedAt,
+		CreatedAt:   job.CreatedAt,
+		UpdatedAt:   job.UpdatedAt,
+	}
+}
+
+func ToJobAdminDTOs(jobs []models.Job) []JobAdminDTO {
+	result := make([]JobAdminDTO, 0, len(jobs))
+	for i := range jobs {
+		result = append(result, ToJobAdminDTO(&jobs[i]))
+	}
+	return result
+}
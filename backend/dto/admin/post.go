@@ -0,0 +1,49 @@
+package admin_dto
+
+import (
+	"flower-backend/models"
+	"flower-backend/utils"
+	"time"
+)
+
+type PostAdminDTO struct {
+	ID             uint      `json:"id"`
+	Title          string    `json:"title"`
+	Content        string    `json:"content"`
+	ImageURL       string    `json:"image_url"`
+	Hidden         bool      `json:"hidden"`
+	AuthorID       uint      `json:"author_id"`
+	AuthorUsername string    `json:"author_username"`
+	Likes          int64     `json:"likes_count"`
+	Reports        int       `json:"reports_count"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+func ToPostAdminDTO(post *models.Post) PostAdminDTO {
+	if post == nil {
+		return PostAdminDTO{}
+	}
+
+	return PostAdminDTO{
+		ID:             post.ID,
+		Title:          utils.SanitizeString(post.Title),
+		Content:        utils.SanitizeHTML(post.Content),
+		ImageURL:       utils.SanitizeURL(post.ImageURL),
+		Hidden:         post.Hidden,
+		AuthorID:       post.UserID,
+		AuthorUsername: utils.SanitizeString(post.User.Username),
+		Likes:          post.LikesCount,
+		Reports:        len(post.Reports),
+		CreatedAt:      post.CreatedAt,
+		UpdatedAt:      post.UpdatedAt,
+	}
+}
+
+func ToPostAdminDTOs(posts []models.Post) []PostAdminDTO {
+	result := make([]PostAdminDTO, 0, len(posts))
+	for i := range posts {
+		result = append(result, ToPostAdminDTO(&posts[i]))
+	}
+	return result
+}
@@ -0,0 +1,49 @@
+package admin_dto
+
+import (
+	public_dto "flower-backend/dto/public"
+	"time"
+)
+
+// MetricTotal is an all-time or windowed total split by auth provider.
+type MetricTotal struct {
+	Total      int64            `json:"total"`
+	ByProvider map[string]int64 `json:"by_provider"`
+}
+
+type StatsOverviewDTO struct {
+	Signups        MetricTotal `json:"signups"`
+	Posts          MetricTotal `json:"posts"`
+	Likes          MetricTotal `json:"likes"`
+	Follows        MetricTotal `json:"follows"`
+	ActiveUsers1d  MetricTotal `json:"active_users_1d"`
+	ActiveUsers7d  MetricTotal `json:"active_users_7d"`
+	ActiveUsers30d MetricTotal `json:"active_users_30d"`
+	GeneratedAt    time.Time   `json:"generated_at"`
+}
+
+type TimeSeriesPointDTO struct {
+	Bucket     string           `json:"bucket"`
+	Total      int64            `json:"total"`
+	ByProvider map[string]int64 `json:"by_provider"`
+}
+
+type TimeSeriesDTO struct {
+	Metric      string               `json:"metric"`
+	Interval    string               `json:"interval"`
+	From        string               `json:"from"`
+	To          string               `json:"to"`
+	Points      []TimeSeriesPointDTO `json:"points"`
+	GeneratedAt time.Time            `json:"generated_at"`
+}
+
+type TopPostDTO struct {
+	Post  public_dto.PublicPostDTO `json:"post"`
+	Likes int64                    `json:"likes_in_range"`
+}
+
+type TopCreatorDTO struct {
+	User  public_dto.PublicUserDTO `json:"user"`
+	Likes int64                    `json:"likes_in_range"`
+	Posts int64                    `json:"posts_in_range"`
+}
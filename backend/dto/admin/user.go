@@ -13,10 +13,12 @@ type UserAdminDTO struct {
 	Email     string    `json:"email"`
 	Avatar    string    `json:"avatar"`
 	Role      string    `json:"role"`
-	Posts     int       `json:"posts"`
+	Provider  string    `json:"provider"`
+	Suspended bool      `json:"suspended"`
+	Posts     int64     `json:"posts"`
 	Likes     int       `json:"likes"`
-	Followers int       `json:"followers"`
-	Following int       `json:"following"`
+	Followers int64     `json:"followers"`
+	Following int64     `json:"following"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
@@ -31,10 +33,12 @@ func ToUserAdminDTO(user *models.User) UserAdminDTO {
 		Email:     utils.SanitizeEmail(user.Email),
 		Avatar:    utils.SanitizeURL(user.Avatar),
 		Role:      utils.SanitizeString(user.Role),
-		Posts:     len(user.Posts),
+		Provider:  utils.SanitizeString(user.Provider),
+		Suspended: user.Suspended,
+		Posts:     user.PostsCount,
 		Likes:     len(user.Likes),
-		Followers: len(user.Followers),
-		Following: len(user.Following),
+		Followers: user.FollowersCount,
+		Following: user.FollowingCount,
 		CreatedAt: user.CreatedAt,
 	}
 }
@@ -58,6 +62,10 @@ func EnsureUserAdminSelectFields(fields []string) []string {
 		"likes":     {},
 		"followers": {},
 		"following": {},
+		// counter columns backing posts, followers and following
+		"posts_count":     {},
+		"followers_count": {},
+		"following_count": {},
 	}
 
 	normalized := make(map[string]struct{}, len(fields))
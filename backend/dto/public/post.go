@@ -7,14 +7,18 @@ import (
 )
 
 type PublicPostDTO struct {
-	ID        uint          `json:"id"`
-	Title     string        `json:"title"`
-	Content   string        `json:"content"`
-	ImageURL  string        `json:"image_url"`
-	CreatedAt time.Time     `json:"created_at"`
-	UpdatedAt time.Time     `json:"updated_at"`
-	Author    PublicUserDTO `json:"author"`
-	Likes     int           `json:"likes_count"`
+	ID           uint          `json:"id"`
+	Title        string        `json:"title"`
+	Content      string        `json:"content"`
+	ImageURL     string        `json:"image_url"`
+	ThumbnailURL string        `json:"thumbnail_url,omitempty"`
+	MediumURL    string        `json:"medium_url,omitempty"`
+	CreatedAt    time.Time     `json:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at"`
+	Author       PublicUserDTO `json:"author"`
+	Likes        int64         `json:"likes_count"`
+	Tags         []string      `json:"tags"`
+	LikedByMe    *bool         `json:"liked_by_me,omitempty"`
 }
 
 func ToPublicPost(post *models.Post) PublicPostDTO {
@@ -22,22 +26,27 @@ func ToPublicPost(post *models.Post) PublicPostDTO {
 		return PublicPostDTO{}
 	}
 
-	// Count likes - handle both preloaded and non-preloaded cases
-	likesCount := 0
-	if post.Likes != nil {
-		likesCount = len(post.Likes)
+	return PublicPostDTO{
+		ID:           post.ID,
+		Title:        utils.SanitizeString(post.Title),
+		Content:      utils.SanitizeHTML(post.Content),
+		ImageURL:     utils.SanitizeURL(post.ImageURL),
+		ThumbnailURL: utils.SanitizeURL(post.ThumbnailURL),
+		MediumURL:    utils.SanitizeURL(post.MediumURL),
+		CreatedAt:    post.CreatedAt,
+		UpdatedAt:    post.UpdatedAt,
+		Author:       ToPublicUser(&post.User),
+		Likes:        post.LikesCount,
+		Tags:         toTagNames(post.Tags),
 	}
+}
 
-	return PublicPostDTO{
-		ID:        post.ID,
-		Title:     utils.SanitizeString(post.Title),
-		Content:   utils.SanitizeHTML(post.Content),
-		ImageURL:  utils.SanitizeURL(post.ImageURL),
-		CreatedAt: post.CreatedAt,
-		UpdatedAt: post.UpdatedAt,
-		Author:    ToPublicUser(&post.User),
-		Likes:     likesCount,
+func toTagNames(tags []models.Tag) []string {
+	names := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		names = append(names, utils.SanitizeString(tag.Name))
 	}
+	return names
 }
 
 func ToPublicPosts(posts []models.Post) []PublicPostDTO {
@@ -47,3 +56,64 @@ func ToPublicPosts(posts []models.Post) []PublicPostDTO {
 	}
 	return result
 }
+
+// RankedPostDTO is a post together with the likes it received in the ranking window.
+type RankedPostDTO struct {
+	PublicPostDTO
+	WindowLikes int64 `json:"window_likes"`
+}
+
+func ToRankedPosts(posts []models.Post, windowLikes map[uint]int64) []RankedPostDTO {
+	result := make([]RankedPostDTO, 0, len(posts))
+	for i := range posts {
+		result = append(result, RankedPostDTO{
+			PublicPostDTO: ToPublicPost(&posts[i]),
+			WindowLikes:   windowLikes[posts[i].ID],
+		})
+	}
+	return result
+}
+
+// PostStatusDTO tells a post's author where its image upload stands.
+type PostStatusDTO struct {
+	ID           uint   `json:"id"`
+	Status       string `json:"status"`
+	ImageURL     string `json:"image_url,omitempty"`
+	ThumbnailURL string `json:"thumbnail_url,omitempty"`
+	MediumURL    string `json:"medium_url,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+func ToPostStatus(post *models.Post) PostStatusDTO {
+	return PostStatusDTO{
+		ID:           post.ID,
+		Status:       post.Status,
+		ImageURL:     utils.SanitizeURL(post.ImageURL),
+		ThumbnailURL: utils.SanitizeURL(post.ThumbnailURL),
+		MediumURL:    utils.SanitizeURL(post.MediumURL),
+		Error:        post.UploadError,
+	}
+}
+
+// DraftPostDTO is a draft or scheduled post as its author sees it.
+type DraftPostDTO struct {
+	PublicPostDTO
+	Status    string     `json:"status"`
+	PublishAt *time.Time `json:"publish_at,omitempty"`
+}
+
+func ToDraftPost(post *models.Post) DraftPostDTO {
+	return DraftPostDTO{
+		PublicPostDTO: ToPublicPost(post),
+		Status:        post.Status,
+		PublishAt:     post.PublishAt,
+	}
+}
+
+func ToDraftPosts(posts []models.Post) []DraftPostDTO {
+	result := make([]DraftPostDTO, 0, len(posts))
+	for i := range posts {
+		result = append(result, ToDraftPost(&posts[i]))
+	}
+	return result
+}
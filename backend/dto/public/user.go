@@ -9,9 +9,14 @@ import (
 )
 
 type PublicUserDTO struct {
-	ID       uint   `json:"id"`
-	Username string `json:"username"`
-	Avatar   string `json:"avatar"`
+	ID             uint   `json:"id"`
+	Username       string `json:"username"`
+	Avatar         string `json:"avatar"`
+	FollowersCount int64  `json:"followers_count"`
+	FollowingCount int64  `json:"following_count"`
+	PostsCount     int64  `json:"posts_count"`
+	FollowedByMe   *bool  `json:"followed_by_me,omitempty"`
+	FollowsMe      *bool  `json:"follows_me,omitempty"`
 }
 
 type AuthOwnerUserDTO struct {
@@ -30,9 +35,12 @@ func ToPublicUser(user *models.User) PublicUserDTO {
 	}
 
 	return PublicUserDTO{
-		ID:       user.ID,
-		Username: utils.SanitizeString(user.Username),
-		Avatar:   utils.SanitizeURL(user.Avatar),
+		ID:             user.ID,
+		Username:       utils.SanitizeString(user.Username),
+		Avatar:         utils.SanitizeURL(user.Avatar),
+		FollowersCount: user.FollowersCount,
+		FollowingCount: user.FollowingCount,
+		PostsCount:     user.PostsCount,
 	}
 }
 
@@ -46,9 +54,12 @@ func ToPublicUsers(users []models.User) []PublicUserDTO {
 
 func EnsurePublicUserSelectFields(fields []string) []string {
 	required := map[string]struct{}{
-		"id":       {},
-		"username": {},
-		"avatar":   {},
+		"id":              {},
+		"username":        {},
+		"avatar":          {},
+		"followers_count": {},
+		"following_count": {},
+		"posts_count":     {},
 	}
 
 	normalized := make(map[string]struct{}, len(fields))
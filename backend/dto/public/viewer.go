@@ -0,0 +1,48 @@
+package public_dto
+
+// ViewerState holds how the authenticated viewer relates to the posts and
+// users on a page. A nil *ViewerState means the request is anonymous and the
+// viewer flags are left out of the response.
+type ViewerState struct {
+	LikedPosts map[uint]bool // posts the viewer has liked
+	Following  map[uint]bool // users the viewer follows
+	FollowedBy map[uint]bool // users that follow the viewer
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func (v *ViewerState) ApplyToUser(dto *PublicUserDTO) {
+	if v == nil || dto == nil {
+		return
+	}
+	dto.FollowedByMe = boolPtr(v.Following[dto.ID])
+	dto.FollowsMe = boolPtr(v.FollowedBy[dto.ID])
+}
+
+func (v *ViewerState) ApplyToUsers(dtos []PublicUserDTO) {
+	for i := range dtos {
+		v.ApplyToUser(&dtos[i])
+	}
+}
+
+func (v *ViewerState) ApplyToPost(dto *PublicPostDTO) {
+	if v == nil || dto == nil {
+		return
+	}
+	dto.LikedByMe = boolPtr(v.LikedPosts[dto.ID])
+	v.ApplyToUser(&dto.Author)
+}
+
+func (v *ViewerState) ApplyToPosts(dtos []PublicPostDTO) {
+	for i := range dtos {
+		v.ApplyToPost(&dtos[i])
+	}
+}
+
+func (v *ViewerState) ApplyToRankedPosts(dtos []RankedPostDTO) {
+	for i := range dtos {
+		v.ApplyToPost(&dtos[i].PublicPostDTO)
+	}
+}
@@ -0,0 +1,91 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"flower-backend/libs"
+	"flower-backend/migrations"
+	"fmt"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Database pings the database.
+func Database(db *gorm.DB) Check {
+	return Check{Name: "database", Run: func(ctx context.Context) (map[string]any, error) {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return nil, err
+		}
+		return nil, sqlDB.PingContext(ctx)
+	}}
+}
+
+// Pool fails when every connection the pool may open is in use, since new
+// requests would then queue for one instead of being served.
+func Pool(db *gorm.DB) Check {
+	return Check{Name: "database_pool", Run: func(ctx context.Context) (map[string]any, error) {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return nil, err
+		}
+		stats := sqlDB.Stats()
+		details := map[string]any{
+			"open":       stats.OpenConnections,
+			"in_use":     stats.InUse,
+			"idle":       stats.Idle,
+			"max_open":   stats.MaxOpenConnections,
+			"wait_count": stats.WaitCount,
+		}
+		if stats.MaxOpenConnections > 0 && stats.InUse >= stats.MaxOpenConnections {
+			return details, fmt.Errorf("all %d connections in use", stats.MaxOpenConnections)
+		}
+		return details, nil
+	}}
+}
+
+// Storage pings the image store. Some backends rate limit their admin API,
+// so the result is reused for every probe within ttl.
+func Storage(storage libs.Storage, ttl time.Duration) Check {
+	return cached(Check{Name: "storage", Run: func(ctx context.Context) (map[string]any, error) {
+		return nil, storage.Ping(ctx)
+	}}, ttl)
+}
+
+// Migrations fails while any known migration is unapplied, such as when a
+// new release starts before its migrations have run.
+func Migrations(migrator *migrations.Migrator) Check {
+	return Check{Name: "migrations", Run: func(ctx context.Context) (map[string]any, error) {
+		pending, err := migrator.Pending(ctx)
+		if err != nil {
+			return nil, err
+		}
+		details := map[string]any{"pending": pending}
+		if pending > 0 {
+			return details, errors.New("migrations pending")
+		}
+		return details, nil
+	}}
+}
+
+// cached runs check at most once per ttl and replays its last outcome in
+// between.
+func cached(check Check, ttl time.Duration) Check {
+	var (
+		mu      sync.Mutex
+		checked time.Time
+		details map[string]any
+		lastErr error
+	)
+	return Check{Name: check.Name, Run: func(ctx context.Context) (map[string]any, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if checked.IsZero() || time.Since(checked) >= ttl {
+			details, lastErr = check.Run(ctx)
+			checked = time.Now()
+		}
+		return details, lastErr
+	}}
+}
@@ -0,0 +1,114 @@
+// Package health answers liveness and readiness probes. Readiness runs a set
+// of dependency checks and reports each one's status and latency; it also
+// fails once the server starts draining, so load balancers stop routing to
+// an instance before it closes its listener.
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	StatusOK       = "ok"
+	StatusFail     = "fail"
+	StatusDraining = "draining"
+)
+
+// checkTimeout bounds each check, so one hung dependency cannot stall a probe.
+const checkTimeout = 2 * time.Second
+
+// Check is one named readiness check. Run returns details worth reporting,
+// such as pool counters, and an error when the dependency is not usable.
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) (map[string]any, error)
+}
+
+// Result is the outcome of one check.
+type Result struct {
+	Status    string         `json:"status"`
+	LatencyMs float64        `json:"latency_ms"`
+	Error     string         `json:"error,omitempty"`
+	Details   map[string]any `json:"details,omitempty"`
+}
+
+// Report is the body of a readiness response.
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+// Ready reports whether every check passed and the server is not draining.
+func (r Report) Ready() bool {
+	return r.Status == StatusOK
+}
+
+// Checker runs the readiness checks for one instance.
+type Checker struct {
+	checks   []Check
+	draining atomic.Bool
+}
+
+func NewChecker(checks ...Check) *Checker {
+	return &Checker{checks: checks}
+}
+
+// Drain makes every later readiness report fail. It is called when shutdown
+// begins and cannot be undone.
+func (c *Checker) Drain() {
+	c.draining.Store(true)
+}
+
+// Draining reports whether Drain has been called.
+func (c *Checker) Draining() bool {
+	return c.draining.Load()
+}
+
+// Ready runs every check concurrently and collects their results. While
+// draining the checks still run, so the report shows why traffic stopped
+// and what the dependencies looked like at the time.
+func (c *Checker) Ready(ctx context.Context) Report {
+	results := make([]Result, len(c.checks))
+	var wg sync.WaitGroup
+	for i, check := range c.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = run(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Checks: make(map[string]Result, len(c.checks))}
+	for i, check := range c.checks {
+		report.Checks[check.Name] = results[i]
+		if results[i].Status != StatusOK {
+			report.Status = StatusFail
+		}
+	}
+	if c.Draining() {
+		report.Status = StatusDraining
+	}
+	return report
+}
+
+func run(ctx context.Context, check Check) Result {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	start := time.Now()
+	details, err := check.Run(ctx)
+	result := Result{
+		Status:    StatusOK,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+		Details:   details,
+	}
+	if err != nil {
+		result.Status = StatusFail
+		result.Error = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,100 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"flower-backend/migrations"
+	"flower-backend/testutil"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestReadyReportsEachCheck(t *testing.T) {
+	checker := NewChecker(
+		Check{Name: "up", Run: func(context.Context) (map[string]any, error) {
+			return map[string]any{"n": 1}, nil
+		}},
+		Check{Name: "down", Run: func(context.Context) (map[string]any, error) {
+			return nil, errors.New("unreachable")
+		}},
+	)
+
+	report := checker.Ready(t.Context())
+	if report.Ready() || report.Status != StatusFail {
+		t.Errorf("status = %q, want %q", report.Status, StatusFail)
+	}
+	if up := report.Checks["up"]; up.Status != StatusOK || up.Details["n"] != 1 {
+		t.Errorf("up = %+v", up)
+	}
+	if down := report.Checks["down"]; down.Status != StatusFail || down.Error != "unreachable" {
+		t.Errorf("down = %+v", down)
+	}
+}
+
+func TestDrainFailsReadiness(t *testing.T) {
+	checker := NewChecker(Check{Name: "up", Run: func(context.Context) (map[string]any, error) {
+		return nil, nil
+	}})
+	if !checker.Ready(t.Context()).Ready() {
+		t.Fatal("not ready before draining")
+	}
+
+	checker.Drain()
+	report := checker.Ready(t.Context())
+	if report.Ready() || report.Status != StatusDraining {
+		t.Errorf("status = %q, want %q", report.Status, StatusDraining)
+	}
+	if report.Checks["up"].Status != StatusOK {
+		t.Errorf("checks stopped running while draining: %+v", report.Checks)
+	}
+}
+
+func TestCheckTimesOut(t *testing.T) {
+	checker := NewChecker(Check{Name: "hung", Run: func(ctx context.Context) (map[string]any, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}})
+	start := time.Now()
+	if checker.Ready(t.Context()).Ready() {
+		t.Error("hung check passed")
+	}
+	if elapsed := time.Since(start); elapsed > checkTimeout+time.Second {
+		t.Errorf("probe took %s, want about %s", elapsed, checkTimeout)
+	}
+}
+
+func TestCachedReusesResult(t *testing.T) {
+	calls := 0
+	check := cached(Check{Name: "counted", Run: func(context.Context) (map[string]any, error) {
+		calls++
+		return nil, nil
+	}}, time.Hour)
+	for range 3 {
+		check.Run(t.Context())
+	}
+	if calls != 1 {
+		t.Errorf("ran %d times within the ttl, want 1", calls)
+	}
+}
+
+func TestMigrationsCheck(t *testing.T) {
+	db := testutil.NewDB(t)
+	migrator, err := migrations.NewMigrator(db, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	check := Migrations(migrator)
+	if _, err := check.Run(t.Context()); err != nil {
+		t.Fatalf("fully migrated database: %v", err)
+	}
+
+	if _, err := migrator.Down(1); err != nil {
+		t.Fatal(err)
+	}
+	details, err := check.Run(t.Context())
+	if err == nil || details["pending"] != 1 {
+		t.Errorf("after rolling back one migration: details %v, err %v", details, err)
+	}
+}
@@ -0,0 +1,59 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+type cronEntry struct {
+	spec     string
+	jobType  string
+	payload  any
+	schedule cron.Schedule
+	next     time.Time
+}
+
+// Cron enqueues a jobType job with payload at every tick of spec, a
+// standard five-field cron expression such as "30 3 * * *". Every replica
+// evaluates the schedule; the tick's unique key collapses their inserts
+// into a single job. Ticks missed while no instance was running are skipped.
+func (q *Queue) Cron(spec, jobType string, payload any) error {
+	schedule, err := cron.ParseStandard(spec)
+	if err != nil {
+		return fmt.Errorf("cron %q for %s: %w", spec, jobType, err)
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.crons = append(q.crons, &cronEntry{
+		spec:     spec,
+		jobType:  jobType,
+		payload:  payload,
+		schedule: schedule,
+		next:     schedule.Next(q.now()),
+	})
+	return nil
+}
+
+func (q *Queue) enqueueCronRuns(ctx context.Context) {
+	q.mu.RLock()
+	entries := q.crons
+	q.mu.RUnlock()
+
+	now := q.now()
+	for _, entry := range entries {
+		if entry.next.After(now) {
+			continue
+		}
+		key := fmt.Sprintf("cron:%s:%d", entry.jobType, entry.next.Unix())
+		if _, err := q.Enqueue(ctx, entry.jobType, entry.payload, UniqueKey(key), RunAt(entry.next)); err != nil {
+			// keep next as is so the tick is tried again on the next poll
+			q.logger.Error("failed to enqueue cron job", zap.String("job_type", entry.jobType), zap.String("spec", entry.spec), zap.Error(err))
+			continue
+		}
+		entry.next = entry.schedule.Next(now)
+	}
+}
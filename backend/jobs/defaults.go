@@ -0,0 +1,75 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"flower-backend/config"
+	"flower-backend/libs"
+	"flower-backend/log"
+	"flower-backend/models"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Job types every instance handles.
+const (
+	TypeDeleteAsset = "storage.delete"
+	TypeSendMail    = "mail.send"
+	TypePruneJobs   = "jobs.prune"
+	// TypeUploadPostImage and TypeNotifyFollowers are handled by the post
+	// service, which registers them
+	TypeUploadPostImage = "post.upload_image"
+	TypeNotifyFollowers = "post.notify_followers"
+)
+
+// pruneAfter is how long succeeded jobs stay around for inspection.
+const pruneAfter = 7 * 24 * time.Hour
+
+// DeleteAsset is the payload of a TypeDeleteAsset job.
+type DeleteAsset struct {
+	PublicID string `json:"public_id"`
+}
+
+// SendMail is the payload of a TypeSendMail job. The body is stored as is,
+// so it carries any link the mail is for until the job is pruned.
+type SendMail struct {
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+// UploadPostImage is the payload of a TypeUploadPostImage job.
+type UploadPostImage struct {
+	PostID uint `json:"post_id"`
+}
+
+// NotifyFollowers is the payload of a TypeNotifyFollowers job.
+type NotifyFollowers struct {
+	PostID uint `json:"post_id"`
+}
+
+// RegisterDefaults registers the built-in handlers: removing images from
+// storage once the rows referencing them are gone, sending mail through the
+// SMTP server in cfg, and a daily prune of succeeded jobs.
+func RegisterDefaults(q *Queue, cfg *config.Config, storage libs.Storage) error {
+	Handle(q, TypeDeleteAsset, func(ctx context.Context, payload DeleteAsset) error {
+		return storage.Delete(ctx, payload.PublicID)
+	})
+	Handle(q, TypeSendMail, func(_ context.Context, payload SendMail) error {
+		err := libs.SendMail(cfg, payload.To, payload.Subject, payload.Body)
+		if errors.Is(err, libs.ErrMailerNotConfigured) {
+			return Permanent(err)
+		}
+		return err
+	})
+	q.Register(TypePruneJobs, func(ctx context.Context, _ *models.Job) error {
+		deleted, err := q.repo.WithContext(ctx).DeleteFinishedBefore(q.now().Add(-pruneAfter))
+		if err != nil {
+			return err
+		}
+		log.FromContext(ctx, q.logger).Info("pruned finished jobs", zap.Int64("count", deleted))
+		return nil
+	})
+	return q.Cron("30 3 * * *", TypePruneJobs, nil)
+}
@@ -0,0 +1,83 @@
+package jobs
+
+import (
+	"encoding/json"
+	"errors"
+	"flower-backend/models"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+const (
+	defaultMaxAttempts = 5
+	backoffBase        = 10 * time.Second
+	backoffMax         = time.Hour
+)
+
+// EnqueueOption adjusts a job built by NewJob.
+type EnqueueOption func(*models.Job)
+
+// RunAt delays the job until at.
+func RunAt(at time.Time) EnqueueOption {
+	return func(job *models.Job) { job.RunAt = at }
+}
+
+// MaxAttempts sets how many times the job runs before it is marked dead.
+func MaxAttempts(n int) EnqueueOption {
+	return func(job *models.Job) { job.MaxAttempts = n }
+}
+
+// UniqueKey makes enqueueing a second job with the same key a no-op.
+func UniqueKey(key string) EnqueueOption {
+	return func(job *models.Job) { job.UniqueKey = &key }
+}
+
+// NewJob builds a queued job with payload encoded as JSON. Callers that
+// need the job to commit together with other writes create it in their own
+// transaction; the queue picks it up on its next poll. RunAt defaults to the
+// database clock when the row is inserted.
+func NewJob(jobType string, payload any, opts ...EnqueueOption) (*models.Job, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("encode %s payload: %w", jobType, err)
+	}
+	job := &models.Job{
+		Type:        jobType,
+		Payload:     string(data),
+		Status:      models.JobQueued,
+		MaxAttempts: defaultMaxAttempts,
+	}
+	for _, opt := range opts {
+		opt(job)
+	}
+	return job, nil
+}
+
+// permanentError marks a failure that retrying cannot fix.
+type permanentError struct{ err error }
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so the job is marked dead at once instead of retried,
+// e.g. when its payload cannot be decoded.
+func Permanent(err error) error {
+	return permanentError{err: err}
+}
+
+func isPermanent(err error) bool {
+	var permanent permanentError
+	return errors.As(err, &permanent)
+}
+
+// backoff is the delay before retrying a job whose attempt-th run failed:
+// 10s doubling up to an hour, plus up to 20% jitter so jobs that failed
+// together do not all retry together.
+func backoff(attempt int) time.Duration {
+	delay := backoffMax
+	if attempt >= 1 && attempt <= 20 {
+		delay = min(backoffBase<<(attempt-1), backoffMax)
+	}
+	return delay + rand.N(delay/5+1)
+}
@@ -0,0 +1,353 @@
+// Package jobs runs background work stored in the jobs table. Handlers are
+// registered per job type; a pool of workers claims due jobs, retries
+// failures with exponential backoff and marks a job dead once its attempts
+// are used up. Several replicas can share one table: each job is claimed by
+// exactly one of them.
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flower-backend/config"
+	"flower-backend/log"
+	"flower-backend/metrics"
+	"flower-backend/models"
+	job_repository "flower-backend/repositories/v1/job"
+	"flower-backend/tracing"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var tracer = otel.Tracer("flower-backend/jobs")
+
+// reapInterval is how often jobs left running by a lost worker are requeued.
+const reapInterval = time.Minute
+
+// Handler runs one job. Returning an error schedules a retry unless the
+// error is Permanent or the job has no attempts left.
+type Handler func(ctx context.Context, job *models.Job) error
+
+// DeadHandler is told when a job gives up for good, with the error of its
+// last attempt, so work waiting on the job can be marked as failed.
+type DeadHandler func(ctx context.Context, job *models.Job, err error)
+
+// Queue claims and runs jobs for the handlers registered on it.
+type Queue struct {
+	repo    job_repository.JobRepository
+	logger  *zap.Logger
+	metrics *metrics.Metrics
+	now     func() time.Time
+	worker  string
+	workers int
+	poll    time.Duration
+	timeout time.Duration
+
+	mu       sync.RWMutex
+	handlers map[string]Handler
+	onDead   map[string]DeadHandler
+	crons    []*cronEntry
+
+	wake     chan struct{}
+	stop     chan struct{}
+	done     chan struct{}
+	started  bool
+	stopOnce sync.Once
+	running  sync.WaitGroup
+	cancel   context.CancelFunc
+}
+
+// New returns a queue over the jobs table in db. It reads the clock from
+// db's NowFunc, so jobs inserted through GORM and the queue agree on what
+// is due. Nothing runs until Start.
+func New(db *gorm.DB, cfg *config.Config, logger *zap.Logger, m *metrics.Metrics) *Queue {
+	workers := cfg.JobWorkers
+	if workers < 1 {
+		workers = 4
+	}
+	poll := cfg.JobPollInterval
+	if poll <= 0 {
+		poll = time.Second
+	}
+	timeout := cfg.JobTimeout
+	if timeout <= 0 {
+		timeout = 5 * time.Minute
+	}
+	hostname, _ := os.Hostname()
+	return &Queue{
+		repo:     job_repository.NewJobRepository(db, cfg, logger.Sugar()),
+		logger:   logger,
+		metrics:  m,
+		now:      db.NowFunc,
+		worker:   fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), uuid.NewString()[:8]),
+		workers:  workers,
+		poll:     poll,
+		timeout:  timeout,
+		handlers: map[string]Handler{},
+		onDead:   map[string]DeadHandler{},
+		wake:     make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Register sets the handler for jobType. Jobs of types with no handler on
+// this instance are left for instances that have one.
+func (q *Queue) Register(jobType string, handler Handler) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.handlers[jobType] = handler
+}
+
+// OnDead sets the handler called when a jobType job is marked dead.
+func (q *Queue) OnDead(jobType string, handler DeadHandler) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.onDead[jobType] = handler
+}
+
+// Handle registers fn for jobType, decoding each job's JSON payload into T.
+func Handle[T any](q *Queue, jobType string, fn func(ctx context.Context, payload T) error) {
+	q.Register(jobType, func(ctx context.Context, job *models.Job) error {
+		var payload T
+		if err := json.Unmarshal([]byte(job.Payload), &payload); err != nil {
+			return Permanent(fmt.Errorf("decode %s payload: %w", jobType, err))
+		}
+		return fn(ctx, payload)
+	})
+}
+
+// Enqueue stores a job and wakes the workers. With a UniqueKey that is
+// already taken nothing is stored and the returned job has no ID.
+func (q *Queue) Enqueue(ctx context.Context, jobType string, payload any, opts ...EnqueueOption) (*models.Job, error) {
+	job, err := NewJob(jobType, payload, opts...)
+	if err != nil {
+		return nil, err
+	}
+	created, err := q.repo.WithContext(ctx).Create(job)
+	if err != nil {
+		return nil, err
+	}
+	if created {
+		q.notify()
+	}
+	return job, nil
+}
+
+// Start launches the dispatcher and returns immediately.
+func (q *Queue) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	q.mu.Lock()
+	q.started = true
+	q.cancel = cancel
+	q.mu.Unlock()
+	go q.dispatch(ctx)
+	q.logger.Info("job queue started", zap.String("worker", q.worker), zap.Int("workers", q.workers))
+}
+
+// Shutdown stops claiming jobs and waits for running ones to finish. If ctx
+// ends first the running handlers are cancelled, their jobs are put back in
+// the queue to run again, and ctx's error is returned.
+func (q *Queue) Shutdown(ctx context.Context) error {
+	q.mu.RLock()
+	started, cancel := q.started, q.cancel
+	q.mu.RUnlock()
+	if !started {
+		return nil
+	}
+	q.stopOnce.Do(func() { close(q.stop) })
+	<-q.done
+
+	finished := make(chan struct{})
+	go func() {
+		q.running.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+		cancel()
+		q.logger.Info("job queue drained")
+		return nil
+	case <-ctx.Done():
+		cancel()
+		<-finished
+		q.logger.Warn("job queue shutdown interrupted running jobs", zap.Error(ctx.Err()))
+		return ctx.Err()
+	}
+}
+
+// notify wakes the dispatcher without blocking; one pending wake is enough.
+func (q *Queue) notify() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (q *Queue) dispatch(ctx context.Context) {
+	defer close(q.done)
+
+	slots := make(chan struct{}, q.workers)
+	ticker := time.NewTicker(q.poll)
+	defer ticker.Stop()
+
+	var lastReap time.Time
+	for {
+		q.enqueueCronRuns(ctx)
+		if now := q.now(); now.Sub(lastReap) >= reapInterval {
+			q.reap(ctx, now)
+			lastReap = now
+		}
+		q.claim(ctx, slots)
+
+		select {
+		case <-q.stop:
+			return
+		case <-ticker.C:
+		case <-q.wake:
+		}
+	}
+}
+
+// claim fills the free worker slots with due jobs.
+func (q *Queue) claim(ctx context.Context, slots chan struct{}) {
+	free := cap(slots) - len(slots)
+	if free == 0 {
+		return
+	}
+	claimed, err := q.repo.WithContext(ctx).Claim(q.types(), q.worker, q.now(), free)
+	if err != nil {
+		q.logger.Error("failed to claim jobs", zap.Error(err))
+	}
+	for i := range claimed {
+		job := &claimed[i]
+		slots <- struct{}{}
+		q.running.Add(1)
+		go func() {
+			defer q.running.Done()
+			q.run(ctx, job)
+			<-slots
+			// a slot is free again; pick up any backlog without waiting for the tick
+			q.notify()
+		}()
+	}
+}
+
+// reap requeues jobs whose worker has held them for twice the job timeout,
+// which only happens when that worker died.
+func (q *Queue) reap(ctx context.Context, now time.Time) {
+	requeued, err := q.repo.WithContext(ctx).RequeueStale(now.Add(-2*q.timeout), now)
+	if err != nil {
+		q.logger.Error("failed to requeue stale jobs", zap.Error(err))
+		return
+	}
+	if requeued > 0 {
+		q.logger.Warn("requeued jobs from a lost worker", zap.Int64("count", requeued))
+	}
+}
+
+func (q *Queue) types() []string {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	types := make([]string, 0, len(q.handlers))
+	for jobType := range q.handlers {
+		types = append(types, jobType)
+	}
+	return types
+}
+
+func (q *Queue) handler(jobType string) Handler {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	return q.handlers[jobType]
+}
+
+// run executes one claimed job and records its outcome.
+func (q *Queue) run(ctx context.Context, job *models.Job) {
+	logger := q.logger.With(
+		zap.Uint("job_id", job.ID),
+		zap.String("job_type", job.Type),
+		zap.Int("attempt", job.Attempts),
+	)
+	ctx, span := tracer.Start(log.WithLogger(ctx, logger), "job "+job.Type, trace.WithAttributes(
+		attribute.Int64("job.id", int64(job.ID)),
+		attribute.Int("job.attempt", job.Attempts),
+	))
+
+	start := time.Now()
+	err := q.call(ctx, job)
+	duration := time.Since(start)
+	q.metrics.JobDuration.WithLabelValues(job.Type).Observe(duration.Seconds())
+	tracing.End(span, &err)
+
+	// the outcome is recorded even when shutdown cancelled ctx
+	repo := q.repo.WithContext(context.WithoutCancel(ctx))
+	now := q.now()
+	switch {
+	case err == nil:
+		q.metrics.JobRuns.WithLabelValues(job.Type, "success").Inc()
+		logger.Info("job completed", zap.Duration("duration", duration))
+		err = repo.Complete(job.ID, q.worker, now)
+	case ctx.Err() != nil:
+		// interrupted by shutdown rather than failed; run it again soon
+		q.metrics.JobRuns.WithLabelValues(job.Type, "retry").Inc()
+		logger.Warn("job interrupted by shutdown", zap.Error(err))
+		err = repo.Reschedule(job.ID, q.worker, "interrupted by shutdown: "+err.Error(), now)
+	case isPermanent(err) || job.Attempts >= job.MaxAttempts:
+		q.metrics.JobRuns.WithLabelValues(job.Type, "dead").Inc()
+		logger.Error("job failed permanently", zap.Duration("duration", duration), zap.Error(err))
+		q.mu.RLock()
+		onDead := q.onDead[job.Type]
+		q.mu.RUnlock()
+		cause := err
+		err = repo.Bury(job.ID, q.worker, cause.Error(), now)
+		// only once buried, so a job another worker has taken over is not
+		// reported dead
+		if err == nil && onDead != nil {
+			onDead(context.WithoutCancel(ctx), job, cause)
+		}
+	default:
+		retryAt := now.Add(backoff(job.Attempts))
+		q.metrics.JobRuns.WithLabelValues(job.Type, "retry").Inc()
+		logger.Warn("job failed, will retry", zap.Time("retry_at", retryAt), zap.Error(err))
+		err = repo.Reschedule(job.ID, q.worker, err.Error(), retryAt)
+	}
+	if errors.Is(err, job_repository.ErrLockLost) {
+		logger.Warn("job outcome discarded; it was requeued as stale while running")
+	} else if err != nil {
+		logger.Error("failed to record job outcome", zap.Error(err))
+	}
+}
+
+// call runs the job's handler under the job timeout, turning a panic into
+// an error so one bad job cannot take the worker pool down.
+func (q *Queue) call(ctx context.Context, job *models.Job) (err error) {
+	handler := q.handler(job.Type)
+	if handler == nil {
+		return Permanent(fmt.Errorf("no handler for job type %q", job.Type))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, q.timeout)
+	defer cancel()
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	if err := handler(ctx, job); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) && ctx.Err() != nil {
+			return fmt.Errorf("job timed out after %s: %w", q.timeout, err)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,309 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"flower-backend/metrics"
+	"flower-backend/models"
+	"flower-backend/testutil"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// start is where the database clock stands when a test begins; the queue
+// reads it through NowFunc.
+var start = time.Date(2025, 6, 1, 12, 0, 30, 0, time.UTC)
+
+func newTestQueue(t *testing.T, db *gorm.DB) *Queue {
+	t.Helper()
+	cfg := testutil.Config()
+	cfg.JobPollInterval = 10 * time.Millisecond
+	q := New(db, cfg, zap.NewNop(), metrics.New())
+	t.Cleanup(func() { q.Shutdown(context.Background()) })
+	return q
+}
+
+// waitForJob polls until the job reaches status and returns it.
+func waitForJob(t *testing.T, db *gorm.DB, id uint, status string) models.Job {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	var job models.Job
+	for time.Now().Before(deadline) {
+		if err := db.First(&job, id).Error; err != nil {
+			t.Fatalf("load job %d: %v", id, err)
+		}
+		if job.Status == status {
+			return job
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("job %d is %s after 5s, want %s (last error %q)", id, job.Status, status, job.LastError)
+	return job
+}
+
+type greeting struct {
+	Name string `json:"name"`
+}
+
+func TestJobRunsWithTypedPayload(t *testing.T) {
+	db, _ := testutil.NewDBWithClock(t, start)
+	q := newTestQueue(t, db)
+
+	got := make(chan string, 1)
+	Handle(q, "greet", func(_ context.Context, payload greeting) error {
+		got <- payload.Name
+		return nil
+	})
+	q.Start()
+
+	job, err := q.Enqueue(t.Context(), "greet", greeting{Name: "lily"})
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	done := waitForJob(t, db, job.ID, models.JobSucceeded)
+	if name := <-got; name != "lily" {
+		t.Errorf("handler got %q, want lily", name)
+	}
+	if done.Attempts != 1 || done.FinishedAt == nil {
+		t.Errorf("finished job = %+v, want one attempt and a finish time", done)
+	}
+}
+
+func TestFailedJobBacksOffThenDies(t *testing.T) {
+	db, clk := testutil.NewDBWithClock(t, start)
+	q := newTestQueue(t, db)
+	q.Register("flaky", func(context.Context, *models.Job) error {
+		return errors.New("upstream unavailable")
+	})
+	q.Start()
+
+	job, err := q.Enqueue(t.Context(), "flaky", nil, MaxAttempts(2))
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	// the first failure is retried after a backoff, not straight away
+	var retrying models.Job
+	deadline := time.Now().Add(5 * time.Second)
+	for retrying.Attempts != 1 || retrying.Status != models.JobQueued {
+		if time.Now().After(deadline) {
+			t.Fatalf("job never went back to the queue: %+v", retrying)
+		}
+		time.Sleep(10 * time.Millisecond)
+		db.First(&retrying, job.ID)
+	}
+	if wait := retrying.RunAt.Sub(clk.Now()); wait < backoffBase || wait > backoffBase*6/5+time.Second {
+		t.Errorf("retry scheduled %s ahead, want about %s", wait, backoffBase)
+	}
+	if retrying.LastError != "upstream unavailable" {
+		t.Errorf("last_error = %q", retrying.LastError)
+	}
+
+	clk.Advance(time.Minute)
+	dead := waitForJob(t, db, job.ID, models.JobDead)
+	if dead.Attempts != 2 {
+		t.Errorf("attempts = %d, want 2", dead.Attempts)
+	}
+}
+
+func TestPermanentErrorSkipsRetries(t *testing.T) {
+	db, _ := testutil.NewDBWithClock(t, start)
+	q := newTestQueue(t, db)
+	Handle(q, "greet", func(context.Context, greeting) error { return nil })
+	buried := make(chan error, 1)
+	q.OnDead("greet", func(_ context.Context, _ *models.Job, err error) { buried <- err })
+	q.Start()
+
+	// a payload that does not decode can never succeed
+	job := &models.Job{Type: "greet", Payload: "not json", Status: models.JobQueued, MaxAttempts: 5}
+	if err := db.Create(job).Error; err != nil {
+		t.Fatal(err)
+	}
+	q.notify()
+	if dead := waitForJob(t, db, job.ID, models.JobDead); dead.Attempts != 1 {
+		t.Errorf("attempts = %d, want 1", dead.Attempts)
+	}
+	if err := <-buried; !isPermanent(err) {
+		t.Errorf("OnDead got %v, want the permanent decode error", err)
+	}
+}
+
+func TestMailWithoutSMTPDiesAtOnce(t *testing.T) {
+	db, _ := testutil.NewDBWithClock(t, start)
+	q := newTestQueue(t, db)
+	if err := RegisterDefaults(q, testutil.Config(), nil); err != nil {
+		t.Fatalf("RegisterDefaults: %v", err)
+	}
+	q.Start()
+
+	job, err := q.Enqueue(t.Context(), TypeSendMail, SendMail{To: "reed@example.com", Subject: "Hi", Body: "Hello"})
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	if dead := waitForJob(t, db, job.ID, models.JobDead); dead.Attempts != 1 {
+		t.Errorf("attempts = %d, want 1", dead.Attempts)
+	}
+}
+
+func TestReplicasRunEachJobOnce(t *testing.T) {
+	db, _ := testutil.NewDBWithClock(t, start)
+	runs := map[string]*atomic.Int32{}
+	var mu sync.Mutex
+	handler := func(_ context.Context, payload greeting) error {
+		mu.Lock()
+		counter, ok := runs[payload.Name]
+		if !ok {
+			counter = &atomic.Int32{}
+			runs[payload.Name] = counter
+		}
+		mu.Unlock()
+		counter.Add(1)
+		return nil
+	}
+
+	var ids []uint
+	enqueuer := newTestQueue(t, db)
+	for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"} {
+		job, err := enqueuer.Enqueue(t.Context(), "greet", greeting{Name: name})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, job.ID)
+	}
+	for range 3 {
+		q := newTestQueue(t, db)
+		Handle(q, "greet", handler)
+		q.Start()
+	}
+
+	for _, id := range ids {
+		waitForJob(t, db, id, models.JobSucceeded)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	for name, counter := range runs {
+		if n := counter.Load(); n != 1 {
+			t.Errorf("job %s ran %d times, want once", name, n)
+		}
+	}
+}
+
+func TestCronEnqueuesOneJobAcrossReplicas(t *testing.T) {
+	db, clk := testutil.NewDBWithClock(t, start)
+	for range 2 {
+		q := newTestQueue(t, db)
+		q.Register("tick", func(context.Context, *models.Job) error { return nil })
+		if err := q.Cron("* * * * *", "tick", nil); err != nil {
+			t.Fatalf("Cron: %v", err)
+		}
+		q.Start()
+	}
+	if err := newTestQueue(t, db).Cron("not a spec", "tick", nil); err == nil {
+		t.Error("Cron accepted an invalid spec")
+	}
+
+	clk.Advance(time.Minute)
+	deadline := time.Now().Add(5 * time.Second)
+	var jobs []models.Job
+	for time.Now().Before(deadline) {
+		db.Where("type = ? AND status = ?", "tick", models.JobSucceeded).Find(&jobs)
+		if len(jobs) > 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	// give the other replica time to try its own insert
+	time.Sleep(100 * time.Millisecond)
+
+	var count int64
+	db.Model(&models.Job{}).Where("type = ?", "tick").Count(&count)
+	if count != 1 {
+		t.Errorf("%d tick jobs, want 1", count)
+	}
+}
+
+func TestShutdownRequeuesInterruptedJobs(t *testing.T) {
+	db, _ := testutil.NewDBWithClock(t, start)
+	q := newTestQueue(t, db)
+	started := make(chan struct{})
+	q.Register("slow", func(ctx context.Context, _ *models.Job) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	q.Start()
+
+	job, err := q.Enqueue(t.Context(), "slow", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := q.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown = %v, want deadline exceeded", err)
+	}
+	var requeued models.Job
+	db.First(&requeued, job.ID)
+	if requeued.Status != models.JobQueued || requeued.LockedBy != "" {
+		t.Errorf("interrupted job = %+v, want queued and unlocked", requeued)
+	}
+}
+
+func TestOutcomeOfATakenOverJobIsDiscarded(t *testing.T) {
+	db, _ := testutil.NewDBWithClock(t, start)
+	q := newTestQueue(t, db)
+	ran := make(chan struct{})
+	q.Register("stuck", func(_ context.Context, job *models.Job) error {
+		// the reaper requeued it and another replica claimed it meanwhile
+		db.Model(&models.Job{}).Where("id = ?", job.ID).Update("locked_by", "other-replica")
+		close(ran)
+		return Permanent(errors.New("upstream rejected it"))
+	})
+	var dead atomic.Bool
+	q.OnDead("stuck", func(context.Context, *models.Job, error) { dead.Store(true) })
+	q.Start()
+
+	job, err := q.Enqueue(t.Context(), "stuck", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-ran
+	// waits for the worker to record the outcome
+	if err := q.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	var held models.Job
+	db.First(&held, job.ID)
+	if held.Status != models.JobRunning || held.LockedBy != "other-replica" {
+		t.Errorf("job = %+v, want still running under the other replica", held)
+	}
+	if dead.Load() {
+		t.Error("OnDead ran for a job this worker no longer held")
+	}
+}
+
+func TestBackoffGrowsAndCaps(t *testing.T) {
+	for _, tt := range []struct {
+		attempt int
+		base    time.Duration
+	}{
+		{1, 10 * time.Second},
+		{2, 20 * time.Second},
+		{4, 80 * time.Second},
+		{12, time.Hour},
+		{100, time.Hour},
+	} {
+		got := backoff(tt.attempt)
+		if got < tt.base || got > tt.base*6/5 {
+			t.Errorf("backoff(%d) = %s, want %s plus up to 20%%", tt.attempt, got, tt.base)
+		}
+	}
+}
@@ -4,15 +4,24 @@ import (
 	"bytes"
 	"context"
 	"flower-backend/config"
+	"flower-backend/tracing"
 	"fmt"
+	"net/url"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/cloudinary/cloudinary-go/v2"
+	"github.com/cloudinary/cloudinary-go/v2/api"
+	"github.com/cloudinary/cloudinary-go/v2/api/admin"
 	"github.com/cloudinary/cloudinary-go/v2/api/uploader"
-	"go.uber.org/zap"
 )
 
+// signatureLifetime is how long Cloudinary accepts an upload signature after
+// its timestamp.
+const signatureLifetime = time.Hour
+
 // NewCloudinary creates a configured Cloudinary client using application config.
 func NewCloudinary(cfg *config.Config) (*cloudinary.Cloudinary, error) {
 	if cfg == nil {
@@ -29,6 +38,9 @@ func NewCloudinary(cfg *config.Config) (*cloudinary.Cloudinary, error) {
 	}
 
 	cld.Config.URL.Secure = cfg.GO_ENV == "production"
+	// trace upload, destroy and ping calls as children of the caller's span
+	cld.Upload.Client.Transport = tracing.Transport(cld.Upload.Client.Transport)
+	cld.Admin.Client.Transport = tracing.Transport(cld.Admin.Client.Transport)
 
 	return cld, nil
 }
@@ -36,6 +48,7 @@ func NewCloudinary(cfg *config.Config) (*cloudinary.Cloudinary, error) {
 // UploadToCloudinary uploads an image buffer to Cloudinary with optional public ID.
 //
 // Parameters:
+//   - ctx: Carries the caller's deadline and trace
 //   - cld: The Cloudinary client instance
 //   - buffer: The image data as a byte slice
 //   - publicId: Optional public ID for the uploaded image (empty string if not provided)
@@ -43,10 +56,7 @@ func NewCloudinary(cfg *config.Config) (*cloudinary.Cloudinary, error) {
 // Returns:
 //   - *uploader.UploadResult: The upload result containing the image URL and metadata
 //   - error: Any error that occurred during upload
-func UploadToCloudinary(cld *cloudinary.Cloudinary, buffer []byte, publicId string) (*uploader.UploadResult, error) {
-	logger := zap.L()
-	ctx := context.Background()
-
+func UploadToCloudinary(ctx context.Context, cld *cloudinary.Cloudinary, buffer []byte, publicId string) (*uploader.UploadResult, error) {
 	uploadParams := uploader.UploadParams{
 		AllowedFormats: []string{"png", "jpg", "webp"},
 		ResourceType:   "image",
@@ -62,26 +72,104 @@ func UploadToCloudinary(cld *cloudinary.Cloudinary, buffer []byte, publicId stri
 	// Upload the image buffer
 	uploadResult, err := cld.Upload.Upload(ctx, bytes.NewReader(buffer), uploadParams)
 	if err != nil {
-		logger.Error("Error uploading image to Cloudinary", zap.Error(err))
 		return nil, fmt.Errorf("error uploading image to Cloudinary: %w", err)
 	}
 
 	return uploadResult, nil
 }
 
-func DeleteFromCloudinary(cld *cloudinary.Cloudinary, publicId string) error {
-	logger := zap.L()
-	ctx := context.Background()
+func DeleteFromCloudinary(ctx context.Context, cld *cloudinary.Cloudinary, publicId string) error {
 	_, err := cld.Upload.Destroy(ctx, uploader.DestroyParams{
 		PublicID: publicId,
 	})
 	if err != nil {
-		logger.Error("Error deleting image from Cloudinary", zap.Error(err))
 		return fmt.Errorf("error deleting image from Cloudinary: %w", err)
 	}
 	return err
 }
 
+// cloudinaryStorage adapts a Cloudinary client to Storage.
+type cloudinaryStorage struct {
+	cld *cloudinary.Cloudinary
+}
+
+func (s *cloudinaryStorage) Upload(ctx context.Context, buffer []byte, publicId string) (string, error) {
+	result, err := UploadToCloudinary(ctx, s.cld, buffer, publicId)
+	if err != nil {
+		return "", err
+	}
+	return result.SecureURL, nil
+}
+
+func (s *cloudinaryStorage) Delete(ctx context.Context, publicId string) error {
+	return DeleteFromCloudinary(ctx, s.cld, publicId)
+}
+
+// SignUpload signs the same upload options UploadToCloudinary sends, for a
+// form POST straight to Cloudinary. Cloudinary has no expiry parameter; it
+// refuses signatures an hour past their timestamp, so the timestamp is
+// backdated to make the signature lapse at expiresAt.
+func (s *cloudinaryStorage) SignUpload(_ context.Context, publicId string, expiresAt time.Time) (*SignedUpload, error) {
+	now := time.Now()
+	timestamp := expiresAt.Add(-signatureLifetime)
+	if timestamp.After(now) {
+		timestamp = now
+	}
+	params := url.Values{
+		"allowed_formats": {"png,jpg,webp"},
+		"folder":          {"flower-sharing"},
+		"public_id":       {publicId},
+		"timestamp":       {strconv.FormatInt(timestamp.Unix(), 10)},
+		"transformation":  {"q_auto"},
+	}
+	cloud := s.cld.Config.Cloud
+	signature, err := api.SignParameters(params, cloud.APISecret)
+	if err != nil {
+		return nil, fmt.Errorf("error signing Cloudinary upload: %w", err)
+	}
+
+	fields := map[string]string{"api_key": cloud.APIKey, "signature": signature}
+	for key := range params {
+		fields[key] = params.Get(key)
+	}
+	return &SignedUpload{
+		URL:       fmt.Sprintf("%s/v1_1/%s/image/upload", s.cld.Config.API.UploadPrefix, cloud.CloudName),
+		Method:    "POST",
+		Fields:    fields,
+		ExpiresAt: timestamp.Add(signatureLifetime),
+	}, nil
+}
+
+func (s *cloudinaryStorage) Stat(ctx context.Context, publicId string) (*Asset, error) {
+	result, err := s.cld.Admin.Asset(ctx, admin.AssetParams{PublicID: "flower-sharing/" + publicId})
+	if err != nil {
+		return nil, fmt.Errorf("error looking up Cloudinary asset: %w", err)
+	}
+	if result.Error.Message != "" {
+		if strings.HasPrefix(result.Error.Message, "Resource not found") {
+			return nil, ErrAssetNotFound
+		}
+		return nil, fmt.Errorf("error looking up Cloudinary asset: %s", result.Error.Message)
+	}
+	return &Asset{
+		PublicID: publicId,
+		URL:      result.SecureURL,
+		Bytes:    int64(result.Bytes),
+		Format:   result.Format,
+	}, nil
+}
+
+func (s *cloudinaryStorage) Ping(ctx context.Context) error {
+	result, err := s.cld.Admin.Ping(ctx)
+	if err != nil {
+		return fmt.Errorf("error pinging Cloudinary: %w", err)
+	}
+	if result.Error.Message != "" {
+		return fmt.Errorf("error pinging Cloudinary: %s", result.Error.Message)
+	}
+	return nil
+}
+
 func ExtractPublicId(imageURL string) string {
 	parts := strings.Split(imageURL, "/")
 	last := parts[len(parts)-1]
@@ -0,0 +1,73 @@
+package libs
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+
+	// decoders for the formats DecodeImage accepts
+	_ "image/gif"
+	_ "image/png"
+)
+
+// renditionQuality is the JPEG quality scaled copies are encoded at.
+const renditionQuality = 85
+
+// DecodeImage decodes a JPEG, PNG or GIF image.
+func DecodeImage(data []byte) (image.Image, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	return img, err
+}
+
+// ScaleJPEG scales img down to width pixels wide, keeping its aspect ratio,
+// and encodes it as JPEG with transparent areas on white. It reports false
+// without encoding anything when img is no wider than width already.
+func ScaleJPEG(img image.Image, width int) ([]byte, bool, error) {
+	bounds := img.Bounds()
+	if bounds.Dx() <= width {
+		return nil, false, nil
+	}
+	height := max(1, bounds.Dy()*width/bounds.Dx())
+
+	// flatten onto white first so the box filter reads plain RGBA bytes
+	src := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(src, src.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(src, src.Bounds(), img, bounds.Min, draw.Over)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, boxScale(src, width, height), &jpeg.Options{Quality: renditionQuality}); err != nil {
+		return nil, false, err
+	}
+	return buf.Bytes(), true, nil
+}
+
+// boxScale shrinks src to width x height, averaging the source pixels each
+// destination pixel covers.
+func boxScale(src *image.RGBA, width, height int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	srcW, srcH := src.Bounds().Dx(), src.Bounds().Dy()
+	for y := range height {
+		y0, y1 := y*srcH/height, max((y+1)*srcH/height, y*srcH/height+1)
+		for x := range width {
+			x0, x1 := x*srcW/width, max((x+1)*srcW/width, x*srcW/width+1)
+			var sum [4]int
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride+x0*4 : sy*src.Stride+x1*4]
+				for i := 0; i < len(row); i += 4 {
+					sum[0] += int(row[i])
+					sum[1] += int(row[i+1])
+					sum[2] += int(row[i+2])
+					sum[3] += int(row[i+3])
+				}
+			}
+			n := (y1 - y0) * (x1 - x0)
+			i := dst.PixOffset(x, y)
+			for c := range sum {
+				dst.Pix[i+c] = uint8(sum[c] / n)
+			}
+		}
+	}
+	return dst
+}
@@ -0,0 +1,38 @@
+package libs_test
+
+import (
+	"bytes"
+	"flower-backend/libs"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+)
+
+func TestScaleJPEG(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 800, 400))
+	for i := range src.Pix {
+		src.Pix[i] = 0xff
+	}
+	// a transparent corner comes out white rather than black
+	src.SetNRGBA(0, 0, color.NRGBA{})
+
+	scaled, ok, err := libs.ScaleJPEG(src, 320)
+	if err != nil || !ok {
+		t.Fatalf("ScaleJPEG(320) = %v, %v", ok, err)
+	}
+	got, err := jpeg.Decode(bytes.NewReader(scaled))
+	if err != nil {
+		t.Fatalf("decode rendition: %v", err)
+	}
+	if size := got.Bounds().Size(); size != image.Pt(320, 160) {
+		t.Errorf("rendition is %v, want 320x160", size)
+	}
+	if r, g, b, _ := got.At(0, 0).RGBA(); r>>8 < 0xf0 || g>>8 < 0xf0 || b>>8 < 0xf0 {
+		t.Errorf("corner = %v, want white", got.At(0, 0))
+	}
+
+	if _, ok, err := libs.ScaleJPEG(src, 1080); ok || err != nil {
+		t.Errorf("ScaleJPEG(1080) = %v, %v, want no rendition of a narrower image", ok, err)
+	}
+}
@@ -5,69 +5,49 @@ import (
 	"encoding/base64"
 	"errors"
 	"flower-backend/config"
-	"flower-backend/log"
 	"fmt"
-	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"go.uber.org/zap"
 )
 
-var (
+// JWT signs and verifies access and refresh tokens with the configured
+// secrets. Expiry is measured against now, so tests can move time.
+type JWT struct {
 	cfg    *config.Config
 	logger *zap.SugaredLogger
-	once   sync.Once
-)
-
-// InitJWT allows callers to inject a shared config/logger and avoid
-// loading configuration at package init time.
-func InitJWT(c *config.Config, l *zap.SugaredLogger) {
-	if c != nil {
-		cfg = c
-	}
-	if l != nil {
-		logger = l
-	}
-	initConfig()
+	now    func() time.Time
 }
 
-func initConfig() {
-	once.Do(func() {
-		if cfg == nil {
-			cfg = config.LoadConfig()
-		}
-		if logger == nil {
-			logger = log.InitLog().Sugar()
-		}
-	})
+// NewJWT returns a signer using cfg's secrets and lifetimes.
+func NewJWT(cfg *config.Config, logger *zap.SugaredLogger, now func() time.Time) *JWT {
+	return &JWT{cfg: cfg, logger: logger, now: now}
 }
 
 // GenerateAccessToken generates an access token with user ID (backward compatible)
-func GenerateAccessToken(UserId uint) string {
-	initConfig()
+func (j *JWT) GenerateAccessToken(UserId uint) string {
 	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"sub": UserId,
-		"exp": time.Now().Add(cfg.JWTExpiry).Unix(),
+		"exp": j.now().Add(j.cfg.JWTExpiry).Unix(),
 	})
-	accessTokenString, err := accessToken.SignedString([]byte(cfg.JWTSecret))
+	accessTokenString, err := accessToken.SignedString([]byte(j.cfg.JWTSecret))
 	if err != nil {
-		logger.Error("failed to generate access token", zap.Error(err))
+		j.logger.Error("failed to generate access token", zap.Error(err))
 		return ""
 	}
 	return accessTokenString
 }
 
 // GenerateRefreshToken generates a refresh token with user ID (backward compatible)
-func GenerateRefreshToken(UserId uint) string {
-	initConfig()
+func (j *JWT) GenerateRefreshToken(UserId uint) string {
 	refreshToken := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"sub": UserId,
-		"exp": time.Now().Add(cfg.JWTRefreshExpiry).Unix(),
+		"exp": j.now().Add(j.cfg.JWTRefreshExpiry).Unix(),
 	})
-	refreshTokenString, err := refreshToken.SignedString([]byte(cfg.JWTRefreshSecret))
+	refreshTokenString, err := refreshToken.SignedString([]byte(j.cfg.JWTRefreshSecret))
 	if err != nil {
-		logger.Error("failed to generate refresh token", zap.Error(err))
+		j.logger.Error("failed to generate refresh token", zap.Error(err))
 		return ""
 	}
 	return refreshTokenString
@@ -82,43 +62,22 @@ func GenerateRandomString(length int) string {
 	return base64.URLEncoding.EncodeToString(b)[:length]
 }
 
-func VerifyAccessToken(tokenString string) (uint, error) {
-	initConfig()
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (any, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(cfg.JWTSecret), nil
-	})
-
-	if err != nil {
-		return 0, err
-	}
-
-	if !token.Valid {
-		return 0, errors.New("invalid token")
-	}
-
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
-		return 0, errors.New("invalid token claims")
-	}
+func (j *JWT) VerifyAccessToken(tokenString string) (uint, error) {
+	return j.verify(tokenString, j.cfg.JWTSecret)
+}
 
-	sub, ok := claims["sub"].(float64)
-	if !ok {
-		return 0, errors.New("subject claim missing or invalid")
-	}
-	return uint(sub), nil
+func (j *JWT) VerifyRefreshToken(tokenString string) (uint, error) {
+	return j.verify(tokenString, j.cfg.JWTRefreshSecret)
 }
 
-func VerifyRefreshToken(tokenString string) (uint, error) {
-	initConfig()
+// verify checks the signature and expiry and returns the subject user ID.
+func (j *JWT) verify(tokenString, secret string) (uint, error) {
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (any, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
-		return []byte(cfg.JWTRefreshSecret), nil
-	})
+		return []byte(secret), nil
+	}, jwt.WithTimeFunc(j.now))
 	if err != nil {
 		return 0, err
 	}
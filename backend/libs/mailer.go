@@ -0,0 +1,55 @@
+package libs
+
+import (
+	"errors"
+	"flower-backend/config"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+)
+
+// ErrMailerNotConfigured is returned when SMTP_HOST or SMTP_FROM is not set.
+var ErrMailerNotConfigured = errors.New("mailer: smtp is not configured")
+
+// SendMail sends a plain text email through the configured SMTP server.
+//
+// Parameters:
+//   - cfg: Application config holding the SMTP settings
+//   - to: Recipient address
+//   - subject: Mail subject
+//   - body: Plain text body
+//
+// Returns:
+//   - error: ErrMailerNotConfigured if SMTP is not set up, or any delivery error
+func SendMail(cfg *config.Config, to, subject, body string) error {
+	if cfg == nil || cfg.SMTPHost == "" || cfg.SMTPFrom == "" {
+		return ErrMailerNotConfigured
+	}
+
+	// Reject header injection through the recipient or subject
+	if strings.ContainsAny(to, "\r\n") || strings.ContainsAny(subject, "\r\n") {
+		return fmt.Errorf("mailer: invalid header value")
+	}
+
+	var auth smtp.Auth
+	if cfg.SMTPUsername != "" {
+		auth = smtp.PlainAuth("", cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPHost)
+	}
+
+	msg := strings.Join([]string{
+		"From: " + cfg.SMTPFrom,
+		"To: " + to,
+		"Subject: " + subject,
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		body,
+	}, "\r\n")
+
+	addr := net.JoinHostPort(cfg.SMTPHost, cfg.SMTPPort)
+	if err := smtp.SendMail(addr, auth, cfg.SMTPFrom, []string{to}, []byte(msg)); err != nil {
+		return fmt.Errorf("mailer: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package libs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flower-backend/config"
+	"flower-backend/tracing"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/github"
+	"golang.org/x/oauth2/google"
+)
+
+// ErrOAuthProfileInvalid is returned when a provider's user info cannot be parsed.
+var ErrOAuthProfileInvalid = errors.New("invalid oauth profile")
+
+// OAuthProfile is the account a provider reports after a successful login.
+type OAuthProfile struct {
+	ID        string
+	Email     string
+	Name      string
+	AvatarURL string
+	// Raw is the provider's user info response, kept as provider_data
+	Raw string
+}
+
+// OAuthProvider drives one provider's authorization code flow.
+type OAuthProvider interface {
+	AuthCodeURL(state string) string
+	Exchange(ctx context.Context, code string) (*oauth2.Token, error)
+	FetchProfile(ctx context.Context, token *oauth2.Token) (*OAuthProfile, error)
+}
+
+// NewOAuthProvider returns the provider registered under name ("google" or
+// "github"), configured from cfg.
+func NewOAuthProvider(name string, cfg *config.Config) (OAuthProvider, error) {
+	switch name {
+	case "google":
+		return &googleProvider{config: &oauth2.Config{
+			ClientID:     cfg.GoogleClientID,
+			ClientSecret: cfg.GoogleClientSecret,
+			RedirectURL:  cfg.GoogleRedirectURL,
+			Scopes: []string{
+				"https://www.googleapis.com/auth/userinfo.email",
+				"https://www.googleapis.com/auth/userinfo.profile",
+			},
+			Endpoint: google.Endpoint,
+		}}, nil
+	case "github":
+		return &githubProvider{config: &oauth2.Config{
+			ClientID:     cfg.GithubClientID,
+			ClientSecret: cfg.GithubClientSecret,
+			RedirectURL:  cfg.GithubRedirectURL,
+			Scopes:       []string{"user:email"},
+			Endpoint:     github.Endpoint,
+		}}, nil
+	}
+	return nil, fmt.Errorf("unknown oauth provider %q", name)
+}
+
+// oauthHTTPClient is used for token exchanges and profile fetches; its
+// transport records a client span for each call.
+var oauthHTTPClient = &http.Client{Timeout: 10 * time.Second, Transport: tracing.Transport(nil)}
+
+// exchangeContext makes oauth2 exchange codes through oauthHTTPClient.
+func exchangeContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, oauth2.HTTPClient, oauthHTTPClient)
+}
+
+// getOAuthJSON fetches url with the bearer token and returns the raw body.
+func getOAuthJSON(ctx context.Context, url string, token *oauth2.Token) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+
+	resp, err := oauthHTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("oauth user info: unexpected status %d", resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+type googleProvider struct {
+	config *oauth2.Config
+}
+
+func (p *googleProvider) AuthCodeURL(state string) string {
+	return p.config.AuthCodeURL(state, oauth2.AccessTypeOffline)
+}
+
+func (p *googleProvider) Exchange(ctx context.Context, code string) (*oauth2.Token, error) {
+	return p.config.Exchange(exchangeContext(ctx), code)
+}
+
+func (p *googleProvider) FetchProfile(ctx context.Context, token *oauth2.Token) (*OAuthProfile, error) {
+	data, err := getOAuthJSON(ctx, "https://www.googleapis.com/oauth2/v2/userinfo", token)
+	if err != nil {
+		return nil, err
+	}
+
+	var googleUser struct {
+		ID            string `json:"id"`
+		Email         string `json:"email"`
+		VerifiedEmail bool   `json:"verified_email"`
+		Name          string `json:"name"`
+		Picture       string `json:"picture"`
+	}
+	if err := json.Unmarshal(data, &googleUser); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrOAuthProfileInvalid, err)
+	}
+
+	return &OAuthProfile{
+		ID:        googleUser.ID,
+		Email:     googleUser.Email,
+		Name:      googleUser.Name,
+		AvatarURL: googleUser.Picture,
+		Raw:       string(data),
+	}, nil
+}
+
+type githubProvider struct {
+	config *oauth2.Config
+}
+
+func (p *githubProvider) AuthCodeURL(state string) string {
+	return p.config.AuthCodeURL(state)
+}
+
+func (p *githubProvider) Exchange(ctx context.Context, code string) (*oauth2.Token, error) {
+	return p.config.Exchange(exchangeContext(ctx), code)
+}
+
+func (p *githubProvider) FetchProfile(ctx context.Context, token *oauth2.Token) (*OAuthProfile, error) {
+	data, err := getOAuthJSON(ctx, "https://api.github.com/user", token)
+	if err != nil {
+		return nil, err
+	}
+
+	var githubUser struct {
+		ID        int64  `json:"id"`
+		Login     string `json:"login"`
+		Email     string `json:"email"`
+		Name      string `json:"name"`
+		AvatarURL string `json:"avatar_url"`
+	}
+	if err := json.Unmarshal(data, &githubUser); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrOAuthProfileInvalid, err)
+	}
+
+	// If email is not public, fall back to the primary verified address
+	if githubUser.Email == "" {
+		if emailData, err := getOAuthJSON(ctx, "https://api.github.com/user/emails", token); err == nil {
+			var emails []struct {
+				Email    string `json:"email"`
+				Primary  bool   `json:"primary"`
+				Verified bool   `json:"verified"`
+			}
+			if json.Unmarshal(emailData, &emails) == nil {
+				for _, email := range emails {
+					if email.Primary && email.Verified {
+						githubUser.Email = email.Email
+						break
+					}
+				}
+			}
+		}
+	}
+
+	// Use login as name if name is empty
+	if githubUser.Name == "" {
+		githubUser.Name = githubUser.Login
+	}
+
+	return &OAuthProfile{
+		ID:        fmt.Sprintf("%d", githubUser.ID),
+		Email:     githubUser.Email,
+		Name:      githubUser.Name,
+		AvatarURL: githubUser.AvatarURL,
+		Raw:       string(data),
+	}, nil
+}
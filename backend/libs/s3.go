@@ -0,0 +1,258 @@
+package libs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flower-backend/config"
+	"flower-backend/tracing"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+)
+
+// minPartSize is the smallest part S3 accepts in a multipart upload, other
+// than the last one.
+const minPartSize = 5 << 20
+
+// URLSigner hands out short-lived URLs for images kept in a private bucket.
+type URLSigner interface {
+	SignURL(ctx context.Context, key string, expiry time.Duration) (string, error)
+}
+
+// S3Storage keeps images in a bucket of any S3-compatible service. Objects
+// are keyed like Cloudinary public IDs, "flower-sharing/<publicId>", so
+// ExtractPublicId recovers the key from the URLs it hands out.
+type S3Storage struct {
+	client   *s3.Client
+	presign  *s3.PresignClient
+	bucket   string
+	partSize int64
+	// maxUploadSize caps what a signed direct upload may send
+	maxUploadSize int64
+	// baseURL is where images are served from: the public bucket URL, or
+	// the API route that redirects to signed URLs
+	baseURL string
+}
+
+// NewS3Storage returns a Storage backed by the configured bucket. Unless
+// S3PublicURL is set, the URLs it returns point at /api/v1/images, which
+// redirects to a signed URL; see SignURL.
+func NewS3Storage(cfg *config.Config) (*S3Storage, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("s3 config: cfg is nil")
+	}
+	if cfg.S3Bucket == "" {
+		return nil, fmt.Errorf("s3 config: bucket is not set")
+	}
+
+	options := s3.Options{
+		Region:       cfg.S3Region,
+		UsePathStyle: cfg.S3UsePathStyle,
+		Credentials:  credentials.NewStaticCredentialsProvider(cfg.S3AccessKeyID, cfg.S3SecretAccessKey, ""),
+		// trace object calls as children of the caller's span
+		HTTPClient: &http.Client{Transport: tracing.Transport(http.DefaultTransport)},
+		// most S3-compatible services reject the SDK's default trailing checksums
+		RequestChecksumCalculation: aws.RequestChecksumCalculationWhenRequired,
+		ResponseChecksumValidation: aws.ResponseChecksumValidationWhenRequired,
+	}
+	if cfg.S3Endpoint != "" {
+		options.BaseEndpoint = aws.String(cfg.S3Endpoint)
+	}
+	client := s3.New(options)
+
+	partSize := cfg.S3PartSize
+	if partSize < minPartSize {
+		partSize = minPartSize
+	}
+	maxUploadSize := cfg.DirectUploadMaxSize
+	if maxUploadSize <= 0 {
+		maxUploadSize = 10 << 20
+	}
+	baseURL := strings.TrimSuffix(cfg.S3PublicURL, "/")
+	if baseURL == "" {
+		baseURL = strings.TrimSuffix(cfg.APIBaseURL, "/") + "/api/v1/images"
+	}
+	return &S3Storage{
+		client:        client,
+		presign:       s3.NewPresignClient(client),
+		bucket:        cfg.S3Bucket,
+		partSize:      partSize,
+		maxUploadSize: maxUploadSize,
+		baseURL:       baseURL,
+	}, nil
+}
+
+func (s *S3Storage) key(publicId string) string {
+	return "flower-sharing/" + publicId
+}
+
+func (s *S3Storage) url(key string) string {
+	return s.baseURL + "/" + key
+}
+
+// Upload stores buffer under publicId, in parts when it is larger than the
+// configured part size.
+func (s *S3Storage) Upload(ctx context.Context, buffer []byte, publicId string) (string, error) {
+	key := s.key(publicId)
+	contentType := http.DetectContentType(buffer)
+	if int64(len(buffer)) > s.partSize {
+		if err := s.uploadParts(ctx, key, contentType, buffer); err != nil {
+			return "", fmt.Errorf("error uploading image to S3: %w", err)
+		}
+		return s.url(key), nil
+	}
+
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(s.bucket),
+		Key:           aws.String(key),
+		Body:          bytes.NewReader(buffer),
+		ContentLength: aws.Int64(int64(len(buffer))),
+		ContentType:   aws.String(contentType),
+	})
+	if err != nil {
+		return "", fmt.Errorf("error uploading image to S3: %w", err)
+	}
+	return s.url(key), nil
+}
+
+// uploadParts sends buffer as a multipart upload, aborting it on failure so
+// the bucket is not billed for orphaned parts.
+func (s *S3Storage) uploadParts(ctx context.Context, key, contentType string, buffer []byte) error {
+	created, err := s.client.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		ContentType: aws.String(contentType),
+	})
+	if err != nil {
+		return err
+	}
+
+	var parts []types.CompletedPart
+	for offset := int64(0); offset < int64(len(buffer)); offset += s.partSize {
+		part := buffer[offset:min(offset+s.partSize, int64(len(buffer)))]
+		number := int32(len(parts) + 1)
+		uploaded, err := s.client.UploadPart(ctx, &s3.UploadPartInput{
+			Bucket:        aws.String(s.bucket),
+			Key:           aws.String(key),
+			UploadId:      created.UploadId,
+			PartNumber:    aws.Int32(number),
+			Body:          bytes.NewReader(part),
+			ContentLength: aws.Int64(int64(len(part))),
+		})
+		if err != nil {
+			s.abort(ctx, key, created.UploadId)
+			return fmt.Errorf("part %d: %w", number, err)
+		}
+		parts = append(parts, types.CompletedPart{ETag: uploaded.ETag, PartNumber: aws.Int32(number)})
+	}
+
+	_, err = s.client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(s.bucket),
+		Key:             aws.String(key),
+		UploadId:        created.UploadId,
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: parts},
+	})
+	if err != nil {
+		s.abort(ctx, key, created.UploadId)
+	}
+	return err
+}
+
+func (s *S3Storage) abort(ctx context.Context, key string, uploadId *string) {
+	// the upload has already failed; a bucket lifecycle rule catches what this misses
+	_, _ = s.client.AbortMultipartUpload(context.WithoutCancel(ctx), &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(s.bucket),
+		Key:      aws.String(key),
+		UploadId: uploadId,
+	})
+}
+
+// Delete removes the object under publicId, which like Cloudinary's
+// includes the folder.
+func (s *S3Storage) Delete(ctx context.Context, publicId string) error {
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(publicId),
+	})
+	if err != nil {
+		return fmt.Errorf("error deleting image from S3: %w", err)
+	}
+	return nil
+}
+
+// SignUpload presigns a form POST of the object under publicId. Its policy
+// only admits an image/* Content-Type, which the client sends as a field
+// with the file, and at most maxUploadSize bytes, so a public bucket never
+// serves pages or large binaries uploaded in the images' name.
+func (s *S3Storage) SignUpload(ctx context.Context, publicId string, expiresAt time.Time) (*SignedUpload, error) {
+	request, err := s.presign.PresignPostObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key(publicId)),
+	}, func(options *s3.PresignPostOptions) {
+		options.Expires = time.Until(expiresAt)
+		options.Conditions = []any{
+			[]any{"starts-with", "$Content-Type", "image/"},
+			[]any{"content-length-range", 1, s.maxUploadSize},
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error signing S3 upload: %w", err)
+	}
+	return &SignedUpload{URL: request.URL, Method: http.MethodPost, Fields: request.Values, ExpiresAt: expiresAt}, nil
+}
+
+func (s *S3Storage) Stat(ctx context.Context, publicId string) (*Asset, error) {
+	key := s.key(publicId)
+	head, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrAssetNotFound
+		}
+		return nil, fmt.Errorf("error looking up S3 object: %w", err)
+	}
+	return &Asset{
+		PublicID: publicId,
+		URL:      s.url(key),
+		Bytes:    aws.ToInt64(head.ContentLength),
+		Format:   strings.TrimPrefix(aws.ToString(head.ContentType), "image/"),
+	}, nil
+}
+
+// SignURL returns a GET URL for the object under key valid for expiry.
+func (s *S3Storage) SignURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	request, err := s.presign.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	}, s3.WithPresignExpires(expiry))
+	if err != nil {
+		return "", fmt.Errorf("error signing S3 URL: %w", err)
+	}
+	return request.URL, nil
+}
+
+func (s *S3Storage) Ping(ctx context.Context) error {
+	if _, err := s.client.HeadBucket(ctx, &s3.HeadBucketInput{Bucket: aws.String(s.bucket)}); err != nil {
+		return fmt.Errorf("error pinging S3: %w", err)
+	}
+	return nil
+}
+
+// isNotFound reports whether err is S3 saying the object does not exist.
+func isNotFound(err error) bool {
+	var notFound *types.NotFound
+	var noSuchKey *types.NoSuchKey
+	var apiErr smithy.APIError
+	return errors.As(err, &notFound) || errors.As(err, &noSuchKey) ||
+		(errors.As(err, &apiErr) && apiErr.ErrorCode() == "NotFound")
+}
@@ -0,0 +1,356 @@
+package libs_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"flower-backend/libs"
+	"flower-backend/testutil"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const bucket = "flowers"
+
+var png = []byte("\x89PNG\r\n\x1a\n fake image")
+
+// fakeS3 serves the path-style object API for one bucket, enough for
+// S3Storage: plain and multipart uploads, HEAD, GET and DELETE.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	types   map[string]string
+	uploads map[string]map[int][]byte
+	parts   int
+}
+
+func newFakeS3(t *testing.T) (*fakeS3, *httptest.Server) {
+	f := &fakeS3{objects: map[string][]byte{}, types: map[string]string{}, uploads: map[string]map[int][]byte{}}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	return f, srv
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	name, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if name != bucket {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	// form uploads carry their signature and policy in the form
+	if r.Method == http.MethodPost && key == "" {
+		f.postObject(w, r)
+		return
+	}
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256") && r.URL.Query().Get("X-Amz-Signature") == "" {
+		http.Error(w, "unsigned request", http.StatusForbidden)
+		return
+	}
+	query := r.URL.Query()
+	body, _ := io.ReadAll(r.Body)
+
+	switch {
+	case r.Method == http.MethodHead && key == "":
+	case r.Method == http.MethodPost && query.Has("uploads"):
+		id := fmt.Sprintf("upload-%d", len(f.uploads)+1)
+		f.uploads[id] = map[int][]byte{}
+		f.types[key] = r.Header.Get("Content-Type")
+		fmt.Fprintf(w, "<InitiateMultipartUploadResult><Bucket>%s</Bucket><Key>%s</Key><UploadId>%s</UploadId></InitiateMultipartUploadResult>", bucket, key, id)
+	case r.Method == http.MethodPut && query.Has("uploadId"):
+		number, _ := strconv.Atoi(query.Get("partNumber"))
+		f.uploads[query.Get("uploadId")][number] = body
+		f.parts++
+		w.Header().Set("ETag", fmt.Sprintf(`"part-%d"`, number))
+	case r.Method == http.MethodPost && query.Has("uploadId"):
+		var complete struct {
+			Parts []struct {
+				PartNumber int
+			} `xml:"Part"`
+		}
+		if err := xml.Unmarshal(body, &complete); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		parts := f.uploads[query.Get("uploadId")]
+		sort.Slice(complete.Parts, func(i, j int) bool { return complete.Parts[i].PartNumber < complete.Parts[j].PartNumber })
+		var object []byte
+		for _, part := range complete.Parts {
+			object = append(object, parts[part.PartNumber]...)
+		}
+		f.objects[key] = object
+		delete(f.uploads, query.Get("uploadId"))
+		fmt.Fprintf(w, "<CompleteMultipartUploadResult><Bucket>%s</Bucket><Key>%s</Key><ETag>\"done\"</ETag></CompleteMultipartUploadResult>", bucket, key)
+	case r.Method == http.MethodPut:
+		f.objects[key] = body
+		f.types[key] = r.Header.Get("Content-Type")
+		w.Header().Set("ETag", `"object"`)
+	case r.Method == http.MethodHead || r.Method == http.MethodGet:
+		object, ok := f.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(object)))
+		w.Header().Set("Content-Type", f.types[key])
+		if r.Method == http.MethodGet {
+			w.Write(object)
+		}
+	case r.Method == http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "not implemented", http.StatusNotImplemented)
+	}
+}
+
+// postObject stores a form upload once the fields and file satisfy its
+// policy's conditions.
+func (f *fakeS3) postObject(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "missing file", http.StatusBadRequest)
+		return
+	}
+	object, _ := io.ReadAll(file)
+	field := func(name string) string {
+		for key, values := range r.MultipartForm.Value {
+			if strings.EqualFold(key, name) {
+				return values[0]
+			}
+		}
+		return ""
+	}
+	if field("X-Amz-Signature") == "" {
+		http.Error(w, "unsigned request", http.StatusForbidden)
+		return
+	}
+	encoded, _ := base64.StdEncoding.DecodeString(field("policy"))
+	var policy struct {
+		Conditions []any `json:"conditions"`
+	}
+	if err := json.Unmarshal(encoded, &policy); err != nil {
+		http.Error(w, "invalid policy", http.StatusBadRequest)
+		return
+	}
+	for _, condition := range policy.Conditions {
+		ok := true
+		switch c := condition.(type) {
+		case map[string]any:
+			for name, want := range c {
+				ok = ok && (name == "bucket" || field(name) == want)
+			}
+		case []any:
+			switch c[0] {
+			case "eq":
+				ok = field(strings.TrimPrefix(c[1].(string), "$")) == c[2]
+			case "starts-with":
+				ok = strings.HasPrefix(field(strings.TrimPrefix(c[1].(string), "$")), c[2].(string))
+			case "content-length-range":
+				size := float64(len(object))
+				ok = size >= c[1].(float64) && size <= c[2].(float64)
+			}
+		}
+		if !ok {
+			http.Error(w, fmt.Sprintf("policy condition failed: %v", condition), http.StatusForbidden)
+			return
+		}
+	}
+	f.objects[field("key")] = object
+	f.types[field("key")] = field("Content-Type")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// postForm sends file to a signed form upload with the given Content-Type.
+func postForm(t *testing.T, upload *libs.SignedUpload, contentType string, file []byte) *http.Response {
+	t.Helper()
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	for name, value := range upload.Fields {
+		form.WriteField(name, value)
+	}
+	form.WriteField("Content-Type", contentType)
+	part, _ := form.CreateFormFile("file", "upload")
+	part.Write(file)
+	form.Close()
+	resp, err := http.Post(upload.URL, form.FormDataContentType(), &body)
+	if err != nil {
+		t.Fatalf("signed upload: %v", err)
+	}
+	resp.Body.Close()
+	return resp
+}
+
+func newS3Storage(t *testing.T, endpoint, publicURL string) *libs.S3Storage {
+	t.Helper()
+	cfg := testutil.Config()
+	cfg.APIBaseURL = "https://api.test"
+	cfg.S3Endpoint = endpoint
+	cfg.S3Bucket = bucket
+	cfg.S3Region = "us-east-1"
+	cfg.S3AccessKeyID = "key"
+	cfg.S3SecretAccessKey = "secret"
+	cfg.S3UsePathStyle = true
+	cfg.S3PublicURL = publicURL
+	storage, err := libs.NewS3Storage(cfg)
+	if err != nil {
+		t.Fatalf("NewS3Storage: %v", err)
+	}
+	return storage
+}
+
+func TestS3StorageUploadStatDelete(t *testing.T) {
+	fake, srv := newFakeS3(t)
+	storage := newS3Storage(t, srv.URL, "https://cdn.test/")
+
+	url, err := storage.Upload(t.Context(), png, "post_image_1_1")
+	if err != nil {
+		t.Fatalf("Upload: %v", err)
+	}
+	if want := "https://cdn.test/flower-sharing/post_image_1_1"; url != want {
+		t.Errorf("url = %q, want %q", url, want)
+	}
+	asset, err := storage.Stat(t.Context(), "post_image_1_1")
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if asset.URL != url || asset.Bytes != int64(len(png)) || asset.Format != "png" {
+		t.Errorf("asset = %+v", asset)
+	}
+
+	if err := storage.Delete(t.Context(), libs.ExtractPublicId(url)); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if len(fake.objects) != 0 {
+		t.Errorf("objects after delete = %d, want 0", len(fake.objects))
+	}
+	if _, err := storage.Stat(t.Context(), "post_image_1_1"); !errors.Is(err, libs.ErrAssetNotFound) {
+		t.Errorf("Stat after delete error = %v, want ErrAssetNotFound", err)
+	}
+}
+
+func TestS3StorageUploadsLargeImagesInParts(t *testing.T) {
+	fake, srv := newFakeS3(t)
+	storage := newS3Storage(t, srv.URL, "https://cdn.test")
+
+	image := append(append([]byte(nil), png...), bytes.Repeat([]byte{7}, 11<<20)...)
+	if _, err := storage.Upload(t.Context(), image, "large"); err != nil {
+		t.Fatalf("Upload: %v", err)
+	}
+	if fake.parts != 3 {
+		t.Errorf("uploaded %d parts, want 3 of at most 5 MiB", fake.parts)
+	}
+	if !bytes.Equal(fake.objects["flower-sharing/large"], image) {
+		t.Error("the assembled object differs from the image")
+	}
+}
+
+func TestS3StorageSignedURLs(t *testing.T) {
+	_, srv := newFakeS3(t)
+	storage := newS3Storage(t, srv.URL, "")
+
+	// a private bucket's images are served through the API's redirect
+	url, err := storage.Upload(t.Context(), png, "private")
+	if err != nil {
+		t.Fatalf("Upload: %v", err)
+	}
+	if want := "https://api.test/api/v1/images/flower-sharing/private"; url != want {
+		t.Errorf("url = %q, want %q", url, want)
+	}
+	signed, err := storage.SignURL(t.Context(), libs.ExtractPublicId(url), time.Minute)
+	if err != nil {
+		t.Fatalf("SignURL: %v", err)
+	}
+	resp, err := http.Get(signed)
+	if err != nil {
+		t.Fatalf("GET signed URL: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !bytes.Equal(body, png) {
+		t.Errorf("GET signed URL = %d %q", resp.StatusCode, body)
+	}
+
+	// a client uploads straight to the bucket with the signed parameters
+	upload, err := storage.SignUpload(t.Context(), "direct", time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("SignUpload: %v", err)
+	}
+	if resp := postForm(t, upload, "image/png", png); resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("signed upload status = %d", resp.StatusCode)
+	}
+	if _, err := storage.Stat(t.Context(), "direct"); err != nil {
+		t.Errorf("Stat after signed upload: %v", err)
+	}
+}
+
+func TestS3StorageSignedUploadsOnlyTakeImages(t *testing.T) {
+	fake, srv := newFakeS3(t)
+	cfg := testutil.Config()
+	cfg.S3Endpoint = srv.URL
+	cfg.S3Bucket = bucket
+	cfg.S3Region = "us-east-1"
+	cfg.S3AccessKeyID = "key"
+	cfg.S3SecretAccessKey = "secret"
+	cfg.S3UsePathStyle = true
+	cfg.DirectUploadMaxSize = 1 << 10
+	storage, err := libs.NewS3Storage(cfg)
+	if err != nil {
+		t.Fatalf("NewS3Storage: %v", err)
+	}
+	upload, err := storage.SignUpload(t.Context(), "direct", time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("SignUpload: %v", err)
+	}
+
+	for name, tt := range map[string]struct {
+		contentType string
+		file        []byte
+	}{
+		"html page":       {"text/html", []byte("<script>alert(1)</script>")},
+		"oversized image": {"image/png", bytes.Repeat([]byte{7}, 2<<10)},
+	} {
+		if resp := postForm(t, upload, tt.contentType, tt.file); resp.StatusCode != http.StatusForbidden {
+			t.Errorf("%s upload status = %d, want 403", name, resp.StatusCode)
+		}
+	}
+	if len(fake.objects) != 0 {
+		t.Errorf("stored %d objects, want none", len(fake.objects))
+	}
+}
+
+func TestS3StoragePing(t *testing.T) {
+	_, srv := newFakeS3(t)
+	if err := newS3Storage(t, srv.URL, "").Ping(t.Context()); err != nil {
+		t.Errorf("Ping: %v", err)
+	}
+
+	cfg := testutil.Config()
+	cfg.S3Endpoint = srv.URL
+	cfg.S3Bucket = "missing"
+	cfg.S3UsePathStyle = true
+	missing, err := libs.NewS3Storage(cfg)
+	if err != nil {
+		t.Fatalf("NewS3Storage: %v", err)
+	}
+	if err := missing.Ping(t.Context()); err == nil {
+		t.Error("Ping of a missing bucket succeeded")
+	}
+}
@@ -0,0 +1,79 @@
+package libs
+
+import (
+	"context"
+	"errors"
+	"flower-backend/config"
+	"flower-backend/metrics"
+	"time"
+)
+
+// ErrAssetNotFound is returned by Stat when nothing is stored under the
+// public ID.
+var ErrAssetNotFound = errors.New("asset not found")
+
+// Storage keeps uploaded images and returns the URL they are served from.
+// Public IDs are the caller-chosen names that ExtractPublicId recovers from
+// those URLs. ctx bounds the call and carries the caller's trace.
+type Storage interface {
+	Upload(ctx context.Context, buffer []byte, publicId string) (string, error)
+	Delete(ctx context.Context, publicId string) error
+	// SignUpload returns the parameters a client needs to upload an image
+	// under publicId directly to the backend, valid until about expiresAt.
+	SignUpload(ctx context.Context, publicId string, expiresAt time.Time) (*SignedUpload, error)
+	// Stat describes the image stored under publicId, or returns
+	// ErrAssetNotFound.
+	Stat(ctx context.Context, publicId string) (*Asset, error)
+	// Ping reports whether the backend is reachable, for readiness checks.
+	Ping(ctx context.Context) error
+}
+
+// SignedUpload tells a client how to upload an image itself: send Method to
+// URL with Headers, and for a form upload the Fields along with the file
+// and a Content-Type field naming its image type.
+type SignedUpload struct {
+	URL       string            `json:"url"`
+	Method    string            `json:"method"`
+	Fields    map[string]string `json:"fields,omitempty"`
+	Headers   map[string]string `json:"headers,omitempty"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
+
+// Asset is an image found in storage.
+type Asset struct {
+	PublicID string
+	URL      string
+	Bytes    int64
+	Format   string
+}
+
+// NewCloudinaryStorage returns a Storage backed by the configured Cloudinary account.
+func NewCloudinaryStorage(cfg *config.Config) (Storage, error) {
+	cld, err := NewCloudinary(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &cloudinaryStorage{cld: cld}, nil
+}
+
+// instrumentedStorage records upload sizes and durations around another Storage.
+type instrumentedStorage struct {
+	Storage
+	metrics *metrics.Metrics
+}
+
+func NewInstrumentedStorage(s Storage, m *metrics.Metrics) Storage {
+	return &instrumentedStorage{Storage: s, metrics: m}
+}
+
+func (s *instrumentedStorage) Upload(ctx context.Context, buffer []byte, publicId string) (string, error) {
+	start := time.Now()
+	url, err := s.Storage.Upload(ctx, buffer, publicId)
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	s.metrics.ImageUploadDuration.WithLabelValues(result).Observe(time.Since(start).Seconds())
+	s.metrics.ImageUploadBytes.Observe(float64(len(buffer)))
+	return url, err
+}
@@ -0,0 +1,49 @@
+package log
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type loggerKey struct{}
+
+// WithLogger returns a copy of ctx carrying logger.
+func WithLogger(ctx context.Context, logger *zap.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext returns the logger carried by ctx, or fallback when there is none.
+func FromContext(ctx context.Context, fallback *zap.Logger) *zap.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*zap.Logger); ok {
+		return logger
+	}
+	return fallback
+}
+
+// SugarFromContext is FromContext for code that logs through a SugaredLogger.
+func SugarFromContext(ctx context.Context, fallback *zap.SugaredLogger) *zap.SugaredLogger {
+	if logger, ok := ctx.Value(loggerKey{}).(*zap.Logger); ok {
+		return logger.Sugar()
+	}
+	return fallback
+}
+
+// With adds fields to the logger carried by ctx. A ctx without a logger is
+// returned unchanged.
+func With(ctx context.Context, fields ...zap.Field) context.Context {
+	logger, ok := ctx.Value(loggerKey{}).(*zap.Logger)
+	if !ok {
+		return ctx
+	}
+	return WithLogger(ctx, logger.With(fields...))
+}
+
+// ForRequest derives a logger for a request that began at start. Loggers
+// built by InitLog stamp every line it writes with the time elapsed since
+// then as "latency".
+func ForRequest(logger *zap.Logger, start time.Time) *zap.Logger {
+	return logger.With(zap.Field{Key: "latency", Type: zapcore.SkipType, Interface: requestStart(start)})
+}
@@ -0,0 +1,142 @@
+package log
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const redacted = "[REDACTED]"
+
+// sensitiveKeys are field names whose values are never written.
+var sensitiveKeys = map[string]bool{
+	"password":      true,
+	"password_hash": true,
+	"token":         true,
+	"access_token":  true,
+	"accesstoken":   true,
+	"refresh_token": true,
+	"refreshtoken":  true,
+	"authorization": true,
+	"cookie":        true,
+	"secret":        true,
+}
+
+var (
+	emailPattern  = regexp.MustCompile(`([A-Za-z0-9._%+-])[A-Za-z0-9._%+-]*@([A-Za-z0-9.-]+\.[A-Za-z]{2,})`)
+	bearerPattern = regexp.MustCompile(`(?i)bearer\s+[A-Za-z0-9\-._~+/]+=*`)
+	jwtPattern    = regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`)
+)
+
+// requestStart marks the start of a request; see ForRequest.
+type requestStart time.Time
+
+// appCore is the leaf core built by InitLog. It redacts credentials and
+// email addresses from messages and fields, and stamps request loggers
+// with their latency. It sits beneath any sampler so sampling still applies.
+type appCore struct {
+	zapcore.Core
+	start time.Time
+}
+
+func (c *appCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := &appCore{start: c.start}
+	kept := make([]zapcore.Field, 0, len(fields))
+	for _, f := range fields {
+		if start, ok := f.Interface.(requestStart); ok && f.Type == zapcore.SkipType {
+			clone.start = time.Time(start)
+			continue
+		}
+		kept = append(kept, f)
+	}
+	clone.Core = c.Core.With(redactFields(kept))
+	return clone
+}
+
+func (c *appCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *appCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	entry.Message = redactString(entry.Message)
+	fields = redactFields(fields)
+	if !c.start.IsZero() {
+		fields = append(fields, zap.Duration("latency", time.Since(c.start)))
+	}
+	return c.Core.Write(entry, fields)
+}
+
+func redactFields(fields []zapcore.Field) []zapcore.Field {
+	out := make([]zapcore.Field, len(fields))
+	for i, f := range fields {
+		out[i] = redactField(f)
+	}
+	return out
+}
+
+func redactField(f zapcore.Field) zapcore.Field {
+	key := strings.ToLower(f.Key)
+	switch {
+	case sensitiveKeys[key]:
+		return zap.String(f.Key, redacted)
+	case f.Type == zapcore.StringType:
+		f.String = redactString(f.String)
+		return f
+	case f.Type == zapcore.ReflectType && f.Interface != nil:
+		return zap.Any(f.Key, redactValue(f.Interface))
+	}
+	return f
+}
+
+// redactString masks email addresses down to their first letter and domain
+// and drops bearer tokens and JWTs.
+func redactString(s string) string {
+	if strings.Contains(s, "@") {
+		s = emailPattern.ReplaceAllString(s, "$1***@$2")
+	}
+	s = bearerPattern.ReplaceAllString(s, "Bearer "+redacted)
+	return jwtPattern.ReplaceAllString(s, redacted)
+}
+
+// redactValue round-trips an arbitrary value through JSON so nested
+// sensitive keys, such as a user's email, are redacted too.
+func redactValue(v any) any {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return redacted
+	}
+	var decoded any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return redacted
+	}
+	return redactJSON(decoded)
+}
+
+func redactJSON(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if sensitiveKeys[strings.ToLower(key)] {
+				v[key] = redacted
+			} else {
+				v[key] = redactJSON(value)
+			}
+		}
+		return v
+	case []any:
+		for i, value := range v {
+			v[i] = redactJSON(value)
+		}
+		return v
+	case string:
+		return redactString(v)
+	}
+	return v
+}
@@ -38,6 +38,11 @@ func myEncodeLevel(level zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
 	}
 }
 
+// Clone keeps the prefix and log files on loggers derived with With.
+func (e *logEncoder) Clone() zapcore.Encoder {
+	return &logEncoder{Encoder: e.Encoder.Clone(), errFile: e.errFile, file: e.file, currentDate: e.currentDate}
+}
+
 func (e *logEncoder) EncodeEntry(entry zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
 	buff, err := e.Encoder.EncodeEntry(entry, fields)
 	if err != nil {
@@ -80,38 +85,42 @@ func (e *logEncoder) EncodeEntry(entry zapcore.Entry, fields []zapcore.Field) (*
 	return buff, nil
 }
 
-func InitLog() *zap.Logger {
-	config := config.LoadConfig()
-
-	if config.GO_ENV == "development" {
+// InitLog builds the application logger. LOG_LEVEL sets the minimum level;
+// with LOG_DEBUG_SAMPLING on, debug lines are sampled per message so hot
+// paths cannot flood the output. Credentials and email addresses are
+// redacted from every line.
+func InitLog(appCfg *config.Config) *zap.Logger {
+	var encoder zapcore.Encoder
+	if appCfg.GO_ENV == "development" {
 		cfg := zap.NewDevelopmentConfig()
 		cfg.EncoderConfig.EncodeTime = zapcore.TimeEncoderOfLayout("2006-01-02 15:04:05")
 		cfg.EncoderConfig.EncodeLevel = myEncodeLevel
-
-		encoder := &logEncoder{
+		encoder = &logEncoder{
 			Encoder: zapcore.NewConsoleEncoder(cfg.EncoderConfig),
 		}
-		core := zapcore.NewCore(
-			encoder,
-			zapcore.AddSync(os.Stdout),
-			zapcore.InfoLevel,
-		)
-		logger := zap.New(core, zap.AddCaller())
-
-		zap.ReplaceGlobals(logger)
-		return logger
 	} else {
 		cfg := zap.NewProductionConfig()
 		cfg.EncoderConfig.EncodeTime = zapcore.TimeEncoderOfLayout("2006-01-02 15:04:05")
 		cfg.EncoderConfig.EncodeLevel = myEncodeLevel
-		encoder := zapcore.NewJSONEncoder(cfg.EncoderConfig)
-		core := zapcore.NewCore(
-			encoder,
-			zapcore.AddSync(os.Stdout),
-			zapcore.InfoLevel,
-		)
-		logger := zap.New(core, zap.AddCaller())
-		zap.ReplaceGlobals(logger)
-		return logger
+		encoder = zapcore.NewJSONEncoder(cfg.EncoderConfig)
 	}
+
+	level, err := zapcore.ParseLevel(appCfg.LogLevel)
+	if err != nil {
+		level = zapcore.InfoLevel
+	}
+	sink := zapcore.AddSync(os.Stdout)
+
+	// debug lines go through their own core so only they are sampled
+	var debug zapcore.Core = &appCore{Core: zapcore.NewCore(encoder, sink, zap.LevelEnablerFunc(func(l zapcore.Level) bool {
+		return l == zapcore.DebugLevel && level <= zapcore.DebugLevel
+	}))}
+	if appCfg.LogDebugSampling {
+		debug = zapcore.NewSamplerWithOptions(debug, time.Second, 10, 100)
+	}
+	rest := &appCore{Core: zapcore.NewCore(encoder, sink, zap.LevelEnablerFunc(func(l zapcore.Level) bool {
+		return l > zapcore.DebugLevel && l >= level
+	}))}
+
+	return zap.New(zapcore.NewTee(rest, debug), zap.AddCaller())
 }
@@ -0,0 +1,93 @@
+package log
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func newObserved() (*zap.Logger, *observer.ObservedLogs) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	return zap.New(&appCore{Core: core}), logs
+}
+
+func TestRedactsSensitiveFields(t *testing.T) {
+	logger, logs := newObserved()
+	user := map[string]any{"id": 7, "email": "alice@example.com", "password": "hunter2"}
+
+	logger.Info("login for bob@example.com with Bearer abc.def",
+		zap.String("password", "hunter2"),
+		zap.String("Authorization", "Bearer abc"),
+		zap.String("note", "contact carol@example.org"),
+		zap.Any("user", user),
+	)
+
+	entry := logs.All()[0]
+	if entry.Message != "login for b***@example.com with Bearer [REDACTED]" {
+		t.Errorf("message = %q", entry.Message)
+	}
+	fields := entry.ContextMap()
+	if fields["password"] != redacted || fields["Authorization"] != redacted {
+		t.Errorf("credentials not redacted: %v", fields)
+	}
+	if fields["note"] != "contact c***@example.org" {
+		t.Errorf("note = %q", fields["note"])
+	}
+	logged := fields["user"].(map[string]any)
+	if logged["password"] != redacted || logged["email"] != "a***@example.com" {
+		t.Errorf("user = %v", logged)
+	}
+	if user["password"] != "hunter2" {
+		t.Error("redaction modified the caller's value")
+	}
+}
+
+func TestRedactsFieldsAddedWithWith(t *testing.T) {
+	logger, logs := newObserved()
+	logger.With(zap.String("token", "secret-value")).Info("refreshed")
+
+	if got := logs.All()[0].ContextMap()["token"]; got != redacted {
+		t.Errorf("token = %v", got)
+	}
+}
+
+func TestForRequestStampsLatency(t *testing.T) {
+	logger, logs := newObserved()
+	start := time.Now().Add(-50 * time.Millisecond)
+
+	ForRequest(logger, start).With(zap.String("request_id", "req-1")).Info("done")
+
+	fields := logs.All()[0].ContextMap()
+	if fields["request_id"] != "req-1" {
+		t.Errorf("request_id = %v", fields["request_id"])
+	}
+	latency, ok := fields["latency"].(time.Duration)
+	if !ok || latency < 50*time.Millisecond {
+		t.Errorf("latency = %v, want at least 50ms", fields["latency"])
+	}
+}
+
+func TestContextCarriesLogger(t *testing.T) {
+	fallback, fallbackLogs := newObserved()
+	logger, logs := newObserved()
+
+	ctx := context.Background()
+	if With(ctx, zap.Uint("user_id", 1)) != ctx {
+		t.Error("With changed a context that carries no logger")
+	}
+	FromContext(ctx, fallback).Info("fallback")
+
+	ctx = With(WithLogger(ctx, logger), zap.Uint("user_id", 42))
+	SugarFromContext(ctx, fallback.Sugar()).Info("scoped")
+
+	if fallbackLogs.Len() != 1 || logs.Len() != 1 {
+		t.Fatalf("fallback wrote %d lines, scoped wrote %d", fallbackLogs.Len(), logs.Len())
+	}
+	if got := logs.All()[0].ContextMap()["user_id"]; got != uint64(42) {
+		t.Errorf("user_id = %v", got)
+	}
+}
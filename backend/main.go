@@ -2,14 +2,18 @@ package main
 
 import (
 	"context"
+	"flower-backend/app"
+	"flower-backend/cache"
 	"flower-backend/config"
 	"flower-backend/database"
 	"flower-backend/log"
+	"flower-backend/metrics"
 	"flower-backend/middlewares"
+	"flower-backend/migrations"
 	"flower-backend/models"
-	user_repository "flower-backend/repositories/v1/user"
 	v1Routes "flower-backend/routes/v1"
 	"flower-backend/tasks"
+	"flower-backend/tracing"
 	"flower-backend/utils"
 	"net/http"
 	"os"
@@ -23,6 +27,8 @@ import (
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gorm.io/gorm"
 )
 
 //	@title			Flower Sharing API
@@ -45,38 +51,92 @@ import (
 //	@description				Type "Bearer" followed by a space and JWT token.
 
 func main() {
-	// log
-	logger := log.InitLog()
-	defer logger.Sync()
 	// config
 	cfg := config.LoadConfig()
+	// log
+	logger := log.InitLog(cfg)
 
+	code := runCommand(cfg, logger, os.Args[1:])
+	logger.Sync()
+	os.Exit(code)
+}
+
+// runServe runs the HTTP server until SIGINT or SIGTERM.
+func runServe(cfg *config.Config, logger *zap.Logger, args []string) int {
 	// Update Swagger host dynamically based on environment
 	utils.UpdateSwaggerHost(cfg.APIBaseURL)
 
 	logger.Info("starting server")
+	// tracing; a no-op unless TRACING_EXPORTER selects an exporter
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg)
+	if err != nil {
+		logger.Error("failed to set up tracing", zap.Error(err))
+		return 1
+	}
 	// db
-	database.ConnectDB(cfg, logger)
-	db := database.DB
+	db, err := database.ConnectDB(cfg, logger)
+	if err != nil {
+		logger.Error("failed to connect to database", zap.Error(err))
+		return 1
+	}
+	if err := tracing.InstrumentDB(db); err != nil {
+		logger.Error("failed to instrument database", zap.Error(err))
+		return 1
+	}
 
-	if err := db.AutoMigrate(&models.User{}, &models.Post{}, &models.Token{}); err != nil {
+	// counters added to an existing database start at zero and need a recount
+	needsRecount := db.Migrator().HasTable(&models.Post{}) && !db.Migrator().HasColumn(&models.Post{}, "likes_count")
+
+	// apply pending migrations; the lock lets replicas start together
+	migrator, err := migrations.NewMigrator(db, logger)
+	if err != nil {
+		logger.Error("failed to load migrations", zap.Error(err))
+		return 1
+	}
+	if _, err := migrator.Up(); err != nil {
 		logger.Error("failed to migrate database", zap.Error(err))
-		os.Exit(1)
+		return 1
 	}
 	logger.Info("database migrated")
 
-	// periodic cleanup of expired refresh tokens to prevent bloat
-	userRepo := user_repository.NewUserRepository(db, cfg, logger.Sugar())
-	tasks.StartTokenCleanup(userRepo, logger)
+	a, err := app.New(cfg, logger, db)
+	if err != nil {
+		logger.Error("failed to initialize app", zap.Error(err))
+		return 1
+	}
+
+	if needsRecount {
+		if err := tasks.RecountCounters(a.Posts, a.Users, logger); err != nil {
+			return 1
+		}
+	}
+
+	// periodic tasks such as token cleanup and like aggregation; one
+	// replica at a time runs them
+	a.Scheduler.Start()
+	// background jobs, such as deleting images of removed posts
+	a.Jobs.Start()
 	// gin setup
 	r := gin.New()
+	// server span first so everything after it joins the request's trace
+	r.Use(middlewares.Tracing(cfg.TracingServiceName))
 	// attach request id early for tracing
 	r.Use(middlewares.RequestID(logger))
-	r.Use(ginzap.Ginzap(logger, time.RFC3339, true))
+	// request latency and in-flight count for /metrics
+	r.Use(middlewares.Metrics(a.Metrics))
+	r.Use(ginzap.GinzapWithConfig(logger, &ginzap.Config{
+		TimeFormat:   time.RFC3339,
+		UTC:          true,
+		DefaultLevel: zapcore.InfoLevel,
+		SkipPaths:    middlewares.ProbePaths,
+		Context: func(c *gin.Context) []zapcore.Field {
+			return tracing.LogFields(c.Request.Context())
+		},
+	}))
 	r.Use(ginzap.RecoveryWithZap(logger, true))
 	// http logger
 	if cfg.GO_ENV == "production" {
-		r.Use(middlewares.HttpLogger)
+		r.Use(middlewares.HttpLogger(logger))
 	}
 	// panic recovery
 	r.Use(func(c *gin.Context) {
@@ -90,10 +150,10 @@ func main() {
 	})
 
 	// Request timeout middleware - prevents resource exhaustion
-	r.Use(middlewares.TimeoutByRoute(logger))
+	r.Use(middlewares.TimeoutByRoute(logger, a.Metrics))
 
 	// helmet - security headers including XSS protection
-	r.Use(middlewares.Helmet())
+	r.Use(middlewares.Helmet(cfg))
 
 	// XSS Protection - sanitize JSON input
 	r.Use(middlewares.XSSProtection(logger))
@@ -105,23 +165,29 @@ func main() {
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     cfg.AllowOrigins,
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"},
-		AllowHeaders:     []string{"Content-Type", "Authorization", "X-Requested-With", "Accept", "Origin", "X-CSRF-Token", "X-CSRFToken"},
-		ExposeHeaders:    []string{"Content-Length", "Content-Type"},
+		AllowHeaders:     []string{"Content-Type", "Authorization", "X-Requested-With", "Accept", "Origin", "X-CSRF-Token", "X-CSRFToken", "If-None-Match", "If-Modified-Since"},
+		ExposeHeaders:    []string{"Content-Length", "Content-Type", "ETag", "Last-Modified"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
 
 	// CSRF protection - double submit cookie for unsafe methods
-	r.Use(middlewares.CSRFProtection(cfg, logger))
+	r.Use(middlewares.CSRFProtection(cfg, logger, a.Metrics))
+
+	// Rate limiter: 60 requests per minute per IP; image redirects have their own
+	r.Use(middlewares.RateLimiter(logger, a.Metrics))
 
-	// Rate limiter: 60 requests per minute per IP
-	r.Use(middlewares.RateLimiter())
+	// span per controller call, inside the middlewares above
+	r.Use(middlewares.TraceController())
 
 	// Swagger documentation route
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	// Setup routes
-	v1Routes.Routes(r)
+	v1Routes.Routes(r, a)
+
+	// Prometheus metrics, on their own listener or behind a token
+	metricsSrv := serveMetrics(cfg, r, a.Metrics, logger)
 
 	port := cfg.Port
 	if port == "" {
@@ -164,6 +230,14 @@ func main() {
 
 	logger.Info("shutting down server...")
 
+	// Fail readiness first and keep serving while load balancers notice,
+	// so in-flight and newly routed requests are not refused
+	a.Health.Drain()
+	if cfg.ShutdownDrainDelay > 0 {
+		logger.Info("draining", zap.Duration("delay", cfg.ShutdownDrainDelay))
+		time.Sleep(cfg.ShutdownDrainDelay)
+	}
+
 	// Graceful shutdown with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -171,8 +245,56 @@ func main() {
 	if err := srv.Shutdown(ctx); err != nil {
 		logger.Error("server forced to shutdown", zap.Error(err))
 	}
+	if metricsSrv != nil {
+		if err := metricsSrv.Shutdown(ctx); err != nil {
+			logger.Error("metrics server forced to shutdown", zap.Error(err))
+		}
+	}
+	// cancel running tasks and hand the lease to another replica
+	if err := a.Scheduler.Shutdown(ctx); err != nil {
+		logger.Error("scheduler forced to shutdown", zap.Error(err))
+	}
+	// let running jobs finish; those cut off are requeued for the next instance
+	if err := a.Jobs.Shutdown(ctx); err != nil {
+		logger.Error("job queue forced to shutdown", zap.Error(err))
+	}
+
+	handleServerShutdown(db, a.Cache, logger)
+	if err := shutdownTracing(ctx); err != nil {
+		logger.Error("failed to flush traces", zap.Error(err))
+	}
+	return 0
+}
+
+// serveMetrics exposes /metrics. With METRICS_ADDR set it starts a separate
+// listener, which it returns for shutdown; otherwise the endpoint is mounted
+// on r and only answers requests carrying METRICS_TOKEN. With neither set the
+// endpoint stays off rather than publishing metrics to everyone.
+func serveMetrics(cfg *config.Config, r *gin.Engine, m *metrics.Metrics, logger *zap.Logger) *http.Server {
+	handler := m.Handler(cfg.MetricsToken)
+	if cfg.MetricsAddr == "" {
+		if cfg.MetricsToken == "" {
+			logger.Info("metrics endpoint disabled; set METRICS_ADDR or METRICS_TOKEN to enable it")
+			return nil
+		}
+		r.GET("/metrics", gin.WrapH(handler))
+		return nil
+	}
 
-	handleServerShutdown(logger)
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", handler)
+	srv := &http.Server{
+		Addr:              cfg.MetricsAddr,
+		Handler:           mux,
+		ReadHeaderTimeout: cfg.ReadTimeout,
+	}
+	go func() {
+		logger.Info("metrics listening on " + cfg.MetricsAddr)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Error("failed to start metrics server", zap.Error(err))
+		}
+	}()
+	return srv
 }
 
 /**
@@ -181,15 +303,16 @@ func main() {
  * - Attempts to disconnect from the database before shutting down the server.
  * - Logs a success message if the disconnection is successful.
  * - If an error occurs during disconnection, it is logged to the console.
- * - Exits the process with status code `0` (indicating a successful shutdown).
  */
-func handleServerShutdown(logger *zap.Logger) {
+func handleServerShutdown(db *gorm.DB, store cache.Cache, logger *zap.Logger) {
+	if err := cache.Close(store); err != nil {
+		logger.Error("failed to close cache", zap.Error(err))
+	}
 	logger.Info("disconnecting from database...")
-	if err := database.DisconnectDB(logger); err != nil {
+	if err := database.DisconnectDB(db, logger); err != nil {
 		logger.Error("failed to disconnect from database", zap.Error(err))
 	} else {
 		logger.Info("database disconnected successfully")
 	}
 	logger.Warn("server shutdown")
-	os.Exit(0)
 }
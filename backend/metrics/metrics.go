@@ -0,0 +1,205 @@
+// Package metrics holds the Prometheus collectors exposed on /metrics. The
+// App builds one Metrics and passes it to the layers that record into it.
+package metrics
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	"flower-backend/cache"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "flower"
+
+// Metrics holds the Prometheus collectors of one App. Each instance has its
+// own registry, so tests can run several side by side.
+type Metrics struct {
+	// Registry holds every collector served by Handler
+	Registry *prometheus.Registry
+
+	// HTTPRequestDuration observes handler latency by route template and status
+	HTTPRequestDuration *prometheus.HistogramVec
+
+	// HTTPRequestsInFlight counts requests currently being served
+	HTTPRequestsInFlight prometheus.Gauge
+
+	// RateLimitRejections counts requests refused by the per-IP rate limiter
+	RateLimitRejections prometheus.Counter
+
+	// CSRFFailures counts unsafe requests without a matching CSRF token
+	CSRFFailures prometheus.Counter
+
+	// RequestTimeouts counts requests aborted by TimeoutByRoute
+	RequestTimeouts *prometheus.CounterVec
+
+	// ImageUploadDuration observes how long image uploads to storage take
+	ImageUploadDuration *prometheus.HistogramVec
+
+	// ImageUploadBytes observes the size of uploaded images
+	ImageUploadBytes prometheus.Histogram
+
+	// TokenCleanupRuns counts runs of the expired token cleanup by result
+	TokenCleanupRuns *prometheus.CounterVec
+
+	// TokenCleanupDeleted counts refresh tokens removed by the cleanup
+	TokenCleanupDeleted prometheus.Counter
+
+	// JobRuns counts job attempts by type and result: success, retry or dead
+	JobRuns *prometheus.CounterVec
+
+	// JobDuration observes how long job attempts take
+	JobDuration *prometheus.HistogramVec
+
+	// TaskRuns counts scheduled task runs by task and result: success or error
+	TaskRuns *prometheus.CounterVec
+
+	// TaskDuration observes how long scheduled task runs take
+	TaskDuration *prometheus.HistogramVec
+
+	// SchedulerLeader is 1 while this instance holds the scheduler lease
+	SchedulerLeader prometheus.Gauge
+}
+
+// New builds a fresh set of collectors, along with the Go runtime, process
+// and cache counters.
+func New() *Metrics {
+	registry := prometheus.NewRegistry()
+	factory := promauto.With(registry)
+	m := &Metrics{
+		Registry: registry,
+		HTTPRequestDuration: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "Time spent serving HTTP requests.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		HTTPRequestsInFlight: factory.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_in_flight",
+			Help:      "Number of HTTP requests currently being served.",
+		}),
+		RateLimitRejections: factory.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "rate_limit_rejections_total",
+			Help:      "Requests rejected by the rate limiter.",
+		}),
+		CSRFFailures: factory.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "csrf_failures_total",
+			Help:      "Requests rejected by CSRF validation.",
+		}),
+		RequestTimeouts: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_timeouts_total",
+			Help:      "Requests aborted because they exceeded their route timeout.",
+		}, []string{"method", "route"}),
+		ImageUploadDuration: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "storage",
+			Name:      "upload_duration_seconds",
+			Help:      "Time spent uploading images to storage.",
+			Buckets:   []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120},
+		}, []string{"result"}),
+		ImageUploadBytes: factory.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "storage",
+			Name:      "upload_size_bytes",
+			Help:      "Size of images uploaded to storage.",
+			Buckets:   prometheus.ExponentialBuckets(16*1024, 2, 10), // 16KiB to 8MiB
+		}),
+		TokenCleanupRuns: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "tasks",
+			Name:      "token_cleanup_runs_total",
+			Help:      "Runs of the expired refresh token cleanup.",
+		}, []string{"result"}),
+		TokenCleanupDeleted: factory.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "tasks",
+			Name:      "token_cleanup_deleted_total",
+			Help:      "Expired refresh tokens deleted by the cleanup.",
+		}),
+		JobRuns: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "jobs",
+			Name:      "runs_total",
+			Help:      "Background job attempts by result.",
+		}, []string{"type", "result"}),
+		JobDuration: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "jobs",
+			Name:      "duration_seconds",
+			Help:      "Time spent running background jobs.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"type"}),
+		TaskRuns: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "scheduler",
+			Name:      "runs_total",
+			Help:      "Scheduled task runs by result.",
+		}, []string{"task", "result"}),
+		TaskDuration: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "scheduler",
+			Name:      "duration_seconds",
+			Help:      "Time spent running scheduled tasks.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"task"}),
+		SchedulerLeader: factory.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "scheduler",
+			Name:      "leader",
+			Help:      "Whether this instance runs the scheduled tasks.",
+		}),
+	}
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	factory.NewCounterFunc(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "cache",
+		Name:      "hits_total",
+		Help:      "Cache lookups that found an entry.",
+	}, func() float64 { return float64(cache.GetStats().Hits) })
+	factory.NewCounterFunc(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "cache",
+		Name:      "misses_total",
+		Help:      "Cache lookups that found nothing.",
+	}, func() float64 { return float64(cache.GetStats().Misses) })
+	return m
+}
+
+// RegisterDB exposes the connection pool statistics of db.
+func (m *Metrics) RegisterDB(db *sql.DB, name string) error {
+	return m.Registry.Register(collectors.NewDBStatsCollector(db, name))
+}
+
+// Handler serves the registry in the Prometheus text format. A non-empty
+// token must be presented as a Bearer token.
+func (m *Metrics) Handler(token string) http.Handler {
+	handler := promhttp.HandlerFor(m.Registry, promhttp.HandlerOpts{Registry: m.Registry})
+	if token == "" {
+		return handler
+	}
+	expected := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="metrics"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,70 @@
+package metrics_test
+
+import (
+	"flower-backend/metrics"
+	"flower-backend/middlewares"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	promtest "github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestHandlerRequiresToken(t *testing.T) {
+	handler := metrics.New().Handler("scrape-secret")
+
+	tests := []struct {
+		name       string
+		auth       string
+		wantStatus int
+	}{
+		{name: "no token", wantStatus: http.StatusUnauthorized},
+		{name: "wrong token", auth: "Bearer nope", wantStatus: http.StatusUnauthorized},
+		{name: "valid token", auth: "Bearer scrape-secret", wantStatus: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestMetricsMiddlewareLabelsByRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	m := metrics.New()
+	r := gin.New()
+	r.Use(middlewares.Metrics(m))
+	r.GET("/post/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for _, path := range []string{"/post/1", "/post/2", "/missing"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	byRoute := promtest.CollectAndCount(m.HTTPRequestDuration)
+	if byRoute != 2 {
+		t.Errorf("%d request duration series, want 2 (one route template, one unmatched)", byRoute)
+	}
+
+	rec := httptest.NewRecorder()
+	m.Handler("").ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := rec.Body.String()
+	for _, want := range []string{
+		`flower_http_request_duration_seconds_count{method="GET",route="/post/:id",status="200"} 2`,
+		`flower_http_request_duration_seconds_count{method="GET",route="unmatched",status="404"} 1`,
+		"flower_http_requests_in_flight 0",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics output missing %q", want)
+		}
+	}
+}
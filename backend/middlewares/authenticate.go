@@ -3,58 +3,102 @@ package middlewares
 import (
 	"errors"
 	"flower-backend/libs"
+	"flower-backend/log"
+	user_repository "flower-backend/repositories/v1/user"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
-func Authenticate(c *gin.Context) {
-	authHeader := c.GetHeader("Authorization")
-	if !strings.HasPrefix(authHeader, "Bearer ") {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Access denied"})
-		c.Abort()
-		return
-	}
+// Authenticate rejects requests without a valid access token and sets
+// user_id for the handlers that follow. The account is looked up through
+// users on every request, so suspending or deleting it takes effect before
+// its access tokens expire.
+func Authenticate(tokens *libs.JWT, users user_repository.UserRepository, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		if !strings.HasPrefix(authHeader, "Bearer ") {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Access denied"})
+			c.Abort()
+			return
+		}
+
+		token := strings.TrimPrefix(authHeader, "Bearer ")
+
+		userId, err := tokens.VerifyAccessToken(token)
+		if err != nil {
+			// Check if it's a token expiration error
+			if errors.Is(err, jwt.ErrTokenExpired) {
+				c.JSON(http.StatusUnauthorized, gin.H{
+					"code":    "AuthenticationError",
+					"message": "Access token expired, request a new one with refresh token",
+				})
+				c.Abort()
+				return
+			}
 
-	token := strings.TrimPrefix(authHeader, "Bearer ")
+			// Check if it's a general validation error (invalid token)
+			if errors.Is(err, jwt.ErrTokenMalformed) || errors.Is(err, jwt.ErrTokenSignatureInvalid) {
+				c.JSON(http.StatusUnauthorized, gin.H{
+					"code":    "AuthenticationError",
+					"message": "Access token invalid",
+				})
+				c.Abort()
+				return
+			}
 
-	userId, err := libs.VerifyAccessToken(token)
-	if err != nil {
-		// Check if it's a token expiration error
-		if errors.Is(err, jwt.ErrTokenExpired) {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"code":    "AuthenticationError",
-				"message": "Access token expired, request a new one with refresh token",
+			// Catch-all for other errors
+			log.FromContext(c.Request.Context(), logger).Error("Error during authentication", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"code":    "ServerError",
+				"message": "Internal server error",
+				"error":   err.Error(),
 			})
 			c.Abort()
 			return
 		}
 
-		// Check if it's a general validation error (invalid token)
-		if errors.Is(err, jwt.ErrTokenMalformed) || errors.Is(err, jwt.ErrTokenSignatureInvalid) {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"code":    "AuthenticationError",
-				"message": "Access token invalid",
+		user, err := users.WithContext(c.Request.Context()).GetAuthByID(userId)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusUnauthorized, gin.H{
+					"code":    "AuthenticationError",
+					"message": "Access token invalid",
+				})
+				c.Abort()
+				return
+			}
+
+			log.FromContext(c.Request.Context(), logger).Error("Error while loading authenticated user", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"code":    "ServerError",
+				"message": "Internal server error",
+			})
+			c.Abort()
+			return
+		}
+		if user.Suspended {
+			c.JSON(http.StatusForbidden, gin.H{
+				"code":    "AccountSuspended",
+				"message": "Account is suspended",
 			})
 			c.Abort()
 			return
 		}
 
-		// Catch-all for other errors
-		zap.L().Error("Error during authentication", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"code":    "ServerError",
-			"message": "Internal server error",
-			"error":   err.Error(),
-		})
-		c.Abort()
-		return
+		setUser(c, userId)
+		log.FromContext(c.Request.Context(), logger).Info("User authenticated")
+		c.Next()
 	}
+}
 
+// setUser records the authenticated user for the handlers that follow and
+// adds it to the request's logger.
+func setUser(c *gin.Context, userId uint) {
 	c.Set("user_id", userId)
-	zap.L().Info("User authenticated", zap.Uint("user_id", userId))
-	c.Next()
+	c.Request = c.Request.WithContext(log.With(c.Request.Context(), zap.Uint("user_id", userId)))
 }
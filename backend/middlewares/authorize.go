@@ -1,7 +1,7 @@
 package middlewares
 
 import (
-	"flower-backend/database"
+	"flower-backend/log"
 	"flower-backend/models"
 	"net/http"
 
@@ -10,7 +10,9 @@ import (
 	"gorm.io/gorm"
 )
 
-func Authorize(roles []string) gin.HandlerFunc {
+// Authorize lets through only active users whose role is in roles. It runs
+// after Authenticate and reads the role fresh from db on every request.
+func Authorize(db *gorm.DB, logger *zap.Logger, roles []string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userId, exists := c.Get("user_id")
 		if !exists {
@@ -24,7 +26,7 @@ func Authorize(roles []string) gin.HandlerFunc {
 
 		userIdUint, ok := userId.(uint)
 		if !ok {
-			zap.L().Error("Error while authorizing user: invalid userId type")
+			log.FromContext(c.Request.Context(), logger).Error("Error while authorizing user: invalid userId type")
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"code":    "ServerError",
 				"message": "Internal server error",
@@ -35,7 +37,7 @@ func Authorize(roles []string) gin.HandlerFunc {
 
 		// Fetch user from database
 		var user models.User
-		if err := database.DB.Select("role").Where("id = ?", userIdUint).First(&user).Error; err != nil {
+		if err := db.Select("role", "suspended").Where("id = ?", userIdUint).First(&user).Error; err != nil {
 			if err == gorm.ErrRecordNotFound {
 				c.JSON(http.StatusNotFound, gin.H{
 					"code":    "NotFound",
@@ -46,7 +48,7 @@ func Authorize(roles []string) gin.HandlerFunc {
 			}
 
 			// Catch-all for other database errors
-			zap.L().Error("Error while authorizing user", zap.Error(err))
+			log.FromContext(c.Request.Context(), logger).Error("Error while authorizing user", zap.Error(err))
 			c.JSON(http.StatusInternalServerError, gin.H{
 				"code":    "ServerError",
 				"message": "Internal server error",
@@ -56,6 +58,15 @@ func Authorize(roles []string) gin.HandlerFunc {
 			return
 		}
 
+		if user.Suspended {
+			c.JSON(http.StatusForbidden, gin.H{
+				"code":    "AccountSuspended",
+				"message": "Account is suspended",
+			})
+			c.Abort()
+			return
+		}
+
 		// Check if user's role is in allowed roles
 		roleMap := make(map[string]bool, len(roles))
 		for _, role := range roles {
@@ -3,6 +3,8 @@ package middlewares
 import (
 	"flower-backend/config"
 	"flower-backend/libs"
+	"flower-backend/log"
+	"flower-backend/metrics"
 	"net/http"
 	"time"
 
@@ -19,7 +21,7 @@ const (
 // - Issues a non-HttpOnly SameSite cookie with a random token (24h TTL) when missing.
 // - Requires the same token to be sent in the X-CSRF-Token header for unsafe methods.
 // - Skips safe methods: GET, HEAD, OPTIONS.
-func CSRFProtection(cfg *config.Config, logger *zap.Logger) gin.HandlerFunc {
+func CSRFProtection(cfg *config.Config, logger *zap.Logger, m *metrics.Metrics) gin.HandlerFunc {
 	secure := cfg.GO_ENV == "production"
 	maxAge := int((24 * time.Hour).Seconds())
 
@@ -49,7 +51,8 @@ func CSRFProtection(cfg *config.Config, logger *zap.Logger) gin.HandlerFunc {
 		}
 
 		if headerToken == "" || headerToken != csrfToken {
-			logger.Warn("csrf token validation failed",
+			m.CSRFFailures.Inc()
+			log.FromContext(c.Request.Context(), logger).Warn("csrf token validation failed",
 				zap.String("ip", c.ClientIP()),
 				zap.String("path", c.FullPath()),
 				zap.String("method", method),
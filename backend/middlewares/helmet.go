@@ -6,10 +6,8 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func Helmet() gin.HandlerFunc {
+func Helmet(cfg *config.Config) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		cfg := config.LoadConfig()
-
 		// Prevent clickjacking attacks by disallowing embedding in iframes
 		c.Header("X-Frame-Options", "DENY")
 
@@ -50,7 +48,8 @@ func Helmet() gin.HandlerFunc {
 		// Prevent DNS prefetching
 		c.Header("X-DNS-Prefetch-Control", "off")
 
-		// Prevent browser caching of sensitive data
+		// Prevent browser caching of sensitive data. Routes that may be
+		// cached (HTTPCache, images) replace these headers.
 		if c.Request.URL.Path != "/swagger/*any" {
 			c.Header("Cache-Control", "no-store, no-cache, must-revalidate, proxy-revalidate")
 			c.Header("Pragma", "no-cache")
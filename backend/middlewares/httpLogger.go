@@ -1,6 +1,8 @@
 package middlewares
 
 import (
+	"flower-backend/log"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -9,29 +11,32 @@ import (
 	"go.uber.org/zap"
 )
 
-var skipRoutes string = "/api/v1"
+// API requests are logged by RequestID; static assets are not worth a line.
+var (
+	skipRoutes    = "/api/v1"
+	staticAssetRE = regexp.MustCompile(`\.(jpg|jpeg|png|gif|svg|ico|woff2|css|js)$`)
+)
 
-func HttpLogger(c *gin.Context) {
-	path := c.Request.URL.Path
+func HttpLogger(logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		path := c.Request.URL.Path
+		if strings.HasPrefix(path, skipRoutes) || staticAssetRE.MatchString(path) {
+			c.Next()
+			return
+		}
 
-	if strings.Contains(c.Request.URL.Path, skipRoutes) {
+		start := time.Now()
 		c.Next()
-		return
-	}
 
-	start := time.Now()
-	method := c.Request.Method
-	ip := c.ClientIP()
-	userAgent := c.Request.UserAgent()
-	statusCode := strconv.Itoa(c.Writer.Status())
-	protocol := c.Request.Proto
-	hostname := c.Request.Host
-	duration := time.Since(start)
-
-	if strings.Contains(c.Request.URL.Path, `/\.(jpg|jpeg|png|gif|svg|ico|woff2|css|js)$/`) {
-		return
+		log.FromContext(c.Request.Context(), logger).Info("HTTP request",
+			zap.String("path", path),
+			zap.String("method", c.Request.Method),
+			zap.String("statusCode", strconv.Itoa(c.Writer.Status())),
+			zap.String("ip", c.ClientIP()),
+			zap.String("userAgent", c.Request.UserAgent()),
+			zap.String("protocol", c.Request.Proto),
+			zap.String("hostname", c.Request.Host),
+			zap.Duration("duration", time.Since(start)),
+		)
 	}
-
-	zap.L().Info("HTTP request", zap.String("path", path), zap.String("method", method), zap.String("statusCode", statusCode), zap.String("ip", ip), zap.String("userAgent", userAgent), zap.String("statusCode", statusCode), zap.String("protocol", protocol), zap.String("hostname", hostname), zap.Duration("duration", duration))
-	c.Next()
 }
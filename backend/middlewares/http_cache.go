@@ -0,0 +1,67 @@
+package middlewares
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bufferedWriter holds the response body so headers derived from it can
+// still be set before anything reaches the client.
+type bufferedWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+// HTTPCache sets a Cache-Control policy on successful GET responses, and an
+// ETag hashed from the body unless the handler already set its own.
+// Authenticated responses carry viewer-specific flags, so they are private
+// and must be revalidated; anonymous ones may be shared for maxAge. The
+// no-cache headers Helmet sets on every response are dropped from these.
+func HTTPCache(maxAge time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+
+		original := c.Writer
+		writer := &bufferedWriter{ResponseWriter: original}
+		c.Writer = writer
+		c.Next()
+		c.Writer = original
+
+		header := original.Header()
+		header.Add("Vary", "Authorization")
+		switch original.Status() {
+		case http.StatusOK, http.StatusNotModified:
+			if header.Get("ETag") == "" && original.Status() == http.StatusOK {
+				sum := sha256.Sum256(writer.body.Bytes())
+				header.Set("ETag", `"`+hex.EncodeToString(sum[:16])+`"`)
+			}
+			header.Del("Pragma")
+			header.Del("Expires")
+			if c.GetHeader("Authorization") != "" {
+				header.Set("Cache-Control", "private, no-cache")
+			} else {
+				header.Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(maxAge.Seconds())))
+			}
+		default:
+			header.Set("Cache-Control", "no-store")
+		}
+		original.Write(writer.body.Bytes())
+	}
+}
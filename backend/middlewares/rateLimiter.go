@@ -1,9 +1,12 @@
 package middlewares
 
 import (
+	"flower-backend/log"
+	"flower-backend/metrics"
 	"flower-backend/utils"
 	"net/http"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -25,31 +28,50 @@ type rateLimiterEntry struct {
 	expires  time.Time
 }
 
-var (
-	limiter *rateLimiter
-	once    sync.Once
-)
-
 // RateLimiter returns a Gin middleware that limits requests per IP
 // 1-minute time window for request limiting
 // Allow a maximum of 60 requests per window per IP
 // Use the latest standard rate-limit headers (RFC 7239)
 // Disable deprecated X-RateLimit headers
-func RateLimiter() gin.HandlerFunc {
-	once.Do(func() {
-		limiter = &rateLimiter{
-			requests: make(map[string]*rateLimiterEntry),
-			limit:    60,
-			window:   1 * time.Minute,
-			cleanup:  time.NewTicker(5 * time.Minute),
-			ttl:      15 * time.Minute,
-		}
-
-		// Start cleanup goroutine to remove old entries
-		go limiter.cleanupOldEntries()
+// Each call keeps its own counters, so separate engines do not share limits.
+// Image redirects are left to ImageRateLimiter.
+func RateLimiter(logger *zap.Logger, m *metrics.Metrics) gin.HandlerFunc {
+	return newRateLimiter(60, logger, m, func(path string) bool {
+		// probes come from a few addresses and would exhaust their budget
+		return isProbePath(path) || strings.HasPrefix(path, ImagePathPrefix)
 	})
+}
+
+// ImagePathPrefix is where images kept in a private bucket are served.
+const ImagePathPrefix = "/api/v1/images/"
+
+// ImageRateLimiter limits image redirects per IP with a budget of their own:
+// every <img> on a page is one request, so a single feed page would use up
+// most of the API's.
+func ImageRateLimiter(logger *zap.Logger, m *metrics.Metrics) gin.HandlerFunc {
+	return newRateLimiter(600, logger, m, nil)
+}
+
+// newRateLimiter allows limit requests per minute per IP, except on paths
+// skip reports true for.
+func newRateLimiter(limit int, logger *zap.Logger, m *metrics.Metrics, skip func(path string) bool) gin.HandlerFunc {
+	limiter := &rateLimiter{
+		requests: make(map[string]*rateLimiterEntry),
+		limit:    limit,
+		window:   1 * time.Minute,
+		cleanup:  time.NewTicker(5 * time.Minute),
+		ttl:      15 * time.Minute,
+	}
+
+	// Start cleanup goroutine to remove old entries
+	go limiter.cleanupOldEntries()
 
 	return func(c *gin.Context) {
+		if skip != nil && skip(c.Request.URL.Path) {
+			c.Next()
+			return
+		}
+
 		ip := c.ClientIP()
 		now := time.Now()
 
@@ -78,7 +100,7 @@ func RateLimiter() gin.HandlerFunc {
 			resetTime := oldestRequest.Add(limiter.window)
 
 			// Set standard rate-limit headers (RFC 7239)
-			c.Header("RateLimit-Limit", "60")
+			c.Header("RateLimit-Limit", strconv.Itoa(limiter.limit))
 			c.Header("RateLimit-Remaining", "0")
 			c.Header("RateLimit-Reset", utils.FormatUnixTime(resetTime.Unix()))
 
@@ -89,7 +111,8 @@ func RateLimiter() gin.HandlerFunc {
 			}
 			c.Header("Retry-After", strconv.Itoa(retryAfter))
 
-			zap.L().Warn("rate limit exceeded",
+			m.RateLimitRejections.Inc()
+			log.FromContext(c.Request.Context(), logger).Warn("rate limit exceeded",
 				zap.String("ip", ip),
 				zap.Int("count", count),
 				zap.Time("reset_time", resetTime),
@@ -120,7 +143,7 @@ func RateLimiter() gin.HandlerFunc {
 		}
 
 		// Set standard rate-limit headers (RFC 7239)
-		c.Header("RateLimit-Limit", "60")
+		c.Header("RateLimit-Limit", strconv.Itoa(limiter.limit))
 		c.Header("RateLimit-Remaining", strconv.Itoa(remaining))
 		c.Header("RateLimit-Reset", utils.FormatUnixTime(resetTime.Unix()))
 
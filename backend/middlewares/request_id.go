@@ -1,10 +1,14 @@
 package middlewares
 
 import (
+	"flower-backend/log"
+	"flower-backend/tracing"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
@@ -24,17 +28,33 @@ func RequestID(baseLogger *zap.Logger) gin.HandlerFunc {
 		c.Writer.Header().Set(requestIDHeader, reqID)
 		c.Set(requestIDKey, reqID)
 
-		// Use a request-scoped logger
-		logger := baseLogger.With(zap.String(requestIDKey, reqID))
-		c.Set("logger", logger)
+		// Tie the trace to the request id
+		trace.SpanFromContext(c.Request.Context()).SetAttributes(attribute.String("request.id", reqID))
 
+		// Request-scoped logger carrying the request and trace ids and the
+		// route; it travels in the request context so services and
+		// repositories log with it too. Authentication adds the user id.
 		start := time.Now()
+		logger := log.ForRequest(baseLogger, start).With(
+			append([]zap.Field{
+				zap.String(requestIDKey, reqID),
+				zap.String("method", c.Request.Method),
+				zap.String("route", c.FullPath()),
+			}, tracing.LogFields(c.Request.Context())...)...,
+		)
+		c.Request = c.Request.WithContext(log.WithLogger(c.Request.Context(), logger))
+		c.Set("logger", logger)
+
 		c.Next()
 
-		// Minimal completion log with request id
-		logger.Info("request completed",
-			zap.String("method", c.Request.Method),
-			zap.String("path", c.FullPath()),
+		// Passing probes would drown out real traffic
+		if isProbePath(c.Request.URL.Path) && c.Writer.Status() < 400 {
+			return
+		}
+
+		// Completion log through the request's final logger, which by now
+		// carries the user id if the request was authenticated
+		log.FromContext(c.Request.Context(), logger).Info("request completed",
 			zap.Int("status", c.Writer.Status()),
 			zap.Duration("duration", time.Since(start)),
 			zap.String("client_ip", c.ClientIP()),
@@ -2,6 +2,8 @@ package middlewares
 
 import (
 	"context"
+	"flower-backend/log"
+	"flower-backend/metrics"
 	"net/http"
 	"time"
 
@@ -30,6 +32,19 @@ func DefaultTimeoutConfig(logger *zap.Logger) TimeoutConfig {
 	}
 }
 
+// ProbePaths are polled every few seconds by load balancers and
+// orchestrators; they are exempt from rate limiting, tracing and access logs.
+var ProbePaths = []string{"/livez", "/readyz"}
+
+func isProbePath(path string) bool {
+	for _, probe := range ProbePaths {
+		if path == probe {
+			return true
+		}
+	}
+	return false
+}
+
 func isSwaggerPath(path string) bool {
 	return path == SwaggerIndexPath ||
 		path == SwaggerDocPath ||
@@ -64,7 +79,7 @@ func RequestTimeout(config TimeoutConfig) gin.HandlerFunc {
 			return
 		case <-ctx.Done():
 			if ctx.Err() == context.DeadlineExceeded {
-				config.Logger.Warn("request timeout",
+				log.FromContext(c.Request.Context(), config.Logger).Warn("request timeout",
 					zap.String("method", c.Request.Method),
 					zap.String("path", c.Request.URL.Path),
 					zap.String("ip", c.ClientIP()),
@@ -109,7 +124,7 @@ func ContextTimeoutMiddleware(timeout time.Duration, logger *zap.Logger) gin.Han
 		c.Next()
 
 		if ctx.Err() == context.DeadlineExceeded {
-			logger.Warn("request context deadline exceeded",
+			log.FromContext(c.Request.Context(), logger).Warn("request context deadline exceeded",
 				zap.String("method", c.Request.Method),
 				zap.String("path", c.Request.URL.Path),
 				zap.String("ip", c.ClientIP()),
@@ -120,7 +135,7 @@ func ContextTimeoutMiddleware(timeout time.Duration, logger *zap.Logger) gin.Han
 }
 
 // TimeoutByRoute returns different timeout durations based on route patterns
-func TimeoutByRoute(logger *zap.Logger) gin.HandlerFunc {
+func TimeoutByRoute(logger *zap.Logger, m *metrics.Metrics) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if isSwaggerPath(c.Request.URL.Path) {
 			c.Next()
@@ -165,7 +180,8 @@ func TimeoutByRoute(logger *zap.Logger) gin.HandlerFunc {
 			return
 		case <-ctx.Done():
 			if ctx.Err() == context.DeadlineExceeded {
-				logger.Warn("request timeout",
+				m.RequestTimeouts.WithLabelValues(c.Request.Method, c.FullPath()).Inc()
+				log.FromContext(c.Request.Context(), logger).Warn("request timeout",
 					zap.String("method", c.Request.Method),
 					zap.String("path", c.Request.URL.Path),
 					zap.String("ip", c.ClientIP()),
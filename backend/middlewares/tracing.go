@@ -0,0 +1,47 @@
+package middlewares
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/otel"
+)
+
+var controllerTracer = otel.Tracer("flower-backend/controllers")
+
+// Tracing starts a server span for each request, continuing the trace from
+// an incoming W3C traceparent header. Swagger, /metrics and the probes are
+// not traced.
+func Tracing(service string) gin.HandlerFunc {
+	return otelgin.Middleware(service, otelgin.WithFilter(func(r *http.Request) bool {
+		return !isSwaggerPath(r.URL.Path) && !isProbePath(r.URL.Path) && r.URL.Path != "/metrics"
+	}))
+}
+
+// TraceController opens a child span named after the handler that serves
+// the route, so controller time is visible apart from the middlewares.
+func TraceController() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.FullPath() == "" {
+			c.Next()
+			return
+		}
+		ctx, span := controllerTracer.Start(c.Request.Context(), controllerName(c.HandlerName()))
+		defer span.End()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// controllerName turns a handler name such as
+// "flower-backend/controllers/v1/post.PostController.GetPostByID-fm"
+// into "post.PostController.GetPostByID".
+func controllerName(handler string) string {
+	if i := strings.LastIndex(handler, "/"); i >= 0 {
+		handler = handler[i+1:]
+	}
+	handler = strings.TrimSuffix(handler, "-fm")
+	return strings.NewReplacer("(*", "", ")", "").Replace(handler)
+}
@@ -3,6 +3,7 @@ package middlewares
 import (
 	"bytes"
 	"encoding/json"
+	"flower-backend/log"
 	"flower-backend/utils"
 	"io"
 	"net/http"
@@ -21,7 +22,7 @@ func XSSProtection(logger *zap.Logger) gin.HandlerFunc {
 			// Read the body
 			bodyBytes, err := io.ReadAll(c.Request.Body)
 			if err != nil {
-				logger.Error("failed to read request body", zap.Error(err))
+				log.FromContext(c.Request.Context(), logger).Error("failed to read request body", zap.Error(err))
 				c.Next()
 				return
 			}
@@ -39,7 +40,7 @@ func XSSProtection(logger *zap.Logger) gin.HandlerFunc {
 
 			// Check for XSS patterns
 			if containsXSS(data) {
-				logger.Warn("XSS attack attempt detected",
+				log.FromContext(c.Request.Context(), logger).Warn("XSS attack attempt detected",
 					zap.String("ip", c.ClientIP()),
 					zap.String("path", c.Request.URL.Path),
 					zap.String("method", c.Request.Method),
@@ -56,7 +57,7 @@ func XSSProtection(logger *zap.Logger) gin.HandlerFunc {
 			// Marshal back to JSON
 			sanitizedBytes, err := json.Marshal(sanitized)
 			if err != nil {
-				logger.Error("failed to marshal sanitized data", zap.Error(err))
+				log.FromContext(c.Request.Context(), logger).Error("failed to marshal sanitized data", zap.Error(err))
 				c.Next()
 				return
 			}
@@ -0,0 +1,47 @@
+package migrations
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var nameSanitizer = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Create writes an empty up/down pair to dir, numbered after the highest
+// version already there, and returns the paths written.
+func Create(dir, name string) (string, string, error) {
+	name = strings.Trim(nameSanitizer.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" {
+		return "", "", fmt.Errorf("migration name is required")
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", "", err
+	}
+	var next int64 = 1
+	for _, entry := range entries {
+		match := fileNamePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		if version, _ := strconv.ParseInt(match[1], 10, 64); version >= next {
+			next = version + 1
+		}
+	}
+
+	base := fmt.Sprintf("%04d_%s", next, name)
+	upPath := filepath.Join(dir, base+".up.sql")
+	downPath := filepath.Join(dir, base+".down.sql")
+	if err := os.WriteFile(upPath, []byte("-- "+base+" up\n"), 0o644); err != nil {
+		return "", "", err
+	}
+	if err := os.WriteFile(downPath, []byte("-- "+base+" down\n"), 0o644); err != nil {
+		return "", "", err
+	}
+	return upPath, downPath, nil
+}
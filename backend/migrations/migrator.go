@@ -0,0 +1,322 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flower-backend/models"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	lockName    = "flower_schema_migrations"
+	lockTimeout = 60 // seconds to wait for another instance to finish
+)
+
+// baselineVersion is the migration that reproduces the schema AutoMigrate
+// used to create; databases created that way are adopted at this version.
+const baselineVersion = 1
+
+var ErrLockTimeout = errors.New("timed out waiting for the migration lock")
+
+// schemaMigration records one applied migration.
+type schemaMigration struct {
+	Version   int64     `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"size:255;not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// MigrationStatus describes a known migration and whether it is applied.
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	AppliedAt *time.Time
+}
+
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+	logger     *zap.Logger
+}
+
+func NewMigrator(db *gorm.DB, logger *zap.Logger) (*Migrator, error) {
+	migrations, err := Load(db.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations, logger: logger}, nil
+}
+
+// Up applies every pending migration in order and returns how many ran.
+func (m *Migrator) Up() (int, error) {
+	applied := 0
+	err := m.withLock(func() error {
+		done, err := m.appliedVersions()
+		if err != nil {
+			return err
+		}
+		if len(done) == 0 {
+			adopted, err := m.adoptLegacySchema()
+			if err != nil {
+				return err
+			}
+			if adopted {
+				if done, err = m.appliedVersions(); err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := done[migration.Version]; ok {
+				continue
+			}
+			if err := m.apply(migration, migration.Up, true); err != nil {
+				return err
+			}
+			applied++
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down rolls back the latest steps applied migrations and returns how many ran.
+func (m *Migrator) Down(steps int) (int, error) {
+	reverted := 0
+	err := m.withLock(func() error {
+		done, err := m.appliedVersions()
+		if err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0 && reverted < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := done[migration.Version]; !ok {
+				continue
+			}
+			if migration.Down == "" {
+				return fmt.Errorf("migration %d_%s cannot be rolled back", migration.Version, migration.Name)
+			}
+			if err := m.apply(migration, migration.Down, false); err != nil {
+				return err
+			}
+			reverted++
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Status lists every known migration with the time it was applied, if any.
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	if err := m.ensureTable(); err != nil {
+		return nil, err
+	}
+	done, err := m.appliedVersions()
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if appliedAt, ok := done[migration.Version]; ok {
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Pending reports how many known migrations have not been applied. Unlike
+// Status it never creates schema_migrations, so it is safe to poll.
+func (m *Migrator) Pending(ctx context.Context) (int, error) {
+	db := m.db.WithContext(ctx)
+	if !db.Migrator().HasTable(&schemaMigration{}) {
+		return len(m.migrations), nil
+	}
+	var versions []int64
+	if err := db.Model(&schemaMigration{}).Pluck("version", &versions).Error; err != nil {
+		return 0, err
+	}
+	done := make(map[int64]bool, len(versions))
+	for _, version := range versions {
+		done[version] = true
+	}
+	pending := 0
+	for _, migration := range m.migrations {
+		if !done[migration.Version] {
+			pending++
+		}
+	}
+	return pending, nil
+}
+
+// apply runs one direction of a migration and updates schema_migrations.
+// MySQL commits DDL implicitly, so a failure part-way through a file can
+// leave earlier statements applied; keep each migration small.
+func (m *Migrator) apply(migration Migration, script string, up bool) error {
+	direction := "down"
+	if up {
+		direction = "up"
+	}
+	start := time.Now()
+	err := m.db.Transaction(func(tx *gorm.DB) error {
+		for _, statement := range splitStatements(script) {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+		if up {
+			return tx.Create(&schemaMigration{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now()}).Error
+		}
+		return tx.Delete(&schemaMigration{}, migration.Version).Error
+	})
+	if err != nil {
+		m.logger.Error("failed to run migration",
+			zap.Int64("version", migration.Version),
+			zap.String("name", migration.Name),
+			zap.String("direction", direction),
+			zap.Error(err))
+		return fmt.Errorf("migration %d_%s %s: %w", migration.Version, migration.Name, direction, err)
+	}
+	m.logger.Info("migration applied",
+		zap.Int64("version", migration.Version),
+		zap.String("name", migration.Name),
+		zap.String("direction", direction),
+		zap.Duration("duration", time.Since(start)))
+	return nil
+}
+
+// adoptLegacySchema handles databases created by AutoMigrate before
+// versioned migrations existed: it brings them up to the baseline shape one
+// last time and records the baseline as applied instead of re-creating it.
+func (m *Migrator) adoptLegacySchema() (bool, error) {
+	if !m.db.Migrator().HasTable(&models.User{}) {
+		return false, nil
+	}
+	m.logger.Info("adopting schema created by AutoMigrate", zap.Int("baseline_version", baselineVersion))
+	if err := m.db.AutoMigrate(&models.User{}, &baselinePost{}, &models.Token{}, &models.PostLike{}, &models.UserFollow{}, &models.PostReport{}, &models.PostView{}, &models.Tag{}, &models.PostLikeBucket{}); err != nil {
+		m.logger.Error("failed to bring legacy schema up to baseline", zap.Error(err))
+		return false, err
+	}
+	for _, migration := range m.migrations {
+		if migration.Version > baselineVersion {
+			break
+		}
+		record := schemaMigration{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now()}
+		if err := m.db.Create(&record).Error; err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// baselinePost is the posts table as of the baseline. Adopting with
+// models.Post would also add the columns later migrations add themselves.
+type baselinePost struct {
+	ID         uint   `gorm:"primaryKey"`
+	Title      string `gorm:"not null"`
+	Content    string `gorm:"not null"`
+	ImageURL   string
+	Hidden     bool  `gorm:"default:false;index"`
+	LikesCount int64 `gorm:"not null;default:0"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	UserID     uint `gorm:"not null"`
+}
+
+func (baselinePost) TableName() string {
+	return "posts"
+}
+
+func (m *Migrator) ensureTable() error {
+	if m.db.Migrator().HasTable(&schemaMigration{}) {
+		return nil
+	}
+	return m.db.Migrator().CreateTable(&schemaMigration{})
+}
+
+func (m *Migrator) appliedVersions() (map[int64]time.Time, error) {
+	var records []schemaMigration
+	if err := m.db.Find(&records).Error; err != nil {
+		return nil, err
+	}
+	done := make(map[int64]time.Time, len(records))
+	for _, record := range records {
+		done[record.Version] = record.AppliedAt
+	}
+	return done, nil
+}
+
+// withLock runs fn while holding a database-wide advisory lock, so replicas
+// starting together migrate one at a time and the rest find nothing to do.
+func (m *Migrator) withLock(fn func() error) error {
+	dialect := m.db.Dialector.Name()
+	if dialect != "mysql" && dialect != "postgres" {
+		// SQLite serialises writers on the file itself
+		if err := m.ensureTable(); err != nil {
+			return err
+		}
+		return fn()
+	}
+
+	sqlDB, err := m.db.DB()
+	if err != nil {
+		return err
+	}
+	// Advisory locks are bound to the session, so hold one connection throughout
+	ctx := context.Background()
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if dialect == "mysql" {
+		var acquired sql.NullInt64
+		if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", lockName, lockTimeout).Scan(&acquired); err != nil {
+			return err
+		}
+		if !acquired.Valid || acquired.Int64 != 1 {
+			return ErrLockTimeout
+		}
+		defer conn.ExecContext(ctx, "SELECT RELEASE_LOCK(?)", lockName)
+	} else {
+		if err := tryAdvisoryLock(ctx, conn); err != nil {
+			return err
+		}
+		defer conn.ExecContext(ctx, "SELECT pg_advisory_unlock(hashtext($1))", lockName)
+	}
+
+	if err := m.ensureTable(); err != nil {
+		return err
+	}
+	return fn()
+}
+
+// tryAdvisoryLock polls pg_try_advisory_lock until it succeeds or lockTimeout
+// passes, mirroring the wait semantics of MySQL's GET_LOCK.
+func tryAdvisoryLock(ctx context.Context, conn *sql.Conn) error {
+	deadline := time.Now().Add(lockTimeout * time.Second)
+	for {
+		var acquired bool
+		if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock(hashtext($1))", lockName).Scan(&acquired); err != nil {
+			return err
+		}
+		if acquired {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return ErrLockTimeout
+		}
+		time.Sleep(time.Second)
+	}
+}
@@ -0,0 +1,137 @@
+package migrations_test
+
+import (
+	"flower-backend/database"
+	"flower-backend/migrations"
+	"flower-backend/models"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// The models as they were when AutoMigrate created the schema, before
+// versioned migrations existed.
+type legacyUser struct {
+	ID           uint   `gorm:"primaryKey"`
+	Username     string `gorm:"not null;unique"`
+	Email        string `gorm:"not null;unique"`
+	Password     string
+	Avatar       string
+	CreatedAt    time.Time
+	Role         string        `gorm:"default:user"`
+	Provider     string        `gorm:"default:local"`
+	ProviderID   string        `gorm:""`
+	ProviderData string        `gorm:"type:text"`
+	Posts        []legacyPost  `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;foreignKey:UserID"`
+	Tokens       []legacyToken `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;foreignKey:UserID"`
+	Likes        []legacyPost  `gorm:"many2many:post_likes;joinForeignKey:user_id;joinReferences:post_id"`
+	Followers    []legacyUser  `gorm:"many2many:user_follows;joinForeignKey:following_id;joinReferences:follower_id"`
+	Following    []legacyUser  `gorm:"many2many:user_follows;joinForeignKey:follower_id;joinReferences:following_id"`
+}
+
+func (legacyUser) TableName() string { return "users" }
+
+type legacyPost struct {
+	ID        uint   `gorm:"primaryKey"`
+	Title     string `gorm:"not null"`
+	Content   string `gorm:"not null"`
+	ImageURL  string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	UserID    uint         `gorm:"not null"`
+	User      legacyUser   `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;foreignKey:UserID"`
+	Likes     []legacyUser `gorm:"many2many:post_likes;joinForeignKey:post_id;joinReferences:user_id"`
+}
+
+func (legacyPost) TableName() string { return "posts" }
+
+type legacyToken struct {
+	ID        uint       `gorm:"primaryKey"`
+	Token     string     `gorm:"not null;unique"`
+	UserID    uint       `gorm:"not null;index"`
+	User      legacyUser `gorm:"foreignKey:UserID"`
+	ExpiresAt time.Time  `gorm:"index"`
+}
+
+func (legacyToken) TableName() string { return "tokens" }
+
+func TestUpAdoptsAutoMigrateSchema(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(database.SQLiteDSN(filepath.Join(t.TempDir(), "legacy.db"))), &gorm.Config{
+		Logger: gormlogger.Default.LogMode(gormlogger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	if err := db.AutoMigrate(&legacyUser{}, &legacyPost{}, &legacyToken{}); err != nil {
+		t.Fatalf("create legacy schema: %v", err)
+	}
+	fern := legacyUser{Username: "fern", Email: "fern@example.com"}
+	moss := legacyUser{Username: "moss", Email: "moss@example.com"}
+	db.Create(&fern)
+	db.Create(&moss)
+	post := legacyPost{Title: "Tulips", Content: "Spring is here", UserID: fern.ID}
+	db.Create(&post)
+	if err := db.Model(&moss).Association("Likes").Append(&post); err != nil {
+		t.Fatalf("like: %v", err)
+	}
+
+	migrator, err := migrations.NewMigrator(db, zap.NewNop())
+	if err != nil {
+		t.Fatalf("load migrations: %v", err)
+	}
+	applied, err := migrator.Up()
+	if err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+	statuses, err := migrator.Status()
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	// the baseline is recorded as adopted rather than run again
+	if applied != len(statuses)-1 {
+		t.Errorf("Up() applied %d migrations, want all %d after the baseline", applied, len(statuses)-1)
+	}
+	for _, status := range statuses {
+		if status.AppliedAt == nil {
+			t.Errorf("migration %d_%s not applied", status.Version, status.Name)
+		}
+	}
+
+	// existing rows read through the current models, with the new columns' defaults
+	var adopted models.Post
+	if err := db.Preload("Likes").First(&adopted, post.ID).Error; err != nil {
+		t.Fatalf("load adopted post: %v", err)
+	}
+	if adopted.Status != models.PostPublished || len(adopted.Likes) != 1 {
+		t.Errorf("adopted post = status %q with %d likes, want published with 1", adopted.Status, len(adopted.Likes))
+	}
+	if err := db.Create(&models.Post{Title: "Roses", Content: "Red", UserID: moss.ID, ThumbnailURL: "thumb"}).Error; err != nil {
+		t.Errorf("create post on the adopted schema: %v", err)
+	}
+
+	// every later migration rolls back and applies again on top of it
+	reverted, err := migrator.Down(len(statuses) - 1)
+	if err != nil || reverted != len(statuses)-1 {
+		t.Fatalf("Down() = %d, %v, want %d", reverted, err, len(statuses)-1)
+	}
+	if db.Migrator().HasColumn(&models.Post{}, "status") {
+		t.Error("posts.status still present after rolling back to the baseline")
+	}
+	if applied, err := migrator.Up(); err != nil || applied != len(statuses)-1 {
+		t.Errorf("Up() after Down = %d, %v, want %d", applied, err, len(statuses)-1)
+	}
+	if err := db.First(&adopted, post.ID).Error; err != nil {
+		t.Errorf("adopted post lost across Down and Up: %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+-- Baseline schema, matching what AutoMigrate produced before versioned migrations.
+
+CREATE TABLE `users` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `username` varchar(191) NOT NULL,
+  `email` varchar(191) NOT NULL,
+  `password` longtext,
+  `avatar` longtext,
+  `created_at` datetime(3) NULL,
+  `role` varchar(191) DEFAULT 'user',
+  `provider` varchar(191) DEFAULT 'local',
+  `provider_id` longtext,
+  `provider_data` text,
+  `suspended` boolean DEFAULT false,
+  `followers_count` bigint NOT NULL DEFAULT 0,
+  `following_count` bigint NOT NULL DEFAULT 0,
+  `posts_count` bigint NOT NULL DEFAULT 0,
+  PRIMARY KEY (`id`),
+  CONSTRAINT `uni_users_username` UNIQUE (`username`),
+  CONSTRAINT `uni_users_email` UNIQUE (`email`),
+  INDEX `idx_users_suspended` (`suspended`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE `posts` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `title` longtext NOT NULL,
+  `content` longtext NOT NULL,
+  `image_url` longtext,
+  `hidden` boolean DEFAULT false,
+  `likes_count` bigint NOT NULL DEFAULT 0,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  `user_id` bigint unsigned NOT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_posts_hidden` (`hidden`),
+  CONSTRAINT `fk_users_posts` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE ON UPDATE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE `tokens` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `token` varchar(191) NOT NULL,
+  `user_id` bigint unsigned NOT NULL,
+  `expires_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  CONSTRAINT `uni_tokens_token` UNIQUE (`token`),
+  INDEX `idx_tokens_user_id` (`user_id`),
+  INDEX `idx_tokens_expires_at` (`expires_at`),
+  CONSTRAINT `fk_users_tokens` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE ON UPDATE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE `post_likes` (
+  `post_id` bigint unsigned NOT NULL,
+  `user_id` bigint unsigned NOT NULL,
+  `created_at` datetime(3) NULL,
+  PRIMARY KEY (`post_id`, `user_id`),
+  INDEX `idx_post_likes_created_at` (`created_at`),
+  CONSTRAINT `fk_post_likes_post` FOREIGN KEY (`post_id`) REFERENCES `posts` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `fk_post_likes_user` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE `user_follows` (
+  `follower_id` bigint unsigned NOT NULL,
+  `following_id` bigint unsigned NOT NULL,
+  `created_at` datetime(3) NULL,
+  PRIMARY KEY (`follower_id`, `following_id`),
+  INDEX `idx_user_follows_created_at` (`created_at`),
+  CONSTRAINT `fk_user_follows_follower` FOREIGN KEY (`follower_id`) REFERENCES `users` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `fk_user_follows_following` FOREIGN KEY (`following_id`) REFERENCES `users` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE `post_reports` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `post_id` bigint unsigned NOT NULL,
+  `user_id` bigint unsigned NOT NULL,
+  `reason` text,
+  `created_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_post_reports_post_user` (`post_id`, `user_id`),
+  CONSTRAINT `fk_posts_reports` FOREIGN KEY (`post_id`) REFERENCES `posts` (`id`) ON DELETE CASCADE ON UPDATE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE `post_views` (
+  `user_id` bigint unsigned NOT NULL,
+  `post_id` bigint unsigned NOT NULL,
+  `seen_at` datetime(3) NULL,
+  PRIMARY KEY (`user_id`, `post_id`),
+  INDEX `idx_post_views_post_id` (`post_id`),
+  INDEX `idx_post_views_seen_at` (`seen_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE `tags` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `name` varchar(30) NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_tags_name` (`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE `post_tags` (
+  `post_id` bigint unsigned NOT NULL,
+  `tag_id` bigint unsigned NOT NULL,
+  PRIMARY KEY (`post_id`, `tag_id`),
+  CONSTRAINT `fk_post_tags_post` FOREIGN KEY (`post_id`) REFERENCES `posts` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `fk_post_tags_tag` FOREIGN KEY (`tag_id`) REFERENCES `tags` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE `post_like_buckets` (
+  `post_id` bigint unsigned NOT NULL,
+  `bucket_start` datetime(3) NOT NULL,
+  `count` bigint NOT NULL,
+  PRIMARY KEY (`post_id`, `bucket_start`),
+  INDEX `idx_post_like_buckets_bucket_start` (`bucket_start`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,21 @@
+CREATE TABLE `jobs` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `type` varchar(100) NOT NULL,
+  `payload` text,
+  `status` varchar(20) NOT NULL DEFAULT 'queued',
+  `run_at` datetime(3) NOT NULL,
+  `attempts` bigint NOT NULL DEFAULT 0,
+  `max_attempts` bigint NOT NULL DEFAULT 5,
+  `last_error` text,
+  `unique_key` varchar(191) NULL,
+  `locked_by` varchar(191),
+  `locked_at` datetime(3) NULL,
+  `finished_at` datetime(3) NULL,
+  `created_at` datetime(3) NULL,
+  `updated_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_jobs_type` (`type`),
+  INDEX `idx_jobs_status_run_at` (`status`, `run_at`),
+  UNIQUE INDEX `idx_jobs_unique_key` (`unique_key`),
+  INDEX `idx_jobs_finished_at` (`finished_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,20 @@
+CREATE TABLE `scheduler_leases` (
+  `name` varchar(100) NOT NULL,
+  `holder` varchar(191) NOT NULL,
+  `expires_at` datetime(3) NOT NULL,
+  PRIMARY KEY (`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+CREATE TABLE `task_runs` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `task` varchar(100) NOT NULL,
+  `scheduled_for` datetime(3) NOT NULL,
+  `holder` varchar(191),
+  `started_at` datetime(3) NOT NULL,
+  `finished_at` datetime(3) NULL,
+  `duration_ms` bigint,
+  `rows_affected` bigint,
+  `error` text,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_task_runs_task_scheduled_for` (`task`, `scheduled_for`),
+  INDEX `idx_task_runs_started_at` (`started_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
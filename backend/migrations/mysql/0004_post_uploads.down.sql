@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS `post_uploads`;
+DROP INDEX `idx_posts_status` ON `posts`;
+ALTER TABLE `posts` DROP COLUMN `upload_error`;
+ALTER TABLE `posts` DROP COLUMN `status`;
@@ -0,0 +1,11 @@
+ALTER TABLE `posts` ADD COLUMN `status` varchar(20) NOT NULL DEFAULT 'published';
+ALTER TABLE `posts` ADD COLUMN `upload_error` text;
+CREATE INDEX `idx_posts_status` ON `posts` (`status`);
+
+CREATE TABLE `post_uploads` (
+  `post_id` bigint unsigned NOT NULL,
+  `data` longblob NOT NULL,
+  `created_at` datetime(3) NULL,
+  PRIMARY KEY (`post_id`),
+  CONSTRAINT `fk_post_uploads_post` FOREIGN KEY (`post_id`) REFERENCES `posts` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,10 @@
+CREATE TABLE `direct_uploads` (
+  `asset_id` varchar(191) NOT NULL,
+  `user_id` bigint unsigned NOT NULL,
+  `expires_at` datetime(3) NOT NULL,
+  `post_id` bigint unsigned NULL,
+  `created_at` datetime(3) NULL,
+  PRIMARY KEY (`asset_id`),
+  INDEX `idx_direct_uploads_user_id` (`user_id`),
+  INDEX `idx_direct_uploads_created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
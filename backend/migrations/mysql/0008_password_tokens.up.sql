@@ -0,0 +1,12 @@
+CREATE TABLE `password_tokens` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `token_hash` varchar(64) NOT NULL,
+  `user_id` bigint unsigned NOT NULL,
+  `expires_at` datetime(3) NULL,
+  `created_at` datetime(3) NULL,
+  PRIMARY KEY (`id`),
+  CONSTRAINT `uni_password_tokens_token_hash` UNIQUE (`token_hash`),
+  INDEX `idx_password_tokens_user_id` (`user_id`),
+  INDEX `idx_password_tokens_expires_at` (`expires_at`),
+  CONSTRAINT `fk_password_tokens_user` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,2 @@
+ALTER TABLE `posts` ADD COLUMN `thumbnail_url` text;
+ALTER TABLE `posts` ADD COLUMN `medium_url` text;
@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS "post_like_buckets";
+DROP TABLE IF EXISTS "post_tags";
+DROP TABLE IF EXISTS "tags";
+DROP TABLE IF EXISTS "post_views";
+DROP TABLE IF EXISTS "post_reports";
+DROP TABLE IF EXISTS "user_follows";
+DROP TABLE IF EXISTS "post_likes";
+DROP TABLE IF EXISTS "tokens";
+DROP TABLE IF EXISTS "posts";
+DROP TABLE IF EXISTS "users";
@@ -0,0 +1,20 @@
+CREATE TABLE "jobs" (
+  "id" bigserial PRIMARY KEY,
+  "type" varchar(100) NOT NULL,
+  "payload" text,
+  "status" varchar(20) NOT NULL DEFAULT 'queued',
+  "run_at" timestamptz NOT NULL,
+  "attempts" bigint NOT NULL DEFAULT 0,
+  "max_attempts" bigint NOT NULL DEFAULT 5,
+  "last_error" text,
+  "unique_key" varchar(191),
+  "locked_by" varchar(191),
+  "locked_at" timestamptz,
+  "finished_at" timestamptz,
+  "created_at" timestamptz,
+  "updated_at" timestamptz
+);
+CREATE INDEX "idx_jobs_type" ON "jobs" ("type");
+CREATE INDEX "idx_jobs_status_run_at" ON "jobs" ("status", "run_at");
+CREATE UNIQUE INDEX "idx_jobs_unique_key" ON "jobs" ("unique_key");
+CREATE INDEX "idx_jobs_finished_at" ON "jobs" ("finished_at");
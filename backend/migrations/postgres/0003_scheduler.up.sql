@@ -0,0 +1,18 @@
+CREATE TABLE "scheduler_leases" (
+  "name" varchar(100) PRIMARY KEY,
+  "holder" varchar(191) NOT NULL,
+  "expires_at" timestamptz NOT NULL
+);
+CREATE TABLE "task_runs" (
+  "id" bigserial PRIMARY KEY,
+  "task" varchar(100) NOT NULL,
+  "scheduled_for" timestamptz NOT NULL,
+  "holder" varchar(191),
+  "started_at" timestamptz NOT NULL,
+  "finished_at" timestamptz,
+  "duration_ms" bigint,
+  "rows_affected" bigint,
+  "error" text
+);
+CREATE UNIQUE INDEX "idx_task_runs_task_scheduled_for" ON "task_runs" ("task", "scheduled_for");
+CREATE INDEX "idx_task_runs_started_at" ON "task_runs" ("started_at");
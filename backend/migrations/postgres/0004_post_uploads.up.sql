@@ -0,0 +1,10 @@
+ALTER TABLE "posts" ADD COLUMN "status" varchar(20) NOT NULL DEFAULT 'published';
+ALTER TABLE "posts" ADD COLUMN "upload_error" text;
+CREATE INDEX "idx_posts_status" ON "posts" ("status");
+
+CREATE TABLE "post_uploads" (
+  "post_id" bigint PRIMARY KEY,
+  "data" bytea NOT NULL,
+  "created_at" timestamptz,
+  CONSTRAINT "fk_post_uploads_post" FOREIGN KEY ("post_id") REFERENCES "posts" ("id") ON DELETE CASCADE
+);
@@ -0,0 +1,9 @@
+CREATE TABLE "direct_uploads" (
+  "asset_id" varchar(191) PRIMARY KEY,
+  "user_id" bigint NOT NULL,
+  "expires_at" timestamptz NOT NULL,
+  "post_id" bigint,
+  "created_at" timestamptz
+);
+CREATE INDEX "idx_direct_uploads_user_id" ON "direct_uploads" ("user_id");
+CREATE INDEX "idx_direct_uploads_created_at" ON "direct_uploads" ("created_at");
@@ -0,0 +1,13 @@
+CREATE TABLE "notifications" (
+  "id" bigserial PRIMARY KEY,
+  "user_id" bigint NOT NULL,
+  "type" varchar(50) NOT NULL,
+  "post_id" bigint NOT NULL,
+  "actor_id" bigint NOT NULL,
+  "created_at" timestamptz,
+  CONSTRAINT "fk_notifications_user" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE,
+  CONSTRAINT "fk_notifications_post" FOREIGN KEY ("post_id") REFERENCES "posts" ("id") ON DELETE CASCADE,
+  CONSTRAINT "fk_notifications_actor" FOREIGN KEY ("actor_id") REFERENCES "users" ("id") ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX "idx_notifications_user_type_post" ON "notifications" ("user_id", "type", "post_id");
+CREATE INDEX "idx_notifications_created_at" ON "notifications" ("created_at");
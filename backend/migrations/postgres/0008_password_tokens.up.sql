@@ -0,0 +1,11 @@
+CREATE TABLE "password_tokens" (
+  "id" bigserial PRIMARY KEY,
+  "token_hash" varchar(64) NOT NULL,
+  "user_id" bigint NOT NULL,
+  "expires_at" timestamptz,
+  "created_at" timestamptz,
+  CONSTRAINT "uni_password_tokens_token_hash" UNIQUE ("token_hash"),
+  CONSTRAINT "fk_password_tokens_user" FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE
+);
+CREATE INDEX "idx_password_tokens_user_id" ON "password_tokens" ("user_id");
+CREATE INDEX "idx_password_tokens_expires_at" ON "password_tokens" ("expires_at");
@@ -0,0 +1,2 @@
+ALTER TABLE "posts" DROP COLUMN "medium_url";
+ALTER TABLE "posts" DROP COLUMN "thumbnail_url";
@@ -0,0 +1,107 @@
+-- Baseline schema, matching what AutoMigrate produced before versioned migrations.
+
+CREATE TABLE `users` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `username` text NOT NULL,
+  `email` text NOT NULL,
+  `password` text,
+  `avatar` text,
+  `created_at` datetime,
+  `role` text DEFAULT 'user',
+  `provider` text DEFAULT 'local',
+  `provider_id` text,
+  `provider_data` text,
+  `suspended` numeric DEFAULT false,
+  `followers_count` integer NOT NULL DEFAULT 0,
+  `following_count` integer NOT NULL DEFAULT 0,
+  `posts_count` integer NOT NULL DEFAULT 0,
+  CONSTRAINT `uni_users_username` UNIQUE (`username`),
+  CONSTRAINT `uni_users_email` UNIQUE (`email`)
+);
+CREATE INDEX `idx_users_suspended` ON `users` (`suspended`);
+
+CREATE TABLE `posts` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `title` text NOT NULL,
+  `content` text NOT NULL,
+  `image_url` text,
+  `hidden` numeric DEFAULT false,
+  `likes_count` integer NOT NULL DEFAULT 0,
+  `created_at` datetime,
+  `updated_at` datetime,
+  `user_id` integer NOT NULL,
+  CONSTRAINT `fk_users_posts` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE INDEX `idx_posts_hidden` ON `posts` (`hidden`);
+
+CREATE TABLE `tokens` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `token` text NOT NULL,
+  `user_id` integer NOT NULL,
+  `expires_at` datetime,
+  CONSTRAINT `uni_tokens_token` UNIQUE (`token`),
+  CONSTRAINT `fk_users_tokens` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE INDEX `idx_tokens_user_id` ON `tokens` (`user_id`);
+CREATE INDEX `idx_tokens_expires_at` ON `tokens` (`expires_at`);
+
+CREATE TABLE `post_likes` (
+  `post_id` integer NOT NULL,
+  `user_id` integer NOT NULL,
+  `created_at` datetime,
+  PRIMARY KEY (`post_id`, `user_id`),
+  CONSTRAINT `fk_post_likes_post` FOREIGN KEY (`post_id`) REFERENCES `posts` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `fk_post_likes_user` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE
+);
+CREATE INDEX `idx_post_likes_created_at` ON `post_likes` (`created_at`);
+
+CREATE TABLE `user_follows` (
+  `follower_id` integer NOT NULL,
+  `following_id` integer NOT NULL,
+  `created_at` datetime,
+  PRIMARY KEY (`follower_id`, `following_id`),
+  CONSTRAINT `fk_user_follows_follower` FOREIGN KEY (`follower_id`) REFERENCES `users` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `fk_user_follows_following` FOREIGN KEY (`following_id`) REFERENCES `users` (`id`) ON DELETE CASCADE
+);
+CREATE INDEX `idx_user_follows_created_at` ON `user_follows` (`created_at`);
+
+CREATE TABLE `post_reports` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `post_id` integer NOT NULL,
+  `user_id` integer NOT NULL,
+  `reason` text,
+  `created_at` datetime,
+  CONSTRAINT `fk_posts_reports` FOREIGN KEY (`post_id`) REFERENCES `posts` (`id`) ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE UNIQUE INDEX `idx_post_reports_post_user` ON `post_reports` (`post_id`, `user_id`);
+
+CREATE TABLE `post_views` (
+  `user_id` integer NOT NULL,
+  `post_id` integer NOT NULL,
+  `seen_at` datetime,
+  PRIMARY KEY (`user_id`, `post_id`)
+);
+CREATE INDEX `idx_post_views_post_id` ON `post_views` (`post_id`);
+CREATE INDEX `idx_post_views_seen_at` ON `post_views` (`seen_at`);
+
+CREATE TABLE `tags` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `name` text NOT NULL
+);
+CREATE UNIQUE INDEX `idx_tags_name` ON `tags` (`name`);
+
+CREATE TABLE `post_tags` (
+  `post_id` integer NOT NULL,
+  `tag_id` integer NOT NULL,
+  PRIMARY KEY (`post_id`, `tag_id`),
+  CONSTRAINT `fk_post_tags_post` FOREIGN KEY (`post_id`) REFERENCES `posts` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `fk_post_tags_tag` FOREIGN KEY (`tag_id`) REFERENCES `tags` (`id`) ON DELETE CASCADE
+);
+
+CREATE TABLE `post_like_buckets` (
+  `post_id` integer NOT NULL,
+  `bucket_start` datetime NOT NULL,
+  `count` integer NOT NULL,
+  PRIMARY KEY (`post_id`, `bucket_start`)
+);
+CREATE INDEX `idx_post_like_buckets_bucket_start` ON `post_like_buckets` (`bucket_start`);
@@ -0,0 +1,20 @@
+CREATE TABLE `jobs` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `type` text NOT NULL,
+  `payload` text,
+  `status` text NOT NULL DEFAULT 'queued',
+  `run_at` datetime NOT NULL,
+  `attempts` integer NOT NULL DEFAULT 0,
+  `max_attempts` integer NOT NULL DEFAULT 5,
+  `last_error` text,
+  `unique_key` text,
+  `locked_by` text,
+  `locked_at` datetime,
+  `finished_at` datetime,
+  `created_at` datetime,
+  `updated_at` datetime
+);
+CREATE INDEX `idx_jobs_type` ON `jobs` (`type`);
+CREATE INDEX `idx_jobs_status_run_at` ON `jobs` (`status`, `run_at`);
+CREATE UNIQUE INDEX `idx_jobs_unique_key` ON `jobs` (`unique_key`);
+CREATE INDEX `idx_jobs_finished_at` ON `jobs` (`finished_at`);
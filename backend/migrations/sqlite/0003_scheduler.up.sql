@@ -0,0 +1,18 @@
+CREATE TABLE `scheduler_leases` (
+  `name` text PRIMARY KEY,
+  `holder` text NOT NULL,
+  `expires_at` datetime NOT NULL
+);
+CREATE TABLE `task_runs` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `task` text NOT NULL,
+  `scheduled_for` datetime NOT NULL,
+  `holder` text,
+  `started_at` datetime NOT NULL,
+  `finished_at` datetime,
+  `duration_ms` integer,
+  `rows_affected` integer,
+  `error` text
+);
+CREATE UNIQUE INDEX `idx_task_runs_task_scheduled_for` ON `task_runs` (`task`, `scheduled_for`);
+CREATE INDEX `idx_task_runs_started_at` ON `task_runs` (`started_at`);
@@ -0,0 +1,10 @@
+ALTER TABLE `posts` ADD COLUMN `status` text NOT NULL DEFAULT 'published';
+ALTER TABLE `posts` ADD COLUMN `upload_error` text;
+CREATE INDEX `idx_posts_status` ON `posts` (`status`);
+
+CREATE TABLE `post_uploads` (
+  `post_id` integer PRIMARY KEY,
+  `data` blob NOT NULL,
+  `created_at` datetime,
+  CONSTRAINT `fk_post_uploads_post` FOREIGN KEY (`post_id`) REFERENCES `posts` (`id`) ON DELETE CASCADE
+);
@@ -0,0 +1,9 @@
+CREATE TABLE `direct_uploads` (
+  `asset_id` text PRIMARY KEY,
+  `user_id` integer NOT NULL,
+  `expires_at` datetime NOT NULL,
+  `post_id` integer,
+  `created_at` datetime
+);
+CREATE INDEX `idx_direct_uploads_user_id` ON `direct_uploads` (`user_id`);
+CREATE INDEX `idx_direct_uploads_created_at` ON `direct_uploads` (`created_at`);
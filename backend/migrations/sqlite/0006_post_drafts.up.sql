@@ -0,0 +1,2 @@
+ALTER TABLE `posts` ADD COLUMN `publish_at` datetime;
+CREATE INDEX `idx_posts_publish_at` ON `posts` (`publish_at`);
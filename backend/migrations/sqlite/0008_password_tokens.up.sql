@@ -0,0 +1,11 @@
+CREATE TABLE `password_tokens` (
+  `id` integer PRIMARY KEY AUTOINCREMENT,
+  `token_hash` text NOT NULL,
+  `user_id` integer NOT NULL,
+  `expires_at` datetime,
+  `created_at` datetime,
+  CONSTRAINT `uni_password_tokens_token_hash` UNIQUE (`token_hash`),
+  CONSTRAINT `fk_password_tokens_user` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE
+);
+CREATE INDEX `idx_password_tokens_user_id` ON `password_tokens` (`user_id`);
+CREATE INDEX `idx_password_tokens_expires_at` ON `password_tokens` (`expires_at`);
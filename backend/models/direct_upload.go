@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// DirectUpload records an image upload signed for a user, who sends the
+// image straight to storage and then creates a post from AssetID. PostID is
+// set once a post uses it.
+type DirectUpload struct {
+	AssetID   string    `gorm:"primaryKey;size:191" json:"asset_id"`
+	UserID    uint      `gorm:"not null;index" json:"user_id"`
+	ExpiresAt time.Time `gorm:"not null" json:"expires_at"`
+	PostID    *uint     `json:"post_id,omitempty"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
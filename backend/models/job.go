@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Job statuses. A failed attempt goes back to queued with a later RunAt
+// until MaxAttempts is used up; the job is then dead until an admin retries it.
+const (
+	JobQueued    = "queued"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobDead      = "dead"
+)
+
+// Job is a unit of background work, run by the jobs package.
+type Job struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	Type        string    `gorm:"size:100;not null;index" json:"type"`
+	Payload     string    `gorm:"type:text" json:"payload"`
+	Status      string    `gorm:"size:20;not null;default:queued;index:idx_jobs_status_run_at,priority:1" json:"status"`
+	RunAt       time.Time `gorm:"not null;index:idx_jobs_status_run_at,priority:2" json:"run_at"`
+	Attempts    int       `gorm:"not null;default:0" json:"attempts"`
+	MaxAttempts int       `gorm:"not null;default:5" json:"max_attempts"`
+	LastError   string    `gorm:"type:text" json:"last_error,omitempty"`
+	// UniqueKey makes enqueueing the same job twice a no-op; cron runs use
+	// it so every replica can enqueue a run and only one row results
+	UniqueKey  *string    `gorm:"size:191;uniqueIndex" json:"unique_key,omitempty"`
+	LockedBy   string     `gorm:"size:191" json:"locked_by,omitempty"`
+	LockedAt   *time.Time `json:"locked_at,omitempty"`
+	FinishedAt *time.Time `gorm:"index" json:"finished_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// BeforeCreate makes a job without a RunAt due as soon as it is inserted,
+// on the database's clock so it matches the queue's.
+func (j *Job) BeforeCreate(tx *gorm.DB) error {
+	if j.RunAt.IsZero() {
+		j.RunAt = tx.NowFunc()
+	}
+	return nil
+}
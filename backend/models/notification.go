@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// Notification types.
+const (
+	// NotificationPostPublished tells a follower that ActorID published PostID
+	NotificationPostPublished = "post.published"
+)
+
+// Notification is something that happened for UserID to look at.
+type Notification struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_notifications_user_type_post" json:"user_id"`
+	Type      string    `gorm:"size:50;not null;uniqueIndex:idx_notifications_user_type_post" json:"type"`
+	PostID    uint      `gorm:"not null;uniqueIndex:idx_notifications_user_type_post" json:"post_id"`
+	ActorID   uint      `gorm:"not null" json:"actor_id"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+	User      User      `gorm:"constraint:OnDelete:CASCADE;foreignKey:UserID" json:"-"`
+	Post      Post      `gorm:"constraint:OnDelete:CASCADE;foreignKey:PostID" json:"-"`
+	Actor     User      `gorm:"constraint:OnDelete:CASCADE;foreignKey:ActorID" json:"-"`
+}
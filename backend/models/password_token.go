@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// PasswordToken lets the holder of a set-password link choose a password
+// once before ExpiresAt. Only the SHA-256 of the link's token is stored.
+type PasswordToken struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	TokenHash string    `gorm:"size:64;not null;unique" json:"-"`
+	UserID    uint      `gorm:"not null;index" json:"user_id"`
+	User      User      `gorm:"constraint:OnDelete:CASCADE;foreignKey:UserID" json:"-"`
+	ExpiresAt time.Time `gorm:"index" json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -1,15 +1,64 @@
 package models
 
-import "time"
+import (
+	"slices"
+	"time"
+)
+
+// Post statuses. A post created with an asynchronous upload is processing
+// until its image is stored, then published, or failed until its author
+// retries the upload. A draft waits for its author to publish it, and a
+// scheduled post is published once PublishAt passes. Only published posts
+// are listed publicly.
+const (
+	PostProcessing = "processing"
+	PostPublished  = "published"
+	PostFailed     = "failed"
+	PostDraft      = "draft"
+	PostScheduled  = "scheduled"
+)
 
 type Post struct {
-	ID        uint      `gorm:"primaryKey" json:"id"`
-	Title     string    `gorm:"not null" json:"title"`
-	Content   string    `gorm:"not null" json:"content"`
-	ImageURL  string    `json:"image_url"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	UserID    uint      `gorm:"not null" json:"user_id"`
-	User      User      `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;foreignKey:UserID" json:"user"`
-	Likes     []User    `gorm:"many2many:post_likes" json:"likes"`
+	ID           uint         `gorm:"primaryKey" json:"id"`
+	Title        string       `gorm:"not null" json:"title"`
+	Content      string       `gorm:"not null" json:"content"`
+	ImageURL     string       `json:"image_url"`
+	ThumbnailURL string       `gorm:"type:text" json:"thumbnail_url,omitempty"` // renditions made by the background upload,
+	MediumURL    string       `gorm:"type:text" json:"medium_url,omitempty"`    // empty when the image was stored inline
+	Hidden       bool         `gorm:"default:false;index" json:"hidden"`        // hidden by an admin from public listings
+	LikesCount   int64        `gorm:"not null;default:0" json:"likes_count"`    // denormalized count of post_likes rows
+	Status       string       `gorm:"size:20;not null;default:published;index" json:"status"`
+	UploadError  string       `gorm:"type:text" json:"upload_error,omitempty"` // why the last background upload failed
+	PublishAt    *time.Time   `gorm:"index" json:"publish_at,omitempty"`       // when a scheduled post goes public
+	CreatedAt    time.Time    `json:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+	UserID       uint         `gorm:"not null" json:"user_id"`
+	User         User         `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;foreignKey:UserID" json:"user"`
+	Likes        []User       `gorm:"many2many:post_likes" json:"likes"`
+	Reports      []PostReport `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;foreignKey:PostID" json:"-"`
+	Tags         []Tag        `gorm:"many2many:post_tags" json:"tags"`
+}
+
+// Images returns where the post's image and its renditions are stored.
+func (p *Post) Images() PostImages {
+	return PostImages{ImageURL: p.ImageURL, ThumbnailURL: p.ThumbnailURL, MediumURL: p.MediumURL}
+}
+
+// PostImages are the URLs of a post's image and its renditions. A rendition
+// the image is too small for has the original's URL.
+type PostImages struct {
+	ImageURL     string
+	ThumbnailURL string
+	MediumURL    string
+}
+
+// URLs lists the distinct stored images, for deleting them.
+func (i PostImages) URLs() []string {
+	var urls []string
+	for _, url := range []string{i.ImageURL, i.ThumbnailURL, i.MediumURL} {
+		if url != "" && !slices.Contains(urls, url) {
+			urls = append(urls, url)
+		}
+	}
+	return urls
 }
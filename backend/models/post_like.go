@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// PostLike maps the post_likes join table so likes carry a timestamp.
+type PostLike struct {
+	PostID    uint      `gorm:"primaryKey" json:"post_id"`
+	UserID    uint      `gorm:"primaryKey" json:"user_id"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
+
+func (PostLike) TableName() string {
+	return "post_likes"
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// PostLikeBucket holds the number of likes a post received in one hour,
+// maintained by the like aggregator task for trending and popular listings.
+type PostLikeBucket struct {
+	PostID      uint      `gorm:"primaryKey" json:"post_id"`
+	BucketStart time.Time `gorm:"primaryKey;index" json:"bucket_start"`
+	Count       int64     `gorm:"not null" json:"count"`
+}
+
+func (PostLikeBucket) TableName() string {
+	return "post_like_buckets"
+}
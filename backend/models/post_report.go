@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+type PostReport struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	PostID    uint      `gorm:"not null;uniqueIndex:idx_post_reports_post_user" json:"post_id"`
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_post_reports_post_user" json:"user_id"`
+	Reason    string    `gorm:"type:text" json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
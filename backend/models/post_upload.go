@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// PostUpload holds the image of a post whose upload runs in the background.
+// It is removed once the image is stored, and kept after a failure so the
+// upload can be retried without sending the image again.
+type PostUpload struct {
+	PostID    uint      `gorm:"primaryKey" json:"post_id"`
+	Data      []byte    `gorm:"not null" json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+	Post      Post      `gorm:"constraint:OnDelete:CASCADE;foreignKey:PostID" json:"-"`
+}
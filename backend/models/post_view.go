@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// PostView records the first time a post was shown to a user in their feed.
+type PostView struct {
+	UserID uint      `gorm:"primaryKey" json:"user_id"`
+	PostID uint      `gorm:"primaryKey;index" json:"post_id"`
+	SeenAt time.Time `gorm:"index" json:"seen_at"`
+}
+
+func (PostView) TableName() string {
+	return "post_views"
+}
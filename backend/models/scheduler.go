@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// SchedulerLease is the row replicas compete for; whichever holds an
+// unexpired lease runs the scheduled tasks.
+type SchedulerLease struct {
+	Name      string    `gorm:"primaryKey;size:100" json:"name"`
+	Holder    string    `gorm:"size:191;not null" json:"holder"`
+	ExpiresAt time.Time `gorm:"not null" json:"expires_at"`
+}
+
+// TaskRun records one tick of a scheduled task. A tick has a single row,
+// so an instance that finds the row already there knows not to run it.
+type TaskRun struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	Task         string     `gorm:"size:100;not null;uniqueIndex:idx_task_runs_task_scheduled_for,priority:1" json:"task"`
+	ScheduledFor time.Time  `gorm:"not null;uniqueIndex:idx_task_runs_task_scheduled_for,priority:2" json:"scheduled_for"`
+	Holder       string     `gorm:"size:191" json:"holder"`
+	StartedAt    time.Time  `gorm:"not null;index" json:"started_at"`
+	FinishedAt   *time.Time `json:"finished_at,omitempty"`
+	DurationMs   int64      `json:"duration_ms"`
+	RowsAffected int64      `json:"rows_affected"`
+	Error        string     `gorm:"type:text" json:"error,omitempty"`
+}
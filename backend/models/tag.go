@@ -0,0 +1,7 @@
+package models
+
+type Tag struct {
+	ID    uint   `gorm:"primaryKey" json:"id"`
+	Name  string `gorm:"size:30;not null;uniqueIndex" json:"name"`
+	Posts []Post `gorm:"many2many:post_tags" json:"-"`
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// UserFollow maps the user_follows join table so follows carry a timestamp.
+type UserFollow struct {
+	FollowerID  uint      `gorm:"primaryKey" json:"follower_id"`
+	FollowingID uint      `gorm:"primaryKey" json:"following_id"`
+	CreatedAt   time.Time `gorm:"index" json:"created_at"`
+}
+
+func (UserFollow) TableName() string {
+	return "user_follows"
+}
@@ -0,0 +1,43 @@
+package feed_repository
+
+import (
+	"context"
+	"flower-backend/config"
+	"flower-backend/log"
+	"flower-backend/models"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type FeedRepository interface {
+	// WithContext returns a repository whose calls run under ctx
+	WithContext(ctx context.Context) FeedRepository
+	GetFollowedPosts(userID uint, since time.Time, limit int) ([]models.Post, error)
+	GetPopularPosts(userID uint, since time.Time, limit int) ([]models.Post, error)
+	GetPostsByIDs(ids []uint) ([]models.Post, error)
+	GetFollowingIDs(userID uint) ([]uint, error)
+	CountLikesBetween(postIDs []uint, since, until time.Time) (map[uint]int64, error)
+	CountAuthorAffinity(userID uint, authorIDs []uint, since time.Time) (map[uint]int64, error)
+	GetSeenPostIDs(userID uint, postIDs []uint, before time.Time) (map[uint]bool, error)
+	RecordViews(userID uint, postIDs []uint, seenAt time.Time) error
+}
+
+type feedRepository struct {
+	db     *gorm.DB
+	cfg    *config.Config
+	logger *zap.SugaredLogger
+}
+
+func NewFeedRepository(db *gorm.DB, cfg *config.Config, logger *zap.SugaredLogger) FeedRepository {
+	return newTracedFeedRepository(&feedRepository{
+		db:     db,
+		cfg:    cfg,
+		logger: logger,
+	})
+}
+
+func (r *feedRepository) WithContext(ctx context.Context) FeedRepository {
+	return &feedRepository{db: r.db.WithContext(ctx), cfg: r.cfg, logger: log.SugarFromContext(ctx, r.logger)}
+}
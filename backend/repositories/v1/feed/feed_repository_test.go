@@ -0,0 +1,149 @@
+package feed_repository
+
+import (
+	"flower-backend/models"
+	"flower-backend/testutil"
+	"slices"
+	"sort"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func newTestRepository(t *testing.T) (*feedRepository, *gorm.DB) {
+	t.Helper()
+	db := testutil.NewDB(t)
+	return &feedRepository{db: db, cfg: testutil.Config(), logger: testutil.Logger()}, db
+}
+
+func postIDs(posts []models.Post) []uint {
+	ids := make([]uint, 0, len(posts))
+	for _, post := range posts {
+		ids = append(ids, post.ID)
+	}
+	return ids
+}
+
+func TestCandidatePosts(t *testing.T) {
+	repo, db := newTestRepository(t)
+	reader := testutil.CreateUser(t, db)
+	followed := testutil.CreateUser(t, db)
+	stranger := testutil.CreateUser(t, db)
+	testutil.CreateFollow(t, db, reader.ID, followed.ID)
+
+	now := time.Now()
+	week := now.Add(-7 * 24 * time.Hour)
+	older := testutil.CreatePost(t, db, followed.ID, func(p *models.Post) { p.CreatedAt = now.Add(-2 * time.Hour) })
+	newer := testutil.CreatePost(t, db, followed.ID, func(p *models.Post) { p.CreatedAt = now.Add(-time.Hour) })
+	testutil.CreatePost(t, db, followed.ID, func(p *models.Post) { p.CreatedAt = now.Add(-30 * 24 * time.Hour) })
+	testutil.CreatePost(t, db, followed.ID, func(p *models.Post) { p.Hidden = true })
+	popular := testutil.CreatePost(t, db, stranger.ID, func(p *models.Post) { p.LikesCount = 10 })
+	own := testutil.CreatePost(t, db, reader.ID, func(p *models.Post) { p.LikesCount = 50 })
+
+	t.Run("GetFollowedPosts", func(t *testing.T) {
+		posts, err := repo.GetFollowedPosts(reader.ID, week, 10)
+		if err != nil {
+			t.Fatalf("GetFollowedPosts() error = %v", err)
+		}
+		if got := postIDs(posts); !slices.Equal(got, []uint{newer.ID, older.ID}) {
+			t.Errorf("GetFollowedPosts() = %v, want [%d %d]", got, newer.ID, older.ID)
+		}
+		limited, _ := repo.GetFollowedPosts(reader.ID, week, 1)
+		if len(limited) != 1 {
+			t.Errorf("GetFollowedPosts(limit 1) returned %d posts", len(limited))
+		}
+	})
+
+	t.Run("GetPopularPosts excludes own posts", func(t *testing.T) {
+		posts, err := repo.GetPopularPosts(reader.ID, week, 1)
+		if err != nil {
+			t.Fatalf("GetPopularPosts() error = %v", err)
+		}
+		if got := postIDs(posts); !slices.Equal(got, []uint{popular.ID}) {
+			t.Errorf("GetPopularPosts() = %v, want [%d], not the viewer's own %d", got, popular.ID, own.ID)
+		}
+	})
+
+	t.Run("GetPostsByIDs", func(t *testing.T) {
+		posts, err := repo.GetPostsByIDs([]uint{older.ID, popular.ID})
+		if err != nil || len(posts) != 2 {
+			t.Fatalf("GetPostsByIDs() = %v, %v, want 2 posts", postIDs(posts), err)
+		}
+		if posts[0].User.ID == 0 {
+			t.Error("GetPostsByIDs() did not preload authors")
+		}
+		if none, err := repo.GetPostsByIDs(nil); err != nil || len(none) != 0 {
+			t.Errorf("GetPostsByIDs(nil) = %v, %v, want empty", none, err)
+		}
+	})
+
+	t.Run("GetFollowingIDs", func(t *testing.T) {
+		ids, err := repo.GetFollowingIDs(reader.ID)
+		if err != nil || !slices.Equal(ids, []uint{followed.ID}) {
+			t.Errorf("GetFollowingIDs() = %v, %v, want [%d]", ids, err, followed.ID)
+		}
+	})
+}
+
+func TestSignals(t *testing.T) {
+	repo, db := newTestRepository(t)
+	reader := testutil.CreateUser(t, db)
+	author := testutil.CreateUser(t, db)
+	other := testutil.CreateUser(t, db)
+	first := testutil.CreatePost(t, db, author.ID)
+	second := testutil.CreatePost(t, db, author.ID)
+	elsewhere := testutil.CreatePost(t, db, other.ID)
+
+	now := time.Now()
+	db.Create(&models.PostLike{PostID: first.ID, UserID: reader.ID, CreatedAt: now.Add(-time.Hour)})
+	db.Create(&models.PostLike{PostID: second.ID, UserID: reader.ID, CreatedAt: now.Add(-2 * time.Hour)})
+	db.Create(&models.PostLike{PostID: first.ID, UserID: other.ID, CreatedAt: now.Add(-48 * time.Hour)})
+	db.Create(&models.PostLike{PostID: elsewhere.ID, UserID: reader.ID, CreatedAt: now.Add(-90 * 24 * time.Hour)})
+
+	t.Run("CountLikesBetween", func(t *testing.T) {
+		counts, err := repo.CountLikesBetween([]uint{first.ID, second.ID, elsewhere.ID}, now.Add(-24*time.Hour), now)
+		if err != nil {
+			t.Fatalf("CountLikesBetween() error = %v", err)
+		}
+		if counts[first.ID] != 1 || counts[second.ID] != 1 || counts[elsewhere.ID] != 0 {
+			t.Errorf("CountLikesBetween() = %v, want first=1 second=1 elsewhere=0", counts)
+		}
+	})
+
+	t.Run("CountAuthorAffinity", func(t *testing.T) {
+		counts, err := repo.CountAuthorAffinity(reader.ID, []uint{author.ID, other.ID}, now.Add(-30*24*time.Hour))
+		if err != nil {
+			t.Fatalf("CountAuthorAffinity() error = %v", err)
+		}
+		if counts[author.ID] != 2 || counts[other.ID] != 0 {
+			t.Errorf("CountAuthorAffinity() = %v, want author=2 other=0", counts)
+		}
+	})
+
+	t.Run("views", func(t *testing.T) {
+		shown := now.Add(-time.Hour)
+		if err := repo.RecordViews(reader.ID, []uint{first.ID, second.ID}, shown); err != nil {
+			t.Fatalf("RecordViews() error = %v", err)
+		}
+		// A later view must not move the first-seen time forward
+		if err := repo.RecordViews(reader.ID, []uint{first.ID}, now); err != nil {
+			t.Fatalf("RecordViews() error = %v", err)
+		}
+		seen, err := repo.GetSeenPostIDs(reader.ID, []uint{first.ID, second.ID, elsewhere.ID}, now.Add(-time.Minute))
+		if err != nil {
+			t.Fatalf("GetSeenPostIDs() error = %v", err)
+		}
+		var ids []uint
+		for id := range seen {
+			ids = append(ids, id)
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		if !slices.Equal(ids, []uint{first.ID, second.ID}) {
+			t.Errorf("GetSeenPostIDs() = %v, want [%d %d]", ids, first.ID, second.ID)
+		}
+		if err := repo.RecordViews(reader.ID, nil, now); err != nil {
+			t.Errorf("RecordViews(nil) error = %v", err)
+		}
+	})
+}
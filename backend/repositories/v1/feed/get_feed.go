@@ -0,0 +1,147 @@
+package feed_repository
+
+import (
+	"flower-backend/models"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm/clause"
+)
+
+type countRow struct {
+	ID    uint
+	Count int64
+}
+
+// GetFollowedPosts returns recent posts written by users that userID follows.
+func (r *feedRepository) GetFollowedPosts(userID uint, since time.Time, limit int) ([]models.Post, error) {
+	var posts []models.Post
+	err := r.db.
+		Joins("JOIN user_follows ON user_follows.following_id = posts.user_id").
+		Where("user_follows.follower_id = ? AND posts.hidden = ? AND posts.status = ? AND posts.created_at >= ?", userID, false, models.PostPublished, since).
+		Order("posts.created_at DESC").
+		Limit(limit).
+		Find(&posts).Error
+	if err != nil {
+		r.logger.Error("failed to get followed posts", zap.Error(err))
+		return nil, err
+	}
+	return posts, nil
+}
+
+// GetPopularPosts returns the most liked recent posts, excluding the viewer's own.
+func (r *feedRepository) GetPopularPosts(userID uint, since time.Time, limit int) ([]models.Post, error) {
+	var posts []models.Post
+	err := r.db.
+		Where("user_id <> ? AND hidden = ? AND status = ? AND created_at >= ?", userID, false, models.PostPublished, since).
+		Order("likes_count DESC, id DESC").
+		Limit(limit).
+		Find(&posts).Error
+	if err != nil {
+		r.logger.Error("failed to get popular posts", zap.Error(err))
+		return nil, err
+	}
+	return posts, nil
+}
+
+func (r *feedRepository) GetPostsByIDs(ids []uint) ([]models.Post, error) {
+	var posts []models.Post
+	if len(ids) == 0 {
+		return posts, nil
+	}
+	if err := r.db.Preload("User").Preload("Tags").Where("id IN ?", ids).Find(&posts).Error; err != nil {
+		r.logger.Error("failed to get posts by ids", zap.Error(err))
+		return nil, err
+	}
+	return posts, nil
+}
+
+func (r *feedRepository) GetFollowingIDs(userID uint) ([]uint, error) {
+	var ids []uint
+	if err := r.db.Model(&models.UserFollow{}).Where("follower_id = ?", userID).Pluck("following_id", &ids).Error; err != nil {
+		r.logger.Error("failed to get following ids", zap.Error(err))
+		return nil, err
+	}
+	return ids, nil
+}
+
+// CountLikesBetween counts likes per post created in [since, until).
+func (r *feedRepository) CountLikesBetween(postIDs []uint, since, until time.Time) (map[uint]int64, error) {
+	counts := make(map[uint]int64, len(postIDs))
+	if len(postIDs) == 0 {
+		return counts, nil
+	}
+	var rows []countRow
+	err := r.db.Model(&models.PostLike{}).
+		Select("post_id AS id, COUNT(*) AS count").
+		Where("post_id IN ? AND created_at >= ? AND created_at < ?", postIDs, since, until).
+		Group("post_id").
+		Scan(&rows).Error
+	if err != nil {
+		r.logger.Error("failed to count recent likes", zap.Error(err))
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.ID] = row.Count
+	}
+	return counts, nil
+}
+
+// CountAuthorAffinity counts how many posts by each author userID has liked since.
+func (r *feedRepository) CountAuthorAffinity(userID uint, authorIDs []uint, since time.Time) (map[uint]int64, error) {
+	counts := make(map[uint]int64, len(authorIDs))
+	if len(authorIDs) == 0 {
+		return counts, nil
+	}
+	var rows []countRow
+	err := r.db.Table("post_likes").
+		Select("posts.user_id AS id, COUNT(*) AS count").
+		Joins("JOIN posts ON posts.id = post_likes.post_id").
+		Where("post_likes.user_id = ? AND posts.user_id IN ? AND post_likes.created_at >= ?", userID, authorIDs, since).
+		Group("posts.user_id").
+		Scan(&rows).Error
+	if err != nil {
+		r.logger.Error("failed to count author affinity", zap.Error(err))
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.ID] = row.Count
+	}
+	return counts, nil
+}
+
+// GetSeenPostIDs returns which of postIDs the user was first shown before the given time.
+func (r *feedRepository) GetSeenPostIDs(userID uint, postIDs []uint, before time.Time) (map[uint]bool, error) {
+	seen := make(map[uint]bool)
+	if len(postIDs) == 0 {
+		return seen, nil
+	}
+	var ids []uint
+	err := r.db.Model(&models.PostView{}).
+		Where("user_id = ? AND post_id IN ? AND seen_at < ?", userID, postIDs, before).
+		Pluck("post_id", &ids).Error
+	if err != nil {
+		r.logger.Error("failed to get seen posts", zap.Error(err))
+		return nil, err
+	}
+	for _, id := range ids {
+		seen[id] = true
+	}
+	return seen, nil
+}
+
+// RecordViews stores the first time each post was shown; later views are ignored.
+func (r *feedRepository) RecordViews(userID uint, postIDs []uint, seenAt time.Time) error {
+	if len(postIDs) == 0 {
+		return nil
+	}
+	views := make([]models.PostView, 0, len(postIDs))
+	for _, postID := range postIDs {
+		views = append(views, models.PostView{UserID: userID, PostID: postID, SeenAt: seenAt})
+	}
+	if err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&views).Error; err != nil {
+		r.logger.Error("failed to record post views", zap.Error(err))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package feed_repository
+
+import (
+	"context"
+	"flower-backend/models"
+	"flower-backend/tracing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("flower-backend/repositories/v1/feed")
+
+// tracedFeedRepository starts a span around every call and runs the wrapped
+// repository under it, so the GORM query spans nest beneath.
+type tracedFeedRepository struct {
+	FeedRepository
+	ctx context.Context
+}
+
+func newTracedFeedRepository(repo FeedRepository) FeedRepository {
+	return &tracedFeedRepository{FeedRepository: repo, ctx: context.Background()}
+}
+
+func (r *tracedFeedRepository) WithContext(ctx context.Context) FeedRepository {
+	return &tracedFeedRepository{FeedRepository: r.FeedRepository, ctx: ctx}
+}
+
+// start opens the span for method and returns the wrapped repository bound to it.
+func (r *tracedFeedRepository) start(method string) (FeedRepository, trace.Span) {
+	ctx, span := tracer.Start(r.ctx, "FeedRepository."+method)
+	return r.FeedRepository.WithContext(ctx), span
+}
+
+func (r *tracedFeedRepository) GetFollowedPosts(userID uint, since time.Time, limit int) (_ []models.Post, err error) {
+	repo, span := r.start("GetFollowedPosts")
+	defer tracing.End(span, &err)
+	return repo.GetFollowedPosts(userID, since, limit)
+}
+
+func (r *tracedFeedRepository) GetPopularPosts(userID uint, since time.Time, limit int) (_ []models.Post, err error) {
+	repo, span := r.start("GetPopularPosts")
+	defer tracing.End(span, &err)
+	return repo.GetPopularPosts(userID, since, limit)
+}
+
+func (r *tracedFeedRepository) GetPostsByIDs(ids []uint) (_ []models.Post, err error) {
+	repo, span := r.start("GetPostsByIDs")
+	defer tracing.End(span, &err)
+	return repo.GetPostsByIDs(ids)
+}
+
+func (r *tracedFeedRepository) GetFollowingIDs(userID uint) (_ []uint, err error) {
+	repo, span := r.start("GetFollowingIDs")
+	defer tracing.End(span, &err)
+	return repo.GetFollowingIDs(userID)
+}
+
+func (r *tracedFeedRepository) CountLikesBetween(postIDs []uint, since, until time.Time) (_ map[uint]int64, err error) {
+	repo, span := r.start("CountLikesBetween")
+	defer tracing.End(span, &err)
+	return repo.CountLikesBetween(postIDs, since, until)
+}
+
+func (r *tracedFeedRepository) CountAuthorAffinity(userID uint, authorIDs []uint, since time.Time) (_ map[uint]int64, err error) {
+	repo, span := r.start("CountAuthorAffinity")
+	defer tracing.End(span, &err)
+	return repo.CountAuthorAffinity(userID, authorIDs, since)
+}
+
+func (r *tracedFeedRepository) GetSeenPostIDs(userID uint, postIDs []uint, before time.Time) (_ map[uint]bool, err error) {
+	repo, span := r.start("GetSeenPostIDs")
+	defer tracing.End(span, &err)
+	return repo.GetSeenPostIDs(userID, postIDs, before)
+}
+
+func (r *tracedFeedRepository) RecordViews(userID uint, postIDs []uint, seenAt time.Time) (err error) {
+	repo, span := r.start("RecordViews")
+	defer tracing.End(span, &err)
+	return repo.RecordViews(userID, postIDs, seenAt)
+}
@@ -0,0 +1,91 @@
+package job_repository
+
+import (
+	"flower-backend/models"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func (r *jobRepository) GetByID(id uint) (*models.Job, error) {
+	var job models.Job
+	if err := r.db.First(&job, id).Error; err != nil {
+		if err != gorm.ErrRecordNotFound {
+			r.logger.Error("failed to get job", zap.Uint("job_id", id), zap.Error(err))
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *jobRepository) GetAllWithFilter(filter JobFilter, page, limit int) ([]models.Job, int64, error) {
+	var jobs []models.Job
+	var total int64
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 20
+	}
+
+	offset := (page - 1) * limit
+
+	query := r.db.Model(&models.Job{})
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		r.logger.Error("failed to count jobs", zap.Error(err))
+		return nil, 0, err
+	}
+
+	err := query.Order("id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&jobs).Error
+	if err != nil {
+		r.logger.Error("failed to get jobs", zap.Error(err))
+		return nil, 0, err
+	}
+	return jobs, total, nil
+}
+
+// retryUpdates gives a dead job a fresh set of attempts, due now.
+func retryUpdates(now time.Time) map[string]any {
+	return map[string]any{
+		"status":      models.JobQueued,
+		"attempts":    0,
+		"run_at":      now,
+		"finished_at": nil,
+	}
+}
+
+// Retry requeues a dead job and reports whether it was dead.
+func (r *jobRepository) Retry(id uint, now time.Time) (bool, error) {
+	result := r.db.Model(&models.Job{}).Where("id = ? AND status = ?", id, models.JobDead).Updates(retryUpdates(now))
+	if result.Error != nil {
+		r.logger.Error("failed to retry job", zap.Uint("job_id", id), zap.Error(result.Error))
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// RetryDead requeues every dead job, or only those of jobType when it is set.
+func (r *jobRepository) RetryDead(jobType string, now time.Time) (int64, error) {
+	query := r.db.Model(&models.Job{}).Where("status = ?", models.JobDead)
+	if jobType != "" {
+		query = query.Where("type = ?", jobType)
+	}
+	result := query.Updates(retryUpdates(now))
+	if result.Error != nil {
+		r.logger.Error("failed to retry dead jobs", zap.Error(result.Error))
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
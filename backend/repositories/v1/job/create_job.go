@@ -0,0 +1,19 @@
+package job_repository
+
+import (
+	"flower-backend/models"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm/clause"
+)
+
+// Create inserts job and reports whether a row was added. A job whose
+// UniqueKey is already taken is skipped rather than treated as an error.
+func (r *jobRepository) Create(job *models.Job) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(job)
+	if result.Error != nil {
+		r.logger.Error("failed to create job", zap.String("type", job.Type), zap.Error(result.Error))
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
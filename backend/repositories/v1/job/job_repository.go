@@ -0,0 +1,52 @@
+package job_repository
+
+import (
+	"context"
+	"flower-backend/config"
+	"flower-backend/log"
+	"flower-backend/models"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// JobFilter narrows an admin job listing; nil or empty fields match everything.
+type JobFilter struct {
+	Status string
+	Type   string
+}
+
+type JobRepository interface {
+	// WithContext returns a repository whose calls run under ctx
+	WithContext(ctx context.Context) JobRepository
+	Create(job *models.Job) (bool, error)
+	Claim(types []string, worker string, now time.Time, limit int) ([]models.Job, error)
+	Complete(id uint, worker string, now time.Time) error
+	Reschedule(id uint, worker, lastError string, runAt time.Time) error
+	Bury(id uint, worker, lastError string, now time.Time) error
+	RequeueStale(lockedBefore, now time.Time) (int64, error)
+	GetByID(id uint) (*models.Job, error)
+	GetAllWithFilter(filter JobFilter, page, limit int) ([]models.Job, int64, error)
+	Retry(id uint, now time.Time) (bool, error)
+	RetryDead(jobType string, now time.Time) (int64, error)
+	DeleteFinishedBefore(before time.Time) (int64, error)
+}
+
+type jobRepository struct {
+	db     *gorm.DB
+	cfg    *config.Config
+	logger *zap.SugaredLogger
+}
+
+func NewJobRepository(db *gorm.DB, cfg *config.Config, logger *zap.SugaredLogger) JobRepository {
+	return newTracedJobRepository(&jobRepository{
+		db:     db,
+		cfg:    cfg,
+		logger: logger,
+	})
+}
+
+func (r *jobRepository) WithContext(ctx context.Context) JobRepository {
+	return &jobRepository{db: r.db.WithContext(ctx), cfg: r.cfg, logger: log.SugarFromContext(ctx, r.logger)}
+}
@@ -0,0 +1,145 @@
+package job_repository
+
+import (
+	"errors"
+	"flower-backend/models"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ErrLockLost is returned when a worker records the outcome of a job it no
+// longer holds: the job went stale, was requeued and may be running elsewhere.
+var ErrLockLost = errors.New("job is no longer locked by this worker")
+
+// Claim locks up to limit due jobs of the given types for worker. Each row
+// is taken with a guarded UPDATE, so when several replicas race for the same
+// job exactly one of them gets it; no row-locking dialect features are needed.
+func (r *jobRepository) Claim(types []string, worker string, now time.Time, limit int) ([]models.Job, error) {
+	if len(types) == 0 || limit < 1 {
+		return nil, nil
+	}
+
+	var candidates []models.Job
+	err := r.db.Where("status = ? AND run_at <= ? AND type IN ?", models.JobQueued, now, types).
+		Order("run_at").
+		Limit(limit).
+		Find(&candidates).Error
+	if err != nil {
+		r.logger.Error("failed to find due jobs", zap.Error(err))
+		return nil, err
+	}
+
+	claimed := candidates[:0]
+	for _, job := range candidates {
+		result := r.db.Model(&models.Job{}).
+			Where("id = ? AND status = ?", job.ID, models.JobQueued).
+			Updates(map[string]any{
+				"status":    models.JobRunning,
+				"locked_by": worker,
+				"locked_at": now,
+				"attempts":  gorm.Expr("attempts + 1"),
+			})
+		if result.Error != nil {
+			r.logger.Error("failed to claim job", zap.Uint("job_id", job.ID), zap.Error(result.Error))
+			return claimed, result.Error
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+		job.Status = models.JobRunning
+		job.LockedBy = worker
+		job.LockedAt = &now
+		job.Attempts++
+		claimed = append(claimed, job)
+	}
+	return claimed, nil
+}
+
+// Complete marks a job worker holds as succeeded.
+func (r *jobRepository) Complete(id uint, worker string, now time.Time) error {
+	err := r.release(id, worker, map[string]any{
+		"status":      models.JobSucceeded,
+		"finished_at": now,
+		"last_error":  "",
+	})
+	if err != nil && !errors.Is(err, ErrLockLost) {
+		r.logger.Error("failed to complete job", zap.Uint("job_id", id), zap.Error(err))
+	}
+	return err
+}
+
+// Reschedule puts a failed job worker holds back in the queue to run again
+// at runAt.
+func (r *jobRepository) Reschedule(id uint, worker, lastError string, runAt time.Time) error {
+	err := r.release(id, worker, map[string]any{
+		"status":     models.JobQueued,
+		"run_at":     runAt,
+		"last_error": lastError,
+	})
+	if err != nil && !errors.Is(err, ErrLockLost) {
+		r.logger.Error("failed to reschedule job", zap.Uint("job_id", id), zap.Error(err))
+	}
+	return err
+}
+
+// Bury marks a job worker holds dead after its last attempt failed.
+func (r *jobRepository) Bury(id uint, worker, lastError string, now time.Time) error {
+	err := r.release(id, worker, map[string]any{
+		"status":      models.JobDead,
+		"finished_at": now,
+		"last_error":  lastError,
+	})
+	if err != nil && !errors.Is(err, ErrLockLost) {
+		r.logger.Error("failed to bury job", zap.Uint("job_id", id), zap.Error(err))
+	}
+	return err
+}
+
+// release unlocks a job with updates applied, provided worker still holds
+// it. Otherwise the job has been requeued since and ErrLockLost is returned.
+func (r *jobRepository) release(id uint, worker string, updates map[string]any) error {
+	updates["locked_by"] = ""
+	updates["locked_at"] = nil
+	result := r.db.Model(&models.Job{}).
+		Where("id = ? AND status = ? AND locked_by = ?", id, models.JobRunning, worker).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrLockLost
+	}
+	return nil
+}
+
+// RequeueStale returns jobs whose worker stopped reporting, such as after a
+// crash, to the queue. The attempt they used stays counted.
+func (r *jobRepository) RequeueStale(lockedBefore, now time.Time) (int64, error) {
+	result := r.db.Model(&models.Job{}).
+		Where("status = ? AND locked_at < ?", models.JobRunning, lockedBefore).
+		Updates(map[string]any{
+			"status":     models.JobQueued,
+			"locked_by":  "",
+			"locked_at":  nil,
+			"run_at":     now,
+			"last_error": "worker stopped before the job finished",
+		})
+	if result.Error != nil {
+		r.logger.Error("failed to requeue stale jobs", zap.Error(result.Error))
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+// DeleteFinishedBefore removes succeeded jobs that finished before the
+// cutoff. Dead jobs are kept for inspection until retried or cleared by hand.
+func (r *jobRepository) DeleteFinishedBefore(before time.Time) (int64, error) {
+	result := r.db.Where("status = ? AND finished_at < ?", models.JobSucceeded, before).Delete(&models.Job{})
+	if result.Error != nil {
+		r.logger.Error("failed to delete finished jobs", zap.Error(result.Error))
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
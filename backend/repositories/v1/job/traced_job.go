@@ -0,0 +1,100 @@
+package job_repository
+
+import (
+	"context"
+	"flower-backend/models"
+	"flower-backend/tracing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("flower-backend/repositories/v1/job")
+
+// tracedJobRepository starts a span around every call and runs the wrapped
+// repository under it, so the GORM query spans nest beneath.
+type tracedJobRepository struct {
+	JobRepository
+	ctx context.Context
+}
+
+func newTracedJobRepository(repo JobRepository) JobRepository {
+	return &tracedJobRepository{JobRepository: repo, ctx: context.Background()}
+}
+
+func (r *tracedJobRepository) WithContext(ctx context.Context) JobRepository {
+	return &tracedJobRepository{JobRepository: r.JobRepository, ctx: ctx}
+}
+
+// start opens the span for method and returns the wrapped repository bound to it.
+func (r *tracedJobRepository) start(method string) (JobRepository, trace.Span) {
+	ctx, span := tracer.Start(r.ctx, "JobRepository."+method)
+	return r.JobRepository.WithContext(ctx), span
+}
+
+func (r *tracedJobRepository) Create(job *models.Job) (_ bool, err error) {
+	repo, span := r.start("Create")
+	defer tracing.End(span, &err)
+	return repo.Create(job)
+}
+
+func (r *tracedJobRepository) Claim(types []string, worker string, now time.Time, limit int) (_ []models.Job, err error) {
+	repo, span := r.start("Claim")
+	defer tracing.End(span, &err)
+	return repo.Claim(types, worker, now, limit)
+}
+
+func (r *tracedJobRepository) Complete(id uint, worker string, now time.Time) (err error) {
+	repo, span := r.start("Complete")
+	defer tracing.End(span, &err)
+	return repo.Complete(id, worker, now)
+}
+
+func (r *tracedJobRepository) Reschedule(id uint, worker, lastError string, runAt time.Time) (err error) {
+	repo, span := r.start("Reschedule")
+	defer tracing.End(span, &err)
+	return repo.Reschedule(id, worker, lastError, runAt)
+}
+
+func (r *tracedJobRepository) Bury(id uint, worker, lastError string, now time.Time) (err error) {
+	repo, span := r.start("Bury")
+	defer tracing.End(span, &err)
+	return repo.Bury(id, worker, lastError, now)
+}
+
+func (r *tracedJobRepository) RequeueStale(lockedBefore, now time.Time) (_ int64, err error) {
+	repo, span := r.start("RequeueStale")
+	defer tracing.End(span, &err)
+	return repo.RequeueStale(lockedBefore, now)
+}
+
+func (r *tracedJobRepository) GetByID(id uint) (_ *models.Job, err error) {
+	repo, span := r.start("GetByID")
+	defer tracing.End(span, &err)
+	return repo.GetByID(id)
+}
+
+func (r *tracedJobRepository) GetAllWithFilter(filter JobFilter, page, limit int) (_ []models.Job, _ int64, err error) {
+	repo, span := r.start("GetAllWithFilter")
+	defer tracing.End(span, &err)
+	return repo.GetAllWithFilter(filter, page, limit)
+}
+
+func (r *tracedJobRepository) Retry(id uint, now time.Time) (_ bool, err error) {
+	repo, span := r.start("Retry")
+	defer tracing.End(span, &err)
+	return repo.Retry(id, now)
+}
+
+func (r *tracedJobRepository) RetryDead(jobType string, now time.Time) (_ int64, err error) {
+	repo, span := r.start("RetryDead")
+	defer tracing.End(span, &err)
+	return repo.RetryDead(jobType, now)
+}
+
+func (r *tracedJobRepository) DeleteFinishedBefore(before time.Time) (_ int64, err error) {
+	repo, span := r.start("DeleteFinishedBefore")
+	defer tracing.End(span, &err)
+	return repo.DeleteFinishedBefore(before)
+}
@@ -0,0 +1,142 @@
+package post_repository
+
+import (
+	"flower-backend/models"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PostFilter narrows admin post listings. Nil fields are not filtered on.
+type PostFilter struct {
+	AuthorID *uint
+	From     *time.Time
+	To       *time.Time
+	Reported *bool
+	Hidden   *bool
+}
+
+func (r *postRepository) GetAllWithFilter(filter PostFilter, page, limit int) ([]models.Post, int64, error) {
+	var posts []models.Post
+	var total int64
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 20
+	}
+
+	offset := (page - 1) * limit
+
+	query := r.db.Model(&models.Post{})
+	if filter.AuthorID != nil {
+		query = query.Where("posts.user_id = ?", *filter.AuthorID)
+	}
+	if filter.From != nil {
+		query = query.Where("posts.created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("posts.created_at < ?", *filter.To)
+	}
+	if filter.Hidden != nil {
+		query = query.Where("posts.hidden = ?", *filter.Hidden)
+	}
+	if filter.Reported != nil {
+		reported := r.db.Table("post_reports").Select("1").Where("post_reports.post_id = posts.id")
+		if *filter.Reported {
+			query = query.Where("EXISTS (?)", reported)
+		} else {
+			query = query.Where("NOT EXISTS (?)", reported)
+		}
+	}
+
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		r.logger.Error("failed to count filtered posts", zap.Error(err))
+		return nil, 0, err
+	}
+
+	err := query.Preload("User").Preload("Reports").
+		Order("posts.created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&posts).Error
+	if err != nil {
+		r.logger.Error("failed to get filtered posts", zap.Error(err))
+		return nil, 0, err
+	}
+	return posts, total, nil
+}
+
+func (r *postRepository) SetHidden(postIDs []uint, hidden bool) (int64, error) {
+	result := r.db.Model(&models.Post{}).Where("id IN ?", postIDs).Update("hidden", hidden)
+	if result.Error != nil {
+		r.logger.Error("failed to update post visibility", zap.Error(result.Error))
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+func (r *postRepository) TransferOwnership(postID, newUserID uint) (*models.Post, error) {
+	var userCount int64
+	if err := r.db.Model(&models.User{}).Where("id = ?", newUserID).Count(&userCount).Error; err != nil {
+		r.logger.Error("failed to check if user exists", zap.Error(err))
+		return nil, err
+	}
+	if userCount == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	var post models.Post
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&post, postID).Error; err != nil {
+			return err
+		}
+		if post.UserID == newUserID {
+			return nil
+		}
+		oldUserID := post.UserID
+		if err := tx.Model(&post).UpdateColumn("user_id", newUserID).Error; err != nil {
+			return err
+		}
+		post.UserID = newUserID
+		if post.Status != models.PostPublished {
+			return nil
+		}
+		// Move the post from the previous owner's counter to the new owner's
+		if err := tx.Model(&models.User{}).Where("id = ? AND posts_count > 0", oldUserID).
+			UpdateColumn("posts_count", gorm.Expr("posts_count - ?", 1)).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.User{}).Where("id = ?", newUserID).
+			UpdateColumn("posts_count", gorm.Expr("posts_count + ?", 1)).Error
+	})
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, gorm.ErrRecordNotFound
+		}
+		r.logger.Error("failed to transfer post ownership", zap.Error(err))
+		return nil, err
+	}
+	return &post, nil
+}
+
+func (r *postRepository) CreateReport(report *models.PostReport) error {
+	var postCount int64
+	if err := r.db.Model(&models.Post{}).Where("id = ?", report.PostID).Count(&postCount).Error; err != nil {
+		r.logger.Error("failed to check if post exists", zap.Error(err))
+		return err
+	}
+	if postCount == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	// A user can report a post once; repeated reports are a no-op
+	if err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(report).Error; err != nil {
+		r.logger.Error("failed to create post report", zap.Error(err))
+		return err
+	}
+	return nil
+}
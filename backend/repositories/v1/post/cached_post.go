@@ -0,0 +1,186 @@
+package post_repository
+
+import (
+	"context"
+	"flower-backend/cache"
+	"flower-backend/log"
+	"flower-backend/models"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// cachedPostRepository serves the hottest post reads from cache and drops
+// the affected keys after every write. Bulk maintenance such as RecountLikes
+// does not enumerate keys; those entries age out within the TTL.
+type cachedPostRepository struct {
+	PostRepository
+	store  cache.Cache
+	ttl    time.Duration
+	logger *zap.SugaredLogger
+}
+
+func newCachedPostRepository(repo PostRepository, store cache.Cache, ttl time.Duration, logger *zap.SugaredLogger) PostRepository {
+	return &cachedPostRepository{PostRepository: repo, store: store, ttl: ttl, logger: logger}
+}
+
+func (r *cachedPostRepository) WithContext(ctx context.Context) PostRepository {
+	return &cachedPostRepository{PostRepository: r.PostRepository.WithContext(ctx), store: r.store, ttl: r.ttl, logger: log.SugarFromContext(ctx, r.logger)}
+}
+
+func (r *cachedPostRepository) GetByID(id uint) (*models.Post, error) {
+	key := cache.PostKey(id)
+	var post models.Post
+	if cache.Load(r.store, key, &post) {
+		return &post, nil
+	}
+	found, err := r.PostRepository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	cache.Save(r.store, key, found, r.ttl)
+	return found, nil
+}
+
+func (r *cachedPostRepository) GetAll() ([]models.Post, error) {
+	var posts []models.Post
+	if cache.Load(r.store, cache.PostsAllKey, &posts) {
+		return posts, nil
+	}
+	posts, err := r.PostRepository.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	cache.Save(r.store, cache.PostsAllKey, posts, r.ttl)
+	return posts, nil
+}
+
+func (r *cachedPostRepository) Create(post *models.Post) error {
+	err := r.PostRepository.Create(post)
+	r.store.Delete(cache.PostsAllKey, cache.UserKey(post.UserID))
+	return err
+}
+
+func (r *cachedPostRepository) CreateWithUpload(post *models.Post, image []byte) error {
+	err := r.PostRepository.CreateWithUpload(post, image)
+	r.store.Delete(cache.PostsAllKey, cache.UserKey(post.UserID))
+	return err
+}
+
+func (r *cachedPostRepository) CompleteUpload(postID uint, images models.PostImages) (bool, error) {
+	completed, err := r.PostRepository.CompleteUpload(postID, images)
+	r.store.Delete(append(r.publishedKeys(postID), cache.PostsAllKey)...)
+	return completed, err
+}
+
+func (r *cachedPostRepository) FailUpload(postID uint, reason string) error {
+	err := r.PostRepository.FailUpload(postID, reason)
+	r.store.Delete(cache.PostKey(postID))
+	return err
+}
+
+func (r *cachedPostRepository) RetryUpload(postID uint, image []byte) (bool, error) {
+	retried, err := r.PostRepository.RetryUpload(postID, image)
+	r.store.Delete(cache.PostKey(postID))
+	return retried, err
+}
+
+func (r *cachedPostRepository) CreateWithAsset(post *models.Post, assetID string) (bool, error) {
+	created, err := r.PostRepository.CreateWithAsset(post, assetID)
+	r.store.Delete(cache.PostsAllKey, cache.UserKey(post.UserID))
+	return created, err
+}
+
+func (r *cachedPostRepository) UpdateDraft(postID uint, updates map[string]any) (bool, error) {
+	updated, err := r.PostRepository.UpdateDraft(postID, updates)
+	r.store.Delete(cache.PostKey(postID))
+	return updated, err
+}
+
+func (r *cachedPostRepository) PublishDraft(postID uint, now time.Time) (bool, error) {
+	published, err := r.PostRepository.PublishDraft(postID, now)
+	r.store.Delete(append(r.publishedKeys(postID), cache.PostsAllKey)...)
+	return published, err
+}
+
+func (r *cachedPostRepository) PublishDue(now time.Time, limit int) ([]uint, error) {
+	published, err := r.PostRepository.PublishDue(now, limit)
+	if len(published) > 0 {
+		r.store.Delete(append(r.publishedKeys(published...), cache.PostsAllKey)...)
+	}
+	return published, err
+}
+
+// publishedKeys returns the keys of posts that went public and of their
+// authors, whose posts_count moved with them.
+func (r *cachedPostRepository) publishedKeys(postIDs ...uint) []string {
+	keys := make([]string, 0, 2*len(postIDs))
+	for _, postID := range postIDs {
+		keys = append(keys, cache.PostKey(postID))
+		if post, err := r.PostRepository.GetByID(postID); err == nil {
+			keys = append(keys, cache.UserKey(post.UserID))
+		}
+	}
+	return keys
+}
+
+func (r *cachedPostRepository) UpdateByIDWithSelect(postId uint, updates map[string]any, selectFields []string) (*models.Post, error) {
+	post, err := r.PostRepository.UpdateByIDWithSelect(postId, updates, selectFields)
+	r.store.Delete(cache.PostKey(postId), cache.PostsAllKey)
+	return post, err
+}
+
+func (r *cachedPostRepository) DeleteByID(postID, userID uint) error {
+	err := r.PostRepository.DeleteByID(postID, userID)
+	r.store.Delete(cache.PostKey(postID), cache.PostsAllKey, cache.UserKey(userID))
+	return err
+}
+
+func (r *cachedPostRepository) AdminDeleteByID(postID uint) error {
+	keys := []string{cache.PostKey(postID), cache.PostsAllKey}
+	// The author's post count changes too
+	if post, err := r.PostRepository.GetByID(postID); err == nil {
+		keys = append(keys, cache.UserKey(post.UserID))
+	}
+	err := r.PostRepository.AdminDeleteByID(postID)
+	r.store.Delete(keys...)
+	return err
+}
+
+func (r *cachedPostRepository) Like(postID, userID uint) error {
+	err := r.PostRepository.Like(postID, userID)
+	r.store.Delete(cache.PostKey(postID), cache.PostsAllKey, cache.UserKey(userID))
+	return err
+}
+
+func (r *cachedPostRepository) Unlike(postID, userID uint) error {
+	err := r.PostRepository.Unlike(postID, userID)
+	r.store.Delete(cache.PostKey(postID), cache.PostsAllKey, cache.UserKey(userID))
+	return err
+}
+
+func (r *cachedPostRepository) SetHidden(postIDs []uint, hidden bool) (int64, error) {
+	affected, err := r.PostRepository.SetHidden(postIDs, hidden)
+	keys := []string{cache.PostsAllKey}
+	for _, id := range postIDs {
+		keys = append(keys, cache.PostKey(id))
+	}
+	r.store.Delete(keys...)
+	return affected, err
+}
+
+func (r *cachedPostRepository) TransferOwnership(postID, newUserID uint) (*models.Post, error) {
+	keys := []string{cache.PostKey(postID), cache.PostsAllKey, cache.UserKey(newUserID)}
+	if post, err := r.PostRepository.GetByID(postID); err == nil {
+		keys = append(keys, cache.UserKey(post.UserID))
+	}
+	post, err := r.PostRepository.TransferOwnership(postID, newUserID)
+	r.store.Delete(keys...)
+	return post, err
+}
+
+func (r *cachedPostRepository) ReplaceTags(postID uint, tags []models.Tag) error {
+	err := r.PostRepository.ReplaceTags(postID, tags)
+	r.store.Delete(cache.PostKey(postID), cache.PostsAllKey)
+	return err
+}
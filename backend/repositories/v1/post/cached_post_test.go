@@ -0,0 +1,107 @@
+package post_repository
+
+import (
+	"flower-backend/cache"
+	"flower-backend/testutil"
+	"testing"
+	"time"
+)
+
+func TestCachedPostRepositoryInvalidation(t *testing.T) {
+	inner, db := newTestRepository(t)
+	repo := newCachedPostRepository(inner, cache.NewMemoryCache(100), time.Minute, testutil.Logger())
+	author := testutil.CreateUser(t, db)
+	fan := testutil.CreateUser(t, db)
+	post := testutil.CreatePost(t, db, author.ID)
+
+	if _, err := repo.GetByID(post.ID); err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if all, _ := repo.GetAll(); len(all) != 1 {
+		t.Fatalf("GetAll() returned %d posts, want 1", len(all))
+	}
+
+	steps := []struct {
+		name  string
+		write func() error
+		check func(t *testing.T)
+	}{
+		{
+			name:  "Like",
+			write: func() error { return repo.Like(post.ID, fan.ID) },
+			check: func(t *testing.T) {
+				if got, _ := repo.GetByID(post.ID); got.LikesCount != 1 {
+					t.Errorf("cached likes_count = %d, want 1", got.LikesCount)
+				}
+			},
+		},
+		{
+			name: "UpdateByIDWithSelect",
+			write: func() error {
+				_, err := repo.UpdateByIDWithSelect(post.ID, map[string]any{"title": "Renamed"}, []string{"title"})
+				return err
+			},
+			check: func(t *testing.T) {
+				if got, _ := repo.GetByID(post.ID); got.Title != "Renamed" {
+					t.Errorf("cached title = %q, want Renamed", got.Title)
+				}
+				if all, _ := repo.GetAll(); all[0].Title != "Renamed" {
+					t.Errorf("cached listing title = %q, want Renamed", all[0].Title)
+				}
+			},
+		},
+		{
+			name: "SetHidden",
+			write: func() error {
+				_, err := repo.SetHidden([]uint{post.ID}, true)
+				return err
+			},
+			check: func(t *testing.T) {
+				if got, _ := repo.GetByID(post.ID); !got.Hidden {
+					t.Error("cached post is not hidden")
+				}
+			},
+		},
+		{
+			name:  "DeleteByID",
+			write: func() error { return repo.DeleteByID(post.ID, author.ID) },
+			check: func(t *testing.T) {
+				if _, err := repo.GetByID(post.ID); err == nil {
+					t.Error("deleted post is still served from the cache")
+				}
+				if all, _ := repo.GetAll(); len(all) != 0 {
+					t.Errorf("cached listing has %d posts, want 0", len(all))
+				}
+			},
+		},
+	}
+	for _, step := range steps {
+		t.Run(step.name, func(t *testing.T) {
+			if err := step.write(); err != nil {
+				t.Fatalf("%s error = %v", step.name, err)
+			}
+			step.check(t)
+		})
+	}
+}
+
+func TestCachedPostRepositoryAdminDelete(t *testing.T) {
+	inner, db := newTestRepository(t)
+	store := cache.NewMemoryCache(100)
+	repo := newCachedPostRepository(inner, store, time.Minute, testutil.Logger())
+	author := testutil.CreateUser(t, db)
+	post := testutil.CreatePost(t, db, author.ID)
+	repo.GetByID(post.ID)
+	cache.Save(store, cache.UserKey(author.ID), author, time.Minute)
+
+	if err := repo.AdminDeleteByID(post.ID); err != nil {
+		t.Fatalf("AdminDeleteByID() error = %v", err)
+	}
+	if _, err := repo.GetByID(post.ID); err == nil {
+		t.Error("deleted post is still served from the cache")
+	}
+	// The author's posts_count changed, so their cached profile must go
+	if _, ok := store.Get(cache.UserKey(author.ID)); ok {
+		t.Error("author is still cached after their post was deleted")
+	}
+}
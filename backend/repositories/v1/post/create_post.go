@@ -4,10 +4,23 @@ import (
 	"flower-backend/models"
 
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 func (r *postRepository) Create(post *models.Post) error {
-	if err := r.db.Create(post).Error; err != nil {
+	if post.Status == "" {
+		post.Status = models.PostPublished
+	}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(post).Error; err != nil {
+			return err
+		}
+		if post.Status != models.PostPublished {
+			return nil
+		}
+		return published(tx, post.ID)
+	})
+	if err != nil {
 		r.logger.Error("failed to create post", zap.Error(err))
 		return err
 	}
@@ -1,6 +1,7 @@
 package post_repository
 
 import (
+	"flower-backend/jobs"
 	"flower-backend/libs"
 	"flower-backend/models"
 
@@ -8,9 +9,21 @@ import (
 	"gorm.io/gorm"
 )
 
+// DeleteByID deletes postID and everything hanging off it. A post userID did
+// not write is reported as gorm.ErrRecordNotFound.
 func (r *postRepository) DeleteByID(postID, userID uint) error {
+	return r.deletePost(r.db.Where("id = ? AND user_id = ?", postID, userID))
+}
+
+// AdminDeleteByID deletes postID like DeleteByID, whoever wrote it.
+func (r *postRepository) AdminDeleteByID(postID uint) error {
+	return r.deletePost(r.db.Where("id = ?", postID))
+}
+
+// deletePost deletes the post query finds.
+func (r *postRepository) deletePost(query *gorm.DB) error {
 	var post models.Post
-	if err := r.db.First(&post, postID).Error; err != nil {
+	if err := query.First(&post).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return gorm.ErrRecordNotFound
 		}
@@ -18,25 +31,76 @@ func (r *postRepository) DeleteByID(postID, userID uint) error {
 		return err
 	}
 
-	if post.ImageURL != "" {
-		cld, _ := libs.NewCloudinary(r.cfg)
-		publicId := libs.ExtractPublicId(post.ImageURL)
-		if err := libs.DeleteFromCloudinary(cld, publicId); err != nil {
-			r.logger.Error("failed to delete image from cloudinary", zap.Error(err))
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// The images are removed by background jobs created with the delete,
+		// so a storage outage neither blocks the delete nor loses them
+		for _, url := range post.Images().URLs() {
+			job, err := jobs.NewJob(jobs.TypeDeleteAsset, jobs.DeleteAsset{PublicID: libs.ExtractPublicId(url)})
+			if err != nil {
+				return err
+			}
+			if err := tx.Create(job).Error; err != nil {
+				r.logger.Error("failed to enqueue image deletion", zap.Error(err))
+				return err
+			}
+		}
+
+		// Delete all likes associated with this post first
+		if err := tx.Exec("DELETE FROM post_likes WHERE post_id = ?", post.ID).Error; err != nil {
+			r.logger.Error("failed to delete post likes", zap.Error(err))
 			return err
 		}
-	}
 
-	// Delete all likes associated with this post first
-	if err := r.db.Exec("DELETE FROM post_likes WHERE post_id = ?", postID).Error; err != nil {
-		r.logger.Error("failed to delete post likes", zap.Error(err))
-		return err
-	}
+		// Delete tag links and like buckets for this post
+		if err := tx.Exec("DELETE FROM post_tags WHERE post_id = ?", post.ID).Error; err != nil {
+			r.logger.Error("failed to delete post tags", zap.Error(err))
+			return err
+		}
+		if err := tx.Where("post_id = ?", post.ID).Delete(&models.PostLikeBucket{}).Error; err != nil {
+			r.logger.Error("failed to delete post like buckets", zap.Error(err))
+			return err
+		}
 
-	// Now delete the post
-	if err := r.db.Delete(&post).Error; err != nil {
-		r.logger.Error("failed to delete post", zap.Error(err))
-		return err
-	}
-	return nil
+		// Delete reports filed against this post
+		if err := tx.Where("post_id = ?", post.ID).Delete(&models.PostReport{}).Error; err != nil {
+			r.logger.Error("failed to delete post reports", zap.Error(err))
+			return err
+		}
+
+		// Delete an image still waiting to be uploaded
+		if err := tx.Where("post_id = ?", post.ID).Delete(&models.PostUpload{}).Error; err != nil {
+			r.logger.Error("failed to delete post upload", zap.Error(err))
+			return err
+		}
+
+		// Delete feed views recorded for this post
+		if err := tx.Where("post_id = ?", post.ID).Delete(&models.PostView{}).Error; err != nil {
+			r.logger.Error("failed to delete post views", zap.Error(err))
+			return err
+		}
+
+		// Delete notifications sent about this post
+		if err := tx.Where("post_id = ?", post.ID).Delete(&models.Notification{}).Error; err != nil {
+			r.logger.Error("failed to delete post notifications", zap.Error(err))
+			return err
+		}
+
+		// Now delete the post
+		result := tx.Delete(&post)
+		if result.Error != nil {
+			r.logger.Error("failed to delete post", zap.Error(result.Error))
+			return result.Error
+		}
+
+		// Only the request that actually removed the row adjusts the author's
+		// counter, which counts published posts
+		if result.RowsAffected > 0 && post.Status == models.PostPublished {
+			if err := tx.Model(&models.User{}).Where("id = ? AND posts_count > 0", post.UserID).
+				UpdateColumn("posts_count", gorm.Expr("posts_count - ?", 1)).Error; err != nil {
+				r.logger.Error("failed to decrement user posts count", zap.Error(err))
+				return err
+			}
+		}
+		return nil
+	})
 }
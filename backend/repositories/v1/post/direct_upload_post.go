@@ -0,0 +1,104 @@
+package post_repository
+
+import (
+	"errors"
+	"flower-backend/jobs"
+	"flower-backend/models"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// errUploadTaken rolls back CreateWithAsset when another post got the upload first.
+var errUploadTaken = errors.New("direct upload already used")
+
+// CreateDirectUpload records an upload signed for a user.
+func (r *postRepository) CreateDirectUpload(upload *models.DirectUpload) error {
+	if err := r.db.Create(upload).Error; err != nil {
+		r.logger.Error("failed to create direct upload", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// GetDirectUpload returns the signed upload of assetID.
+func (r *postRepository) GetDirectUpload(assetID string) (*models.DirectUpload, error) {
+	var upload models.DirectUpload
+	if err := r.db.Where("asset_id = ?", assetID).First(&upload).Error; err != nil {
+		if err != gorm.ErrRecordNotFound {
+			r.logger.Error("failed to get direct upload", zap.Error(err))
+		}
+		return nil, err
+	}
+	return &upload, nil
+}
+
+// CreateWithAsset creates a post whose image was uploaded directly as
+// assetID, published unless it has another status, and marks the upload as
+// used by it. It reports false, creating nothing, when the upload is gone or
+// another post already uses it.
+func (r *postRepository) CreateWithAsset(post *models.Post, assetID string) (bool, error) {
+	if post.Status == "" {
+		post.Status = models.PostPublished
+	}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(post).Error; err != nil {
+			return err
+		}
+		result := tx.Model(&models.DirectUpload{}).
+			Where("asset_id = ? AND post_id IS NULL", assetID).
+			Update("post_id", post.ID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errUploadTaken
+		}
+		if post.Status != models.PostPublished {
+			return nil
+		}
+		return published(tx, post.ID)
+	})
+	if errors.Is(err, errUploadTaken) {
+		post.ID = 0
+		return false, nil
+	}
+	if err != nil {
+		r.logger.Error("failed to create post with asset", zap.Error(err))
+		return false, err
+	}
+	return true, nil
+}
+
+// DeleteDirectUploadsBefore forgets uploads signed before the cutoff. The
+// images of those no post used are queued for deletion, since nothing will
+// reference them now.
+func (r *postRepository) DeleteDirectUploadsBefore(before time.Time) (int64, error) {
+	var deleted int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var unused []string
+		if err := tx.Model(&models.DirectUpload{}).
+			Where("created_at < ? AND post_id IS NULL", before).
+			Pluck("asset_id", &unused).Error; err != nil {
+			return err
+		}
+		for _, assetID := range unused {
+			job, err := jobs.NewJob(jobs.TypeDeleteAsset, jobs.DeleteAsset{PublicID: "flower-sharing/" + assetID})
+			if err != nil {
+				return err
+			}
+			if err := tx.Create(job).Error; err != nil {
+				return err
+			}
+		}
+		result := tx.Where("created_at < ?", before).Delete(&models.DirectUpload{})
+		deleted = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		r.logger.Error("failed to delete direct uploads", zap.Error(err))
+		return 0, err
+	}
+	return deleted, nil
+}
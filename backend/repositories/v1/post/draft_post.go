@@ -0,0 +1,173 @@
+package post_repository
+
+import (
+	"flower-backend/jobs"
+	"flower-backend/models"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// unpublished are the statuses a post's author can still edit and publish.
+var unpublished = []string{models.PostDraft, models.PostScheduled}
+
+// GetDraftsByUserID returns the user's drafts and scheduled posts, most
+// recently edited first.
+func (r *postRepository) GetDraftsByUserID(userID uint, page, limit int) ([]models.Post, int64, error) {
+	var posts []models.Post
+	var total int64
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 6
+	}
+
+	query := r.db.Model(&models.Post{}).Where("user_id = ? AND status IN ?", userID, unpublished)
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		r.logger.Error("failed to count drafts", zap.Error(err))
+		return nil, 0, err
+	}
+	err := query.
+		Preload("Tags").
+		Order("updated_at DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&posts).Error
+	if err != nil {
+		r.logger.Error("failed to get drafts", zap.Error(err))
+		return nil, 0, err
+	}
+	return posts, total, nil
+}
+
+// UpdateDraft applies updates to a draft or scheduled post. It reports
+// false when the post is gone or already published.
+func (r *postRepository) UpdateDraft(postID uint, updates map[string]any) (bool, error) {
+	result := r.db.Model(&models.Post{}).
+		Where("id = ? AND status IN ?", postID, unpublished).
+		Updates(updates)
+	if result.Error != nil {
+		r.logger.Error("failed to update draft", zap.Error(result.Error))
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// PublishDraft publishes a draft or scheduled post now. It reports false
+// when the post is gone or already published.
+func (r *postRepository) PublishDraft(postID uint, now time.Time) (bool, error) {
+	var published bool
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		published, err = publish(tx, postID, unpublished, now)
+		return err
+	})
+	if err != nil {
+		r.logger.Error("failed to publish draft", zap.Error(err))
+		return false, err
+	}
+	return published, nil
+}
+
+// PublishDue publishes up to limit scheduled posts whose time has come and
+// returns their IDs.
+func (r *postRepository) PublishDue(now time.Time, limit int) ([]uint, error) {
+	var due []uint
+	if err := r.db.Model(&models.Post{}).
+		Where("status = ? AND publish_at <= ?", models.PostScheduled, now).
+		Order("publish_at").
+		Limit(limit).
+		Pluck("id", &due).Error; err != nil {
+		r.logger.Error("failed to find due posts", zap.Error(err))
+		return nil, err
+	}
+
+	published := make([]uint, 0, len(due))
+	for _, postID := range due {
+		// one transaction per post, so a failure does not hold back the others
+		err := r.db.Transaction(func(tx *gorm.DB) error {
+			ok, err := publish(tx, postID, []string{models.PostScheduled}, now)
+			if ok {
+				published = append(published, postID)
+			}
+			return err
+		})
+		if err != nil {
+			r.logger.Error("failed to publish scheduled post", zap.Uint("id", postID), zap.Error(err))
+			return published, err
+		}
+	}
+	return published, nil
+}
+
+// NotifyFollowers tells the author's followers about a published post. It
+// is safe to repeat: followers already notified are skipped.
+func (r *postRepository) NotifyFollowers(postID uint) (int64, error) {
+	var post models.Post
+	if err := r.db.Select("id", "user_id", "status", "hidden").First(&post, postID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return 0, nil
+		}
+		return 0, err
+	}
+	if post.Status != models.PostPublished || post.Hidden {
+		return 0, nil
+	}
+
+	var followerIDs []uint
+	if err := r.db.Model(&models.UserFollow{}).
+		Where("following_id = ?", post.UserID).
+		Pluck("follower_id", &followerIDs).Error; err != nil {
+		r.logger.Error("failed to get followers", zap.Error(err))
+		return 0, err
+	}
+	if len(followerIDs) == 0 {
+		return 0, nil
+	}
+	notifications := make([]models.Notification, 0, len(followerIDs))
+	for _, followerID := range followerIDs {
+		notifications = append(notifications, models.Notification{
+			UserID:  followerID,
+			Type:    models.NotificationPostPublished,
+			PostID:  post.ID,
+			ActorID: post.UserID,
+		})
+	}
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(notifications, 500)
+	if result.Error != nil {
+		r.logger.Error("failed to notify followers", zap.Error(result.Error))
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+// publish moves a post in one of the from statuses to published; see
+// published. Listings order by created_at, so the post is dated from when it
+// went public.
+func publish(tx *gorm.DB, postID uint, from []string, now time.Time) (bool, error) {
+	result := tx.Model(&models.Post{}).
+		Where("id = ? AND status IN ?", postID, from).
+		Updates(map[string]any{"status": models.PostPublished, "publish_at": nil, "created_at": now})
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+	return true, published(tx, postID)
+}
+
+// published does what goes with a post becoming public: its author's
+// posts_count, which counts published posts only, goes up and the
+// notification of their followers is queued.
+func published(tx *gorm.DB, postID uint) error {
+	if err := tx.Exec("UPDATE users SET posts_count = posts_count + 1 WHERE id = (SELECT user_id FROM posts WHERE id = ?)", postID).Error; err != nil {
+		return err
+	}
+	job, err := jobs.NewJob(jobs.TypeNotifyFollowers, jobs.NotifyFollowers{PostID: postID})
+	if err != nil {
+		return err
+	}
+	return tx.Create(job).Error
+}
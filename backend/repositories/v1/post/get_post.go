@@ -2,6 +2,7 @@ package post_repository
 
 import (
 	"flower-backend/models"
+	"strings"
 
 	"go.uber.org/zap"
 	"gorm.io/gorm"
@@ -9,7 +10,7 @@ import (
 
 func (r *postRepository) GetByID(id uint) (*models.Post, error) {
 	var post models.Post
-	if err := r.db.Preload("User").Preload("Likes").Where("id = ?", id).First(&post).Error; err != nil {
+	if err := r.db.Preload("User").Preload("Tags").Where("id = ?", id).First(&post).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, gorm.ErrRecordNotFound
 		}
@@ -21,7 +22,7 @@ func (r *postRepository) GetByID(id uint) (*models.Post, error) {
 
 func (r *postRepository) GetAllByUserID(userID uint) ([]models.Post, error) {
 	var posts []models.Post
-	if err := r.db.Preload("User").Preload("Likes").Where("user_id = ?", userID).Find(&posts).Error; err != nil {
+	if err := r.db.Preload("User").Preload("Tags").Where("user_id = ? AND hidden = ? AND status = ?", userID, false, models.PostPublished).Find(&posts).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, gorm.ErrRecordNotFound
 		}
@@ -33,7 +34,7 @@ func (r *postRepository) GetAllByUserID(userID uint) ([]models.Post, error) {
 
 func (r *postRepository) GetAll() ([]models.Post, error) {
 	var posts []models.Post
-	if err := r.db.Preload("User").Preload("Likes").Find(&posts).Error; err != nil {
+	if err := r.db.Preload("User").Preload("Tags").Where("hidden = ? AND status = ?", false, models.PostPublished).Find(&posts).Error; err != nil {
 		r.logger.Error("failed to get all posts", zap.Error(err))
 		return nil, err
 	}
@@ -42,8 +43,10 @@ func (r *postRepository) GetAll() ([]models.Post, error) {
 
 func (r *postRepository) Search(query string) ([]models.Post, error) {
 	var posts []models.Post
-	if err := r.db.Preload("User").Preload("Likes").
-		Where("title LIKE ? OR content LIKE ?", "%"+query+"%", "%"+query+"%").
+	if err := r.db.Preload("User").Preload("Tags").
+		// LOWER keeps the search case-insensitive on PostgreSQL as well
+		Where("LOWER(title) LIKE ? OR LOWER(content) LIKE ?", "%"+strings.ToLower(query)+"%", "%"+strings.ToLower(query)+"%").
+		Where("hidden = ? AND status = ?", false, models.PostPublished).
 		Order("created_at DESC").
 		Find(&posts).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
@@ -68,12 +71,13 @@ func (r *postRepository) GetWithPagination(page, limit int) ([]models.Post, int6
 
 	offset := (page - 1) * limit
 
-	if err := r.db.Model(&models.Post{}).Count(&total).Error; err != nil {
+	if err := r.db.Model(&models.Post{}).Where("hidden = ? AND status = ?", false, models.PostPublished).Count(&total).Error; err != nil {
 		r.logger.Error("failed to get total posts", zap.Error(err))
 		return nil, 0, err
 	}
 
-	err := r.db.Preload("User").Preload("Likes").
+	err := r.db.Preload("User").Preload("Tags").
+		Where("hidden = ? AND status = ?", false, models.PostPublished).
 		Order("created_at DESC").
 		Offset(offset).
 		Limit(limit).
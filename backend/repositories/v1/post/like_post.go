@@ -2,15 +2,20 @@ package post_repository
 
 import (
 	"flower-backend/models"
+	"time"
 
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 func (r *postRepository) Like(postID, userID uint) error {
-	// Check if post exists
+	// Only posts the public can see may be liked; drafts, processing and
+	// hidden posts are reported as missing
 	var postCount int64
-	if err := r.db.Model(&models.Post{}).Where("id = ?", postID).Count(&postCount).Error; err != nil {
+	if err := r.db.Model(&models.Post{}).
+		Where("id = ? AND status = ? AND hidden = ?", postID, models.PostPublished, false).
+		Count(&postCount).Error; err != nil {
 		r.logger.Error("failed to check if post exists", zap.Error(err))
 		return err
 	}
@@ -29,42 +34,62 @@ func (r *postRepository) Like(postID, userID uint) error {
 		return gorm.ErrRecordNotFound
 	}
 
-	// Insert directly into the join table using raw SQL
-	// This is more efficient than loading full objects
-	// Using INSERT IGNORE for MySQL to handle potential race conditions
-	result := r.db.Exec(
-		"INSERT IGNORE INTO post_likes (post_id, user_id) VALUES (?, ?)",
-		postID, userID,
-	)
-	if result.Error != nil {
-		r.logger.Error("failed to like post",
-			zap.Uint("post_id", postID),
-			zap.Uint("user_id", userID),
-			zap.Error(result.Error))
-		return result.Error
-	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Insert directly into the join table
+		// This is more efficient than loading full objects
+		// ON CONFLICT DO NOTHING (ON DUPLICATE KEY on MySQL) handles potential race conditions
+		like := models.PostLike{PostID: postID, UserID: userID, CreatedAt: time.Now()}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&like)
+		if result.Error != nil {
+			r.logger.Error("failed to like post",
+				zap.Uint("post_id", postID),
+				zap.Uint("user_id", userID),
+				zap.Error(result.Error))
+			return result.Error
+		}
 
-	// INSERT IGNORE returns 0 rows affected if duplicate exists (race condition)
-	// This is fine - the service layer already checks for duplicates before calling this
-	// If we get here and RowsAffected is 0, it means another request inserted it first
-	// which is acceptable behavior (idempotent operation)
-	return nil
+		// The insert affects 0 rows if a duplicate exists (race condition)
+		// This is fine - the service layer already checks for duplicates before calling this
+		// If we get here and RowsAffected is 0, it means another request inserted it first
+		// which is acceptable behavior (idempotent operation), and the counter is left alone
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		if err := tx.Model(&models.Post{}).Where("id = ?", postID).
+			UpdateColumn("likes_count", gorm.Expr("likes_count + ?", 1)).Error; err != nil {
+			r.logger.Error("failed to increment post likes count", zap.Error(err))
+			return err
+		}
+		return nil
+	})
 }
 
 func (r *postRepository) Unlike(postID, userID uint) error {
-	// Delete directly from the join table using raw SQL
-	// This is more efficient than loading full objects
-	result := r.db.Exec(
-		"DELETE FROM post_likes WHERE post_id = ? AND user_id = ?",
-		postID, userID,
-	)
-	if result.Error != nil {
-		r.logger.Error("failed to unlike post", zap.Error(result.Error))
-		return result.Error
+	var deleted int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Delete directly from the join table using raw SQL
+		// This is more efficient than loading full objects
+		result := tx.Exec(
+			"DELETE FROM post_likes WHERE post_id = ? AND user_id = ?",
+			postID, userID,
+		)
+		if result.Error != nil {
+			return result.Error
+		}
+		deleted = result.RowsAffected
+		if deleted == 0 {
+			return nil
+		}
+		return tx.Model(&models.Post{}).Where("id = ? AND likes_count > 0", postID).
+			UpdateColumn("likes_count", gorm.Expr("likes_count - ?", 1)).Error
+	})
+	if err != nil {
+		r.logger.Error("failed to unlike post", zap.Error(err))
+		return err
 	}
 
 	// Check if any rows were affected (post or like might not exist)
-	if result.RowsAffected == 0 {
+	if deleted == 0 {
 		// Check if post exists
 		var postCount int64
 		if err := r.db.Model(&models.Post{}).Where("id = ?", postID).Count(&postCount).Error; err != nil {
@@ -91,9 +116,28 @@ func (r *postRepository) CheckLikeExists(postID, userID uint) (bool, error) {
 	return count > 0, nil
 }
 
+// GetLikedPostIDs reports which of postIDs the user has liked in a single query.
+func (r *postRepository) GetLikedPostIDs(userID uint, postIDs []uint) (map[uint]bool, error) {
+	liked := make(map[uint]bool)
+	if len(postIDs) == 0 {
+		return liked, nil
+	}
+	var ids []uint
+	if err := r.db.Table("post_likes").
+		Where("user_id = ? AND post_id IN ?", userID, postIDs).
+		Pluck("post_id", &ids).Error; err != nil {
+		r.logger.Error("failed to get liked post ids", zap.Error(err))
+		return nil, err
+	}
+	for _, id := range ids {
+		liked[id] = true
+	}
+	return liked, nil
+}
+
 func (r *postRepository) GetLikesCount(postID uint) (int64, error) {
 	var count int64
-	if err := r.db.Table("post_likes").Where("post_id = ?", postID).Count(&count).Error; err != nil {
+	if err := r.db.Model(&models.Post{}).Where("id = ?", postID).Pluck("likes_count", &count).Error; err != nil {
 		r.logger.Error("failed to get post likes", zap.Error(err))
 		return 0, err
 	}
@@ -112,16 +156,17 @@ func (r *postRepository) GetUserLikedPosts(userID uint, page, limit int) ([]mode
 	}
 
 	offset := (page - 1) * limit
-	if err := r.db.Table("posts").Count(&total).Error; err != nil {
+	query := r.db.Model(&models.Post{}).
+		Joins("JOIN post_likes ON post_likes.post_id = posts.id").
+		Where("post_likes.user_id = ? AND posts.hidden = ? AND posts.status = ?", userID, false, models.PostPublished)
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
 		r.logger.Error("failed to get total posts", zap.Error(err))
 		return nil, 0, err
 	}
 
-	err := r.db.
-		Joins("JOIN post_likes ON post_likes.post_id = posts.id").
-		Where("post_likes.user_id = ?", userID).
+	err := query.
 		Preload("User").
-		Preload("Likes").
+		Preload("Tags").
 		Order("posts.created_at DESC").
 		Offset(offset).
 		Limit(limit).
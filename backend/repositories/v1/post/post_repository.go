@@ -1,28 +1,58 @@
 package post_repository
 
 import (
+	"context"
+	"flower-backend/cache"
 	"flower-backend/config"
+	"flower-backend/log"
 	"flower-backend/models"
+	"time"
 
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
 type PostRepository interface {
+	// WithContext returns a repository whose calls run under ctx
+	WithContext(ctx context.Context) PostRepository
 	Create(post *models.Post) error
+	CreateWithUpload(post *models.Post, image []byte) error
+	GetUpload(postID uint) (*models.PostUpload, error)
+	CompleteUpload(postID uint, images models.PostImages) (bool, error)
+	FailUpload(postID uint, reason string) error
+	RetryUpload(postID uint, image []byte) (bool, error)
+	CreateDirectUpload(upload *models.DirectUpload) error
+	GetDirectUpload(assetID string) (*models.DirectUpload, error)
+	CreateWithAsset(post *models.Post, assetID string) (bool, error)
+	DeleteDirectUploadsBefore(before time.Time) (int64, error)
+	GetDraftsByUserID(userID uint, page, limit int) ([]models.Post, int64, error)
+	UpdateDraft(postID uint, updates map[string]any) (bool, error)
+	PublishDraft(postID uint, now time.Time) (bool, error)
+	PublishDue(now time.Time, limit int) ([]uint, error)
+	NotifyFollowers(postID uint) (int64, error)
 	GetByID(id uint) (*models.Post, error)
 	GetAllByUserID(userID uint) ([]models.Post, error)
 	GetAll() ([]models.Post, error)
 	Search(query string) ([]models.Post, error)
 	GetWithPagination(page, limit int) ([]models.Post, int64, error)
 	UpdateByIDWithSelect(postId uint, updates map[string]any, selectFields []string) (*models.Post, error)
-	Update(post *models.Post) error
 	DeleteByID(postID, userID uint) error
+	AdminDeleteByID(postID uint) error
 	Like(postID, userID uint) error
 	Unlike(postID, userID uint) error
 	CheckLikeExists(postID, userID uint) (bool, error)
 	GetLikesCount(postID uint) (int64, error)
+	GetLikedPostIDs(userID uint, postIDs []uint) (map[uint]bool, error)
 	GetUserLikedPosts(userID uint, page, limit int) ([]models.Post, int64, error)
+	GetAllWithFilter(filter PostFilter, page, limit int) ([]models.Post, int64, error)
+	SetHidden(postIDs []uint, hidden bool) (int64, error)
+	TransferOwnership(postID, newUserID uint) (*models.Post, error)
+	CreateReport(report *models.PostReport) error
+	FindOrCreateTags(names []string) ([]models.Tag, error)
+	ReplaceTags(postID uint, tags []models.Tag) error
+	RebuildLikeBuckets(from time.Time) error
+	GetTopLikedPosts(since *time.Time, tag string, page, limit int) ([]models.Post, map[uint]int64, int64, error)
+	RecountLikes() (int64, error)
 }
 
 type postRepository struct {
@@ -31,10 +61,20 @@ type postRepository struct {
 	logger *zap.SugaredLogger
 }
 
-func NewPostRepository(db *gorm.DB, cfg *config.Config, logger *zap.SugaredLogger) PostRepository {
-	return &postRepository{
+// NewPostRepository builds the repository, serving reads from store unless
+// it is nil.
+func NewPostRepository(db *gorm.DB, cfg *config.Config, store cache.Cache, logger *zap.SugaredLogger) PostRepository {
+	var repo PostRepository = &postRepository{
 		db:     db,
 		logger: logger,
 		cfg:    cfg,
 	}
+	if store != nil {
+		repo = newCachedPostRepository(repo, store, cfg.CacheTTL, logger)
+	}
+	return newTracedPostRepository(repo)
+}
+
+func (r *postRepository) WithContext(ctx context.Context) PostRepository {
+	return &postRepository{db: r.db.WithContext(ctx), cfg: r.cfg, logger: log.SugarFromContext(ctx, r.logger)}
 }
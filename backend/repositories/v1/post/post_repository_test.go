@@ -0,0 +1,917 @@
+package post_repository
+
+import (
+	"flower-backend/models"
+	"flower-backend/testutil"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func newTestRepository(t *testing.T) (*postRepository, *gorm.DB) {
+	t.Helper()
+	db := testutil.NewDB(t)
+	return &postRepository{db: db, cfg: testutil.Config(), logger: testutil.Logger()}, db
+}
+
+func likesCount(t *testing.T, db *gorm.DB, postID uint) int64 {
+	t.Helper()
+	var post models.Post
+	if err := db.First(&post, postID).Error; err != nil {
+		t.Fatalf("load post %d: %v", postID, err)
+	}
+	return post.LikesCount
+}
+
+func likeRows(t *testing.T, db *gorm.DB, postID uint) int64 {
+	t.Helper()
+	var count int64
+	if err := db.Model(&models.PostLike{}).Where("post_id = ?", postID).Count(&count).Error; err != nil {
+		t.Fatalf("count likes: %v", err)
+	}
+	return count
+}
+
+func postIDs(posts []models.Post) []uint {
+	ids := make([]uint, 0, len(posts))
+	for _, post := range posts {
+		ids = append(ids, post.ID)
+	}
+	return ids
+}
+
+func TestCreate(t *testing.T) {
+	repo, db := newTestRepository(t)
+	author := testutil.CreateUser(t, db)
+
+	post := &models.Post{Title: "Tulips", Content: "Spring is here", UserID: author.ID}
+	if err := repo.Create(post); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if post.ID == 0 {
+		t.Fatal("Create() did not assign an ID")
+	}
+
+	var user models.User
+	db.First(&user, author.ID)
+	if user.PostsCount != 1 {
+		t.Errorf("posts_count = %d, want 1", user.PostsCount)
+	}
+
+	if err := repo.Create(&models.Post{Title: "Orphan", Content: "No author", UserID: 9999}); err == nil {
+		t.Error("Create() with unknown author succeeded, want foreign key error")
+	}
+}
+
+func TestDirectUploads(t *testing.T) {
+	repo, db := newTestRepository(t)
+	author := testutil.CreateUser(t, db)
+	now := time.Now()
+	for _, assetID := range []string{"used", "unused", "recent"} {
+		if err := repo.CreateDirectUpload(&models.DirectUpload{AssetID: assetID, UserID: author.ID, ExpiresAt: now}); err != nil {
+			t.Fatalf("CreateDirectUpload(%s) error = %v", assetID, err)
+		}
+	}
+	db.Model(&models.DirectUpload{}).Where("asset_id <> ?", "recent").Update("created_at", now.Add(-48*time.Hour))
+
+	post := &models.Post{Title: "Peonies", Content: "From the garden", UserID: author.ID}
+	if created, err := repo.CreateWithAsset(post, "used"); err != nil || !created {
+		t.Fatalf("CreateWithAsset() = %v, %v, want created", created, err)
+	}
+	if created, err := repo.CreateWithAsset(&models.Post{Title: "Again", Content: "Same image", UserID: author.ID}, "used"); err != nil || created {
+		t.Errorf("second CreateWithAsset() = %v, %v, want refused", created, err)
+	}
+	var user models.User
+	db.First(&user, author.ID)
+	if user.PostsCount != 1 {
+		t.Errorf("posts_count = %d, want 1", user.PostsCount)
+	}
+
+	deleted, err := repo.DeleteDirectUploadsBefore(now.Add(-24 * time.Hour))
+	if err != nil || deleted != 2 {
+		t.Fatalf("DeleteDirectUploadsBefore() = %d, %v, want 2", deleted, err)
+	}
+	var jobs []models.Job
+	db.Where("type = ?", "storage.delete").Find(&jobs)
+	if len(jobs) != 1 || !strings.Contains(jobs[0].Payload, "flower-sharing/unused") {
+		t.Errorf("deletion jobs = %+v, want one for the unused image", jobs)
+	}
+	if _, err := repo.GetDirectUpload("recent"); err != nil {
+		t.Errorf("GetDirectUpload(recent) error = %v, want kept", err)
+	}
+}
+
+func TestDraftsAndScheduledPublishing(t *testing.T) {
+	repo, db := newTestRepository(t)
+	author := testutil.CreateUser(t, db)
+	follower := testutil.CreateUser(t, db)
+	testutil.CreateFollow(t, db, follower.ID, author.ID)
+	now := time.Now()
+	dueAt, laterAt := now.Add(-time.Minute), now.Add(time.Hour)
+
+	draft := &models.Post{Title: "Tulips", Content: "Not yet", UserID: author.ID, Status: models.PostDraft}
+	due := &models.Post{Title: "Roses", Content: "Due", UserID: author.ID, Status: models.PostScheduled, PublishAt: &dueAt}
+	later := &models.Post{Title: "Lilies", Content: "Later", UserID: author.ID, Status: models.PostScheduled, PublishAt: &laterAt}
+	for _, post := range []*models.Post{draft, due, later} {
+		if err := repo.Create(post); err != nil {
+			t.Fatalf("Create(%s) error = %v", post.Title, err)
+		}
+	}
+	if posts, err := repo.GetAll(); err != nil || len(posts) != 0 {
+		t.Errorf("GetAll() = %d posts, %v, want none while unpublished", len(posts), err)
+	}
+	postsCount := func() int64 {
+		var user models.User
+		db.First(&user, author.ID)
+		return user.PostsCount
+	}
+	if n := postsCount(); n != 0 {
+		t.Errorf("posts_count = %d with only unpublished posts, want 0", n)
+	}
+	if drafts, total, err := repo.GetDraftsByUserID(author.ID, 1, 10); err != nil || total != 3 || len(drafts) != 3 {
+		t.Errorf("GetDraftsByUserID() = %d of %d, %v, want 3", len(drafts), total, err)
+	}
+	if drafts, total, _ := repo.GetDraftsByUserID(follower.ID, 1, 10); total != 0 || len(drafts) != 0 {
+		t.Errorf("follower's drafts = %d, want none", total)
+	}
+
+	published, err := repo.PublishDue(now, 10)
+	if err != nil || len(published) != 1 || published[0] != due.ID {
+		t.Fatalf("PublishDue() = %v, %v, want [%d]", published, err, due.ID)
+	}
+	if ok, err := repo.PublishDraft(draft.ID, now); err != nil || !ok {
+		t.Fatalf("PublishDraft() = %v, %v, want published", ok, err)
+	}
+	if ok, err := repo.PublishDraft(draft.ID, now); err != nil || ok {
+		t.Errorf("second PublishDraft() = %v, %v, want refused", ok, err)
+	}
+	if ok, err := repo.UpdateDraft(draft.ID, map[string]any{"title": "Edited"}); err != nil || ok {
+		t.Errorf("UpdateDraft() of a published post = %v, %v, want refused", ok, err)
+	}
+	posts, err := repo.GetAll()
+	if err != nil || len(posts) != 2 {
+		t.Fatalf("GetAll() = %d posts, %v, want the 2 published", len(posts), err)
+	}
+	for _, post := range posts {
+		if post.PublishAt != nil || post.CreatedAt.Before(now.Add(-time.Second)) {
+			t.Errorf("post %d publish_at = %v, created_at = %s, want cleared and dated now", post.ID, post.PublishAt, post.CreatedAt)
+		}
+	}
+
+	if n := postsCount(); n != 2 {
+		t.Errorf("posts_count = %d, want the 2 published", n)
+	}
+
+	var queued int64
+	db.Model(&models.Job{}).Where("type = ?", "post.notify_followers").Count(&queued)
+	if queued != 2 {
+		t.Errorf("notify jobs = %d, want one per published post", queued)
+	}
+	for range 2 {
+		if _, err := repo.NotifyFollowers(due.ID); err != nil {
+			t.Fatalf("NotifyFollowers() error = %v", err)
+		}
+	}
+	if notified, _ := repo.NotifyFollowers(later.ID); notified != 0 {
+		t.Errorf("NotifyFollowers() of a scheduled post = %d, want 0", notified)
+	}
+	var notifications []models.Notification
+	db.Find(&notifications)
+	if len(notifications) != 1 || notifications[0].UserID != follower.ID || notifications[0].PostID != due.ID {
+		t.Errorf("notifications = %+v, want one for the follower about post %d", notifications, due.ID)
+	}
+
+	if err := repo.DeleteByID(due.ID, author.ID); err != nil {
+		t.Fatalf("DeleteByID() error = %v", err)
+	}
+	var left int64
+	db.Model(&models.Notification{}).Count(&left)
+	if left != 0 {
+		t.Errorf("%d notifications left after the post was deleted, want 0", left)
+	}
+	if n := postsCount(); n != 1 {
+		t.Errorf("posts_count after deleting a published post = %d, want 1", n)
+	}
+	if err := repo.DeleteByID(later.ID, author.ID); err != nil {
+		t.Fatalf("DeleteByID() of a scheduled post error = %v", err)
+	}
+	if n := postsCount(); n != 1 {
+		t.Errorf("posts_count after deleting a scheduled post = %d, want 1", n)
+	}
+}
+
+func TestGetByID(t *testing.T) {
+	repo, db := newTestRepository(t)
+	author := testutil.CreateUser(t, db)
+	post := testutil.CreatePost(t, db, author.ID)
+
+	tests := []struct {
+		name    string
+		id      uint
+		wantErr error
+	}{
+		{name: "existing post", id: post.ID},
+		{name: "missing post", id: post.ID + 100, wantErr: gorm.ErrRecordNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.GetByID(tt.id)
+			if err != tt.wantErr {
+				t.Fatalf("GetByID() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && (got.ID != post.ID || got.User.ID != author.ID) {
+				t.Errorf("GetByID() = post %d by %d, want post %d by %d", got.ID, got.User.ID, post.ID, author.ID)
+			}
+		})
+	}
+}
+
+func TestGetAllByUserIDAndGetAll(t *testing.T) {
+	repo, db := newTestRepository(t)
+	alice := testutil.CreateUser(t, db)
+	bob := testutil.CreateUser(t, db)
+	testutil.CreatePost(t, db, alice.ID)
+	testutil.CreatePost(t, db, alice.ID)
+	testutil.CreatePost(t, db, bob.ID)
+
+	posts, err := repo.GetAllByUserID(alice.ID)
+	if err != nil {
+		t.Fatalf("GetAllByUserID() error = %v", err)
+	}
+	if len(posts) != 2 {
+		t.Errorf("GetAllByUserID() returned %d posts, want 2", len(posts))
+	}
+
+	all, err := repo.GetAll()
+	if err != nil {
+		t.Fatalf("GetAll() error = %v", err)
+	}
+	if len(all) != 3 {
+		t.Errorf("GetAll() returned %d posts, want 3", len(all))
+	}
+}
+
+func TestSearch(t *testing.T) {
+	repo, db := newTestRepository(t)
+	author := testutil.CreateUser(t, db)
+	rose := testutil.CreatePost(t, db, author.ID, func(p *models.Post) { p.Title = "Red Rose" })
+	garden := testutil.CreatePost(t, db, author.ID, func(p *models.Post) { p.Content = "A garden full of roses" })
+	testutil.CreatePost(t, db, author.ID, func(p *models.Post) { p.Title = "Hidden rose"; p.Hidden = true })
+	testutil.CreatePost(t, db, author.ID, func(p *models.Post) { p.Title = "Tulip" })
+
+	tests := []struct {
+		name  string
+		query string
+		want  int
+	}{
+		{name: "matches title and content", query: "rose", want: 2},
+		{name: "is case insensitive", query: "ROSE", want: 2},
+		{name: "no match", query: "orchid", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			posts, err := repo.Search(tt.query)
+			if err != nil {
+				t.Fatalf("Search() error = %v", err)
+			}
+			if len(posts) != tt.want {
+				t.Errorf("Search(%q) returned %v, want %d posts", tt.query, postIDs(posts), tt.want)
+			}
+			for _, post := range posts {
+				if post.ID != rose.ID && post.ID != garden.ID {
+					t.Errorf("Search(%q) returned unexpected post %d", tt.query, post.ID)
+				}
+			}
+		})
+	}
+}
+
+func TestGetWithPagination(t *testing.T) {
+	repo, db := newTestRepository(t)
+	author := testutil.CreateUser(t, db)
+	base := time.Now().Add(-time.Hour)
+	var newestFirst []uint
+	for i := 0; i < 8; i++ {
+		post := testutil.CreatePost(t, db, author.ID, func(p *models.Post) { p.CreatedAt = base.Add(time.Duration(i) * time.Minute) })
+		newestFirst = append([]uint{post.ID}, newestFirst...)
+	}
+	testutil.CreatePost(t, db, author.ID, func(p *models.Post) { p.Hidden = true })
+
+	tests := []struct {
+		name  string
+		page  int
+		limit int
+		want  []uint
+	}{
+		{name: "first page", page: 1, limit: 3, want: newestFirst[0:3]},
+		{name: "second page", page: 2, limit: 3, want: newestFirst[3:6]},
+		{name: "partial last page", page: 3, limit: 3, want: newestFirst[6:8]},
+		{name: "past the end", page: 4, limit: 3, want: []uint{}},
+		{name: "page below one is clamped", page: 0, limit: 3, want: newestFirst[0:3]},
+		{name: "negative page is clamped", page: -5, limit: 3, want: newestFirst[0:3]},
+		{name: "limit below one defaults to six", page: 1, limit: 0, want: newestFirst[0:6]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			posts, total, err := repo.GetWithPagination(tt.page, tt.limit)
+			if err != nil {
+				t.Fatalf("GetWithPagination() error = %v", err)
+			}
+			if total != 8 {
+				t.Errorf("total = %d, want 8 (hidden posts excluded)", total)
+			}
+			if got := postIDs(posts); !slices.Equal(got, tt.want) {
+				t.Errorf("GetWithPagination(%d, %d) = %v, want %v", tt.page, tt.limit, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateByIDWithSelect(t *testing.T) {
+	repo, db := newTestRepository(t)
+	author := testutil.CreateUser(t, db)
+	post := testutil.CreatePost(t, db, author.ID, func(p *models.Post) { p.Title = "Before"; p.Content = "Body" })
+
+	updated, err := repo.UpdateByIDWithSelect(post.ID, map[string]any{"title": "After", "likes_count": 99}, []string{"title"})
+	if err != nil {
+		t.Fatalf("UpdateByIDWithSelect() error = %v", err)
+	}
+	if updated.Title != "After" {
+		t.Errorf("title = %q, want After", updated.Title)
+	}
+	if updated.LikesCount != 0 {
+		t.Errorf("likes_count = %d, fields outside the selection must not change", updated.LikesCount)
+	}
+
+	if _, err := repo.UpdateByIDWithSelect(post.ID+100, map[string]any{"title": "x"}, []string{"title"}); err != gorm.ErrRecordNotFound {
+		t.Errorf("UpdateByIDWithSelect() on missing post error = %v, want ErrRecordNotFound", err)
+	}
+}
+
+func TestDeleteByID(t *testing.T) {
+	repo, db := newTestRepository(t)
+	author := testutil.CreateUser(t, db)
+	fan := testutil.CreateUser(t, db)
+	post := testutil.CreatePost(t, db, author.ID)
+	testutil.CreateLike(t, db, post.ID, fan.ID)
+	tags, err := repo.FindOrCreateTags([]string{"spring"})
+	if err != nil {
+		t.Fatalf("FindOrCreateTags() error = %v", err)
+	}
+	if err := repo.ReplaceTags(post.ID, tags); err != nil {
+		t.Fatalf("ReplaceTags() error = %v", err)
+	}
+	if err := repo.CreateReport(&models.PostReport{PostID: post.ID, UserID: fan.ID, Reason: "spam"}); err != nil {
+		t.Fatalf("CreateReport() error = %v", err)
+	}
+
+	if err := repo.DeleteByID(post.ID, fan.ID); err != gorm.ErrRecordNotFound {
+		t.Fatalf("DeleteByID() by another user error = %v, want ErrRecordNotFound", err)
+	}
+	if err := repo.DeleteByID(post.ID, author.ID); err != nil {
+		t.Fatalf("DeleteByID() error = %v", err)
+	}
+
+	for _, table := range []string{"posts", "post_likes", "post_tags", "post_reports"} {
+		var count int64
+		db.Table(table).Count(&count)
+		if count != 0 {
+			t.Errorf("%s has %d rows after delete, want 0", table, count)
+		}
+	}
+	var user models.User
+	db.First(&user, author.ID)
+	if user.PostsCount != 0 {
+		t.Errorf("posts_count = %d, want 0", user.PostsCount)
+	}
+
+	if err := repo.DeleteByID(post.ID, author.ID); err != gorm.ErrRecordNotFound {
+		t.Errorf("second DeleteByID() error = %v, want ErrRecordNotFound", err)
+	}
+}
+
+func TestAdminDeleteByID(t *testing.T) {
+	repo, db := newTestRepository(t)
+	author := testutil.CreateUser(t, db)
+	post := testutil.CreatePost(t, db, author.ID)
+
+	if err := repo.AdminDeleteByID(post.ID); err != nil {
+		t.Fatalf("AdminDeleteByID() error = %v", err)
+	}
+	var count int64
+	db.Model(&models.Post{}).Count(&count)
+	if count != 0 {
+		t.Errorf("%d posts remain, want 0", count)
+	}
+	var user models.User
+	db.First(&user, author.ID)
+	if user.PostsCount != 0 {
+		t.Errorf("posts_count = %d, want 0", user.PostsCount)
+	}
+	if err := repo.AdminDeleteByID(post.ID); err != gorm.ErrRecordNotFound {
+		t.Errorf("second AdminDeleteByID() error = %v, want ErrRecordNotFound", err)
+	}
+}
+
+func TestLike(t *testing.T) {
+	repo, db := newTestRepository(t)
+	author := testutil.CreateUser(t, db)
+	fan := testutil.CreateUser(t, db)
+	post := testutil.CreatePost(t, db, author.ID)
+
+	tests := []struct {
+		name      string
+		postID    uint
+		userID    uint
+		wantErr   error
+		wantCount int64
+	}{
+		{name: "missing post", postID: post.ID + 100, userID: fan.ID, wantErr: gorm.ErrRecordNotFound},
+		{name: "missing user", postID: post.ID, userID: fan.ID + 100, wantErr: gorm.ErrRecordNotFound},
+		{name: "first like", postID: post.ID, userID: fan.ID, wantCount: 1},
+		{name: "repeated like is idempotent", postID: post.ID, userID: fan.ID, wantCount: 1},
+		{name: "author may like own post", postID: post.ID, userID: author.ID, wantCount: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := repo.Like(tt.postID, tt.userID); err != tt.wantErr {
+				t.Fatalf("Like() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if got := likesCount(t, db, post.ID); got != tt.wantCount {
+				t.Errorf("likes_count = %d, want %d", got, tt.wantCount)
+			}
+			if got := likeRows(t, db, post.ID); got != tt.wantCount {
+				t.Errorf("post_likes rows = %d, want %d", got, tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestUnlike(t *testing.T) {
+	repo, db := newTestRepository(t)
+	author := testutil.CreateUser(t, db)
+	fan := testutil.CreateUser(t, db)
+	post := testutil.CreatePost(t, db, author.ID)
+	testutil.CreateLike(t, db, post.ID, fan.ID)
+
+	tests := []struct {
+		name      string
+		postID    uint
+		userID    uint
+		wantErr   error
+		wantCount int64
+	}{
+		{name: "missing post", postID: post.ID + 100, userID: fan.ID, wantErr: gorm.ErrRecordNotFound},
+		{name: "existing like", postID: post.ID, userID: fan.ID, wantCount: 0},
+		{name: "repeated unlike is a no-op", postID: post.ID, userID: fan.ID, wantCount: 0},
+		{name: "never liked", postID: post.ID, userID: author.ID, wantCount: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := repo.Unlike(tt.postID, tt.userID); err != tt.wantErr {
+				t.Fatalf("Unlike() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if got := likesCount(t, db, post.ID); got != tt.wantCount {
+				t.Errorf("likes_count = %d, want %d", got, tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestLikeConcurrentDoubleLike(t *testing.T) {
+	repo, db := newTestRepository(t)
+	author := testutil.CreateUser(t, db)
+	fan := testutil.CreateUser(t, db)
+	post := testutil.CreatePost(t, db, author.ID)
+
+	const attempts = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, attempts)
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- repo.Like(post.ID, fan.ID)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("concurrent Like() error = %v", err)
+		}
+	}
+	if got := likeRows(t, db, post.ID); got != 1 {
+		t.Errorf("post_likes rows = %d, want 1", got)
+	}
+	if got := likesCount(t, db, post.ID); got != 1 {
+		t.Errorf("likes_count = %d, want 1", got)
+	}
+}
+
+func TestLikeConcurrentManyUsers(t *testing.T) {
+	repo, db := newTestRepository(t)
+	author := testutil.CreateUser(t, db)
+	post := testutil.CreatePost(t, db, author.ID)
+
+	const fans = 15
+	users := make([]*models.User, fans)
+	for i := range users {
+		users[i] = testutil.CreateUser(t, db)
+	}
+
+	var wg sync.WaitGroup
+	for _, user := range users {
+		wg.Add(2)
+		// Each user likes twice at once, so every like also races a duplicate
+		for j := 0; j < 2; j++ {
+			go func(userID uint) {
+				defer wg.Done()
+				if err := repo.Like(post.ID, userID); err != nil {
+					t.Errorf("Like() error = %v", err)
+				}
+			}(user.ID)
+		}
+	}
+	wg.Wait()
+
+	if got := likesCount(t, db, post.ID); got != fans {
+		t.Errorf("likes_count = %d, want %d", got, fans)
+	}
+	if got := likeRows(t, db, post.ID); got != fans {
+		t.Errorf("post_likes rows = %d, want %d", got, fans)
+	}
+}
+
+func TestUnlikeConcurrent(t *testing.T) {
+	repo, db := newTestRepository(t)
+	author := testutil.CreateUser(t, db)
+	fan := testutil.CreateUser(t, db)
+	post := testutil.CreatePost(t, db, author.ID)
+	testutil.CreateLike(t, db, post.ID, fan.ID)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := repo.Unlike(post.ID, fan.ID); err != nil {
+				t.Errorf("Unlike() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := likesCount(t, db, post.ID); got != 0 {
+		t.Errorf("likes_count = %d, want 0", got)
+	}
+}
+
+func TestLikeQueries(t *testing.T) {
+	repo, db := newTestRepository(t)
+	author := testutil.CreateUser(t, db)
+	fan := testutil.CreateUser(t, db)
+	liked := testutil.CreatePost(t, db, author.ID, func(p *models.Post) { p.CreatedAt = time.Now().Add(-time.Hour) })
+	likedLater := testutil.CreatePost(t, db, author.ID)
+	hidden := testutil.CreatePost(t, db, author.ID, func(p *models.Post) { p.Hidden = true })
+	other := testutil.CreatePost(t, db, author.ID)
+	testutil.CreateLike(t, db, liked.ID, fan.ID)
+	testutil.CreateLike(t, db, likedLater.ID, fan.ID)
+	testutil.CreateLike(t, db, hidden.ID, fan.ID)
+
+	t.Run("CheckLikeExists", func(t *testing.T) {
+		if exists, err := repo.CheckLikeExists(liked.ID, fan.ID); err != nil || !exists {
+			t.Errorf("CheckLikeExists(liked) = %v, %v, want true", exists, err)
+		}
+		if exists, err := repo.CheckLikeExists(other.ID, fan.ID); err != nil || exists {
+			t.Errorf("CheckLikeExists(other) = %v, %v, want false", exists, err)
+		}
+	})
+
+	t.Run("GetLikesCount", func(t *testing.T) {
+		if count, err := repo.GetLikesCount(liked.ID); err != nil || count != 1 {
+			t.Errorf("GetLikesCount() = %d, %v, want 1", count, err)
+		}
+	})
+
+	t.Run("GetLikedPostIDs", func(t *testing.T) {
+		got, err := repo.GetLikedPostIDs(fan.ID, []uint{liked.ID, other.ID})
+		if err != nil {
+			t.Fatalf("GetLikedPostIDs() error = %v", err)
+		}
+		if !got[liked.ID] || got[other.ID] || len(got) != 1 {
+			t.Errorf("GetLikedPostIDs() = %v, want only %d", got, liked.ID)
+		}
+		empty, err := repo.GetLikedPostIDs(fan.ID, nil)
+		if err != nil || len(empty) != 0 {
+			t.Errorf("GetLikedPostIDs(nil) = %v, %v, want empty", empty, err)
+		}
+	})
+
+	t.Run("GetUserLikedPosts", func(t *testing.T) {
+		posts, total, err := repo.GetUserLikedPosts(fan.ID, 1, 1)
+		if err != nil {
+			t.Fatalf("GetUserLikedPosts() error = %v", err)
+		}
+		if total != 2 {
+			t.Errorf("total = %d, want 2 (visible liked posts only)", total)
+		}
+		if got := postIDs(posts); !slices.Equal(got, []uint{likedLater.ID}) {
+			t.Errorf("GetUserLikedPosts(page 1) = %v, want [%d]", got, likedLater.ID)
+		}
+		posts, _, _ = repo.GetUserLikedPosts(fan.ID, 0, 0)
+		if got := postIDs(posts); !slices.Equal(got, []uint{likedLater.ID, liked.ID}) {
+			t.Errorf("GetUserLikedPosts(defaults) = %v, want [%d %d]", got, likedLater.ID, liked.ID)
+		}
+	})
+}
+
+func TestGetAllWithFilter(t *testing.T) {
+	repo, db := newTestRepository(t)
+	alice := testutil.CreateUser(t, db)
+	bob := testutil.CreateUser(t, db)
+	old := testutil.CreatePost(t, db, alice.ID, func(p *models.Post) { p.CreatedAt = time.Now().Add(-48 * time.Hour) })
+	reported := testutil.CreatePost(t, db, alice.ID)
+	hidden := testutil.CreatePost(t, db, bob.ID, func(p *models.Post) { p.Hidden = true })
+	if err := repo.CreateReport(&models.PostReport{PostID: reported.ID, UserID: bob.ID}); err != nil {
+		t.Fatalf("CreateReport() error = %v", err)
+	}
+
+	yes, no := true, false
+	dayAgo := time.Now().Add(-24 * time.Hour)
+	tests := []struct {
+		name   string
+		filter PostFilter
+		want   []uint
+	}{
+		{name: "no filter", filter: PostFilter{}, want: []uint{hidden.ID, reported.ID, old.ID}},
+		{name: "by author", filter: PostFilter{AuthorID: &bob.ID}, want: []uint{hidden.ID}},
+		{name: "from", filter: PostFilter{From: &dayAgo}, want: []uint{hidden.ID, reported.ID}},
+		{name: "to", filter: PostFilter{To: &dayAgo}, want: []uint{old.ID}},
+		{name: "reported", filter: PostFilter{Reported: &yes}, want: []uint{reported.ID}},
+		{name: "not reported", filter: PostFilter{Reported: &no}, want: []uint{hidden.ID, old.ID}},
+		{name: "hidden", filter: PostFilter{Hidden: &yes}, want: []uint{hidden.ID}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			posts, total, err := repo.GetAllWithFilter(tt.filter, 1, 0)
+			if err != nil {
+				t.Fatalf("GetAllWithFilter() error = %v", err)
+			}
+			if total != int64(len(tt.want)) {
+				t.Errorf("total = %d, want %d", total, len(tt.want))
+			}
+			if got := postIDs(posts); !slices.Equal(got, tt.want) {
+				t.Errorf("GetAllWithFilter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetHidden(t *testing.T) {
+	repo, db := newTestRepository(t)
+	author := testutil.CreateUser(t, db)
+	first := testutil.CreatePost(t, db, author.ID)
+	second := testutil.CreatePost(t, db, author.ID)
+
+	affected, err := repo.SetHidden([]uint{first.ID, second.ID, second.ID + 100}, true)
+	if err != nil {
+		t.Fatalf("SetHidden() error = %v", err)
+	}
+	if affected != 2 {
+		t.Errorf("SetHidden() affected %d rows, want 2", affected)
+	}
+	if _, total, _ := repo.GetWithPagination(1, 10); total != 0 {
+		t.Errorf("visible posts after hiding = %d, want 0", total)
+	}
+}
+
+func TestTransferOwnership(t *testing.T) {
+	repo, db := newTestRepository(t)
+	alice := testutil.CreateUser(t, db)
+	bob := testutil.CreateUser(t, db)
+	post := testutil.CreatePost(t, db, alice.ID)
+
+	tests := []struct {
+		name      string
+		postID    uint
+		newUserID uint
+		wantErr   error
+		wantAlice int64
+		wantBob   int64
+	}{
+		{name: "missing user", postID: post.ID, newUserID: bob.ID + 100, wantErr: gorm.ErrRecordNotFound, wantAlice: 1},
+		{name: "missing post", postID: post.ID + 100, newUserID: bob.ID, wantErr: gorm.ErrRecordNotFound, wantAlice: 1},
+		{name: "transfer", postID: post.ID, newUserID: bob.ID, wantBob: 1},
+		{name: "transfer to current owner is a no-op", postID: post.ID, newUserID: bob.ID, wantBob: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.TransferOwnership(tt.postID, tt.newUserID)
+			if err != tt.wantErr {
+				t.Fatalf("TransferOwnership() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && got.UserID != tt.newUserID {
+				t.Errorf("owner = %d, want %d", got.UserID, tt.newUserID)
+			}
+			var a, b models.User
+			db.First(&a, alice.ID)
+			db.First(&b, bob.ID)
+			if a.PostsCount != tt.wantAlice || b.PostsCount != tt.wantBob {
+				t.Errorf("posts_count alice=%d bob=%d, want %d and %d", a.PostsCount, b.PostsCount, tt.wantAlice, tt.wantBob)
+			}
+		})
+	}
+}
+
+func TestCreateReport(t *testing.T) {
+	repo, db := newTestRepository(t)
+	author := testutil.CreateUser(t, db)
+	reporter := testutil.CreateUser(t, db)
+	post := testutil.CreatePost(t, db, author.ID)
+
+	if err := repo.CreateReport(&models.PostReport{PostID: post.ID + 100, UserID: reporter.ID}); err != gorm.ErrRecordNotFound {
+		t.Errorf("CreateReport() on missing post error = %v, want ErrRecordNotFound", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := repo.CreateReport(&models.PostReport{PostID: post.ID, UserID: reporter.ID, Reason: "spam"}); err != nil {
+			t.Fatalf("CreateReport() attempt %d error = %v", i+1, err)
+		}
+	}
+	var count int64
+	db.Model(&models.PostReport{}).Count(&count)
+	if count != 1 {
+		t.Errorf("post_reports rows = %d, want 1 (repeated reports are a no-op)", count)
+	}
+}
+
+func TestTags(t *testing.T) {
+	repo, db := newTestRepository(t)
+	author := testutil.CreateUser(t, db)
+	post := testutil.CreatePost(t, db, author.ID)
+
+	first, err := repo.FindOrCreateTags([]string{"rose", "tulip"})
+	if err != nil || len(first) != 2 {
+		t.Fatalf("FindOrCreateTags() = %v, %v, want 2 tags", first, err)
+	}
+	again, err := repo.FindOrCreateTags([]string{"rose", "lily"})
+	if err != nil || len(again) != 2 {
+		t.Fatalf("FindOrCreateTags() = %v, %v, want 2 tags", again, err)
+	}
+	var tagCount int64
+	db.Model(&models.Tag{}).Count(&tagCount)
+	if tagCount != 3 {
+		t.Errorf("tags rows = %d, want 3 (existing tags are reused)", tagCount)
+	}
+	if none, err := repo.FindOrCreateTags(nil); err != nil || len(none) != 0 {
+		t.Errorf("FindOrCreateTags(nil) = %v, %v, want empty", none, err)
+	}
+
+	if err := repo.ReplaceTags(post.ID, first); err != nil {
+		t.Fatalf("ReplaceTags() error = %v", err)
+	}
+	if err := repo.ReplaceTags(post.ID, again); err != nil {
+		t.Fatalf("ReplaceTags() error = %v", err)
+	}
+	loaded, _ := repo.GetByID(post.ID)
+	if len(loaded.Tags) != 2 {
+		t.Errorf("post has %d tags after replace, want 2", len(loaded.Tags))
+	}
+	if err := repo.ReplaceTags(post.ID, nil); err != nil {
+		t.Fatalf("ReplaceTags(nil) error = %v", err)
+	}
+	loaded, _ = repo.GetByID(post.ID)
+	if len(loaded.Tags) != 0 {
+		t.Errorf("post has %d tags after clearing, want 0", len(loaded.Tags))
+	}
+}
+
+func TestRebuildLikeBucketsFrom(t *testing.T) {
+	repo, db := newTestRepository(t)
+	author := testutil.CreateUser(t, db)
+	post := testutil.CreatePost(t, db, author.ID)
+	hour := time.Now().UTC().Truncate(time.Hour).Add(-3 * time.Hour)
+	for i, at := range []time.Duration{-time.Second, 0, 59 * time.Minute, time.Hour + time.Minute} {
+		fan := testutil.CreateUser(t, db)
+		if err := db.Create(&models.PostLike{PostID: post.ID, UserID: fan.ID, CreatedAt: hour.Add(at)}).Error; err != nil {
+			t.Fatalf("create like %d: %v", i, err)
+		}
+	}
+	buckets := func() map[time.Time]int64 {
+		var rows []models.PostLikeBucket
+		if err := db.Order("bucket_start").Find(&rows).Error; err != nil {
+			t.Fatalf("load buckets: %v", err)
+		}
+		got := make(map[time.Time]int64, len(rows))
+		for _, row := range rows {
+			got[row.BucketStart.UTC()] = row.Count
+		}
+		return got
+	}
+
+	if err := repo.RebuildLikeBuckets(time.Time{}); err != nil {
+		t.Fatalf("RebuildLikeBuckets() error = %v", err)
+	}
+	want := map[time.Time]int64{hour.Add(-time.Hour): 1, hour: 2, hour.Add(time.Hour): 1}
+	if got := buckets(); !maps.Equal(got, want) {
+		t.Errorf("buckets = %v, want %v", got, want)
+	}
+
+	// A partial rebuild leaves earlier hours alone, even once their likes go
+	db.Where("created_at < ?", hour).Delete(&models.PostLike{})
+	db.Create(&models.PostLike{PostID: post.ID, UserID: author.ID, CreatedAt: hour.Add(time.Hour + 2*time.Minute)})
+	if err := repo.RebuildLikeBuckets(hour.Add(30 * time.Minute)); err != nil {
+		t.Fatalf("RebuildLikeBuckets(from) error = %v", err)
+	}
+	want = map[time.Time]int64{hour.Add(-time.Hour): 1, hour: 2, hour.Add(time.Hour): 2}
+	if got := buckets(); !maps.Equal(got, want) {
+		t.Errorf("buckets after partial rebuild = %v, want %v", got, want)
+	}
+}
+
+func TestLikeBucketsAndTopLikedPosts(t *testing.T) {
+	repo, db := newTestRepository(t)
+	author := testutil.CreateUser(t, db)
+	fans := []*models.User{testutil.CreateUser(t, db), testutil.CreateUser(t, db), testutil.CreateUser(t, db)}
+	popular := testutil.CreatePost(t, db, author.ID)
+	tagged := testutil.CreatePost(t, db, author.ID)
+	hidden := testutil.CreatePost(t, db, author.ID, func(p *models.Post) { p.Hidden = true })
+	stale := testutil.CreatePost(t, db, author.ID)
+	for _, fan := range fans {
+		testutil.CreateLike(t, db, popular.ID, fan.ID)
+		testutil.CreateLike(t, db, hidden.ID, fan.ID)
+	}
+	testutil.CreateLike(t, db, tagged.ID, fans[0].ID)
+	db.Create(&models.PostLike{PostID: stale.ID, UserID: fans[0].ID, CreatedAt: time.Now().Add(-72 * time.Hour)})
+
+	tags, _ := repo.FindOrCreateTags([]string{"rare"})
+	repo.ReplaceTags(tagged.ID, tags)
+
+	if err := repo.RebuildLikeBuckets(time.Time{}); err != nil {
+		t.Fatalf("RebuildLikeBuckets() error = %v", err)
+	}
+	// Rebuilding again must replace rather than double the buckets
+	if err := repo.RebuildLikeBuckets(time.Time{}); err != nil {
+		t.Fatalf("RebuildLikeBuckets() error = %v", err)
+	}
+
+	dayAgo := time.Now().Add(-24 * time.Hour)
+	tests := []struct {
+		name      string
+		since     *time.Time
+		tag       string
+		want      []uint
+		wantLikes map[uint]int64
+	}{
+		{name: "all time", want: []uint{popular.ID, stale.ID, tagged.ID}, wantLikes: map[uint]int64{popular.ID: 3, tagged.ID: 1, stale.ID: 1}},
+		{name: "last day", since: &dayAgo, want: []uint{popular.ID, tagged.ID}, wantLikes: map[uint]int64{popular.ID: 3, tagged.ID: 1}},
+		{name: "by tag", tag: "rare", want: []uint{tagged.ID}, wantLikes: map[uint]int64{tagged.ID: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			posts, likes, total, err := repo.GetTopLikedPosts(tt.since, tt.tag, 1, 10)
+			if err != nil {
+				t.Fatalf("GetTopLikedPosts() error = %v", err)
+			}
+			if total != int64(len(tt.want)) {
+				t.Errorf("total = %d, want %d", total, len(tt.want))
+			}
+			if got := postIDs(posts); !slices.Equal(got, tt.want) {
+				t.Errorf("GetTopLikedPosts() = %v, want %v", got, tt.want)
+			}
+			for id, want := range tt.wantLikes {
+				if likes[id] != want {
+					t.Errorf("likes[%d] = %d, want %d", id, likes[id], want)
+				}
+			}
+		})
+	}
+}
+
+func TestRecountLikes(t *testing.T) {
+	repo, db := newTestRepository(t)
+	author := testutil.CreateUser(t, db)
+	fan := testutil.CreateUser(t, db)
+	post := testutil.CreatePost(t, db, author.ID)
+	testutil.CreateLike(t, db, post.ID, fan.ID)
+	db.Model(&models.Post{}).Where("id = ?", post.ID).UpdateColumn("likes_count", 42)
+
+	if _, err := repo.RecountLikes(); err != nil {
+		t.Fatalf("RecountLikes() error = %v", err)
+	}
+	if got := likesCount(t, db, post.ID); got != 1 {
+		t.Errorf("likes_count = %d after recount, want 1", got)
+	}
+}
@@ -0,0 +1,15 @@
+package post_repository
+
+import (
+	"go.uber.org/zap"
+)
+
+// RecountLikes recomputes likes_count for every post from the post_likes table.
+func (r *postRepository) RecountLikes() (int64, error) {
+	result := r.db.Exec("UPDATE posts SET likes_count = (SELECT COUNT(*) FROM post_likes WHERE post_likes.post_id = posts.id)")
+	if result.Error != nil {
+		r.logger.Error("failed to recount post likes", zap.Error(result.Error))
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
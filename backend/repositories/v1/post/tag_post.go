@@ -0,0 +1,47 @@
+package post_repository
+
+import (
+	"flower-backend/models"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm/clause"
+)
+
+// FindOrCreateTags returns the tags with the given names, creating missing ones.
+func (r *postRepository) FindOrCreateTags(names []string) ([]models.Tag, error) {
+	var tags []models.Tag
+	if len(names) == 0 {
+		return tags, nil
+	}
+
+	newTags := make([]models.Tag, 0, len(names))
+	for _, name := range names {
+		newTags = append(newTags, models.Tag{Name: name})
+	}
+	if err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&newTags).Error; err != nil {
+		r.logger.Error("failed to create tags", zap.Error(err))
+		return nil, err
+	}
+
+	if err := r.db.Where("name IN ?", names).Find(&tags).Error; err != nil {
+		r.logger.Error("failed to get tags", zap.Error(err))
+		return nil, err
+	}
+	return tags, nil
+}
+
+func (r *postRepository) ReplaceTags(postID uint, tags []models.Tag) error {
+	association := r.db.Model(&models.Post{ID: postID}).Association("Tags")
+	if len(tags) == 0 {
+		if err := association.Clear(); err != nil {
+			r.logger.Error("failed to clear post tags", zap.Error(err))
+			return err
+		}
+		return nil
+	}
+	if err := association.Replace(tags); err != nil {
+		r.logger.Error("failed to replace post tags", zap.Error(err))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,262 @@
+package post_repository
+
+import (
+	"context"
+	"flower-backend/models"
+	"flower-backend/tracing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("flower-backend/repositories/v1/post")
+
+// tracedPostRepository starts a span around every call and runs the wrapped
+// repository under it, so the GORM query spans nest beneath.
+type tracedPostRepository struct {
+	PostRepository
+	ctx context.Context
+}
+
+func newTracedPostRepository(repo PostRepository) PostRepository {
+	return &tracedPostRepository{PostRepository: repo, ctx: context.Background()}
+}
+
+func (r *tracedPostRepository) WithContext(ctx context.Context) PostRepository {
+	return &tracedPostRepository{PostRepository: r.PostRepository, ctx: ctx}
+}
+
+// start opens the span for method and returns the wrapped repository bound to it.
+func (r *tracedPostRepository) start(method string) (PostRepository, trace.Span) {
+	ctx, span := tracer.Start(r.ctx, "PostRepository."+method)
+	return r.PostRepository.WithContext(ctx), span
+}
+
+func (r *tracedPostRepository) Create(post *models.Post) (err error) {
+	repo, span := r.start("Create")
+	defer tracing.End(span, &err)
+	return repo.Create(post)
+}
+
+func (r *tracedPostRepository) CreateWithUpload(post *models.Post, image []byte) (err error) {
+	repo, span := r.start("CreateWithUpload")
+	defer tracing.End(span, &err)
+	return repo.CreateWithUpload(post, image)
+}
+
+func (r *tracedPostRepository) GetUpload(postID uint) (_ *models.PostUpload, err error) {
+	repo, span := r.start("GetUpload")
+	defer tracing.End(span, &err)
+	return repo.GetUpload(postID)
+}
+
+func (r *tracedPostRepository) CompleteUpload(postID uint, images models.PostImages) (_ bool, err error) {
+	repo, span := r.start("CompleteUpload")
+	defer tracing.End(span, &err)
+	return repo.CompleteUpload(postID, images)
+}
+
+func (r *tracedPostRepository) FailUpload(postID uint, reason string) (err error) {
+	repo, span := r.start("FailUpload")
+	defer tracing.End(span, &err)
+	return repo.FailUpload(postID, reason)
+}
+
+func (r *tracedPostRepository) RetryUpload(postID uint, image []byte) (_ bool, err error) {
+	repo, span := r.start("RetryUpload")
+	defer tracing.End(span, &err)
+	return repo.RetryUpload(postID, image)
+}
+
+func (r *tracedPostRepository) CreateDirectUpload(upload *models.DirectUpload) (err error) {
+	repo, span := r.start("CreateDirectUpload")
+	defer tracing.End(span, &err)
+	return repo.CreateDirectUpload(upload)
+}
+
+func (r *tracedPostRepository) GetDirectUpload(assetID string) (_ *models.DirectUpload, err error) {
+	repo, span := r.start("GetDirectUpload")
+	defer tracing.End(span, &err)
+	return repo.GetDirectUpload(assetID)
+}
+
+func (r *tracedPostRepository) CreateWithAsset(post *models.Post, assetID string) (_ bool, err error) {
+	repo, span := r.start("CreateWithAsset")
+	defer tracing.End(span, &err)
+	return repo.CreateWithAsset(post, assetID)
+}
+
+func (r *tracedPostRepository) DeleteDirectUploadsBefore(before time.Time) (_ int64, err error) {
+	repo, span := r.start("DeleteDirectUploadsBefore")
+	defer tracing.End(span, &err)
+	return repo.DeleteDirectUploadsBefore(before)
+}
+
+func (r *tracedPostRepository) GetDraftsByUserID(userID uint, page, limit int) (_ []models.Post, _ int64, err error) {
+	repo, span := r.start("GetDraftsByUserID")
+	defer tracing.End(span, &err)
+	return repo.GetDraftsByUserID(userID, page, limit)
+}
+
+func (r *tracedPostRepository) UpdateDraft(postID uint, updates map[string]any) (_ bool, err error) {
+	repo, span := r.start("UpdateDraft")
+	defer tracing.End(span, &err)
+	return repo.UpdateDraft(postID, updates)
+}
+
+func (r *tracedPostRepository) PublishDraft(postID uint, now time.Time) (_ bool, err error) {
+	repo, span := r.start("PublishDraft")
+	defer tracing.End(span, &err)
+	return repo.PublishDraft(postID, now)
+}
+
+func (r *tracedPostRepository) PublishDue(now time.Time, limit int) (_ []uint, err error) {
+	repo, span := r.start("PublishDue")
+	defer tracing.End(span, &err)
+	return repo.PublishDue(now, limit)
+}
+
+func (r *tracedPostRepository) NotifyFollowers(postID uint) (_ int64, err error) {
+	repo, span := r.start("NotifyFollowers")
+	defer tracing.End(span, &err)
+	return repo.NotifyFollowers(postID)
+}
+
+func (r *tracedPostRepository) GetByID(id uint) (_ *models.Post, err error) {
+	repo, span := r.start("GetByID")
+	defer tracing.End(span, &err)
+	return repo.GetByID(id)
+}
+
+func (r *tracedPostRepository) GetAllByUserID(userID uint) (_ []models.Post, err error) {
+	repo, span := r.start("GetAllByUserID")
+	defer tracing.End(span, &err)
+	return repo.GetAllByUserID(userID)
+}
+
+func (r *tracedPostRepository) GetAll() (_ []models.Post, err error) {
+	repo, span := r.start("GetAll")
+	defer tracing.End(span, &err)
+	return repo.GetAll()
+}
+
+func (r *tracedPostRepository) Search(query string) (_ []models.Post, err error) {
+	repo, span := r.start("Search")
+	defer tracing.End(span, &err)
+	return repo.Search(query)
+}
+
+func (r *tracedPostRepository) GetWithPagination(page, limit int) (_ []models.Post, _ int64, err error) {
+	repo, span := r.start("GetWithPagination")
+	defer tracing.End(span, &err)
+	return repo.GetWithPagination(page, limit)
+}
+
+func (r *tracedPostRepository) UpdateByIDWithSelect(postId uint, updates map[string]any, selectFields []string) (_ *models.Post, err error) {
+	repo, span := r.start("UpdateByIDWithSelect")
+	defer tracing.End(span, &err)
+	return repo.UpdateByIDWithSelect(postId, updates, selectFields)
+}
+
+func (r *tracedPostRepository) DeleteByID(postID, userID uint) (err error) {
+	repo, span := r.start("DeleteByID")
+	defer tracing.End(span, &err)
+	return repo.DeleteByID(postID, userID)
+}
+
+func (r *tracedPostRepository) AdminDeleteByID(postID uint) (err error) {
+	repo, span := r.start("AdminDeleteByID")
+	defer tracing.End(span, &err)
+	return repo.AdminDeleteByID(postID)
+}
+
+func (r *tracedPostRepository) Like(postID, userID uint) (err error) {
+	repo, span := r.start("Like")
+	defer tracing.End(span, &err)
+	return repo.Like(postID, userID)
+}
+
+func (r *tracedPostRepository) Unlike(postID, userID uint) (err error) {
+	repo, span := r.start("Unlike")
+	defer tracing.End(span, &err)
+	return repo.Unlike(postID, userID)
+}
+
+func (r *tracedPostRepository) CheckLikeExists(postID, userID uint) (_ bool, err error) {
+	repo, span := r.start("CheckLikeExists")
+	defer tracing.End(span, &err)
+	return repo.CheckLikeExists(postID, userID)
+}
+
+func (r *tracedPostRepository) GetLikesCount(postID uint) (_ int64, err error) {
+	repo, span := r.start("GetLikesCount")
+	defer tracing.End(span, &err)
+	return repo.GetLikesCount(postID)
+}
+
+func (r *tracedPostRepository) GetLikedPostIDs(userID uint, postIDs []uint) (_ map[uint]bool, err error) {
+	repo, span := r.start("GetLikedPostIDs")
+	defer tracing.End(span, &err)
+	return repo.GetLikedPostIDs(userID, postIDs)
+}
+
+func (r *tracedPostRepository) GetUserLikedPosts(userID uint, page, limit int) (_ []models.Post, _ int64, err error) {
+	repo, span := r.start("GetUserLikedPosts")
+	defer tracing.End(span, &err)
+	return repo.GetUserLikedPosts(userID, page, limit)
+}
+
+func (r *tracedPostRepository) GetAllWithFilter(filter PostFilter, page, limit int) (_ []models.Post, _ int64, err error) {
+	repo, span := r.start("GetAllWithFilter")
+	defer tracing.End(span, &err)
+	return repo.GetAllWithFilter(filter, page, limit)
+}
+
+func (r *tracedPostRepository) SetHidden(postIDs []uint, hidden bool) (_ int64, err error) {
+	repo, span := r.start("SetHidden")
+	defer tracing.End(span, &err)
+	return repo.SetHidden(postIDs, hidden)
+}
+
+func (r *tracedPostRepository) TransferOwnership(postID, newUserID uint) (_ *models.Post, err error) {
+	repo, span := r.start("TransferOwnership")
+	defer tracing.End(span, &err)
+	return repo.TransferOwnership(postID, newUserID)
+}
+
+func (r *tracedPostRepository) CreateReport(report *models.PostReport) (err error) {
+	repo, span := r.start("CreateReport")
+	defer tracing.End(span, &err)
+	return repo.CreateReport(report)
+}
+
+func (r *tracedPostRepository) FindOrCreateTags(names []string) (_ []models.Tag, err error) {
+	repo, span := r.start("FindOrCreateTags")
+	defer tracing.End(span, &err)
+	return repo.FindOrCreateTags(names)
+}
+
+func (r *tracedPostRepository) ReplaceTags(postID uint, tags []models.Tag) (err error) {
+	repo, span := r.start("ReplaceTags")
+	defer tracing.End(span, &err)
+	return repo.ReplaceTags(postID, tags)
+}
+
+func (r *tracedPostRepository) RebuildLikeBuckets(from time.Time) (err error) {
+	repo, span := r.start("RebuildLikeBuckets")
+	defer tracing.End(span, &err)
+	return repo.RebuildLikeBuckets(from)
+}
+
+func (r *tracedPostRepository) GetTopLikedPosts(since *time.Time, tag string, page, limit int) (_ []models.Post, _ map[uint]int64, _ int64, err error) {
+	repo, span := r.start("GetTopLikedPosts")
+	defer tracing.End(span, &err)
+	return repo.GetTopLikedPosts(since, tag, page, limit)
+}
+
+func (r *tracedPostRepository) RecountLikes() (_ int64, err error) {
+	repo, span := r.start("RecountLikes")
+	defer tracing.End(span, &err)
+	return repo.RecountLikes()
+}
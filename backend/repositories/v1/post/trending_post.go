@@ -0,0 +1,132 @@
+package post_repository
+
+import (
+	"flower-backend/models"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type postLikesRow struct {
+	PostID uint
+	Likes  int64
+}
+
+// RebuildLikeBuckets recomputes hourly like buckets from post_likes for every
+// hour starting at or after from. A zero from rebuilds the whole table, which
+// also drops likes that were removed from older buckets. The counting runs in
+// the database, so no like rows are loaded.
+func (r *postRepository) RebuildLikeBuckets(from time.Time) error {
+	from = from.Truncate(time.Hour)
+	hour := r.hourOf("created_at")
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		deleteQuery := tx.Where("1 = 1")
+		insert := "INSERT INTO post_like_buckets (post_id, bucket_start, count) " +
+			"SELECT post_id, " + hour + ", COUNT(*) FROM post_likes"
+		var args []any
+		if !from.IsZero() {
+			deleteQuery = tx.Where("bucket_start >= ?", from)
+			insert += " WHERE created_at >= ?"
+			args = append(args, from)
+		}
+		insert += " GROUP BY post_id, " + hour
+		if err := deleteQuery.Delete(&models.PostLikeBucket{}).Error; err != nil {
+			r.logger.Error("failed to clear like buckets", zap.Error(err))
+			return err
+		}
+		if err := tx.Exec(insert, args...).Error; err != nil {
+			r.logger.Error("failed to write like buckets", zap.Error(err))
+			return err
+		}
+		return nil
+	})
+}
+
+// hourOf returns SQL truncating the timestamp column to the start of its
+// hour, in the form the dialect stores timestamps in.
+func (r *postRepository) hourOf(column string) string {
+	switch r.db.Dialector.Name() {
+	case "postgres":
+		return "date_trunc('hour', " + column + ")"
+	case "mysql":
+		return "DATE_FORMAT(" + column + ", '%Y-%m-%d %H:00:00')"
+	default:
+		// SQLite keeps timestamps as text with an explicit offset; strftime
+		// converts to UTC, so the offset is always +00:00
+		return "strftime('%Y-%m-%d %H:00:00+00:00', " + column + ")"
+	}
+}
+
+// GetTopLikedPosts ranks visible posts by the likes recorded in buckets since
+// the given time (all time when since is nil), optionally limited to a tag.
+// It returns the posts in rank order together with their like counts.
+func (r *postRepository) GetTopLikedPosts(since *time.Time, tag string, page, limit int) ([]models.Post, map[uint]int64, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 20
+	}
+	offset := (page - 1) * limit
+
+	query := r.db.Table("post_like_buckets").
+		Joins("JOIN posts ON posts.id = post_like_buckets.post_id").
+		Where("posts.hidden = ? AND posts.status = ?", false, models.PostPublished)
+	if since != nil {
+		query = query.Where("post_like_buckets.bucket_start >= ?", *since)
+	}
+	if tag != "" {
+		query = query.Where("EXISTS (?)", r.db.Table("post_tags").
+			Select("1").
+			Joins("JOIN tags ON tags.id = post_tags.tag_id").
+			Where("post_tags.post_id = posts.id AND tags.name = ?", tag))
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Distinct("post_like_buckets.post_id").Count(&total).Error; err != nil {
+		r.logger.Error("failed to count top liked posts", zap.Error(err))
+		return nil, nil, 0, err
+	}
+
+	var rows []postLikesRow
+	err := query.
+		Select("post_like_buckets.post_id AS post_id, SUM(post_like_buckets.count) AS likes").
+		Group("post_like_buckets.post_id").
+		Order("likes DESC, post_like_buckets.post_id DESC").
+		Offset(offset).
+		Limit(limit).
+		Scan(&rows).Error
+	if err != nil {
+		r.logger.Error("failed to get top liked posts", zap.Error(err))
+		return nil, nil, 0, err
+	}
+
+	ids := make([]uint, 0, len(rows))
+	likes := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.PostID)
+		likes[row.PostID] = row.Likes
+	}
+	if len(ids) == 0 {
+		return []models.Post{}, likes, total, nil
+	}
+
+	var loaded []models.Post
+	if err := r.db.Preload("User").Preload("Tags").Where("id IN ?", ids).Find(&loaded).Error; err != nil {
+		r.logger.Error("failed to load top liked posts", zap.Error(err))
+		return nil, nil, 0, err
+	}
+	byID := make(map[uint]models.Post, len(loaded))
+	for _, post := range loaded {
+		byID[post.ID] = post
+	}
+	posts := make([]models.Post, 0, len(ids))
+	for _, id := range ids {
+		if post, ok := byID[id]; ok {
+			posts = append(posts, post)
+		}
+	}
+	return posts, likes, total, nil
+}
@@ -7,6 +7,9 @@ import (
 	"gorm.io/gorm"
 )
 
+// UpdateByIDWithSelect writes the selectFields present in updates, and only
+// those, then returns the post as stored. Counters, visibility and status
+// changed by other requests meanwhile are left alone.
 func (r *postRepository) UpdateByIDWithSelect(postId uint, updates map[string]any, selectFields []string) (*models.Post, error) {
 	var post models.Post
 
@@ -29,7 +32,7 @@ func (r *postRepository) UpdateByIDWithSelect(postId uint, updates map[string]an
 		return &post, nil
 	}
 
-	if err := r.db.Model(&post).Updates(filtered).Error; err != nil {
+	if err := r.db.Model(&models.Post{ID: postId}).Updates(filtered).Error; err != nil {
 		r.logger.Error("failed to update post", zap.Error(err))
 		return nil, err
 	}
@@ -45,11 +48,3 @@ func (r *postRepository) UpdateByIDWithSelect(postId uint, updates map[string]an
 	r.logger.Info("post updated successfully", zap.Uint("id", postId))
 	return &post, nil
 }
-
-func (r *postRepository) Update(post *models.Post) error {
-	if err := r.db.Save(post).Error; err != nil {
-		r.logger.Error("failed to update post", zap.Error(err))
-		return err
-	}
-	return nil
-}
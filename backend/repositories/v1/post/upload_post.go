@@ -0,0 +1,130 @@
+package post_repository
+
+import (
+	"flower-backend/jobs"
+	"flower-backend/models"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateWithUpload creates a processing post along with its staged image
+// and the job that uploads it, so a post never waits on an upload nobody
+// will run.
+func (r *postRepository) CreateWithUpload(post *models.Post, image []byte) error {
+	post.Status = models.PostProcessing
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(post).Error; err != nil {
+			return err
+		}
+		return stageUpload(tx, post.ID, image)
+	})
+	if err != nil {
+		r.logger.Error("failed to create post with upload", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// GetUpload returns the staged image of a post.
+func (r *postRepository) GetUpload(postID uint) (*models.PostUpload, error) {
+	var upload models.PostUpload
+	if err := r.db.Where("post_id = ?", postID).First(&upload).Error; err != nil {
+		if err != gorm.ErrRecordNotFound {
+			r.logger.Error("failed to get post upload", zap.Error(err))
+		}
+		return nil, err
+	}
+	return &upload, nil
+}
+
+// CompleteUpload publishes a post whose image and renditions are now stored
+// at images and drops its staged copy. It reports false when the post is
+// gone or was not waiting on an upload.
+func (r *postRepository) CompleteUpload(postID uint, images models.PostImages) (bool, error) {
+	var completed bool
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// a failed post still completes, e.g. when an admin retries its dead job
+		result := tx.Model(&models.Post{}).
+			Where("id = ? AND status IN ?", postID, []string{models.PostProcessing, models.PostFailed}).
+			Updates(map[string]any{
+				"image_url":     images.ImageURL,
+				"thumbnail_url": images.ThumbnailURL,
+				"medium_url":    images.MediumURL,
+				"status":        models.PostPublished,
+				"upload_error":  "",
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		completed = result.RowsAffected > 0
+		if err := tx.Where("post_id = ?", postID).Delete(&models.PostUpload{}).Error; err != nil || !completed {
+			return err
+		}
+		return published(tx, postID)
+	})
+	if err != nil {
+		r.logger.Error("failed to complete post upload", zap.Error(err))
+		return false, err
+	}
+	return completed, nil
+}
+
+// FailUpload marks a processing post as failed with reason. The staged
+// image is kept for a retry.
+func (r *postRepository) FailUpload(postID uint, reason string) error {
+	err := r.db.Model(&models.Post{}).
+		Where("id = ? AND status = ?", postID, models.PostProcessing).
+		Updates(map[string]any{"status": models.PostFailed, "upload_error": reason}).Error
+	if err != nil {
+		r.logger.Error("failed to mark post upload failed", zap.Error(err))
+	}
+	return err
+}
+
+// RetryUpload puts a failed post back to processing and queues another
+// upload. A non-nil image replaces the staged one. It reports false when the
+// post is not failed.
+func (r *postRepository) RetryUpload(postID uint, image []byte) (bool, error) {
+	var retried bool
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Post{}).
+			Where("id = ? AND status = ?", postID, models.PostFailed).
+			Updates(map[string]any{"status": models.PostProcessing, "upload_error": ""})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		retried = true
+		if image != nil {
+			return stageUpload(tx, postID, image)
+		}
+		return enqueueUpload(tx, postID)
+	})
+	if err != nil {
+		r.logger.Error("failed to retry post upload", zap.Error(err))
+		return false, err
+	}
+	return retried, nil
+}
+
+// stageUpload stores image for postID, replacing any earlier one, and
+// queues its upload.
+func stageUpload(tx *gorm.DB, postID uint, image []byte) error {
+	upload := models.PostUpload{PostID: postID, Data: image}
+	if err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "post_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"data", "created_at"}),
+	}).Create(&upload).Error; err != nil {
+		return err
+	}
+	return enqueueUpload(tx, postID)
+}
+
+func enqueueUpload(tx *gorm.DB, postID uint) error {
+	job, err := jobs.NewJob(jobs.TypeUploadPostImage, jobs.UploadPostImage{PostID: postID})
+	if err != nil {
+		return err
+	}
+	return tx.Create(job).Error
+}
@@ -0,0 +1,46 @@
+package scheduler_repository
+
+import (
+	"flower-backend/models"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm/clause"
+)
+
+// AcquireLease takes or renews the named lease for holder until expiresAt
+// and reports whether holder has it. The lease is free when nobody has
+// taken it yet or its last holder let it expire.
+func (r *schedulerRepository) AcquireLease(name, holder string, now, expiresAt time.Time) (bool, error) {
+	result := r.db.Model(&models.SchedulerLease{}).
+		Where("name = ? AND (holder = ? OR expires_at < ?)", name, holder, now).
+		Updates(map[string]any{"holder": holder, "expires_at": expiresAt})
+	if result.Error != nil {
+		r.logger.Error("failed to renew scheduler lease", zap.String("lease", name), zap.Error(result.Error))
+		return false, result.Error
+	}
+	if result.RowsAffected > 0 {
+		return true, nil
+	}
+
+	// either the row does not exist yet or someone else holds it
+	result = r.db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.SchedulerLease{Name: name, Holder: holder, ExpiresAt: expiresAt})
+	if result.Error != nil {
+		r.logger.Error("failed to create scheduler lease", zap.String("lease", name), zap.Error(result.Error))
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ReleaseLease expires holder's lease at now so another instance can take
+// it straight away. It does nothing if holder has already lost the lease.
+func (r *schedulerRepository) ReleaseLease(name, holder string, now time.Time) error {
+	result := r.db.Model(&models.SchedulerLease{}).
+		Where("name = ? AND holder = ?", name, holder).
+		Update("expires_at", now)
+	if result.Error != nil {
+		r.logger.Error("failed to release scheduler lease", zap.String("lease", name), zap.Error(result.Error))
+	}
+	return result.Error
+}
@@ -0,0 +1,39 @@
+package scheduler_repository
+
+import (
+	"flower-backend/models"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm/clause"
+)
+
+// StartRun inserts run and reports whether it was added. False means the
+// tick already has a row, i.e. another instance ran or is running it.
+func (r *schedulerRepository) StartRun(run *models.TaskRun) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(run)
+	if result.Error != nil {
+		r.logger.Error("failed to record task run", zap.String("task", run.Task), zap.Error(result.Error))
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// FinishRun stores the outcome of a run started with StartRun.
+func (r *schedulerRepository) FinishRun(run *models.TaskRun) error {
+	err := r.db.Model(run).Select("finished_at", "duration_ms", "rows_affected", "error").Updates(run).Error
+	if err != nil {
+		r.logger.Error("failed to record task run outcome", zap.String("task", run.Task), zap.Error(err))
+	}
+	return err
+}
+
+// DeleteRunsBefore removes the history of runs started before before.
+func (r *schedulerRepository) DeleteRunsBefore(before time.Time) (int64, error) {
+	result := r.db.Where("started_at < ?", before).Delete(&models.TaskRun{})
+	if result.Error != nil {
+		r.logger.Error("failed to delete task runs", zap.Error(result.Error))
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,40 @@
+package scheduler_repository
+
+import (
+	"context"
+	"flower-backend/config"
+	"flower-backend/log"
+	"flower-backend/models"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type SchedulerRepository interface {
+	// WithContext returns a repository whose calls run under ctx
+	WithContext(ctx context.Context) SchedulerRepository
+	AcquireLease(name, holder string, now, expiresAt time.Time) (bool, error)
+	ReleaseLease(name, holder string, now time.Time) error
+	StartRun(run *models.TaskRun) (bool, error)
+	FinishRun(run *models.TaskRun) error
+	DeleteRunsBefore(before time.Time) (int64, error)
+}
+
+type schedulerRepository struct {
+	db     *gorm.DB
+	cfg    *config.Config
+	logger *zap.SugaredLogger
+}
+
+func NewSchedulerRepository(db *gorm.DB, cfg *config.Config, logger *zap.SugaredLogger) SchedulerRepository {
+	return newTracedSchedulerRepository(&schedulerRepository{
+		db:     db,
+		cfg:    cfg,
+		logger: logger,
+	})
+}
+
+func (r *schedulerRepository) WithContext(ctx context.Context) SchedulerRepository {
+	return &schedulerRepository{db: r.db.WithContext(ctx), cfg: r.cfg, logger: log.SugarFromContext(ctx, r.logger)}
+}
@@ -0,0 +1,64 @@
+package scheduler_repository
+
+import (
+	"context"
+	"flower-backend/models"
+	"flower-backend/tracing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("flower-backend/repositories/v1/scheduler")
+
+// tracedSchedulerRepository starts a span around every call and runs the wrapped
+// repository under it, so the GORM query spans nest beneath.
+type tracedSchedulerRepository struct {
+	SchedulerRepository
+	ctx context.Context
+}
+
+func newTracedSchedulerRepository(repo SchedulerRepository) SchedulerRepository {
+	return &tracedSchedulerRepository{SchedulerRepository: repo, ctx: context.Background()}
+}
+
+func (r *tracedSchedulerRepository) WithContext(ctx context.Context) SchedulerRepository {
+	return &tracedSchedulerRepository{SchedulerRepository: r.SchedulerRepository, ctx: ctx}
+}
+
+// start opens the span for method and returns the wrapped repository bound to it.
+func (r *tracedSchedulerRepository) start(method string) (SchedulerRepository, trace.Span) {
+	ctx, span := tracer.Start(r.ctx, "SchedulerRepository."+method)
+	return r.SchedulerRepository.WithContext(ctx), span
+}
+
+func (r *tracedSchedulerRepository) AcquireLease(name, holder string, now, expiresAt time.Time) (_ bool, err error) {
+	repo, span := r.start("AcquireLease")
+	defer tracing.End(span, &err)
+	return repo.AcquireLease(name, holder, now, expiresAt)
+}
+
+func (r *tracedSchedulerRepository) ReleaseLease(name, holder string, now time.Time) (err error) {
+	repo, span := r.start("ReleaseLease")
+	defer tracing.End(span, &err)
+	return repo.ReleaseLease(name, holder, now)
+}
+
+func (r *tracedSchedulerRepository) StartRun(run *models.TaskRun) (_ bool, err error) {
+	repo, span := r.start("StartRun")
+	defer tracing.End(span, &err)
+	return repo.StartRun(run)
+}
+
+func (r *tracedSchedulerRepository) FinishRun(run *models.TaskRun) (err error) {
+	repo, span := r.start("FinishRun")
+	defer tracing.End(span, &err)
+	return repo.FinishRun(run)
+}
+
+func (r *tracedSchedulerRepository) DeleteRunsBefore(before time.Time) (_ int64, err error) {
+	repo, span := r.start("DeleteRunsBefore")
+	defer tracing.End(span, &err)
+	return repo.DeleteRunsBefore(before)
+}
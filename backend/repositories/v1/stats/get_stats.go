@@ -0,0 +1,196 @@
+package stats_repository
+
+import (
+	"flower-backend/models"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// metricSource describes where a countable metric lives and which column
+// points at the acting user, so it can be split by the user's provider.
+// Rows not matching where, when set, are left out.
+type metricSource struct {
+	table      string
+	userColumn string
+	where      string
+	args       []any
+}
+
+// visiblePosts restricts the posts aliased alias to the published ones that
+// are not hidden, which are all post stats count: drafts, failed uploads and
+// moderated posts are not content anyone sees. It takes visiblePostArgs.
+func visiblePosts(alias string) string {
+	return alias + ".status = ? AND " + alias + ".hidden = ?"
+}
+
+var visiblePostArgs = []any{models.PostPublished, false}
+
+var metricSources = map[string]metricSource{
+	MetricSignups: {table: "users", userColumn: "id"},
+	MetricPosts:   {table: "posts", userColumn: "user_id", where: visiblePosts("t"), args: visiblePostArgs},
+	MetricLikes:   {table: "post_likes", userColumn: "user_id"},
+	MetricFollows: {table: "user_follows", userColumn: "follower_id"},
+}
+
+// activityQuery unions every action that makes a user "active".
+const activityQuery = `SELECT user_id, created_at FROM posts WHERE created_at >= ? AND created_at < ?
+	UNION ALL SELECT user_id, created_at FROM post_likes WHERE created_at >= ? AND created_at < ?
+	UNION ALL SELECT follower_id AS user_id, created_at FROM user_follows WHERE created_at >= ? AND created_at < ?`
+
+func (r *statsRepository) CountByProvider(metric string) ([]ProviderCount, error) {
+	source, ok := metricSources[metric]
+	if !ok {
+		return nil, fmt.Errorf("unknown metric: %s", metric)
+	}
+
+	var rows []ProviderCount
+	err := r.db.Table(source.table + " AS t").
+		Select("u.provider AS provider, COUNT(*) AS count").
+		Joins("JOIN users u ON u.id = t." + source.userColumn).
+		Scopes(source.filter).
+		Group("u.provider").
+		Scan(&rows).Error
+	if err != nil {
+		r.logger.Error("failed to count by provider", zap.String("metric", metric), zap.Error(err))
+		return nil, err
+	}
+	return rows, nil
+}
+
+// filter applies where, when set, to a query over the source.
+func (s metricSource) filter(db *gorm.DB) *gorm.DB {
+	if s.where == "" {
+		return db
+	}
+	return db.Where(s.where, s.args...)
+}
+
+func (r *statsRepository) CountByDay(metric string, from, to time.Time) ([]DailyCount, error) {
+	source, ok := metricSources[metric]
+	if !ok {
+		return nil, fmt.Errorf("unknown metric: %s", metric)
+	}
+
+	var rows []DailyCount
+	err := r.db.Table(source.table+" AS t").
+		Select("DATE(t.created_at) AS day, u.provider AS provider, COUNT(*) AS count").
+		Joins("JOIN users u ON u.id = t."+source.userColumn).
+		Where("t.created_at >= ? AND t.created_at < ?", from, to).
+		Scopes(source.filter).
+		Group("day, u.provider").
+		Scan(&rows).Error
+	if err != nil {
+		r.logger.Error("failed to count by day", zap.String("metric", metric), zap.Error(err))
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (r *statsRepository) CountActiveUsersByProvider(from, to time.Time) ([]ProviderCount, error) {
+	var rows []ProviderCount
+	err := r.db.Raw(
+		"SELECT u.provider AS provider, COUNT(DISTINCT a.user_id) AS count FROM ("+activityQuery+") a "+
+			"JOIN users u ON u.id = a.user_id GROUP BY u.provider",
+		from, to, from, to, from, to,
+	).Scan(&rows).Error
+	if err != nil {
+		r.logger.Error("failed to count active users", zap.Error(err))
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (r *statsRepository) GetActiveUsersByDay(from, to time.Time) ([]DailyUser, error) {
+	var rows []DailyUser
+	err := r.db.Raw(
+		"SELECT DISTINCT DATE(a.created_at) AS day, a.user_id AS user_id, u.provider AS provider FROM ("+activityQuery+") a "+
+			"JOIN users u ON u.id = a.user_id",
+		from, to, from, to, from, to,
+	).Scan(&rows).Error
+	if err != nil {
+		r.logger.Error("failed to get active users by day", zap.Error(err))
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (r *statsRepository) GetTopPosts(from, to time.Time, limit int) ([]PostLikes, error) {
+	var rows []PostLikes
+	err := r.db.Table("post_likes AS pl").
+		Select("pl.post_id AS post_id, COUNT(*) AS likes").
+		Joins("JOIN posts p ON p.id = pl.post_id").
+		Where("pl.created_at >= ? AND pl.created_at < ?", from, to).
+		Where(visiblePosts("p"), visiblePostArgs...).
+		Group("pl.post_id").
+		Order("likes DESC, post_id ASC").
+		Limit(limit).
+		Scan(&rows).Error
+	if err != nil {
+		r.logger.Error("failed to get top posts", zap.Error(err))
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (r *statsRepository) GetTopCreators(from, to time.Time, limit int) ([]CreatorLikes, error) {
+	var rows []CreatorLikes
+	err := r.db.Table("post_likes AS pl").
+		Select("p.user_id AS user_id, COUNT(*) AS likes").
+		Joins("JOIN posts p ON p.id = pl.post_id").
+		Where("pl.created_at >= ? AND pl.created_at < ?", from, to).
+		Where(visiblePosts("p"), visiblePostArgs...).
+		Group("p.user_id").
+		Order("likes DESC, user_id ASC").
+		Limit(limit).
+		Scan(&rows).Error
+	if err != nil {
+		r.logger.Error("failed to get top creators", zap.Error(err))
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (r *statsRepository) CountPostsByUsers(userIDs []uint, from, to time.Time) ([]UserCount, error) {
+	var rows []UserCount
+	if len(userIDs) == 0 {
+		return rows, nil
+	}
+	err := r.db.Table("posts AS p").
+		Select("p.user_id AS user_id, COUNT(*) AS count").
+		Where("p.user_id IN ? AND p.created_at >= ? AND p.created_at < ?", userIDs, from, to).
+		Where(visiblePosts("p"), visiblePostArgs...).
+		Group("p.user_id").
+		Scan(&rows).Error
+	if err != nil {
+		r.logger.Error("failed to count posts by users", zap.Error(err))
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (r *statsRepository) GetPostsByIDs(ids []uint) ([]models.Post, error) {
+	var posts []models.Post
+	if len(ids) == 0 {
+		return posts, nil
+	}
+	if err := r.db.Preload("User").Where("id IN ?", ids).Find(&posts).Error; err != nil {
+		r.logger.Error("failed to get posts by ids", zap.Error(err))
+		return nil, err
+	}
+	return posts, nil
+}
+
+func (r *statsRepository) GetUsersByIDs(ids []uint) ([]models.User, error) {
+	var users []models.User
+	if len(ids) == 0 {
+		return users, nil
+	}
+	if err := r.db.Where("id IN ?", ids).Find(&users).Error; err != nil {
+		r.logger.Error("failed to get users by ids", zap.Error(err))
+		return nil, err
+	}
+	return users, nil
+}
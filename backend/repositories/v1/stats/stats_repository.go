@@ -0,0 +1,92 @@
+package stats_repository
+
+import (
+	"context"
+	"flower-backend/config"
+	"flower-backend/log"
+	"flower-backend/models"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Metrics supported by the dashboard aggregates.
+const (
+	MetricSignups     = "signups"
+	MetricPosts       = "posts"
+	MetricLikes       = "likes"
+	MetricFollows     = "follows"
+	MetricActiveUsers = "active_users"
+)
+
+// ProviderCount is a total for a single auth provider.
+type ProviderCount struct {
+	Provider string
+	Count    int64
+}
+
+// DailyCount is a total for a single day and auth provider.
+// Day is returned as-is by the driver and normalized by the service.
+type DailyCount struct {
+	Day      string
+	Provider string
+	Count    int64
+}
+
+// DailyUser is a distinct user seen as active on a given day.
+type DailyUser struct {
+	Day      string
+	UserID   uint
+	Provider string
+}
+
+// PostLikes is the number of likes a post received in a range.
+type PostLikes struct {
+	PostID uint
+	Likes  int64
+}
+
+// CreatorLikes is the number of likes a creator's posts received in a range.
+type CreatorLikes struct {
+	UserID uint
+	Likes  int64
+}
+
+// UserCount is a per-user count.
+type UserCount struct {
+	UserID uint
+	Count  int64
+}
+
+type StatsRepository interface {
+	// WithContext returns a repository whose calls run under ctx
+	WithContext(ctx context.Context) StatsRepository
+	CountByProvider(metric string) ([]ProviderCount, error)
+	CountByDay(metric string, from, to time.Time) ([]DailyCount, error)
+	CountActiveUsersByProvider(from, to time.Time) ([]ProviderCount, error)
+	GetActiveUsersByDay(from, to time.Time) ([]DailyUser, error)
+	GetTopPosts(from, to time.Time, limit int) ([]PostLikes, error)
+	GetTopCreators(from, to time.Time, limit int) ([]CreatorLikes, error)
+	CountPostsByUsers(userIDs []uint, from, to time.Time) ([]UserCount, error)
+	GetPostsByIDs(ids []uint) ([]models.Post, error)
+	GetUsersByIDs(ids []uint) ([]models.User, error)
+}
+
+type statsRepository struct {
+	db     *gorm.DB
+	cfg    *config.Config
+	logger *zap.SugaredLogger
+}
+
+func NewStatsRepository(db *gorm.DB, cfg *config.Config, logger *zap.SugaredLogger) StatsRepository {
+	return newTracedStatsRepository(&statsRepository{
+		db:     db,
+		cfg:    cfg,
+		logger: logger,
+	})
+}
+
+func (r *statsRepository) WithContext(ctx context.Context) StatsRepository {
+	return &statsRepository{db: r.db.WithContext(ctx), cfg: r.cfg, logger: log.SugarFromContext(ctx, r.logger)}
+}
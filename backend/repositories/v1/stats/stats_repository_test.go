@@ -0,0 +1,136 @@
+package stats_repository
+
+import (
+	"flower-backend/models"
+	"flower-backend/testutil"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func newTestRepository(t *testing.T) (*statsRepository, *gorm.DB) {
+	t.Helper()
+	db := testutil.NewDB(t)
+	return &statsRepository{db: db, cfg: testutil.Config(), logger: testutil.Logger()}, db
+}
+
+func TestStatsAggregates(t *testing.T) {
+	repo, db := newTestRepository(t)
+	now := time.Now()
+	from, to := now.Add(-24*time.Hour), now.Add(time.Hour)
+
+	alice := testutil.CreateUser(t, db)
+	bob := testutil.CreateUser(t, db, func(u *models.User) { u.Provider = "google" })
+	carol := testutil.CreateUser(t, db, func(u *models.User) { u.CreatedAt = now.Add(-72 * time.Hour) })
+	rose := testutil.CreatePost(t, db, alice.ID)
+	tulip := testutil.CreatePost(t, db, bob.ID)
+	testutil.CreatePost(t, db, carol.ID, func(p *models.Post) { p.CreatedAt = now.Add(-72 * time.Hour) })
+	testutil.CreateLike(t, db, rose.ID, bob.ID)
+	testutil.CreateLike(t, db, rose.ID, carol.ID)
+	testutil.CreateLike(t, db, tulip.ID, alice.ID)
+	testutil.CreateFollow(t, db, alice.ID, bob.ID)
+
+	t.Run("CountByProvider", func(t *testing.T) {
+		rows, err := repo.CountByProvider(MetricSignups)
+		if err != nil {
+			t.Fatalf("CountByProvider() error = %v", err)
+		}
+		got := map[string]int64{}
+		for _, row := range rows {
+			got[row.Provider] = row.Count
+		}
+		if got["local"] != 2 || got["google"] != 1 {
+			t.Errorf("CountByProvider(signups) = %v, want local=2 google=1", got)
+		}
+		if _, err := repo.CountByProvider("unknown"); err == nil {
+			t.Error("CountByProvider(unknown) succeeded, want error")
+		}
+	})
+
+	t.Run("CountByDay", func(t *testing.T) {
+		rows, err := repo.CountByDay(MetricPosts, from, to)
+		if err != nil {
+			t.Fatalf("CountByDay() error = %v", err)
+		}
+		var total int64
+		for _, row := range rows {
+			if row.Day == "" {
+				t.Error("CountByDay() returned an empty day")
+			}
+			total += row.Count
+		}
+		if total != 2 {
+			t.Errorf("CountByDay(posts) total = %d, want 2", total)
+		}
+	})
+
+	t.Run("active users", func(t *testing.T) {
+		rows, err := repo.CountActiveUsersByProvider(from, to)
+		if err != nil {
+			t.Fatalf("CountActiveUsersByProvider() error = %v", err)
+		}
+		var total int64
+		for _, row := range rows {
+			total += row.Count
+		}
+		if total != 3 {
+			t.Errorf("active users = %d, want 3", total)
+		}
+		daily, err := repo.GetActiveUsersByDay(from, to)
+		if err != nil {
+			t.Fatalf("GetActiveUsersByDay() error = %v", err)
+		}
+		users := map[uint]bool{}
+		for _, row := range daily {
+			users[row.UserID] = true
+		}
+		if len(users) != 3 {
+			t.Errorf("GetActiveUsersByDay() saw %d users, want 3", len(users))
+		}
+	})
+
+	t.Run("GetTopPosts", func(t *testing.T) {
+		rows, err := repo.GetTopPosts(from, to, 1)
+		if err != nil {
+			t.Fatalf("GetTopPosts() error = %v", err)
+		}
+		if len(rows) != 1 || rows[0].PostID != rose.ID || rows[0].Likes != 2 {
+			t.Errorf("GetTopPosts() = %+v, want post %d with 2 likes", rows, rose.ID)
+		}
+	})
+
+	t.Run("GetTopCreators", func(t *testing.T) {
+		rows, err := repo.GetTopCreators(from, to, 10)
+		if err != nil {
+			t.Fatalf("GetTopCreators() error = %v", err)
+		}
+		if len(rows) != 2 || rows[0].UserID != alice.ID || rows[0].Likes != 2 {
+			t.Errorf("GetTopCreators() = %+v, want alice first with 2 likes", rows)
+		}
+	})
+
+	t.Run("CountPostsByUsers", func(t *testing.T) {
+		rows, err := repo.CountPostsByUsers([]uint{alice.ID, carol.ID}, from, to)
+		if err != nil {
+			t.Fatalf("CountPostsByUsers() error = %v", err)
+		}
+		if len(rows) != 1 || rows[0].UserID != alice.ID || rows[0].Count != 1 {
+			t.Errorf("CountPostsByUsers() = %+v, want alice with 1 post", rows)
+		}
+		if none, err := repo.CountPostsByUsers(nil, from, to); err != nil || len(none) != 0 {
+			t.Errorf("CountPostsByUsers(nil) = %v, %v, want empty", none, err)
+		}
+	})
+
+	t.Run("lookups", func(t *testing.T) {
+		posts, err := repo.GetPostsByIDs([]uint{rose.ID})
+		if err != nil || len(posts) != 1 || posts[0].User.ID != alice.ID {
+			t.Errorf("GetPostsByIDs() = %+v, %v, want rose with its author", posts, err)
+		}
+		users, err := repo.GetUsersByIDs([]uint{alice.ID, bob.ID})
+		if err != nil || len(users) != 2 {
+			t.Errorf("GetUsersByIDs() = %d users, %v, want 2", len(users), err)
+		}
+	})
+}
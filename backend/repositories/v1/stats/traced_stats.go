@@ -0,0 +1,88 @@
+package stats_repository
+
+import (
+	"context"
+	"flower-backend/models"
+	"flower-backend/tracing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("flower-backend/repositories/v1/stats")
+
+// tracedStatsRepository starts a span around every call and runs the wrapped
+// repository under it, so the GORM query spans nest beneath.
+type tracedStatsRepository struct {
+	StatsRepository
+	ctx context.Context
+}
+
+func newTracedStatsRepository(repo StatsRepository) StatsRepository {
+	return &tracedStatsRepository{StatsRepository: repo, ctx: context.Background()}
+}
+
+func (r *tracedStatsRepository) WithContext(ctx context.Context) StatsRepository {
+	return &tracedStatsRepository{StatsRepository: r.StatsRepository, ctx: ctx}
+}
+
+// start opens the span for method and returns the wrapped repository bound to it.
+func (r *tracedStatsRepository) start(method string) (StatsRepository, trace.Span) {
+	ctx, span := tracer.Start(r.ctx, "StatsRepository."+method)
+	return r.StatsRepository.WithContext(ctx), span
+}
+
+func (r *tracedStatsRepository) CountByProvider(metric string) (_ []ProviderCount, err error) {
+	repo, span := r.start("CountByProvider")
+	defer tracing.End(span, &err)
+	return repo.CountByProvider(metric)
+}
+
+func (r *tracedStatsRepository) CountByDay(metric string, from, to time.Time) (_ []DailyCount, err error) {
+	repo, span := r.start("CountByDay")
+	defer tracing.End(span, &err)
+	return repo.CountByDay(metric, from, to)
+}
+
+func (r *tracedStatsRepository) CountActiveUsersByProvider(from, to time.Time) (_ []ProviderCount, err error) {
+	repo, span := r.start("CountActiveUsersByProvider")
+	defer tracing.End(span, &err)
+	return repo.CountActiveUsersByProvider(from, to)
+}
+
+func (r *tracedStatsRepository) GetActiveUsersByDay(from, to time.Time) (_ []DailyUser, err error) {
+	repo, span := r.start("GetActiveUsersByDay")
+	defer tracing.End(span, &err)
+	return repo.GetActiveUsersByDay(from, to)
+}
+
+func (r *tracedStatsRepository) GetTopPosts(from, to time.Time, limit int) (_ []PostLikes, err error) {
+	repo, span := r.start("GetTopPosts")
+	defer tracing.End(span, &err)
+	return repo.GetTopPosts(from, to, limit)
+}
+
+func (r *tracedStatsRepository) GetTopCreators(from, to time.Time, limit int) (_ []CreatorLikes, err error) {
+	repo, span := r.start("GetTopCreators")
+	defer tracing.End(span, &err)
+	return repo.GetTopCreators(from, to, limit)
+}
+
+func (r *tracedStatsRepository) CountPostsByUsers(userIDs []uint, from, to time.Time) (_ []UserCount, err error) {
+	repo, span := r.start("CountPostsByUsers")
+	defer tracing.End(span, &err)
+	return repo.CountPostsByUsers(userIDs, from, to)
+}
+
+func (r *tracedStatsRepository) GetPostsByIDs(ids []uint) (_ []models.Post, err error) {
+	repo, span := r.start("GetPostsByIDs")
+	defer tracing.End(span, &err)
+	return repo.GetPostsByIDs(ids)
+}
+
+func (r *tracedStatsRepository) GetUsersByIDs(ids []uint) (_ []models.User, err error) {
+	repo, span := r.start("GetUsersByIDs")
+	defer tracing.End(span, &err)
+	return repo.GetUsersByIDs(ids)
+}
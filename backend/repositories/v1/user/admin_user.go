@@ -0,0 +1,118 @@
+package user_repository
+
+import (
+	"flower-backend/models"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// UserFilter narrows admin user listings. Empty fields are not filtered on.
+type UserFilter struct {
+	Role     string
+	Provider string
+}
+
+func (r *userRepository) GetAllWithFilter(filter UserFilter) ([]models.User, error) {
+	var users []models.User
+
+	query := r.db.Model(&models.User{})
+	if filter.Role != "" {
+		query = query.Where("role = ?", filter.Role)
+	}
+	if filter.Provider != "" {
+		// Accounts created before the provider column existed have an empty provider
+		if filter.Provider == "local" {
+			query = query.Where("provider = ? OR provider = '' OR provider IS NULL", filter.Provider)
+		} else {
+			query = query.Where("provider = ?", filter.Provider)
+		}
+	}
+
+	if err := query.Order("id ASC").Find(&users).Error; err != nil {
+		r.logger.Error("failed to get filtered users", zap.Error(err))
+		return nil, err
+	}
+	return users, nil
+}
+
+func (r *userRepository) GetExisting(emails, usernames []string) ([]models.User, error) {
+	var users []models.User
+	if len(emails) == 0 && len(usernames) == 0 {
+		return users, nil
+	}
+	if err := r.db.Select("id", "email", "username").
+		Where("email IN ? OR username IN ?", emails, usernames).
+		Find(&users).Error; err != nil {
+		r.logger.Error("failed to get existing users", zap.Error(err))
+		return nil, err
+	}
+	return users, nil
+}
+
+// Invite is what an imported user gets to choose their password: a password
+// token, stored once the user has an ID, and the job mailing its link.
+type Invite struct {
+	Token *models.PasswordToken
+	Mail  *models.Job
+}
+
+// CreateBatch creates users in one transaction. Unless invites is nil,
+// invites[i] is stored for users[i] in the same transaction, so no account
+// is left without its invite and no invite goes out for a rolled back one.
+func (r *userRepository) CreateBatch(users []models.User, invites []Invite) error {
+	if len(users) == 0 {
+		return nil
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.CreateInBatches(&users, 100).Error; err != nil {
+			r.logger.Error("failed to create users", zap.Error(err))
+			return err
+		}
+		for i, invite := range invites {
+			invite.Token.UserID = users[i].ID
+			if err := tx.Create(invite.Token).Error; err != nil {
+				r.logger.Error("failed to create password token", zap.Error(err))
+				return err
+			}
+			if err := tx.Create(invite.Mail).Error; err != nil {
+				r.logger.Error("failed to enqueue invite", zap.Error(err))
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *userRepository) UpdateRoleByIDs(ids []uint, role string) (int64, error) {
+	result := r.db.Model(&models.User{}).Where("id IN ?", ids).Update("role", role)
+	if result.Error != nil {
+		r.logger.Error("failed to update user roles", zap.Error(result.Error))
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+func (r *userRepository) SetSuspended(ids []uint, suspended bool) (int64, error) {
+	var affected int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.User{}).Where("id IN ?", ids).Update("suspended", suspended)
+		if result.Error != nil {
+			return result.Error
+		}
+		affected = result.RowsAffected
+
+		// Revoke refresh tokens so suspended users are signed out once their access token expires
+		if suspended {
+			if err := tx.Where("user_id IN ?", ids).Delete(&models.Token{}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		r.logger.Error("failed to update user suspension", zap.Error(err))
+		return 0, err
+	}
+	return affected, nil
+}
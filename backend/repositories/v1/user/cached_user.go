@@ -0,0 +1,151 @@
+package user_repository
+
+import (
+	"context"
+	"flower-backend/cache"
+	"flower-backend/log"
+	"flower-backend/models"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// cachedUserRepository serves profile reads from cache and drops the
+// affected keys after every write. Usernames map to an ID rather than a copy
+// of the user, so a renamed user never needs its old name invalidated.
+// Counters touched by bulk maintenance (RecountCounters, cascades from
+// deleting another user) age out within the TTL.
+type cachedUserRepository struct {
+	UserRepository
+	store  cache.Cache
+	ttl    time.Duration
+	logger *zap.SugaredLogger
+}
+
+func newCachedUserRepository(repo UserRepository, store cache.Cache, ttl time.Duration, logger *zap.SugaredLogger) UserRepository {
+	return &cachedUserRepository{UserRepository: repo, store: store, ttl: ttl, logger: logger}
+}
+
+func (r *cachedUserRepository) WithContext(ctx context.Context) UserRepository {
+	return &cachedUserRepository{UserRepository: r.UserRepository.WithContext(ctx), store: r.store, ttl: r.ttl, logger: log.SugarFromContext(ctx, r.logger)}
+}
+
+func (r *cachedUserRepository) GetByID(id uint) (*models.User, error) {
+	var user models.User
+	if cache.Load(r.store, cache.UserKey(id), &user) {
+		likes, err := r.UserRepository.GetLikedPosts(id)
+		if err != nil {
+			return nil, err
+		}
+		user.Likes = likes
+		return &user, nil
+	}
+	found, err := r.UserRepository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	r.saveUser(found)
+	return found, nil
+}
+
+// saveUser caches user without its password hash, which gob would otherwise
+// encode despite json:"-", or its liked posts, which change with every like
+// the user makes. GetByID loads the likes again on a hit.
+func (r *cachedUserRepository) saveUser(user *models.User) {
+	trimmed := *user
+	trimmed.Password = ""
+	trimmed.Likes = nil
+	cache.Save(r.store, cache.UserKey(user.ID), &trimmed, r.ttl)
+}
+
+func (r *cachedUserRepository) GetAuthByID(id uint) (*models.User, error) {
+	key := cache.UserAuthKey(id)
+	var user models.User
+	if cache.Load(r.store, key, &user) {
+		return &user, nil
+	}
+	found, err := r.UserRepository.GetAuthByID(id)
+	if err != nil {
+		return nil, err
+	}
+	cache.Save(r.store, key, found, r.ttl)
+	return found, nil
+}
+
+func (r *cachedUserRepository) GetByUsername(username string) (*models.User, error) {
+	key := cache.UsernameKey(username)
+	var id uint
+	if cache.Load(r.store, key, &id) {
+		// The mapping is stale once the user is renamed
+		if user, err := r.GetByID(id); err == nil && user.Username == username {
+			return user, nil
+		}
+	}
+	user, err := r.UserRepository.GetByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+	cache.Save(r.store, key, user.ID, r.ttl)
+	r.saveUser(user)
+	return user, nil
+}
+
+func (r *cachedUserRepository) UpdateByIDWithSelect(id uint, updates map[string]any, selectFields []string) (*models.User, error) {
+	user, err := r.UserRepository.UpdateByIDWithSelect(id, updates, selectFields)
+	// Posts embed their author, so the listing is refreshed as well
+	r.store.Delete(append(userKeys(id), cache.PostsAllKey)...)
+	return user, err
+}
+
+func (r *cachedUserRepository) UpdatePassword(id uint, passwordHash string) error {
+	err := r.UserRepository.UpdatePassword(id, passwordHash)
+	r.store.Delete(cache.UserKey(id))
+	return err
+}
+
+func (r *cachedUserRepository) SetPasswordWithToken(tokenHash string, now time.Time, passwordHash string) (uint, error) {
+	id, err := r.UserRepository.SetPasswordWithToken(tokenHash, now, passwordHash)
+	if err == nil {
+		r.store.Delete(cache.UserKey(id))
+	}
+	return id, err
+}
+
+func (r *cachedUserRepository) DeleteByID(id uint) error {
+	err := r.UserRepository.DeleteByID(id)
+	r.store.Delete(append(userKeys(id), cache.PostsAllKey)...)
+	return err
+}
+
+func (r *cachedUserRepository) Follow(followerID, followingID uint) error {
+	err := r.UserRepository.Follow(followerID, followingID)
+	r.store.Delete(cache.UserKey(followerID), cache.UserKey(followingID))
+	return err
+}
+
+func (r *cachedUserRepository) Unfollow(followerID, followingID uint) error {
+	err := r.UserRepository.Unfollow(followerID, followingID)
+	r.store.Delete(cache.UserKey(followerID), cache.UserKey(followingID))
+	return err
+}
+
+func (r *cachedUserRepository) UpdateRoleByIDs(ids []uint, role string) (int64, error) {
+	affected, err := r.UserRepository.UpdateRoleByIDs(ids, role)
+	r.store.Delete(userKeys(ids...)...)
+	return affected, err
+}
+
+func (r *cachedUserRepository) SetSuspended(ids []uint, suspended bool) (int64, error) {
+	affected, err := r.UserRepository.SetSuspended(ids, suspended)
+	r.store.Delete(userKeys(ids...)...)
+	return affected, err
+}
+
+// userKeys lists the profile and auth keys of each user.
+func userKeys(ids ...uint) []string {
+	keys := make([]string, 0, 2*len(ids))
+	for _, id := range ids {
+		keys = append(keys, cache.UserKey(id), cache.UserAuthKey(id))
+	}
+	return keys
+}
@@ -0,0 +1,113 @@
+package user_repository
+
+import (
+	"flower-backend/cache"
+	"flower-backend/models"
+	"flower-backend/testutil"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func TestCachedUserRepositoryInvalidation(t *testing.T) {
+	inner, db := newTestRepository(t)
+	repo := newCachedUserRepository(inner, cache.NewMemoryCache(100), time.Minute, testutil.Logger())
+	alice := testutil.CreateUser(t, db)
+	bob := testutil.CreateUser(t, db)
+	oldName := alice.Username
+
+	if _, err := repo.GetByID(bob.ID); err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if _, err := repo.GetByUsername(oldName); err != nil {
+		t.Fatalf("GetByUsername() error = %v", err)
+	}
+
+	if err := repo.Follow(alice.ID, bob.ID); err != nil {
+		t.Fatalf("Follow() error = %v", err)
+	}
+	if got, _ := repo.GetByID(bob.ID); got.FollowersCount != 1 {
+		t.Errorf("cached followers_count = %d, want 1", got.FollowersCount)
+	}
+
+	if _, err := repo.UpdateByIDWithSelect(alice.ID, map[string]any{"username": "renamed"}, []string{"username"}); err != nil {
+		t.Fatalf("UpdateByIDWithSelect() error = %v", err)
+	}
+	if _, err := repo.GetByUsername(oldName); err != gorm.ErrRecordNotFound {
+		t.Errorf("GetByUsername(old name) error = %v, want ErrRecordNotFound", err)
+	}
+	if got, err := repo.GetByUsername("renamed"); err != nil || got.ID != alice.ID {
+		t.Errorf("GetByUsername(renamed) = %v, %v, want alice", got, err)
+	}
+
+	if _, err := repo.SetSuspended([]uint{bob.ID}, true); err != nil {
+		t.Fatalf("SetSuspended() error = %v", err)
+	}
+	if got, _ := repo.GetByID(bob.ID); !got.Suspended {
+		t.Error("cached user is not suspended")
+	}
+}
+
+func TestCachedUserRepositoryAuthLookup(t *testing.T) {
+	inner, db := newTestRepository(t)
+	store := cache.NewMemoryCache(100)
+	repo := newCachedUserRepository(inner, store, time.Minute, testutil.Logger())
+	alice := testutil.CreateUser(t, db)
+	bob := testutil.CreateUser(t, db)
+
+	got, err := repo.GetAuthByID(bob.ID)
+	if err != nil {
+		t.Fatalf("GetAuthByID() error = %v", err)
+	}
+	if got.ID != bob.ID || got.Role != bob.Role || got.Username != "" {
+		t.Errorf("GetAuthByID() = %+v, want only id, role and suspended", got)
+	}
+
+	// Follows only touch the profile entry
+	if err := repo.Follow(alice.ID, bob.ID); err != nil {
+		t.Fatalf("Follow() error = %v", err)
+	}
+	if _, ok := store.Get(cache.UserAuthKey(bob.ID)); !ok {
+		t.Error("Follow() dropped the auth entry")
+	}
+
+	if _, err := repo.SetSuspended([]uint{bob.ID}, true); err != nil {
+		t.Fatalf("SetSuspended() error = %v", err)
+	}
+	if got, _ := repo.GetAuthByID(bob.ID); !got.Suspended {
+		t.Error("cached auth entry is not suspended")
+	}
+}
+
+func TestCachedUserRepositoryTrimsCachedUser(t *testing.T) {
+	inner, db := newTestRepository(t)
+	store := cache.NewMemoryCache(100)
+	repo := newCachedUserRepository(inner, store, time.Minute, testutil.Logger())
+	alice := testutil.CreateUser(t, db)
+	bob := testutil.CreateUser(t, db)
+	first := testutil.CreatePost(t, db, bob.ID)
+	testutil.CreateLike(t, db, first.ID, alice.ID)
+
+	if got, err := repo.GetByID(alice.ID); err != nil || len(got.Likes) != 1 || got.Password == "" {
+		t.Fatalf("GetByID() = %+v, %v, want the full user", got, err)
+	}
+	var cached models.User
+	if !cache.Load(store, cache.UserKey(alice.ID), &cached) {
+		t.Fatal("GetByID() did not cache the user")
+	}
+	if cached.Password != "" || cached.Likes != nil {
+		t.Errorf("cached user has password %q and %d likes, want neither", cached.Password, len(cached.Likes))
+	}
+
+	// Likes are read fresh on a hit, so new ones show up at once
+	second := testutil.CreatePost(t, db, bob.ID)
+	testutil.CreateLike(t, db, second.ID, alice.ID)
+	got, err := repo.GetByID(alice.ID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if len(got.Likes) != 2 {
+		t.Errorf("GetByID() likes = %d, want 2", len(got.Likes))
+	}
+}
@@ -26,11 +26,33 @@ func (r *userRepository) CreateToken(token *models.Token) error {
 	return r.db.Create(token).Error
 }
 
+func (r *userRepository) GetToken(token string) (*models.Token, error) {
+	var found models.Token
+	if err := r.db.Where("token = ?", token).First(&found).Error; err != nil {
+		return nil, err
+	}
+	return &found, nil
+}
+
+func (r *userRepository) DeleteToken(token string) error {
+	if err := r.db.Where("token = ?", token).Delete(&models.Token{}).Error; err != nil {
+		r.logger.Error("failed to delete token", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// DeleteExpiredTokens removes refresh and password tokens expired at now.
 func (r *userRepository) DeleteExpiredTokens(now time.Time) (int64, error) {
 	result := r.db.Where("expires_at < ?", now).Delete(&models.Token{})
 	if result.Error != nil {
 		r.logger.Error("failed to delete expired tokens", zap.Error(result.Error))
 		return 0, result.Error
 	}
-	return result.RowsAffected, nil
+	passwordTokens := r.db.Where("expires_at < ?", now).Delete(&models.PasswordToken{})
+	if passwordTokens.Error != nil {
+		r.logger.Error("failed to delete expired password tokens", zap.Error(passwordTokens.Error))
+		return 0, passwordTokens.Error
+	}
+	return result.RowsAffected + passwordTokens.RowsAffected, nil
 }
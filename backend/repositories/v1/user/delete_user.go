@@ -1,6 +1,7 @@
 package user_repository
 
 import (
+	"flower-backend/jobs"
 	"flower-backend/libs"
 	"flower-backend/models"
 
@@ -18,21 +19,50 @@ func (r *userRepository) DeleteByID(id uint) error {
 		return err
 	}
 
-	if user.Avatar != "" {
-		cld, _ := libs.NewCloudinary(r.cfg)
-		publicId := libs.ExtractPublicId(user.Avatar)
-		if err := libs.DeleteFromCloudinary(cld, publicId); err != nil {
-			r.logger.Error("failed to delete avatar from cloudinary", zap.Error(err))
-			return err
-		}
-	}
-
 	if err := r.db.Where("user_id = ?", id).Delete(&models.Token{}).Error; err != nil {
 		r.logger.Error("failed to delete user tokens", zap.Error(err))
 		return err
 	}
 
-	if err := r.db.Delete(&user).Error; err != nil {
+	if err := r.db.Where("user_id = ?", id).Delete(&models.PostView{}).Error; err != nil {
+		r.logger.Error("failed to delete user post views", zap.Error(err))
+		return err
+	}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Release the counters this user contributed to on other rows
+		if err := tx.Exec("UPDATE users SET following_count = following_count - 1 WHERE following_count > 0 AND id IN (SELECT follower_id FROM user_follows WHERE following_id = ?)", id).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("UPDATE users SET followers_count = followers_count - 1 WHERE followers_count > 0 AND id IN (SELECT following_id FROM user_follows WHERE follower_id = ?)", id).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("UPDATE posts SET likes_count = likes_count - 1 WHERE likes_count > 0 AND id IN (SELECT post_id FROM post_likes WHERE user_id = ?)", id).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("follower_id = ? OR following_id = ?", id, id).Delete(&models.UserFollow{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", id).Delete(&models.PostLike{}).Error; err != nil {
+			return err
+		}
+		// Notifications for the user, by them or about their posts
+		if err := tx.Where("user_id = ? OR actor_id = ?", id, id).Delete(&models.Notification{}).Error; err != nil {
+			return err
+		}
+		// The avatar is removed by a background job that commits with the delete
+		if user.Avatar != "" {
+			job, err := jobs.NewJob(jobs.TypeDeleteAsset, jobs.DeleteAsset{PublicID: libs.ExtractPublicId(user.Avatar)})
+			if err != nil {
+				return err
+			}
+			if err := tx.Create(job).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Delete(&user).Error
+	})
+	if err != nil {
 		r.logger.Error("failed to delete user by id", zap.Error(err))
 		return err
 	}
@@ -5,6 +5,7 @@ import (
 
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 func (r *userRepository) Follow(followerID, followingID uint) error {
@@ -16,8 +17,29 @@ func (r *userRepository) Follow(followerID, followingID uint) error {
 		r.logger.Error("failed to find follower", zap.Error(err))
 		return err
 	}
+	var followingCount int64
+	if err := r.db.Model(&models.User{}).Where("id = ?", followingID).Count(&followingCount).Error; err != nil {
+		r.logger.Error("failed to check if followed user exists", zap.Error(err))
+		return err
+	}
+	if followingCount == 0 {
+		return gorm.ErrRecordNotFound
+	}
 
-	if err := r.db.Model(&follower).Association("Following").Append(&models.User{ID: followingID}); err != nil {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Insert the join row directly so the follow is timestamped
+		follow := models.UserFollow{FollowerID: follower.ID, FollowingID: followingID}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&follow)
+		if result.Error != nil {
+			return result.Error
+		}
+		// Already following, counters are unchanged
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		return adjustFollowCounts(tx, followerID, followingID, 1)
+	})
+	if err != nil {
 		r.logger.Error("failed to follow user", zap.Error(err))
 		return err
 	}
@@ -35,7 +57,17 @@ func (r *userRepository) Unfollow(followerID, followingID uint) error {
 		return err
 	}
 
-	if err := r.db.Model(&follower).Association("Following").Delete(&models.User{ID: followingID}); err != nil {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("follower_id = ? AND following_id = ?", followerID, followingID).Delete(&models.UserFollow{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		return adjustFollowCounts(tx, followerID, followingID, -1)
+	})
+	if err != nil {
 		r.logger.Error("failed to unfollow user", zap.Error(err))
 		return err
 	}
@@ -43,6 +75,17 @@ func (r *userRepository) Unfollow(followerID, followingID uint) error {
 	return nil
 }
 
+// adjustFollowCounts applies delta to the follower's following_count and the
+// followed user's followers_count. Counters never drop below zero.
+func adjustFollowCounts(tx *gorm.DB, followerID, followingID uint, delta int) error {
+	if err := tx.Model(&models.User{}).Where("id = ? AND following_count + ? >= 0", followerID, delta).
+		UpdateColumn("following_count", gorm.Expr("following_count + ?", delta)).Error; err != nil {
+		return err
+	}
+	return tx.Model(&models.User{}).Where("id = ? AND followers_count + ? >= 0", followingID, delta).
+		UpdateColumn("followers_count", gorm.Expr("followers_count + ?", delta)).Error
+}
+
 func (r *userRepository) CheckFollowExists(followerID, followingID uint) (bool, error) {
 	var count int64
 	err := r.db.Table("user_follows").
@@ -55,6 +98,32 @@ func (r *userRepository) CheckFollowExists(followerID, followingID uint) (bool,
 	return count > 0, nil
 }
 
+// GetFollowRelations reports, in a single query, which of userIDs the viewer
+// follows and which of them follow the viewer.
+func (r *userRepository) GetFollowRelations(viewerID uint, userIDs []uint) (map[uint]bool, map[uint]bool, error) {
+	following := make(map[uint]bool)
+	followedBy := make(map[uint]bool)
+	if len(userIDs) == 0 {
+		return following, followedBy, nil
+	}
+	var follows []models.UserFollow
+	if err := r.db.Select("follower_id", "following_id").
+		Where("(follower_id = ? AND following_id IN ?) OR (following_id = ? AND follower_id IN ?)", viewerID, userIDs, viewerID, userIDs).
+		Find(&follows).Error; err != nil {
+		r.logger.Error("failed to get follow relations", zap.Error(err))
+		return nil, nil, err
+	}
+	for _, follow := range follows {
+		if follow.FollowerID == viewerID {
+			following[follow.FollowingID] = true
+		}
+		if follow.FollowingID == viewerID {
+			followedBy[follow.FollowerID] = true
+		}
+	}
+	return following, followedBy, nil
+}
+
 func (r *userRepository) GetFollowers(userID uint) ([]models.User, error) {
 	var user models.User
 	if err := r.db.First(&user, userID).Error; err != nil {
@@ -95,9 +164,9 @@ func (r *userRepository) GetFollowing(userID uint) ([]models.User, error) {
 
 func (r *userRepository) GetFollowersCount(userID uint) (int64, error) {
 	var count int64
-	if err := r.db.Table("user_follows").
-		Where("following_id = ?", userID).
-		Count(&count).Error; err != nil {
+	if err := r.db.Model(&models.User{}).
+		Where("id = ?", userID).
+		Pluck("followers_count", &count).Error; err != nil {
 		r.logger.Error("failed to get user followers count", zap.Error(err))
 		return 0, err
 	}
@@ -106,9 +175,9 @@ func (r *userRepository) GetFollowersCount(userID uint) (int64, error) {
 
 func (r *userRepository) GetFollowingCount(userID uint) (int64, error) {
 	var count int64
-	if err := r.db.Table("user_follows").
-		Where("follower_id = ?", userID).
-		Count(&count).Error; err != nil {
+	if err := r.db.Model(&models.User{}).
+		Where("id = ?", userID).
+		Pluck("following_count", &count).Error; err != nil {
 		r.logger.Error("failed to get user following count", zap.Error(err))
 		return 0, err
 	}
@@ -127,15 +196,17 @@ func (r *userRepository) GetFollowingPosts(userID uint, page, limit int) ([]mode
 	}
 
 	offset := (page - 1) * limit
-	if err := r.db.Table("posts").Count(&total).Error; err != nil {
+	query := r.db.Model(&models.Post{}).
+		Joins("JOIN user_follows ON user_follows.following_id = posts.user_id").
+		Where("user_follows.follower_id = ? AND posts.hidden = ? AND posts.status = ?", userID, false, models.PostPublished)
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
 		r.logger.Error("failed to get total posts", zap.Error(err))
 		return nil, 0, err
 	}
 
-	err := r.db.
-		Joins("JOIN user_follows ON user_follows.following_id = posts.user_id").
-		Where("user_follows.follower_id = ?", userID).
+	err := query.
 		Preload("User").
+		Preload("Tags").
 		Order("posts.created_at DESC").
 		Offset(offset).
 		Limit(limit).
@@ -9,7 +9,7 @@ import (
 
 func (r *userRepository) GetByID(id uint) (*models.User, error) {
 	var user models.User
-	if err := r.db.Preload("Posts").Preload("Likes").Preload("Followers").Preload("Following").Where("id = ?", id).First(&user).Error; err != nil {
+	if err := r.db.Preload("Likes").Where("id = ?", id).First(&user).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, gorm.ErrRecordNotFound
 		}
@@ -19,9 +19,21 @@ func (r *userRepository) GetByID(id uint) (*models.User, error) {
 	return &user, nil
 }
 
+func (r *userRepository) GetAuthByID(id uint) (*models.User, error) {
+	var user models.User
+	if err := r.db.Select("id", "role", "suspended").Where("id = ?", id).First(&user).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, gorm.ErrRecordNotFound
+		}
+		r.logger.Error("failed to get user auth by id", zap.Error(err))
+		return nil, err
+	}
+	return &user, nil
+}
+
 func (r *userRepository) GetByEmail(email string) (*models.User, error) {
 	var user models.User
-	if err := r.db.Preload("Posts").Preload("Likes").Preload("Followers").Preload("Following").Where("email = ?", email).First(&user).Error; err != nil {
+	if err := r.db.Preload("Likes").Where("email = ?", email).First(&user).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, gorm.ErrRecordNotFound
 		}
@@ -33,7 +45,7 @@ func (r *userRepository) GetByEmail(email string) (*models.User, error) {
 
 func (r *userRepository) GetByUsername(username string) (*models.User, error) {
 	var user models.User
-	if err := r.db.Preload("Posts").Preload("Likes").Preload("Followers").Preload("Following").Where("username = ?", username).First(&user).Error; err != nil {
+	if err := r.db.Preload("Likes").Where("username = ?", username).First(&user).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, gorm.ErrRecordNotFound
 		}
@@ -55,9 +67,18 @@ func (r *userRepository) GetByIDWithSelect(id uint, selectFields []string) (*mod
 	return &user, nil
 }
 
+func (r *userRepository) GetLikedPosts(userID uint) ([]models.Post, error) {
+	var posts []models.Post
+	if err := r.db.Model(&models.User{ID: userID}).Association("Likes").Find(&posts); err != nil {
+		r.logger.Error("failed to get liked posts", zap.Error(err))
+		return nil, err
+	}
+	return posts, nil
+}
+
 func (r *userRepository) GetAll() ([]models.User, error) {
 	var users []models.User
-	if err := r.db.Preload("Posts").Preload("Likes").Preload("Followers").Preload("Following").Find(&users).Error; err != nil {
+	if err := r.db.Preload("Likes").Find(&users).Error; err != nil {
 		r.logger.Error("failed to get all users", zap.Error(err))
 		return nil, err
 	}
@@ -0,0 +1,40 @@
+package user_repository
+
+import (
+	"flower-backend/models"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// GetNotifications returns the user's notifications, newest first. Those
+// about a post an admin has since hidden are left out.
+func (r *userRepository) GetNotifications(userID uint, page, limit int) ([]models.Notification, int64, error) {
+	var notifications []models.Notification
+	var total int64
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 20
+	}
+
+	query := r.db.Model(&models.Notification{}).
+		Joins("JOIN posts ON posts.id = notifications.post_id").
+		Where("notifications.user_id = ? AND posts.status = ? AND posts.hidden = ?", userID, models.PostPublished, false)
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		r.logger.Error("failed to count notifications", zap.Error(err))
+		return nil, 0, err
+	}
+	err := query.
+		Order("notifications.created_at DESC, notifications.id DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&notifications).Error
+	if err != nil {
+		r.logger.Error("failed to get notifications", zap.Error(err))
+		return nil, 0, err
+	}
+	return notifications, total, nil
+}
@@ -0,0 +1,51 @@
+package user_repository
+
+import (
+	"flower-backend/models"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func (r *userRepository) CreatePasswordToken(token *models.PasswordToken) error {
+	if err := r.db.Create(token).Error; err != nil {
+		r.logger.Error("failed to create password token", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// SetPasswordWithToken uses up the password token whose hash is tokenHash and
+// stores passwordHash for its user, revoking their refresh tokens like
+// UpdatePassword. It returns gorm.ErrRecordNotFound when the token is unknown,
+// expired at now or already used.
+func (r *userRepository) SetPasswordWithToken(tokenHash string, now time.Time, passwordHash string) (uint, error) {
+	var userID uint
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var token models.PasswordToken
+		if err := tx.Where("token_hash = ? AND expires_at > ?", tokenHash, now).First(&token).Error; err != nil {
+			return err
+		}
+		// A concurrent request may have used the token since it was read
+		result := tx.Where("id = ?", token.ID).Delete(&models.PasswordToken{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if err := tx.Model(&models.User{}).Where("id = ?", token.UserID).Update("password", passwordHash).Error; err != nil {
+			return err
+		}
+		userID = token.UserID
+		return tx.Where("user_id = ?", token.UserID).Delete(&models.Token{}).Error
+	})
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			r.logger.Error("failed to set password with token", zap.Error(err))
+		}
+		return 0, err
+	}
+	return userID, nil
+}
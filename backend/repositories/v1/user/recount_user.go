@@ -0,0 +1,22 @@
+package user_repository
+
+import (
+	"flower-backend/models"
+
+	"go.uber.org/zap"
+)
+
+// RecountCounters recomputes followers_count, following_count and posts_count
+// for every user from the user_follows and posts tables. posts_count counts
+// published posts only.
+func (r *userRepository) RecountCounters() (int64, error) {
+	result := r.db.Exec(`UPDATE users SET
+		followers_count = (SELECT COUNT(*) FROM user_follows WHERE user_follows.following_id = users.id),
+		following_count = (SELECT COUNT(*) FROM user_follows WHERE user_follows.follower_id = users.id),
+		posts_count = (SELECT COUNT(*) FROM posts WHERE posts.user_id = users.id AND posts.status = ?)`, models.PostPublished)
+	if result.Error != nil {
+		r.logger.Error("failed to recount user counters", zap.Error(result.Error))
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,232 @@
+package user_repository
+
+import (
+	"context"
+	"flower-backend/models"
+	"flower-backend/tracing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("flower-backend/repositories/v1/user")
+
+// tracedUserRepository starts a span around every call and runs the wrapped
+// repository under it, so the GORM query spans nest beneath.
+type tracedUserRepository struct {
+	UserRepository
+	ctx context.Context
+}
+
+func newTracedUserRepository(repo UserRepository) UserRepository {
+	return &tracedUserRepository{UserRepository: repo, ctx: context.Background()}
+}
+
+func (r *tracedUserRepository) WithContext(ctx context.Context) UserRepository {
+	return &tracedUserRepository{UserRepository: r.UserRepository, ctx: ctx}
+}
+
+// start opens the span for method and returns the wrapped repository bound to it.
+func (r *tracedUserRepository) start(method string) (UserRepository, trace.Span) {
+	ctx, span := tracer.Start(r.ctx, "UserRepository."+method)
+	return r.UserRepository.WithContext(ctx), span
+}
+
+func (r *tracedUserRepository) Create(user *models.User) (err error) {
+	repo, span := r.start("Create")
+	defer tracing.End(span, &err)
+	return repo.Create(user)
+}
+
+func (r *tracedUserRepository) CreateToken(token *models.Token) (err error) {
+	repo, span := r.start("CreateToken")
+	defer tracing.End(span, &err)
+	return repo.CreateToken(token)
+}
+
+func (r *tracedUserRepository) GetToken(token string) (_ *models.Token, err error) {
+	repo, span := r.start("GetToken")
+	defer tracing.End(span, &err)
+	return repo.GetToken(token)
+}
+
+func (r *tracedUserRepository) DeleteToken(token string) (err error) {
+	repo, span := r.start("DeleteToken")
+	defer tracing.End(span, &err)
+	return repo.DeleteToken(token)
+}
+
+func (r *tracedUserRepository) GetByID(id uint) (_ *models.User, err error) {
+	repo, span := r.start("GetByID")
+	defer tracing.End(span, &err)
+	return repo.GetByID(id)
+}
+
+func (r *tracedUserRepository) GetAuthByID(id uint) (_ *models.User, err error) {
+	repo, span := r.start("GetAuthByID")
+	defer tracing.End(span, &err)
+	return repo.GetAuthByID(id)
+}
+
+func (r *tracedUserRepository) GetByEmail(email string) (_ *models.User, err error) {
+	repo, span := r.start("GetByEmail")
+	defer tracing.End(span, &err)
+	return repo.GetByEmail(email)
+}
+
+func (r *tracedUserRepository) GetByUsername(username string) (_ *models.User, err error) {
+	repo, span := r.start("GetByUsername")
+	defer tracing.End(span, &err)
+	return repo.GetByUsername(username)
+}
+
+func (r *tracedUserRepository) GetByIDWithSelect(id uint, selectFields []string) (_ *models.User, err error) {
+	repo, span := r.start("GetByIDWithSelect")
+	defer tracing.End(span, &err)
+	return repo.GetByIDWithSelect(id, selectFields)
+}
+
+func (r *tracedUserRepository) GetLikedPosts(userID uint) (_ []models.Post, err error) {
+	repo, span := r.start("GetLikedPosts")
+	defer tracing.End(span, &err)
+	return repo.GetLikedPosts(userID)
+}
+
+func (r *tracedUserRepository) GetAll() (_ []models.User, err error) {
+	repo, span := r.start("GetAll")
+	defer tracing.End(span, &err)
+	return repo.GetAll()
+}
+
+func (r *tracedUserRepository) UpdateByIDWithSelect(id uint, updates map[string]any, selectFields []string) (_ *models.User, err error) {
+	repo, span := r.start("UpdateByIDWithSelect")
+	defer tracing.End(span, &err)
+	return repo.UpdateByIDWithSelect(id, updates, selectFields)
+}
+
+func (r *tracedUserRepository) UpdatePassword(id uint, passwordHash string) (err error) {
+	repo, span := r.start("UpdatePassword")
+	defer tracing.End(span, &err)
+	return repo.UpdatePassword(id, passwordHash)
+}
+
+func (r *tracedUserRepository) CreatePasswordToken(token *models.PasswordToken) (err error) {
+	repo, span := r.start("CreatePasswordToken")
+	defer tracing.End(span, &err)
+	return repo.CreatePasswordToken(token)
+}
+
+func (r *tracedUserRepository) SetPasswordWithToken(tokenHash string, now time.Time, passwordHash string) (_ uint, err error) {
+	repo, span := r.start("SetPasswordWithToken")
+	defer tracing.End(span, &err)
+	return repo.SetPasswordWithToken(tokenHash, now, passwordHash)
+}
+
+func (r *tracedUserRepository) DeleteByID(id uint) (err error) {
+	repo, span := r.start("DeleteByID")
+	defer tracing.End(span, &err)
+	return repo.DeleteByID(id)
+}
+
+func (r *tracedUserRepository) Follow(followerID, followingID uint) (err error) {
+	repo, span := r.start("Follow")
+	defer tracing.End(span, &err)
+	return repo.Follow(followerID, followingID)
+}
+
+func (r *tracedUserRepository) Unfollow(followerID, followingID uint) (err error) {
+	repo, span := r.start("Unfollow")
+	defer tracing.End(span, &err)
+	return repo.Unfollow(followerID, followingID)
+}
+
+func (r *tracedUserRepository) CheckFollowExists(followerID, followingID uint) (_ bool, err error) {
+	repo, span := r.start("CheckFollowExists")
+	defer tracing.End(span, &err)
+	return repo.CheckFollowExists(followerID, followingID)
+}
+
+func (r *tracedUserRepository) GetFollowRelations(viewerID uint, userIDs []uint) (_ map[uint]bool, _ map[uint]bool, err error) {
+	repo, span := r.start("GetFollowRelations")
+	defer tracing.End(span, &err)
+	return repo.GetFollowRelations(viewerID, userIDs)
+}
+
+func (r *tracedUserRepository) GetFollowers(userID uint) (_ []models.User, err error) {
+	repo, span := r.start("GetFollowers")
+	defer tracing.End(span, &err)
+	return repo.GetFollowers(userID)
+}
+
+func (r *tracedUserRepository) GetFollowing(userID uint) (_ []models.User, err error) {
+	repo, span := r.start("GetFollowing")
+	defer tracing.End(span, &err)
+	return repo.GetFollowing(userID)
+}
+
+func (r *tracedUserRepository) GetFollowersCount(userID uint) (_ int64, err error) {
+	repo, span := r.start("GetFollowersCount")
+	defer tracing.End(span, &err)
+	return repo.GetFollowersCount(userID)
+}
+
+func (r *tracedUserRepository) GetFollowingCount(userID uint) (_ int64, err error) {
+	repo, span := r.start("GetFollowingCount")
+	defer tracing.End(span, &err)
+	return repo.GetFollowingCount(userID)
+}
+
+func (r *tracedUserRepository) GetFollowingPosts(userID uint, page, limit int) (_ []models.Post, _ int64, err error) {
+	repo, span := r.start("GetFollowingPosts")
+	defer tracing.End(span, &err)
+	return repo.GetFollowingPosts(userID, page, limit)
+}
+
+func (r *tracedUserRepository) GetNotifications(userID uint, page, limit int) (_ []models.Notification, _ int64, err error) {
+	repo, span := r.start("GetNotifications")
+	defer tracing.End(span, &err)
+	return repo.GetNotifications(userID, page, limit)
+}
+
+func (r *tracedUserRepository) DeleteExpiredTokens(now time.Time) (_ int64, err error) {
+	repo, span := r.start("DeleteExpiredTokens")
+	defer tracing.End(span, &err)
+	return repo.DeleteExpiredTokens(now)
+}
+
+func (r *tracedUserRepository) GetAllWithFilter(filter UserFilter) (_ []models.User, err error) {
+	repo, span := r.start("GetAllWithFilter")
+	defer tracing.End(span, &err)
+	return repo.GetAllWithFilter(filter)
+}
+
+func (r *tracedUserRepository) GetExisting(emails, usernames []string) (_ []models.User, err error) {
+	repo, span := r.start("GetExisting")
+	defer tracing.End(span, &err)
+	return repo.GetExisting(emails, usernames)
+}
+
+func (r *tracedUserRepository) CreateBatch(users []models.User, invites []Invite) (err error) {
+	repo, span := r.start("CreateBatch")
+	defer tracing.End(span, &err)
+	return repo.CreateBatch(users, invites)
+}
+
+func (r *tracedUserRepository) UpdateRoleByIDs(ids []uint, role string) (_ int64, err error) {
+	repo, span := r.start("UpdateRoleByIDs")
+	defer tracing.End(span, &err)
+	return repo.UpdateRoleByIDs(ids, role)
+}
+
+func (r *tracedUserRepository) SetSuspended(ids []uint, suspended bool) (_ int64, err error) {
+	repo, span := r.start("SetSuspended")
+	defer tracing.End(span, &err)
+	return repo.SetSuspended(ids, suspended)
+}
+
+func (r *tracedUserRepository) RecountCounters() (_ int64, err error) {
+	repo, span := r.start("RecountCounters")
+	defer tracing.End(span, &err)
+	return repo.RecountCounters()
+}
@@ -7,14 +7,9 @@ import (
 	"gorm.io/gorm"
 )
 
-func (r *userRepository) Update(user *models.User) error {
-	if err := r.db.Save(user).Error; err != nil {
-		r.logger.Error("failed to update user", zap.Error(err))
-		return err
-	}
-	return nil
-}
-
+// UpdateByIDWithSelect writes the selectFields present in updates, and only
+// those, then returns the user as stored. Counters and suspension changed by
+// other requests meanwhile are left alone.
 func (r *userRepository) UpdateByIDWithSelect(id uint, updates map[string]any, selectFields []string) (*models.User, error) {
 	var user models.User
 
@@ -38,7 +33,7 @@ func (r *userRepository) UpdateByIDWithSelect(id uint, updates map[string]any, s
 	}
 
 	// Apply the filtered updates
-	if err := r.db.Model(&user).Updates(filtered).Error; err != nil {
+	if err := r.db.Model(&models.User{ID: id}).Updates(filtered).Error; err != nil {
 		r.logger.Error("failed to update user", zap.Error(err))
 		return nil, err
 	}
@@ -55,3 +50,22 @@ func (r *userRepository) UpdateByIDWithSelect(id uint, updates map[string]any, s
 	r.logger.Info("user updated successfully", zap.Uint("id", id))
 	return &user, nil
 }
+
+// UpdatePassword stores a new password hash and revokes the user's refresh
+// tokens, so existing sessions end once their access token expires.
+func (r *userRepository) UpdatePassword(id uint, passwordHash string) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.User{}).Where("id = ?", id).Update("password", passwordHash)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Where("user_id = ?", id).Delete(&models.Token{}).Error
+	})
+	if err != nil && err != gorm.ErrRecordNotFound {
+		r.logger.Error("failed to update user password", zap.Error(err))
+	}
+	return err
+}
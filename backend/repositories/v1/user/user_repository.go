@@ -1,7 +1,10 @@
 package user_repository
 
 import (
+	"context"
+	"flower-backend/cache"
 	"flower-backend/config"
+	"flower-backend/log"
 	"flower-backend/models"
 	"time"
 
@@ -10,25 +13,43 @@ import (
 )
 
 type UserRepository interface {
+	// WithContext returns a repository whose calls run under ctx
+	WithContext(ctx context.Context) UserRepository
 	Create(user *models.User) error
 	CreateToken(token *models.Token) error
+	GetToken(token string) (*models.Token, error)
+	DeleteToken(token string) error
 	GetByID(id uint) (*models.User, error)
+	// GetAuthByID loads only the ID, role and suspension of a user
+	GetAuthByID(id uint) (*models.User, error)
 	GetByEmail(email string) (*models.User, error)
 	GetByUsername(username string) (*models.User, error)
 	GetByIDWithSelect(id uint, selectFields []string) (*models.User, error)
+	// GetLikedPosts loads the posts a user has liked, as GetByID preloads them
+	GetLikedPosts(userID uint) ([]models.Post, error)
 	GetAll() ([]models.User, error)
-	Update(user *models.User) error
 	UpdateByIDWithSelect(id uint, updates map[string]any, selectFields []string) (*models.User, error)
+	UpdatePassword(id uint, passwordHash string) error
+	CreatePasswordToken(token *models.PasswordToken) error
+	SetPasswordWithToken(tokenHash string, now time.Time, passwordHash string) (uint, error)
 	DeleteByID(id uint) error
 	Follow(followerID, followingID uint) error
 	Unfollow(followerID, followingID uint) error
 	CheckFollowExists(followerID, followingID uint) (bool, error)
+	GetFollowRelations(viewerID uint, userIDs []uint) (map[uint]bool, map[uint]bool, error)
 	GetFollowers(userID uint) ([]models.User, error)
 	GetFollowing(userID uint) ([]models.User, error)
 	GetFollowersCount(userID uint) (int64, error)
 	GetFollowingCount(userID uint) (int64, error)
 	GetFollowingPosts(userID uint, page, limit int) ([]models.Post, int64, error)
+	GetNotifications(userID uint, page, limit int) ([]models.Notification, int64, error)
 	DeleteExpiredTokens(now time.Time) (int64, error)
+	GetAllWithFilter(filter UserFilter) ([]models.User, error)
+	GetExisting(emails, usernames []string) ([]models.User, error)
+	CreateBatch(users []models.User, invites []Invite) error
+	UpdateRoleByIDs(ids []uint, role string) (int64, error)
+	SetSuspended(ids []uint, suspended bool) (int64, error)
+	RecountCounters() (int64, error)
 }
 
 type userRepository struct {
@@ -37,10 +58,20 @@ type userRepository struct {
 	logger *zap.SugaredLogger
 }
 
-func NewUserRepository(db *gorm.DB, cfg *config.Config, logger *zap.SugaredLogger) UserRepository {
-	return &userRepository{
+// NewUserRepository builds the repository, serving reads from store unless
+// it is nil.
+func NewUserRepository(db *gorm.DB, cfg *config.Config, store cache.Cache, logger *zap.SugaredLogger) UserRepository {
+	var repo UserRepository = &userRepository{
 		db:     db,
 		cfg:    cfg,
 		logger: logger,
 	}
+	if store != nil {
+		repo = newCachedUserRepository(repo, store, cfg.CacheTTL, logger)
+	}
+	return newTracedUserRepository(repo)
+}
+
+func (r *userRepository) WithContext(ctx context.Context) UserRepository {
+	return &userRepository{db: r.db.WithContext(ctx), cfg: r.cfg, logger: log.SugarFromContext(ctx, r.logger)}
 }
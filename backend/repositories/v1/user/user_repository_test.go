@@ -0,0 +1,602 @@
+package user_repository
+
+import (
+	"flower-backend/models"
+	"flower-backend/testutil"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func newTestRepository(t *testing.T) (*userRepository, *gorm.DB) {
+	t.Helper()
+	db := testutil.NewDB(t)
+	return &userRepository{db: db, cfg: testutil.Config(), logger: testutil.Logger()}, db
+}
+
+func loadUser(t *testing.T, db *gorm.DB, id uint) models.User {
+	t.Helper()
+	var user models.User
+	if err := db.First(&user, id).Error; err != nil {
+		t.Fatalf("load user %d: %v", id, err)
+	}
+	return user
+}
+
+func followRows(t *testing.T, db *gorm.DB) int64 {
+	t.Helper()
+	var count int64
+	if err := db.Model(&models.UserFollow{}).Count(&count).Error; err != nil {
+		t.Fatalf("count follows: %v", err)
+	}
+	return count
+}
+
+func userIDs(users []models.User) []uint {
+	ids := make([]uint, 0, len(users))
+	for _, user := range users {
+		ids = append(ids, user.ID)
+	}
+	return ids
+}
+
+func TestCreateAndLookups(t *testing.T) {
+	repo, db := newTestRepository(t)
+	user := &models.User{Username: "daisy", Email: "daisy@example.com", Password: "hashed"}
+	if err := repo.Create(user); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if user.Role != "user" || user.Provider != "local" {
+		t.Errorf("defaults role=%q provider=%q, want user and local", user.Role, user.Provider)
+	}
+	if err := repo.Create(&models.User{Username: "daisy", Email: "other@example.com"}); err == nil {
+		t.Error("Create() with duplicate username succeeded, want unique constraint error")
+	}
+	testutil.CreateUser(t, db)
+
+	lookups := []struct {
+		name    string
+		get     func() (*models.User, error)
+		wantErr error
+	}{
+		{name: "GetByID", get: func() (*models.User, error) { return repo.GetByID(user.ID) }},
+		{name: "GetByEmail", get: func() (*models.User, error) { return repo.GetByEmail("daisy@example.com") }},
+		{name: "GetByUsername", get: func() (*models.User, error) { return repo.GetByUsername("daisy") }},
+		{name: "GetByIDWithSelect", get: func() (*models.User, error) {
+			return repo.GetByIDWithSelect(user.ID, []string{"id", "username", "email"})
+		}},
+		{name: "GetByID missing", get: func() (*models.User, error) { return repo.GetByID(user.ID + 100) }, wantErr: gorm.ErrRecordNotFound},
+		{name: "GetByEmail missing", get: func() (*models.User, error) { return repo.GetByEmail("nobody@example.com") }, wantErr: gorm.ErrRecordNotFound},
+		{name: "GetByUsername missing", get: func() (*models.User, error) { return repo.GetByUsername("nobody") }, wantErr: gorm.ErrRecordNotFound},
+	}
+	for _, tt := range lookups {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.get()
+			if err != tt.wantErr {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && (got.ID != user.ID || got.Username != "daisy") {
+				t.Errorf("got user %d %q, want %d daisy", got.ID, got.Username, user.ID)
+			}
+		})
+	}
+
+	all, err := repo.GetAll()
+	if err != nil || len(all) != 2 {
+		t.Errorf("GetAll() = %d users, %v, want 2", len(all), err)
+	}
+}
+
+func TestTokens(t *testing.T) {
+	repo, db := newTestRepository(t)
+	user := testutil.CreateUser(t, db)
+
+	if err := repo.CreateToken(&models.Token{Token: "first", UserID: user.ID}); err != nil {
+		t.Fatalf("CreateToken() error = %v", err)
+	}
+	second := &models.Token{Token: "second", UserID: user.ID}
+	if err := repo.CreateToken(second); err != nil {
+		t.Fatalf("CreateToken() error = %v", err)
+	}
+	var tokens []models.Token
+	db.Where("user_id = ?", user.ID).Find(&tokens)
+	if len(tokens) != 1 || tokens[0].Token != "second" {
+		t.Errorf("tokens = %v, want only the latest", tokens)
+	}
+	if second.ExpiresAt.IsZero() {
+		t.Error("CreateToken() left ExpiresAt unset")
+	}
+
+	other := testutil.CreateUser(t, db)
+	repo.CreateToken(&models.Token{Token: "expired", UserID: other.ID, ExpiresAt: time.Now().Add(-time.Hour)})
+	deleted, err := repo.DeleteExpiredTokens(time.Now())
+	if err != nil || deleted != 1 {
+		t.Errorf("DeleteExpiredTokens() = %d, %v, want 1", deleted, err)
+	}
+}
+
+func TestUpdateByIDWithSelect(t *testing.T) {
+	repo, db := newTestRepository(t)
+	user := testutil.CreateUser(t, db)
+
+	updated, err := repo.UpdateByIDWithSelect(user.ID, map[string]any{"username": "renamed", "role": "admin"}, []string{"username"})
+	if err != nil {
+		t.Fatalf("UpdateByIDWithSelect() error = %v", err)
+	}
+	if updated.Username != "renamed" || updated.Role != "user" {
+		t.Errorf("got username=%q role=%q, want renamed and unchanged role", updated.Username, updated.Role)
+	}
+	if _, err := repo.UpdateByIDWithSelect(user.ID+100, map[string]any{"username": "x"}, []string{"username"}); err != gorm.ErrRecordNotFound {
+		t.Errorf("UpdateByIDWithSelect() on missing user error = %v, want ErrRecordNotFound", err)
+	}
+
+	// Columns outside the update keep what other writers stored
+	db.Model(&models.User{}).Where("id = ?", user.ID).Updates(map[string]any{"followers_count": 3, "suspended": true})
+	updated, err = repo.UpdateByIDWithSelect(user.ID, map[string]any{"avatar": "https://example.com/a.png"}, []string{"avatar"})
+	if err != nil {
+		t.Fatalf("UpdateByIDWithSelect(avatar) error = %v", err)
+	}
+	if updated.Avatar != "https://example.com/a.png" || updated.FollowersCount != 3 || !updated.Suspended {
+		t.Errorf("got avatar=%q followers=%d suspended=%v, want the new avatar and the stored counters", updated.Avatar, updated.FollowersCount, updated.Suspended)
+	}
+}
+
+func TestUpdatePassword(t *testing.T) {
+	repo, db := newTestRepository(t)
+	user := testutil.CreateUser(t, db)
+	repo.CreateToken(&models.Token{Token: "session", UserID: user.ID})
+
+	if err := repo.UpdatePassword(user.ID, "new-hash"); err != nil {
+		t.Fatalf("UpdatePassword() error = %v", err)
+	}
+	if got := loadUser(t, db, user.ID); got.Password != "new-hash" {
+		t.Errorf("password = %q, want new-hash", got.Password)
+	}
+	var tokens int64
+	db.Model(&models.Token{}).Where("user_id = ?", user.ID).Count(&tokens)
+	if tokens != 0 {
+		t.Errorf("%d tokens remain, want 0", tokens)
+	}
+	if err := repo.UpdatePassword(user.ID+100, "x"); err != gorm.ErrRecordNotFound {
+		t.Errorf("UpdatePassword() on missing user error = %v, want ErrRecordNotFound", err)
+	}
+}
+
+func TestSetPasswordWithToken(t *testing.T) {
+	repo, db := newTestRepository(t)
+	user := testutil.CreateUser(t, db)
+	now := time.Now()
+	repo.CreateToken(&models.Token{Token: "session", UserID: user.ID})
+	if err := repo.CreatePasswordToken(&models.PasswordToken{TokenHash: "fresh", UserID: user.ID, ExpiresAt: now.Add(time.Hour)}); err != nil {
+		t.Fatalf("CreatePasswordToken() error = %v", err)
+	}
+	repo.CreatePasswordToken(&models.PasswordToken{TokenHash: "stale", UserID: user.ID, ExpiresAt: now.Add(-time.Minute)})
+
+	if _, err := repo.SetPasswordWithToken("stale", now, "x"); err != gorm.ErrRecordNotFound {
+		t.Errorf("SetPasswordWithToken(expired) error = %v, want ErrRecordNotFound", err)
+	}
+	id, err := repo.SetPasswordWithToken("fresh", now, "new-hash")
+	if err != nil || id != user.ID {
+		t.Fatalf("SetPasswordWithToken() = %d, %v, want %d", id, err, user.ID)
+	}
+	if got := loadUser(t, db, user.ID); got.Password != "new-hash" {
+		t.Errorf("password = %q, want new-hash", got.Password)
+	}
+	var tokens int64
+	db.Model(&models.Token{}).Where("user_id = ?", user.ID).Count(&tokens)
+	if tokens != 0 {
+		t.Errorf("%d refresh tokens remain, want 0", tokens)
+	}
+	if _, err := repo.SetPasswordWithToken("fresh", now, "again"); err != gorm.ErrRecordNotFound {
+		t.Errorf("reusing a token error = %v, want ErrRecordNotFound", err)
+	}
+
+	if deleted, err := repo.DeleteExpiredTokens(now); err != nil || deleted != 1 {
+		t.Errorf("DeleteExpiredTokens() = %d, %v, want the stale password token", deleted, err)
+	}
+}
+
+func TestFollow(t *testing.T) {
+	repo, db := newTestRepository(t)
+	alice := testutil.CreateUser(t, db)
+	bob := testutil.CreateUser(t, db)
+
+	tests := []struct {
+		name          string
+		followerID    uint
+		followingID   uint
+		wantErr       error
+		wantRows      int64
+		wantFollowing int64
+		wantFollowers int64
+	}{
+		{name: "missing follower", followerID: alice.ID + 100, followingID: bob.ID, wantErr: gorm.ErrRecordNotFound},
+		{name: "missing followed user", followerID: alice.ID, followingID: bob.ID + 100, wantErr: gorm.ErrRecordNotFound},
+		{name: "first follow", followerID: alice.ID, followingID: bob.ID, wantRows: 1, wantFollowing: 1, wantFollowers: 1},
+		{name: "repeated follow is idempotent", followerID: alice.ID, followingID: bob.ID, wantRows: 1, wantFollowing: 1, wantFollowers: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := repo.Follow(tt.followerID, tt.followingID); err != tt.wantErr {
+				t.Fatalf("Follow() error = %v, want %v", err, tt.wantErr)
+			}
+			if got := followRows(t, db); got != tt.wantRows {
+				t.Errorf("user_follows rows = %d, want %d", got, tt.wantRows)
+			}
+			if got := loadUser(t, db, alice.ID).FollowingCount; got != tt.wantFollowing {
+				t.Errorf("following_count = %d, want %d", got, tt.wantFollowing)
+			}
+			if got := loadUser(t, db, bob.ID).FollowersCount; got != tt.wantFollowers {
+				t.Errorf("followers_count = %d, want %d", got, tt.wantFollowers)
+			}
+		})
+	}
+}
+
+func TestUnfollow(t *testing.T) {
+	repo, db := newTestRepository(t)
+	alice := testutil.CreateUser(t, db)
+	bob := testutil.CreateUser(t, db)
+	testutil.CreateFollow(t, db, alice.ID, bob.ID)
+
+	tests := []struct {
+		name        string
+		followerID  uint
+		followingID uint
+		wantErr     error
+	}{
+		{name: "missing follower", followerID: alice.ID + 100, followingID: bob.ID, wantErr: gorm.ErrRecordNotFound},
+		{name: "existing follow", followerID: alice.ID, followingID: bob.ID},
+		{name: "repeated unfollow is a no-op", followerID: alice.ID, followingID: bob.ID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := repo.Unfollow(tt.followerID, tt.followingID); err != tt.wantErr {
+				t.Fatalf("Unfollow() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+	if got := followRows(t, db); got != 0 {
+		t.Errorf("user_follows rows = %d, want 0", got)
+	}
+	if a, b := loadUser(t, db, alice.ID), loadUser(t, db, bob.ID); a.FollowingCount != 0 || b.FollowersCount != 0 {
+		t.Errorf("counters following=%d followers=%d, want 0 and 0", a.FollowingCount, b.FollowersCount)
+	}
+}
+
+func TestFollowConcurrentDoubleFollow(t *testing.T) {
+	repo, db := newTestRepository(t)
+	alice := testutil.CreateUser(t, db)
+	bob := testutil.CreateUser(t, db)
+
+	const attempts = 20
+	var wg sync.WaitGroup
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := repo.Follow(alice.ID, bob.ID); err != nil {
+				t.Errorf("concurrent Follow() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := followRows(t, db); got != 1 {
+		t.Errorf("user_follows rows = %d, want 1", got)
+	}
+	if got := loadUser(t, db, alice.ID).FollowingCount; got != 1 {
+		t.Errorf("following_count = %d, want 1", got)
+	}
+	if got := loadUser(t, db, bob.ID).FollowersCount; got != 1 {
+		t.Errorf("followers_count = %d, want 1", got)
+	}
+}
+
+func TestFollowConcurrentFollowAndUnfollow(t *testing.T) {
+	repo, db := newTestRepository(t)
+	alice := testutil.CreateUser(t, db)
+	bob := testutil.CreateUser(t, db)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if err := repo.Follow(alice.ID, bob.ID); err != nil {
+				t.Errorf("Follow() error = %v", err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if err := repo.Unfollow(alice.ID, bob.ID); err != nil {
+				t.Errorf("Unfollow() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Whichever order the calls landed in, the counters must match the rows
+	rows := followRows(t, db)
+	if got := loadUser(t, db, alice.ID).FollowingCount; got != rows {
+		t.Errorf("following_count = %d, want %d", got, rows)
+	}
+	if got := loadUser(t, db, bob.ID).FollowersCount; got != rows {
+		t.Errorf("followers_count = %d, want %d", got, rows)
+	}
+}
+
+func TestFollowQueries(t *testing.T) {
+	repo, db := newTestRepository(t)
+	alice := testutil.CreateUser(t, db)
+	bob := testutil.CreateUser(t, db)
+	carol := testutil.CreateUser(t, db)
+	testutil.CreateFollow(t, db, alice.ID, bob.ID)
+	testutil.CreateFollow(t, db, carol.ID, alice.ID)
+
+	t.Run("CheckFollowExists", func(t *testing.T) {
+		if exists, err := repo.CheckFollowExists(alice.ID, bob.ID); err != nil || !exists {
+			t.Errorf("CheckFollowExists(alice, bob) = %v, %v, want true", exists, err)
+		}
+		if exists, err := repo.CheckFollowExists(bob.ID, alice.ID); err != nil || exists {
+			t.Errorf("CheckFollowExists(bob, alice) = %v, %v, want false", exists, err)
+		}
+	})
+
+	t.Run("GetFollowRelations", func(t *testing.T) {
+		following, followedBy, err := repo.GetFollowRelations(alice.ID, []uint{bob.ID, carol.ID})
+		if err != nil {
+			t.Fatalf("GetFollowRelations() error = %v", err)
+		}
+		if !following[bob.ID] || following[carol.ID] {
+			t.Errorf("following = %v, want only bob", following)
+		}
+		if !followedBy[carol.ID] || followedBy[bob.ID] {
+			t.Errorf("followedBy = %v, want only carol", followedBy)
+		}
+	})
+
+	t.Run("GetFollowers and GetFollowing", func(t *testing.T) {
+		followers, err := repo.GetFollowers(alice.ID)
+		if err != nil || !slices.Equal(userIDs(followers), []uint{carol.ID}) {
+			t.Errorf("GetFollowers() = %v, %v, want [%d]", userIDs(followers), err, carol.ID)
+		}
+		following, err := repo.GetFollowing(alice.ID)
+		if err != nil || !slices.Equal(userIDs(following), []uint{bob.ID}) {
+			t.Errorf("GetFollowing() = %v, %v, want [%d]", userIDs(following), err, bob.ID)
+		}
+		missing, err := repo.GetFollowers(alice.ID + 100)
+		if err != nil || len(missing) != 0 {
+			t.Errorf("GetFollowers(missing) = %v, %v, want empty", missing, err)
+		}
+	})
+
+	t.Run("counts", func(t *testing.T) {
+		if count, err := repo.GetFollowersCount(bob.ID); err != nil || count != 1 {
+			t.Errorf("GetFollowersCount() = %d, %v, want 1", count, err)
+		}
+		if count, err := repo.GetFollowingCount(carol.ID); err != nil || count != 1 {
+			t.Errorf("GetFollowingCount() = %d, %v, want 1", count, err)
+		}
+	})
+}
+
+func TestGetFollowingPosts(t *testing.T) {
+	repo, db := newTestRepository(t)
+	reader := testutil.CreateUser(t, db)
+	followed := testutil.CreateUser(t, db)
+	stranger := testutil.CreateUser(t, db)
+	testutil.CreateFollow(t, db, reader.ID, followed.ID)
+
+	base := time.Now().Add(-time.Hour)
+	var newestFirst []uint
+	for i := 0; i < 4; i++ {
+		post := testutil.CreatePost(t, db, followed.ID, func(p *models.Post) { p.CreatedAt = base.Add(time.Duration(i) * time.Minute) })
+		newestFirst = append([]uint{post.ID}, newestFirst...)
+	}
+	testutil.CreatePost(t, db, followed.ID, func(p *models.Post) { p.Hidden = true })
+	testutil.CreatePost(t, db, stranger.ID)
+
+	tests := []struct {
+		name  string
+		page  int
+		limit int
+		want  []uint
+	}{
+		{name: "first page", page: 1, limit: 3, want: newestFirst[0:3]},
+		{name: "second page", page: 2, limit: 3, want: newestFirst[3:4]},
+		{name: "past the end", page: 3, limit: 3, want: []uint{}},
+		{name: "bounds are clamped", page: 0, limit: 0, want: newestFirst},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			posts, total, err := repo.GetFollowingPosts(reader.ID, tt.page, tt.limit)
+			if err != nil {
+				t.Fatalf("GetFollowingPosts() error = %v", err)
+			}
+			if total != 4 {
+				t.Errorf("total = %d, want 4 (visible posts by followed users only)", total)
+			}
+			got := make([]uint, 0, len(posts))
+			for _, post := range posts {
+				got = append(got, post.ID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("GetFollowingPosts(%d, %d) = %v, want %v", tt.page, tt.limit, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeleteByID(t *testing.T) {
+	repo, db := newTestRepository(t)
+	doomed := testutil.CreateUser(t, db)
+	friend := testutil.CreateUser(t, db)
+	post := testutil.CreatePost(t, db, friend.ID)
+	ownPost := testutil.CreatePost(t, db, doomed.ID)
+	testutil.CreateFollow(t, db, doomed.ID, friend.ID)
+	testutil.CreateFollow(t, db, friend.ID, doomed.ID)
+	testutil.CreateLike(t, db, post.ID, doomed.ID)
+	testutil.CreateLike(t, db, ownPost.ID, friend.ID)
+	repo.CreateToken(&models.Token{Token: "session", UserID: doomed.ID})
+	db.Create([]models.Notification{
+		{UserID: doomed.ID, Type: models.NotificationPostPublished, PostID: post.ID, ActorID: friend.ID},
+		{UserID: friend.ID, Type: models.NotificationPostPublished, PostID: ownPost.ID, ActorID: doomed.ID},
+	})
+
+	if err := repo.DeleteByID(doomed.ID); err != nil {
+		t.Fatalf("DeleteByID() error = %v", err)
+	}
+
+	got := loadUser(t, db, friend.ID)
+	if got.FollowersCount != 0 || got.FollowingCount != 0 {
+		t.Errorf("friend counters followers=%d following=%d, want 0 and 0", got.FollowersCount, got.FollowingCount)
+	}
+	var remaining models.Post
+	db.First(&remaining, post.ID)
+	if remaining.LikesCount != 0 {
+		t.Errorf("likes_count on friend's post = %d, want 0", remaining.LikesCount)
+	}
+	for table, want := range map[string]int64{"users": 1, "posts": 1, "post_likes": 0, "user_follows": 0, "tokens": 0, "notifications": 0} {
+		var count int64
+		db.Table(table).Count(&count)
+		if count != want {
+			t.Errorf("%s has %d rows, want %d", table, count, want)
+		}
+	}
+	if err := repo.DeleteByID(doomed.ID); err != gorm.ErrRecordNotFound {
+		t.Errorf("second DeleteByID() error = %v, want ErrRecordNotFound", err)
+	}
+}
+
+func TestGetNotifications(t *testing.T) {
+	repo, db := newTestRepository(t)
+	author := testutil.CreateUser(t, db)
+	follower := testutil.CreateUser(t, db)
+	shown := testutil.CreatePost(t, db, author.ID)
+	hidden := testutil.CreatePost(t, db, author.ID, func(p *models.Post) { p.Hidden = true })
+	for _, post := range []*models.Post{shown, hidden} {
+		db.Create(&models.Notification{UserID: follower.ID, Type: models.NotificationPostPublished, PostID: post.ID, ActorID: author.ID})
+	}
+
+	notifications, total, err := repo.GetNotifications(follower.ID, 1, 10)
+	if err != nil || total != 1 || len(notifications) != 1 || notifications[0].PostID != shown.ID {
+		t.Errorf("GetNotifications() = %+v of %d, %v, want only post %d", notifications, total, err, shown.ID)
+	}
+}
+
+func TestAdminQueries(t *testing.T) {
+	repo, db := newTestRepository(t)
+	admin := testutil.CreateUser(t, db, func(u *models.User) { u.Role = "admin" })
+	google := testutil.CreateUser(t, db, func(u *models.User) { u.Provider = "google" })
+	local := testutil.CreateUser(t, db)
+	legacy := testutil.CreateUser(t, db)
+	db.Model(&models.User{}).Where("id = ?", legacy.ID).UpdateColumn("provider", "")
+
+	filters := []struct {
+		name   string
+		filter UserFilter
+		want   []uint
+	}{
+		{name: "no filter", filter: UserFilter{}, want: []uint{admin.ID, google.ID, local.ID, legacy.ID}},
+		{name: "by role", filter: UserFilter{Role: "admin"}, want: []uint{admin.ID}},
+		{name: "by provider", filter: UserFilter{Provider: "google"}, want: []uint{google.ID}},
+		{name: "local includes legacy accounts", filter: UserFilter{Provider: "local"}, want: []uint{admin.ID, local.ID, legacy.ID}},
+	}
+	for _, tt := range filters {
+		t.Run(tt.name, func(t *testing.T) {
+			users, err := repo.GetAllWithFilter(tt.filter)
+			if err != nil {
+				t.Fatalf("GetAllWithFilter() error = %v", err)
+			}
+			if got := userIDs(users); !slices.Equal(got, tt.want) {
+				t.Errorf("GetAllWithFilter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("GetExisting", func(t *testing.T) {
+		users, err := repo.GetExisting([]string{google.Email}, []string{local.Username, "nobody"})
+		if err != nil || len(users) != 2 {
+			t.Errorf("GetExisting() = %v, %v, want 2 users", userIDs(users), err)
+		}
+		if none, err := repo.GetExisting(nil, nil); err != nil || len(none) != 0 {
+			t.Errorf("GetExisting(nil, nil) = %v, %v, want empty", none, err)
+		}
+	})
+
+	t.Run("CreateBatch", func(t *testing.T) {
+		batch := []models.User{
+			{Username: "batch1", Email: "batch1@example.com"},
+			{Username: "batch2", Email: "batch2@example.com"},
+		}
+		if err := repo.CreateBatch(batch, nil); err != nil {
+			t.Fatalf("CreateBatch() error = %v", err)
+		}
+		conflicting := []models.User{
+			{Username: "batch3", Email: "batch3@example.com"},
+			{Username: "batch1", Email: "dup@example.com"},
+		}
+		if err := repo.CreateBatch(conflicting, nil); err == nil {
+			t.Fatal("CreateBatch() with a duplicate succeeded, want error")
+		}
+		if _, err := repo.GetByUsername("batch3"); err != gorm.ErrRecordNotFound {
+			t.Errorf("a failed batch must not be partially applied, GetByUsername(batch3) error = %v", err)
+		}
+	})
+
+	t.Run("UpdateRoleByIDs", func(t *testing.T) {
+		affected, err := repo.UpdateRoleByIDs([]uint{local.ID, legacy.ID}, "admin")
+		if err != nil || affected != 2 {
+			t.Errorf("UpdateRoleByIDs() = %d, %v, want 2", affected, err)
+		}
+		if got := loadUser(t, db, local.ID).Role; got != "admin" {
+			t.Errorf("role = %q, want admin", got)
+		}
+	})
+
+	t.Run("SetSuspended", func(t *testing.T) {
+		repo.CreateToken(&models.Token{Token: "google-session", UserID: google.ID})
+		affected, err := repo.SetSuspended([]uint{google.ID}, true)
+		if err != nil || affected != 1 {
+			t.Fatalf("SetSuspended() = %d, %v, want 1", affected, err)
+		}
+		if !loadUser(t, db, google.ID).Suspended {
+			t.Error("user is not suspended")
+		}
+		var tokens int64
+		db.Model(&models.Token{}).Where("user_id = ?", google.ID).Count(&tokens)
+		if tokens != 0 {
+			t.Errorf("suspended user keeps %d tokens, want 0", tokens)
+		}
+		repo.SetSuspended([]uint{google.ID}, false)
+		if loadUser(t, db, google.ID).Suspended {
+			t.Error("user is still suspended")
+		}
+	})
+}
+
+func TestRecountCounters(t *testing.T) {
+	repo, db := newTestRepository(t)
+	alice := testutil.CreateUser(t, db)
+	bob := testutil.CreateUser(t, db)
+	testutil.CreateFollow(t, db, alice.ID, bob.ID)
+	testutil.CreatePost(t, db, bob.ID)
+	// drafts are not counted
+	testutil.CreatePost(t, db, bob.ID, func(p *models.Post) { p.Status = models.PostDraft })
+	db.Model(&models.User{}).Where("1 = 1").UpdateColumns(map[string]any{"followers_count": 7, "following_count": 7, "posts_count": 7})
+
+	if _, err := repo.RecountCounters(); err != nil {
+		t.Fatalf("RecountCounters() error = %v", err)
+	}
+	a, b := loadUser(t, db, alice.ID), loadUser(t, db, bob.ID)
+	if a.FollowingCount != 1 || a.FollowersCount != 0 || a.PostsCount != 0 {
+		t.Errorf("alice counters = %d/%d/%d, want following 1, followers 0, posts 0", a.FollowingCount, a.FollowersCount, a.PostsCount)
+	}
+	if b.FollowingCount != 0 || b.FollowersCount != 1 || b.PostsCount != 1 {
+		t.Errorf("bob counters = %d/%d/%d, want following 0, followers 1, posts 1", b.FollowingCount, b.FollowersCount, b.PostsCount)
+	}
+}
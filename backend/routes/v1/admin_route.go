@@ -1,23 +1,25 @@
 package v1_routes
 
 import (
-	"flower-backend/config"
+	"flower-backend/app"
+	job_controller "flower-backend/controllers/v1/job"
+	admin_post_controller "flower-backend/controllers/v1/post/admin"
+	stats_controller "flower-backend/controllers/v1/stats"
 	admin_user_controller "flower-backend/controllers/v1/user/admin"
-	"flower-backend/database"
-	"flower-backend/log"
 	"flower-backend/middlewares"
 
 	"github.com/gin-gonic/gin"
 )
 
-func AdminRoutes(r *gin.RouterGroup) {
-	cfg := config.LoadConfig()
-	logger := log.InitLog().Sugar()
-	userCtrl := admin_user_controller.NewAdminUserController(database.DB, cfg, logger)
+func AdminRoutes(r *gin.RouterGroup, a *app.App) {
+	userCtrl := admin_user_controller.NewAdminUserController(a)
+	postCtrl := admin_post_controller.NewAdminPostController(a)
+	statsCtrl := stats_controller.NewStatsController(a)
+	jobCtrl := job_controller.NewJobController(a)
 
 	admin := r.Group("/admin")
-	admin.Use(middlewares.Authenticate)
-	admin.Use(middlewares.Authorize([]string{"admin"}))
+	admin.Use(middlewares.Authenticate(a.JWT, a.Users, a.Logger))
+	admin.Use(middlewares.Authorize(a.DB, a.Logger, []string{"admin"}))
 	{
 
 		//user routes
@@ -28,10 +30,48 @@ func AdminRoutes(r *gin.RouterGroup) {
 			adminUser.GET("/username/:username", userCtrl.GetUserByUsername)
 			adminUser.GET("/all", userCtrl.GetUserAll)
 			adminUser.GET("/id/:id/select", userCtrl.GetUserByIDWithSelect)
+			adminUser.GET("/export", userCtrl.ExportUsers)
+			// Create routes
+			adminUser.POST("/import", userCtrl.ImportUsers)
 			// Update routes
 			adminUser.PUT("/id/:id/select", userCtrl.UpdateUserByIDWithSelect)
+			adminUser.POST("/bulk/role", userCtrl.BulkUpdateUserRole)
+			adminUser.POST("/bulk/suspend", userCtrl.SuspendUsers)
+			adminUser.POST("/bulk/unsuspend", userCtrl.UnsuspendUsers)
 			// Delete routes
 			adminUser.DELETE("/:id", userCtrl.DeleteUserByID)
 		}
+
+		//post routes
+		adminPost := admin.Group("/post")
+		{
+			adminPost.GET("/all", postCtrl.GetPostAll)
+			// Update routes
+			adminPost.PUT("/:id", postCtrl.UpdatePostByID)
+			adminPost.PUT("/:id/owner", postCtrl.TransferPostOwnership)
+			adminPost.POST("/bulk/hide", postCtrl.HidePosts)
+			adminPost.POST("/bulk/unhide", postCtrl.UnhidePosts)
+			// Delete routes
+			adminPost.POST("/bulk/delete", postCtrl.DeletePosts)
+		}
+
+		//stats routes
+		adminStats := admin.Group("/stats")
+		{
+			adminStats.GET("/overview", statsCtrl.GetOverview)
+			adminStats.GET("/timeseries", statsCtrl.GetTimeSeries)
+			adminStats.GET("/top-posts", statsCtrl.GetTopPosts)
+			adminStats.GET("/top-creators", statsCtrl.GetTopCreators)
+			adminStats.GET("/cache", statsCtrl.GetCacheStats)
+		}
+
+		//background job routes
+		adminJobs := admin.Group("/jobs")
+		{
+			adminJobs.GET("", jobCtrl.GetJobs)
+			adminJobs.GET("/:id", jobCtrl.GetJobByID)
+			adminJobs.POST("/:id/retry", jobCtrl.RetryJob)
+			adminJobs.POST("/retry", jobCtrl.RetryDeadJobs)
+		}
 	}
 }
@@ -1,19 +1,15 @@
 package v1_routes
 
 import (
-	"flower-backend/config"
+	"flower-backend/app"
 	auth_controller "flower-backend/controllers/v1/auth"
-	"flower-backend/database"
-	"flower-backend/log"
 	"flower-backend/middlewares"
 
 	"github.com/gin-gonic/gin"
 )
 
-func AuthRoutes(r *gin.RouterGroup) {
-	cfg := config.LoadConfig()
-	logger := log.InitLog().Sugar()
-	authCtrl := auth_controller.NewAuthController(database.DB, cfg, logger)
+func AuthRoutes(r *gin.RouterGroup, a *app.App) {
+	authCtrl := auth_controller.NewAuthController(a)
 
 	auth := r.Group("/auth")
 	{
@@ -21,6 +17,7 @@ func AuthRoutes(r *gin.RouterGroup) {
 		auth.POST("/login", authCtrl.Login)
 		auth.POST("/logout", authCtrl.Logout)
 		auth.POST("/refresh-token", authCtrl.RefreshToken)
+		auth.POST("/set-password", authCtrl.SetPassword)
 
 		// OAuth routes
 		auth.GET("/google", authCtrl.GoogleLogin)
@@ -31,7 +28,7 @@ func AuthRoutes(r *gin.RouterGroup) {
 
 	// Protected auth routes
 	authProtected := r.Group("/auth")
-	authProtected.Use(middlewares.Authenticate)
+	authProtected.Use(middlewares.Authenticate(a.JWT, a.Users, a.Logger))
 	{
 		authProtected.GET("/me", authCtrl.Me)
 	}
@@ -0,0 +1,19 @@
+package v1_routes
+
+import (
+	"flower-backend/app"
+	feed_controller "flower-backend/controllers/v1/feed"
+	"flower-backend/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+func FeedRoutes(r *gin.RouterGroup, a *app.App) {
+	feedCtrl := feed_controller.NewFeedController(a)
+
+	feed := r.Group("/feed")
+	feed.Use(middlewares.Authenticate(a.JWT, a.Users, a.Logger))
+	{
+		feed.GET("", feedCtrl.GetFeed)
+	}
+}
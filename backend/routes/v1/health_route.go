@@ -0,0 +1,17 @@
+package v1_routes
+
+import (
+	"flower-backend/app"
+	health_controller "flower-backend/controllers/v1/health"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HealthRoutes registers the probes load balancers and orchestrators poll.
+func HealthRoutes(r *gin.Engine, a *app.App) {
+	healthCtrl := health_controller.NewHealthController(a)
+
+	r.GET("/livez", healthCtrl.Livez)
+	r.GET("/readyz", healthCtrl.Readyz)
+	r.GET("/version", healthCtrl.Version)
+}
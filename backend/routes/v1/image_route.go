@@ -0,0 +1,21 @@
+package v1_routes
+
+import (
+	"flower-backend/app"
+	image_controller "flower-backend/controllers/v1/image"
+	"flower-backend/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ImageRoutes serves images kept in a private bucket through signed URLs,
+// rate limited apart from the rest of the API. Nothing is registered when
+// storage serves images itself.
+func ImageRoutes(r *gin.RouterGroup, a *app.App) {
+	if a.Images == nil {
+		return
+	}
+	imageCtrl := image_controller.NewImageController(a)
+
+	r.GET("/images/*key", middlewares.ImageRateLimiter(a.Logger, a.Metrics), imageCtrl.GetImage)
+}
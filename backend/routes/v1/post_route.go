@@ -1,38 +1,39 @@
 package v1_routes
 
 import (
-	"flower-backend/config"
+	"flower-backend/app"
 	post_controller "flower-backend/controllers/v1/post"
-	"flower-backend/database"
-	"flower-backend/log"
 	"flower-backend/middlewares"
 
 	"github.com/gin-gonic/gin"
 )
 
-func PostRoutes(r *gin.RouterGroup) {
-	cfg := config.LoadConfig()
-	logger := log.InitLog().Sugar()
-	postCtrl := post_controller.NewPostController(database.DB, cfg, logger)
+func PostRoutes(r *gin.RouterGroup, a *app.App) {
+	postCtrl := post_controller.NewPostController(a)
 
 	post := r.Group("/post")
+	post.Use(middlewares.OptionalAuthenticate(a.JWT)) // Optional auth - sets user_id for viewer flags
+	post.Use(middlewares.HTTPCache(a.Config.HTTPCacheMaxAge))
 	{
-		// Public GET routes (no authentication required)
+		// Public GET routes (optional authentication)
 		post.GET("/:id", postCtrl.GetPostByID)
 		post.GET("/user/:user_id/all", postCtrl.GetPostAllByUserID)
 		post.GET("/all", postCtrl.GetPostAll)
 		post.GET("/search", postCtrl.SearchPosts)
 		post.GET("/pagination", postCtrl.GetPostWithPagination)
+		post.GET("/trending", postCtrl.GetTrendingPosts)
+		post.GET("/popular", postCtrl.GetPopularPosts)
 		post.GET("/:id/likes", postCtrl.GetPostLikes)
 		post.GET("/user/:user_id/liked", postCtrl.GetUserLikedPosts)
 	}
 
 	// Protected routes (authentication required)
 	postAuth := r.Group("/post")
-	postAuth.Use(middlewares.Authenticate)
+	postAuth.Use(middlewares.Authenticate(a.JWT, a.Users, a.Logger))
 	{
 		// Create routes
 		postAuth.POST("", postCtrl.CreatePost)
+		postAuth.POST("/upload/sign", postCtrl.SignImageUpload)
 		// Delete routes
 		postAuth.DELETE("/:id", postCtrl.DeletePostByID)
 		// Update routes
@@ -40,5 +41,15 @@ func PostRoutes(r *gin.RouterGroup) {
 		// Like routes
 		postAuth.POST("/:id/like", postCtrl.LikePost)
 		postAuth.DELETE("/:id/dislike", postCtrl.DislikePost)
+		// Report routes
+		postAuth.POST("/:id/report", postCtrl.ReportPost)
+		// Background upload routes
+		postAuth.GET("/:id/status", postCtrl.GetPostStatus)
+		postAuth.POST("/:id/upload/retry", postCtrl.RetryPostUpload)
+		// Draft routes
+		postAuth.GET("/drafts", postCtrl.GetDrafts)
+		postAuth.GET("/drafts/:id", postCtrl.GetDraft)
+		postAuth.PUT("/drafts/:id", postCtrl.UpdateDraft)
+		postAuth.POST("/drafts/:id/publish", postCtrl.PublishDraft)
 	}
 }
@@ -1,13 +1,14 @@
 package v1_routes
 
 import (
+	"flower-backend/app"
 	"flower-backend/middlewares"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 )
 
-func Routes(r *gin.Engine) {
+func Routes(r *gin.Engine, a *app.App) {
 	// Health check endpoint
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
@@ -16,6 +17,9 @@ func Routes(r *gin.Engine) {
 		})
 	})
 
+	// Liveness, readiness and build info
+	HealthRoutes(r, a)
+
 	// API v1 routes
 	api := r.Group("/api/v1")
 	api.Use(middlewares.ValidationError)
@@ -31,15 +35,21 @@ func Routes(r *gin.Engine) {
 
 		// Auth routes
 		// /api/v1/auth
-		AuthRoutes(api)
+		AuthRoutes(api, a)
 		// User routes
 		// /api/v1/user
-		UserRoutes(api)
+		UserRoutes(api, a)
 		// Admin routes
 		// /api/v1/admin
-		AdminRoutes(api)
+		AdminRoutes(api, a)
 		// Post routes
 		// /api/v1/post
-		PostRoutes(api)
+		PostRoutes(api, a)
+		// Feed routes
+		// /api/v1/feed
+		FeedRoutes(api, a)
+		// Image routes
+		// /api/v1/images
+		ImageRoutes(api, a)
 	}
 }
@@ -0,0 +1,774 @@
+package v1_routes_test
+
+import (
+	"bytes"
+	"errors"
+	"flower-backend/cache"
+	"flower-backend/libs"
+	"flower-backend/models"
+	post_repository "flower-backend/repositories/v1/post"
+	"flower-backend/tasks"
+	"flower-backend/testutil/testserver"
+	"fmt"
+	"image"
+	"image/png"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const password = "Passw0rd!"
+
+func TestRegisterAndLogin(t *testing.T) {
+	srv := testserver.New(t)
+	client := srv.NewClient(t)
+
+	tests := []struct {
+		name       string
+		do         func() *testserver.Response
+		wantStatus int
+	}{
+		{name: "register", do: func() *testserver.Response { return client.Register("lily", "lily@example.com", password) }, wantStatus: http.StatusOK},
+		{name: "register weak password", do: func() *testserver.Response { return client.Register("rose", "rose@example.com", "short") }, wantStatus: http.StatusBadRequest},
+		{name: "login wrong password", do: func() *testserver.Response { return client.Login("lily@example.com", "Wr0ngPass!") }, wantStatus: http.StatusUnauthorized},
+		{name: "login unknown email", do: func() *testserver.Response { return client.Login("nobody@example.com", password) }, wantStatus: http.StatusUnauthorized},
+		{name: "login", do: func() *testserver.Response { return client.Login("lily@example.com", password) }, wantStatus: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if resp := tt.do(); resp.StatusCode != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", resp.StatusCode, tt.wantStatus, resp.Body)
+			}
+		})
+	}
+
+	if client.Cookie("refreshToken") == "" {
+		t.Error("login did not set the refreshToken cookie")
+	}
+	me := client.Get("/api/v1/auth/me")
+	if me.StatusCode != http.StatusOK {
+		t.Fatalf("GET /auth/me status = %d: %s", me.StatusCode, me.Body)
+	}
+	if user := me.JSON(t)["user"].(map[string]any); user["email"] != "lily@example.com" {
+		t.Errorf("GET /auth/me user = %v, want lily", user)
+	}
+
+	// Access tokens expire on the injected clock
+	srv.Clock.Advance(srv.Config.JWTExpiry + time.Minute)
+	if resp := client.Get("/api/v1/auth/me"); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("GET /auth/me with expired token status = %d, want 401", resp.StatusCode)
+	}
+}
+
+func TestSuspendedUserLosesAccess(t *testing.T) {
+	srv := testserver.New(t)
+	lily := srv.NewClient(t)
+	lily.MustRegister("lily", "lily@example.com", password)
+
+	if err := srv.DB.Model(&models.User{}).Where("id = ?", lily.UserID).Update("suspended", true).Error; err != nil {
+		t.Fatalf("suspend user: %v", err)
+	}
+
+	// The access token is still valid, but the account no longer is
+	if resp := lily.Get("/api/v1/auth/me"); resp.StatusCode != http.StatusForbidden {
+		t.Errorf("GET /auth/me while suspended status = %d, want 403", resp.StatusCode)
+	}
+	if resp := lily.Get("/api/v1/post/drafts"); resp.StatusCode != http.StatusForbidden {
+		t.Errorf("GET /post/drafts while suspended status = %d, want 403", resp.StatusCode)
+	}
+}
+
+func TestAdminSuspensionPassesThroughCache(t *testing.T) {
+	srv := testserver.New(t, testserver.WithCache(cache.NewMemoryCache(100)))
+	admin := srv.NewClient(t)
+	admin.MustRegister("admin", "admin@example.com", password)
+	lily := srv.NewClient(t)
+	lily.MustRegister("lily", "lily@example.com", password)
+
+	// Authenticate caches lily's account on this request
+	if resp := lily.Get("/api/v1/auth/me"); resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET /auth/me status = %d: %s", resp.StatusCode, resp.Body)
+	}
+	suspend := admin.PostJSON("/api/v1/admin/user/bulk/suspend", map[string]any{"ids": []uint{lily.UserID}})
+	if suspend.StatusCode != http.StatusOK {
+		t.Fatalf("suspend status = %d: %s", suspend.StatusCode, suspend.Body)
+	}
+	if resp := lily.Get("/api/v1/auth/me"); resp.StatusCode != http.StatusForbidden {
+		t.Errorf("GET /auth/me after suspension status = %d, want 403", resp.StatusCode)
+	}
+}
+
+func TestCSRFRequired(t *testing.T) {
+	srv := testserver.New(t)
+
+	resp, err := http.Post(srv.URL+"/api/v1/auth/register", "application/json",
+		strings.NewReader(`{"username":"lily","email":"lily@example.com","password":"Passw0rd!"}`))
+	if err != nil {
+		t.Fatalf("POST /auth/register: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("register without CSRF token status = %d, want 403", resp.StatusCode)
+	}
+}
+
+func TestPostLikeFollowFeed(t *testing.T) {
+	srv := testserver.New(t)
+	alice := srv.NewClient(t)
+	alice.MustRegister("alice", "alice@example.com", password)
+	bob := srv.NewClient(t)
+	bob.MustRegister("bob", "bob@example.com", password)
+
+	// bob posts a photo; the image lands in the fake storage
+	created := bob.PostForm("/api/v1/post",
+		map[string]string{"title": "Tulips", "content": "Spring is here", "tags": "tulips,spring"},
+		map[string][]byte{"image": []byte("\x89PNG fake image")})
+	if created.StatusCode != http.StatusOK {
+		t.Fatalf("create post status = %d: %s", created.StatusCode, created.Body)
+	}
+	var body struct {
+		Post models.Post `json:"post"`
+	}
+	created.Decode(t, &body)
+	post := body.Post
+	if !strings.HasPrefix(post.ImageURL, testserver.StorageURL) || srv.Storage.Len() != 1 {
+		t.Errorf("image_url = %q with %d stored objects, want one upload to fake storage", post.ImageURL, srv.Storage.Len())
+	}
+
+	// alice likes it once; a second like is rejected
+	likePath := fmt.Sprintf("/api/v1/post/%d/like", post.ID)
+	if resp := alice.PostJSON(likePath, nil); resp.StatusCode != http.StatusOK {
+		t.Fatalf("like status = %d: %s", resp.StatusCode, resp.Body)
+	}
+	if resp := alice.PostJSON(likePath, nil); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("second like status = %d, want 400", resp.StatusCode)
+	}
+	if resp := (srv.NewClient(t)).PostJSON(likePath, nil); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("anonymous like status = %d, want 401", resp.StatusCode)
+	}
+	var likes struct {
+		Likes int64 `json:"likes"`
+	}
+	alice.Get(fmt.Sprintf("/api/v1/post/%d/likes", post.ID)).Decode(t, &likes)
+	if likes.Likes != 1 {
+		t.Errorf("likes = %d, want 1", likes.Likes)
+	}
+
+	// alice follows bob; self-follows are refused
+	followPath := fmt.Sprintf("/api/v1/user/follow/%d/%d", alice.UserID, bob.UserID)
+	if resp := alice.PostJSON(followPath, nil); resp.StatusCode != http.StatusOK {
+		t.Fatalf("follow status = %d: %s", resp.StatusCode, resp.Body)
+	}
+	selfPath := fmt.Sprintf("/api/v1/user/follow/%d/%d", alice.UserID, alice.UserID)
+	if resp := alice.PostJSON(selfPath, nil); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("self follow status = %d, want 400", resp.StatusCode)
+	}
+
+	// bob's post shows up in alice's feed, flagged as liked
+	feed := alice.Get("/api/v1/feed")
+	if feed.StatusCode != http.StatusOK {
+		t.Fatalf("feed status = %d: %s", feed.StatusCode, feed.Body)
+	}
+	var page struct {
+		Posts []map[string]any `json:"posts"`
+	}
+	feed.Decode(t, &page)
+	if len(page.Posts) != 1 || uint(page.Posts[0]["id"].(float64)) != post.ID {
+		t.Fatalf("feed = %v, want bob's post %d", page.Posts, post.ID)
+	}
+	if page.Posts[0]["liked_by_me"] != true {
+		t.Errorf("feed post liked_by_me = %v, want true", page.Posts[0]["liked_by_me"])
+	}
+
+	following := alice.Get(fmt.Sprintf("/api/v1/user/following-posts/%d?page=1&limit=10", alice.UserID))
+	if following.StatusCode != http.StatusOK {
+		t.Fatalf("following posts status = %d: %s", following.StatusCode, following.Body)
+	}
+	if total := following.JSON(t)["total"]; total != float64(1) {
+		t.Errorf("following posts total = %v, want 1", total)
+	}
+}
+
+func TestPostRevalidatesAfterLike(t *testing.T) {
+	srv := testserver.New(t)
+	bob := srv.NewClient(t)
+	bob.MustRegister("bob", "bob@example.com", password)
+	created := bob.PostForm("/api/v1/post",
+		map[string]string{"title": "Tulips", "content": "Spring is here"},
+		map[string][]byte{"image": []byte("\x89PNG fake image")})
+	var body struct {
+		Post models.Post `json:"post"`
+	}
+	created.Decode(t, &body)
+	postPath := fmt.Sprintf("/api/v1/post/%d", body.Post.ID)
+
+	reader := srv.NewClient(t)
+	first := reader.Get(postPath)
+	if first.StatusCode != http.StatusOK || first.Header.Get("Last-Modified") != "" {
+		t.Fatalf("GET post = %d with Last-Modified %q, want 200 without", first.StatusCode, first.Header.Get("Last-Modified"))
+	}
+	if resp := bob.PostJSON(postPath+"/like", nil); resp.StatusCode != http.StatusOK {
+		t.Fatalf("like status = %d: %s", resp.StatusCode, resp.Body)
+	}
+
+	// a client revalidating by date alone still sees the new like
+	reader.Header.Set("If-Modified-Since", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	resp := reader.Get(postPath)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("revalidated GET post = %d, want 200", resp.StatusCode)
+	}
+	if likes := resp.JSON(t)["post"].(map[string]any)["likes_count"]; likes != float64(1) {
+		t.Errorf("likes_count = %v, want 1", likes)
+	}
+}
+
+func TestImageUpdatesKeepConcurrentWrites(t *testing.T) {
+	srv := testserver.New(t)
+	bob := srv.NewClient(t)
+	bob.MustRegister("bob", "bob@example.com", password)
+	created := bob.PostForm("/api/v1/post",
+		map[string]string{"title": "Tulips", "content": "Spring is here"},
+		map[string][]byte{"image": []byte("\x89PNG fake image")})
+	var body struct {
+		Post models.Post `json:"post"`
+	}
+	created.Decode(t, &body)
+	post := body.Post
+
+	// A like and a follow land while each new image is uploading
+	srv.Storage.OnUpload(func() {
+		srv.DB.Model(&models.Post{}).Where("id = ?", post.ID).UpdateColumn("likes_count", gorm.Expr("likes_count + 1"))
+		srv.DB.Model(&models.User{}).Where("id = ?", bob.UserID).UpdateColumn("followers_count", gorm.Expr("followers_count + 1"))
+	})
+	resp := bob.PutForm(fmt.Sprintf("/api/v1/post/%d?select=image_url", post.ID), nil, map[string][]byte{"image": []byte("\x89PNG new image")})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("update post image status = %d: %s", resp.StatusCode, resp.Body)
+	}
+	resp = bob.PutForm(fmt.Sprintf("/api/v1/user/id/%d/select?select=avatar", bob.UserID), nil, map[string][]byte{"avatar": []byte("\x89PNG avatar")})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("update avatar status = %d: %s", resp.StatusCode, resp.Body)
+	}
+
+	var stored models.Post
+	srv.DB.First(&stored, post.ID)
+	if stored.LikesCount != 2 {
+		t.Errorf("post likes_count = %d, want 2", stored.LikesCount)
+	}
+	var user models.User
+	srv.DB.First(&user, bob.UserID)
+	if user.FollowersCount != 2 || user.PostsCount != 1 {
+		t.Errorf("user followers_count = %d posts_count = %d, want 2 and 1", user.FollowersCount, user.PostsCount)
+	}
+}
+
+func TestCachedRoutesDropNoCacheHeaders(t *testing.T) {
+	srv := testserver.New(t)
+	bob := srv.NewClient(t)
+	bob.MustRegister("bob", "bob@example.com", password)
+
+	listing := srv.NewClient(t).Get("/api/v1/post/all")
+	if listing.StatusCode != http.StatusOK {
+		t.Fatalf("GET /api/v1/post/all = %d: %s", listing.StatusCode, listing.Body)
+	}
+	if got := listing.Header.Get("Cache-Control"); !strings.HasPrefix(got, "public") {
+		t.Errorf("listing Cache-Control = %q, want public", got)
+	}
+	if listing.Header.Get("Pragma") != "" || listing.Header.Get("Expires") != "" {
+		t.Errorf("listing has Pragma %q and Expires %q, want neither", listing.Header.Get("Pragma"), listing.Header.Get("Expires"))
+	}
+
+	// routes HTTPCache does not handle keep them
+	notifications := bob.Get("/api/v1/user/notifications")
+	if notifications.Header.Get("Pragma") != "no-cache" || notifications.Header.Get("Expires") != "0" {
+		t.Errorf("notifications have Pragma %q and Expires %q, want no-cache and 0", notifications.Header.Get("Pragma"), notifications.Header.Get("Expires"))
+	}
+}
+
+func TestDeletedPostImageRemovedByJob(t *testing.T) {
+	srv := testserver.New(t)
+	bob := srv.NewClient(t)
+	bob.MustRegister("bob", "bob@example.com", password)
+
+	created := bob.PostForm("/api/v1/post",
+		map[string]string{"title": "Tulips", "content": "Spring is here"},
+		map[string][]byte{"image": []byte("\x89PNG fake image")})
+	var body struct {
+		Post models.Post `json:"post"`
+	}
+	created.Decode(t, &body)
+
+	if resp := bob.Delete(fmt.Sprintf("/api/v1/post/%d", body.Post.ID)); resp.StatusCode != http.StatusOK {
+		t.Fatalf("delete post status = %d: %s", resp.StatusCode, resp.Body)
+	}
+	// the delete only queues the image removal
+	if srv.Storage.Len() != 1 {
+		t.Fatalf("image removed inline; %d stored objects", srv.Storage.Len())
+	}
+
+	srv.Jobs.Start()
+	t.Cleanup(func() { srv.Jobs.Shutdown(t.Context()) })
+	deadline := time.Now().Add(5 * time.Second)
+	for srv.Storage.Len() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("image still stored 5s after the post was deleted")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestAsyncPostUploadFailsAndRetries(t *testing.T) {
+	srv := testserver.New(t)
+	bob := srv.NewClient(t)
+	bob.MustRegister("bob", "bob@example.com", password)
+	bob.Header.Set("Prefer", "respond-async")
+	srv.Storage.FailUploads(errors.New("storage unavailable"))
+
+	created := bob.PostForm("/api/v1/post",
+		map[string]string{"title": "Tulips", "content": "Spring is here"},
+		map[string][]byte{"image": []byte("\x89PNG fake image")})
+	var body struct {
+		Post models.Post `json:"post"`
+	}
+	created.Decode(t, &body)
+	statusURL := fmt.Sprintf("/api/v1/post/%d/status", body.Post.ID)
+	if created.StatusCode != http.StatusAccepted || body.Post.Status != models.PostProcessing || created.Header.Get("Location") != statusURL {
+		t.Fatalf("async create = %d %s: %s", created.StatusCode, created.Header.Get("Location"), created.Body)
+	}
+	// nobody sees the post until its image is stored, not even through its status
+	if resp := srv.NewClient(t).Get(fmt.Sprintf("/api/v1/post/%d", body.Post.ID)); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("processing post status = %d, want 404", resp.StatusCode)
+	}
+	lily := srv.NewClient(t)
+	lily.MustRegister("lily", "lily@example.com", password)
+	if resp := lily.Get(statusURL); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("status for another user = %d, want 404", resp.StatusCode)
+	}
+
+	srv.Jobs.Start()
+	t.Cleanup(func() { srv.Jobs.Shutdown(t.Context()) })
+
+	var status struct {
+		Post struct {
+			Status   string `json:"status"`
+			ImageURL string `json:"image_url"`
+			Error    string `json:"error"`
+		} `json:"post"`
+	}
+	waitForStatus := func(want string) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			var post models.Post
+			srv.DB.First(&post, body.Post.ID)
+			if post.Status == want {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("post is %q after 5s, want %q", post.Status, want)
+			}
+			// step the clock to the pending retry rather than waiting out its backoff
+			var job models.Job
+			if srv.DB.Where("type = ? AND status = ?", "post.upload_image", models.JobQueued).First(&job).Error == nil {
+				if wait := job.RunAt.Sub(srv.Clock.Now()); wait > 0 {
+					srv.Clock.Advance(wait)
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		resp := bob.Get(statusURL)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("GET %s = %d: %s", statusURL, resp.StatusCode, resp.Body)
+		}
+		resp.Decode(t, &status)
+	}
+	waitForStatus(models.PostFailed)
+	if !strings.Contains(status.Post.Error, "storage unavailable") {
+		t.Errorf("upload error = %q", status.Post.Error)
+	}
+
+	// the retry reuses the image sent with the post
+	srv.Storage.FailUploads(nil)
+	if resp := bob.PostForm(fmt.Sprintf("/api/v1/post/%d/upload/retry", body.Post.ID), nil, nil); resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("retry status = %d: %s", resp.StatusCode, resp.Body)
+	}
+	waitForStatus(models.PostPublished)
+	if !strings.HasPrefix(status.Post.ImageURL, testserver.StorageURL) || srv.Storage.Len() != 1 {
+		t.Errorf("published image = %q with %d stored objects", status.Post.ImageURL, srv.Storage.Len())
+	}
+	if resp := srv.NewClient(t).Get(fmt.Sprintf("/api/v1/post/%d", body.Post.ID)); resp.StatusCode != http.StatusOK {
+		t.Errorf("published post status = %d, want 200", resp.StatusCode)
+	}
+	if resp := bob.PostForm(fmt.Sprintf("/api/v1/post/%d/upload/retry", body.Post.ID), nil, nil); resp.StatusCode != http.StatusConflict {
+		t.Errorf("retry of a published post = %d, want 409", resp.StatusCode)
+	}
+}
+
+func TestAsyncPostUploadStoresRenditions(t *testing.T) {
+	srv := testserver.New(t)
+	bob := srv.NewClient(t)
+	bob.MustRegister("bob", "bob@example.com", password)
+	bob.Header.Set("Prefer", "respond-async")
+
+	var photo bytes.Buffer
+	if err := png.Encode(&photo, image.NewGray(image.Rect(0, 0, 800, 400))); err != nil {
+		t.Fatal(err)
+	}
+	created := bob.PostForm("/api/v1/post",
+		map[string]string{"title": "Tulips", "content": "Spring is here"},
+		map[string][]byte{"image": photo.Bytes()})
+	var body struct {
+		Post models.Post `json:"post"`
+	}
+	created.Decode(t, &body)
+	if created.StatusCode != http.StatusAccepted {
+		t.Fatalf("async create = %d: %s", created.StatusCode, created.Body)
+	}
+
+	srv.Jobs.Start()
+	t.Cleanup(func() { srv.Jobs.Shutdown(t.Context()) })
+	var post models.Post
+	deadline := time.Now().Add(5 * time.Second)
+	for post.Status != models.PostPublished {
+		if time.Now().After(deadline) {
+			t.Fatalf("post is %q after 5s, want published", post.Status)
+		}
+		time.Sleep(10 * time.Millisecond)
+		srv.DB.First(&post, body.Post.ID)
+	}
+
+	var status struct {
+		Post struct {
+			ImageURL     string `json:"image_url"`
+			ThumbnailURL string `json:"thumbnail_url"`
+			MediumURL    string `json:"medium_url"`
+		} `json:"post"`
+	}
+	bob.Get(fmt.Sprintf("/api/v1/post/%d/status", post.ID)).Decode(t, &status)
+	// the image is narrower than the medium size, so only a thumbnail is made
+	if !strings.Contains(status.Post.ThumbnailURL, "_thumb") || status.Post.MediumURL != status.Post.ImageURL {
+		t.Errorf("status = %+v, want a thumbnail and the original as medium", status.Post)
+	}
+	if srv.Storage.Len() != 2 {
+		t.Errorf("%d stored objects, want the image and its thumbnail", srv.Storage.Len())
+	}
+
+	if resp := bob.Delete(fmt.Sprintf("/api/v1/post/%d", post.ID)); resp.StatusCode != http.StatusOK {
+		t.Fatalf("delete post status = %d: %s", resp.StatusCode, resp.Body)
+	}
+	for srv.Storage.Len() != 0 {
+		if time.Now().After(deadline.Add(5 * time.Second)) {
+			t.Fatalf("%d objects still stored after the post was deleted", srv.Storage.Len())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestCreatePostFromDirectUpload(t *testing.T) {
+	srv := testserver.New(t)
+	bob := srv.NewClient(t)
+	bob.MustRegister("bob", "bob@example.com", password)
+	lily := srv.NewClient(t)
+	lily.MustRegister("lily", "lily@example.com", password)
+
+	var signed struct {
+		AssetID string            `json:"asset_id"`
+		Upload  libs.SignedUpload `json:"upload"`
+	}
+	resp := bob.PostJSON("/api/v1/post/upload/sign", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("sign status = %d: %s", resp.StatusCode, resp.Body)
+	}
+	resp.Decode(t, &signed)
+	if signed.AssetID == "" || signed.Upload.URL == "" || !signed.Upload.ExpiresAt.After(time.Now()) {
+		t.Fatalf("signed upload = %+v", signed)
+	}
+
+	fields := map[string]string{"title": "Peonies", "content": "Straight from the garden", "asset_id": signed.AssetID}
+	createPath := "/api/v1/post"
+	if resp := bob.PostForm(createPath, fields, nil); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("create before the upload = %d, want 400", resp.StatusCode)
+	}
+
+	// a page passed off as the image is refused and removed
+	if _, err := srv.Storage.Upload(t.Context(), []byte("<html><script>alert(1)</script></html>"), signed.AssetID); err != nil {
+		t.Fatalf("upload: %v", err)
+	}
+	if resp := bob.PostForm(createPath, fields, nil); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("create from an HTML upload = %d, want 400", resp.StatusCode)
+	}
+	if srv.Storage.Len() != 0 {
+		t.Errorf("%d stored objects after the HTML upload was refused, want 0", srv.Storage.Len())
+	}
+
+	// the client sends the image to storage itself
+	if _, err := srv.Storage.Upload(t.Context(), []byte("\x89PNG\r\n\x1a\n fake image"), signed.AssetID); err != nil {
+		t.Fatalf("upload: %v", err)
+	}
+	if resp := lily.PostForm(createPath, fields, nil); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("create from another user's asset = %d, want 400", resp.StatusCode)
+	}
+
+	created := bob.PostForm(createPath, fields, nil)
+	if created.StatusCode != http.StatusOK {
+		t.Fatalf("create status = %d: %s", created.StatusCode, created.Body)
+	}
+	var body struct {
+		Post models.Post `json:"post"`
+	}
+	created.Decode(t, &body)
+	if want := testserver.StorageURL + signed.AssetID + ".png"; body.Post.ImageURL != want || body.Post.Status != models.PostPublished {
+		t.Errorf("post image = %q status = %q, want %q published", body.Post.ImageURL, body.Post.Status, want)
+	}
+	if resp := bob.PostForm(createPath, fields, nil); resp.StatusCode != http.StatusConflict {
+		t.Errorf("second post from the same asset = %d, want 409", resp.StatusCode)
+	}
+}
+
+func TestDraftsAndScheduledPublishing(t *testing.T) {
+	srv := testserver.New(t)
+	bob := srv.NewClient(t)
+	bob.MustRegister("bob", "bob@example.com", password)
+	alice := srv.NewClient(t)
+	alice.MustRegister("alice", "alice@example.com", password)
+	if resp := alice.PostJSON(fmt.Sprintf("/api/v1/user/follow/%d/%d", alice.UserID, bob.UserID), nil); resp.StatusCode != http.StatusOK {
+		t.Fatalf("follow status = %d: %s", resp.StatusCode, resp.Body)
+	}
+
+	createDraft := func(title string) uint {
+		t.Helper()
+		created := bob.PostForm("/api/v1/post",
+			map[string]string{"title": title, "content": "Not ready yet", "status": "draft"},
+			map[string][]byte{"image": []byte("\x89PNG fake image")})
+		var body struct {
+			Post models.Post `json:"post"`
+		}
+		created.Decode(t, &body)
+		if created.StatusCode != http.StatusOK || body.Post.Status != models.PostDraft {
+			t.Fatalf("create draft = %d: %s", created.StatusCode, created.Body)
+		}
+		return body.Post.ID
+	}
+	scheduled, published := createDraft("Tulips"), createDraft("Roses")
+
+	// drafts are hidden from everyone but their author
+	if resp := srv.NewClient(t).Get(fmt.Sprintf("/api/v1/post/%d", scheduled)); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("draft post status = %d, want 404", resp.StatusCode)
+	}
+	if resp := alice.Get(fmt.Sprintf("/api/v1/post/drafts/%d", scheduled)); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("another user's draft = %d, want 404", resp.StatusCode)
+	}
+	if resp := alice.PostJSON(fmt.Sprintf("/api/v1/post/%d/like", scheduled), nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("like of a draft = %d, want 404", resp.StatusCode)
+	}
+	if total := bob.Get("/api/v1/post/drafts").JSON(t)["total"]; total != float64(2) {
+		t.Errorf("drafts total = %v, want 2", total)
+	}
+
+	// bob edits one draft and schedules it
+	draftPath := fmt.Sprintf("/api/v1/post/drafts/%d", scheduled)
+	edit := func(form url.Values) *testserver.Response {
+		return bob.Do(http.MethodPut, draftPath, strings.NewReader(form.Encode()), "application/x-www-form-urlencoded")
+	}
+	if resp := edit(url.Values{"publish_at": {srv.Clock.Now().Add(-time.Hour).Format(time.RFC3339)}}); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("schedule in the past = %d, want 400", resp.StatusCode)
+	}
+	resp := edit(url.Values{"title": {"Tulips in bloom"}, "publish_at": {srv.Clock.Now().Add(5 * time.Minute).Format(time.RFC3339)}})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("edit draft = %d: %s", resp.StatusCode, resp.Body)
+	}
+	if post := resp.JSON(t)["post"].(map[string]any); post["title"] != "Tulips in bloom" || post["status"] != models.PostScheduled || post["publish_at"] == nil {
+		t.Errorf("edited draft = %v, want retitled and scheduled", post)
+	}
+
+	// the other one bob publishes by hand
+	publishPath := fmt.Sprintf("/api/v1/post/drafts/%d/publish", published)
+	if resp := bob.PostJSON(publishPath, nil); resp.StatusCode != http.StatusOK {
+		t.Fatalf("publish draft = %d: %s", resp.StatusCode, resp.Body)
+	}
+	if resp := bob.PostJSON(publishPath, nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("publish of a published post = %d, want 404", resp.StatusCode)
+	}
+
+	// the scheduler publishes the scheduled post once its time comes
+	publish := tasks.PublishScheduledPosts(post_repository.NewPostRepository(srv.DB, srv.Config, nil, zap.NewNop().Sugar()), srv.Clock.Now)
+	if rows, err := publish(t.Context()); err != nil || rows != 0 {
+		t.Fatalf("publish task before the publish time = %d, %v, want nothing published", rows, err)
+	}
+	// within the access tokens' lifetime
+	srv.Clock.Advance(6 * time.Minute)
+	if rows, err := publish(t.Context()); err != nil || rows != 1 {
+		t.Fatalf("publish task = %d, %v, want 1 post", rows, err)
+	}
+	if resp := srv.NewClient(t).Get(fmt.Sprintf("/api/v1/post/%d", scheduled)); resp.StatusCode != http.StatusOK {
+		t.Errorf("scheduled post after publishing = %d, want 200", resp.StatusCode)
+	}
+
+	// alice hears about both posts
+	srv.Jobs.Start()
+	t.Cleanup(func() { srv.Jobs.Shutdown(t.Context()) })
+	deadline := time.Now().Add(5 * time.Second)
+	for alice.Get("/api/v1/user/notifications").JSON(t)["total"] != float64(2) {
+		if time.Now().After(deadline) {
+			t.Fatal("alice has not been notified of both posts after 5s")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestAdminRetriesDeadJob(t *testing.T) {
+	srv := testserver.New(t)
+	admin := srv.NewClient(t)
+	admin.MustRegister("admin", "admin@example.com", password)
+	user := srv.NewClient(t)
+	user.MustRegister("lily", "lily@example.com", password)
+
+	dead := models.Job{Type: "storage.delete", Payload: `{"public_id":"gone"}`, Status: models.JobDead, Attempts: 5, MaxAttempts: 5, LastError: "timeout"}
+	if err := srv.DB.Create(&dead).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if resp := user.Get("/api/v1/admin/jobs"); resp.StatusCode != http.StatusForbidden {
+		t.Errorf("non-admin list status = %d, want 403", resp.StatusCode)
+	}
+
+	var list struct {
+		Jobs []struct {
+			ID      uint           `json:"id"`
+			Payload map[string]any `json:"payload"`
+		} `json:"jobs"`
+		Total int64 `json:"total"`
+	}
+	listed := admin.Get("/api/v1/admin/jobs?status=dead")
+	listed.Decode(t, &list)
+	if listed.StatusCode != http.StatusOK || list.Total != 1 || list.Jobs[0].Payload["public_id"] != "gone" {
+		t.Fatalf("GET /admin/jobs?status=dead = %d: %s", listed.StatusCode, listed.Body)
+	}
+	if resp := admin.Get("/api/v1/admin/jobs?status=lost"); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("invalid status filter = %d, want 400", resp.StatusCode)
+	}
+
+	retryPath := fmt.Sprintf("/api/v1/admin/jobs/%d/retry", dead.ID)
+	retried := admin.PostJSON(retryPath, nil)
+	if retried.StatusCode != http.StatusOK {
+		t.Fatalf("retry status = %d: %s", retried.StatusCode, retried.Body)
+	}
+	if job := retried.JSON(t)["job"].(map[string]any); job["status"] != models.JobQueued || job["attempts"] != float64(0) {
+		t.Errorf("retried job = %v, want queued with no attempts", job)
+	}
+	if resp := admin.PostJSON(retryPath, nil); resp.StatusCode != http.StatusConflict {
+		t.Errorf("retrying a queued job = %d, want 409", resp.StatusCode)
+	}
+	if resp := admin.PostJSON("/api/v1/admin/jobs/999/retry", nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("retrying a missing job = %d, want 404", resp.StatusCode)
+	}
+	if resp := admin.PostJSON("/api/v1/admin/jobs/retry", nil); resp.JSON(t)["retried"] != float64(0) {
+		t.Errorf("bulk retry with no dead jobs = %s", resp.Body)
+	}
+}
+
+func TestOAuthLogin(t *testing.T) {
+	srv := testserver.New(t)
+	srv.OAuth.AddProfile("good-code", libs.OAuthProfile{
+		ID:        "42",
+		Email:     "fern@example.com",
+		Name:      "fern",
+		AvatarURL: "https://example.com/fern.png",
+		Raw:       `{"id":"42"}`,
+	})
+
+	for _, provider := range []string{"google", "github"} {
+		t.Run(provider, func(t *testing.T) {
+			client := srv.NewClient(t)
+
+			start := client.Get("/api/v1/auth/" + provider)
+			if start.StatusCode != http.StatusTemporaryRedirect {
+				t.Fatalf("login status = %d, want 307", start.StatusCode)
+			}
+			location, _ := url.Parse(start.Header.Get("Location"))
+			state := location.Query().Get("state")
+			if state == "" || client.Cookie("oauth_state") != state {
+				t.Fatalf("redirect %s does not carry the oauth_state cookie", location)
+			}
+
+			bad := client.Get(fmt.Sprintf("/api/v1/auth/%s/callback?state=%s&code=bad-code", provider, url.QueryEscape(state)))
+			if got := bad.Header.Get("Location"); !strings.HasSuffix(got, "error=token_exchange_failed") {
+				t.Errorf("unknown code redirected to %q", got)
+			}
+
+			client.SetCookie("oauth_state", state)
+			done := client.Get(fmt.Sprintf("/api/v1/auth/%s/callback?state=%s&code=good-code", provider, url.QueryEscape(state)))
+			redirect, _ := url.Parse(done.Header.Get("Location"))
+			if redirect.Path != "/auth/callback" || redirect.Query().Get("access_token") == "" {
+				t.Fatalf("callback redirected to %q, want the frontend with tokens", redirect)
+			}
+		})
+	}
+
+	var user models.User
+	if err := srv.DB.Where("email = ?", "fern@example.com").First(&user).Error; err != nil {
+		t.Fatalf("OAuth user was not created: %v", err)
+	}
+	if user.ProviderID != "42" {
+		t.Errorf("provider_id = %q, want 42", user.ProviderID)
+	}
+	var count int64
+	srv.DB.Model(&models.User{}).Where("email = ?", "fern@example.com").Count(&count)
+	if count != 1 {
+		t.Errorf("%d users for one OAuth email, want 1", count)
+	}
+}
+
+func TestHealthProbes(t *testing.T) {
+	srv := testserver.New(t)
+	client := srv.NewClient(t)
+
+	if resp := client.Get("/livez"); resp.StatusCode != http.StatusOK {
+		t.Errorf("GET /livez status = %d", resp.StatusCode)
+	}
+	if version := client.Get("/version").JSON(t); version["version"] == "" || version["go_version"] == "" {
+		t.Errorf("GET /version = %v, want version and go_version", version)
+	}
+
+	var report struct {
+		Status string `json:"status"`
+		Checks map[string]struct {
+			Status string `json:"status"`
+			Error  string `json:"error"`
+		} `json:"checks"`
+	}
+	ready := client.Get("/readyz")
+	ready.Decode(t, &report)
+	if ready.StatusCode != http.StatusOK || report.Status != "ok" {
+		t.Fatalf("GET /readyz = %d: %s", ready.StatusCode, ready.Body)
+	}
+	for _, name := range []string{"database", "database_pool", "storage", "migrations"} {
+		if report.Checks[name].Status != "ok" {
+			t.Errorf("check %s = %+v, want ok", name, report.Checks[name])
+		}
+	}
+
+	// an unreachable image store takes the instance out of rotation
+	srv.Storage.SetUnreachable(errors.New("connection refused"))
+	ready = client.Get("/readyz")
+	ready.Decode(t, &report)
+	if ready.StatusCode != http.StatusServiceUnavailable || report.Checks["storage"].Error != "connection refused" {
+		t.Errorf("GET /readyz with storage down = %d: %s", ready.StatusCode, ready.Body)
+	}
+	srv.Storage.SetUnreachable(nil)
+
+	// draining fails readiness while liveness and traffic carry on
+	srv.Health.Drain()
+	ready = client.Get("/readyz")
+	ready.Decode(t, &report)
+	if ready.StatusCode != http.StatusServiceUnavailable || report.Status != "draining" {
+		t.Errorf("GET /readyz while draining = %d: %s", ready.StatusCode, ready.Body)
+	}
+	if resp := client.Get("/livez"); resp.StatusCode != http.StatusOK {
+		t.Errorf("GET /livez while draining status = %d", resp.StatusCode)
+	}
+}
@@ -1,22 +1,19 @@
 package v1_routes
 
 import (
-	"flower-backend/config"
+	"flower-backend/app"
 	public_user_controller "flower-backend/controllers/v1/user/public"
-	"flower-backend/database"
-	"flower-backend/log"
 	"flower-backend/middlewares"
 
 	"github.com/gin-gonic/gin"
 )
 
-func UserRoutes(r *gin.RouterGroup) {
-	cfg := config.LoadConfig()
-	logger := log.InitLog().Sugar()
-	userCtrl := public_user_controller.NewUserController(database.DB, cfg, logger)
+func UserRoutes(r *gin.RouterGroup, a *app.App) {
+	userCtrl := public_user_controller.NewUserController(a)
 
 	user := r.Group("/user")
-	user.Use(middlewares.OptionalAuthenticate) // Optional auth - sets user_id if token is valid
+	user.Use(middlewares.OptionalAuthenticate(a.JWT)) // Optional auth - sets user_id if token is valid
+	user.Use(middlewares.HTTPCache(a.Config.HTTPCacheMaxAge))
 	{
 		// Public GET routes (optional authentication)
 		user.GET("/:id", userCtrl.GetUserByID)
@@ -33,7 +30,7 @@ func UserRoutes(r *gin.RouterGroup) {
 
 	// Protected routes (authentication required)
 	userAuth := r.Group("/user")
-	userAuth.Use(middlewares.Authenticate)
+	userAuth.Use(middlewares.Authenticate(a.JWT, a.Users, a.Logger))
 	{
 		// Update routes
 		userAuth.PUT("/id/:id/select", userCtrl.UpdateUserByIDWithSelect)
@@ -42,5 +39,7 @@ func UserRoutes(r *gin.RouterGroup) {
 		// Follow routes
 		userAuth.POST("/follow/:follower_id/:following_id", userCtrl.FollowUser)
 		userAuth.POST("/unfollow/:follower_id/:following_id", userCtrl.UnfollowUser)
+		// Notification routes
+		userAuth.GET("/notifications", userCtrl.GetNotifications)
 	}
 }
@@ -0,0 +1,315 @@
+// Package scheduler runs periodic maintenance tasks on cron schedules.
+// Every replica runs a Scheduler, but only the one holding the lease row in
+// scheduler_leases runs tasks; the others stand by and take over once the
+// lease lapses. Each tick is also claimed in task_runs, so it runs once even
+// while leadership changes hands, and the row keeps the run's history.
+package scheduler
+
+import (
+	"context"
+	"flower-backend/config"
+	"flower-backend/log"
+	"flower-backend/metrics"
+	"flower-backend/models"
+	scheduler_repository "flower-backend/repositories/v1/scheduler"
+	"flower-backend/tracing"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+	"go.opentelemetry.io/otel"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var tracer = otel.Tracer("flower-backend/scheduler")
+
+// leaseName is the scheduler_leases row the replicas compete for.
+const leaseName = "scheduler"
+
+// Task runs one tick of a scheduled task and returns how many rows it
+// changed, which is kept in the run's history. ctx is cancelled when the
+// scheduler shuts down.
+type Task func(ctx context.Context) (rowsAffected int64, err error)
+
+type entry struct {
+	name     string
+	schedule cron.Schedule
+	task     Task
+	next     time.Time
+	running  atomic.Bool
+}
+
+// Scheduler runs the tasks added to it while this instance leads.
+type Scheduler struct {
+	repo     scheduler_repository.SchedulerRepository
+	logger   *zap.Logger
+	metrics  *metrics.Metrics
+	now      func() time.Time
+	holder   string
+	leaseTTL time.Duration
+	tick     time.Duration
+
+	mu      sync.Mutex
+	entries []*entry
+	leader  atomic.Bool
+
+	stop     chan struct{}
+	done     chan struct{}
+	started  bool
+	stopOnce sync.Once
+	running  sync.WaitGroup
+	cancel   context.CancelFunc
+}
+
+// New returns a scheduler over the lease and run tables in db. Like the job
+// queue it reads the clock from db's NowFunc. Nothing runs until Start.
+func New(db *gorm.DB, cfg *config.Config, logger *zap.Logger, m *metrics.Metrics) *Scheduler {
+	leaseTTL := cfg.SchedulerLeaseTTL
+	if leaseTTL <= 0 {
+		leaseTTL = 30 * time.Second
+	}
+	hostname, _ := os.Hostname()
+	s := &Scheduler{
+		repo:     scheduler_repository.NewSchedulerRepository(db, cfg, logger.Sugar()),
+		logger:   logger,
+		metrics:  m,
+		now:      db.NowFunc,
+		holder:   fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), uuid.NewString()[:8]),
+		leaseTTL: leaseTTL,
+		tick:     time.Second,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	// the run history would otherwise grow by every tick of every task
+	if err := s.Add("scheduler.prune_runs", "45 3 * * *", s.pruneRuns); err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// Add schedules task under name at every tick of spec: a standard
+// five-field cron expression such as "0 * * * *", or "@every 5m". @every
+// ticks fall on multiples of the interval rather than counting from start
+// up, so every replica agrees on them. Add must be called before Start.
+func (s *Scheduler) Add(name, spec string, task Task) error {
+	schedule, err := cron.ParseStandard(spec)
+	if err != nil {
+		return fmt.Errorf("schedule %q for %s: %w", spec, name, err)
+	}
+	if constant, ok := schedule.(cron.ConstantDelaySchedule); ok {
+		schedule = every(constant.Delay)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, &entry{
+		name:     name,
+		schedule: schedule,
+		task:     task,
+		next:     schedule.Next(s.now()),
+	})
+	return nil
+}
+
+// Now returns the scheduler's clock, the database's, for tasks that compare
+// against times stored in it.
+func (s *Scheduler) Now() time.Time {
+	return s.now()
+}
+
+// Leader reports whether this instance currently holds the lease.
+func (s *Scheduler) Leader() bool {
+	return s.leader.Load()
+}
+
+// Start launches the scheduler loop and returns immediately.
+func (s *Scheduler) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.mu.Lock()
+	s.started = true
+	s.cancel = cancel
+	s.mu.Unlock()
+	go s.loop(ctx)
+	s.logger.Info("scheduler started", zap.String("holder", s.holder), zap.Int("tasks", len(s.entries)))
+}
+
+// Shutdown stops starting tasks, cancels the running ones and waits for them
+// to return or for ctx to end, whichever is first. The lease is released so
+// another instance takes over without waiting for it to expire.
+func (s *Scheduler) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	started, cancel := s.started, s.cancel
+	s.mu.Unlock()
+	if !started {
+		return nil
+	}
+	s.stopOnce.Do(func() { close(s.stop) })
+	<-s.done
+	cancel()
+
+	finished := make(chan struct{})
+	go func() {
+		s.running.Wait()
+		close(finished)
+	}()
+	var err error
+	select {
+	case <-finished:
+	case <-ctx.Done():
+		err = ctx.Err()
+		s.logger.Warn("scheduler shutdown did not wait for running tasks", zap.Error(err))
+	}
+
+	if s.leader.Swap(false) {
+		s.metrics.SchedulerLeader.Set(0)
+		if releaseErr := s.repo.WithContext(context.WithoutCancel(ctx)).ReleaseLease(leaseName, s.holder, s.now()); releaseErr != nil {
+			s.logger.Error("failed to release scheduler lease", zap.Error(releaseErr))
+		}
+	}
+	s.logger.Info("scheduler stopped")
+	return err
+}
+
+func (s *Scheduler) loop(ctx context.Context) {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.tick)
+	defer ticker.Stop()
+
+	var renewAt time.Time
+	for {
+		now := s.now()
+		// renew well before expiry so one slow query does not cost the lease
+		if !now.Before(renewAt) {
+			s.elect(ctx, now)
+			renewAt = now.Add(s.leaseTTL / 3)
+		}
+		if s.leader.Load() {
+			s.runDue(ctx, now)
+		}
+
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// elect takes or renews the lease. An instance that cannot reach the
+// database steps down, since it can no longer tell whether it still leads.
+func (s *Scheduler) elect(ctx context.Context, now time.Time) {
+	leading, err := s.repo.WithContext(ctx).AcquireLease(leaseName, s.holder, now, now.Add(s.leaseTTL))
+	if err != nil {
+		leading = false
+	}
+	if was := s.leader.Swap(leading); was != leading {
+		if leading {
+			s.metrics.SchedulerLeader.Set(1)
+			s.logger.Info("scheduler lease acquired; running scheduled tasks", zap.String("holder", s.holder))
+		} else {
+			s.metrics.SchedulerLeader.Set(0)
+			s.logger.Warn("scheduler lease lost; standing by", zap.String("holder", s.holder), zap.Error(err))
+		}
+	}
+}
+
+// runDue starts every task whose tick has come. Ticks missed while another
+// instance led collapse into the latest one; if that instance ran it, the
+// task_runs row makes this a no-op.
+func (s *Scheduler) runDue(ctx context.Context, now time.Time) {
+	s.mu.Lock()
+	entries := s.entries
+	s.mu.Unlock()
+
+	for _, e := range entries {
+		if e.next.After(now) {
+			continue
+		}
+		scheduled := e.next
+		for tick := e.schedule.Next(scheduled); !tick.After(now); tick = e.schedule.Next(tick) {
+			scheduled = tick
+		}
+		e.next = e.schedule.Next(now)
+
+		if !e.running.CompareAndSwap(false, true) {
+			s.logger.Warn("skipping scheduled task; its previous run is still going", zap.String("task", e.name))
+			continue
+		}
+		s.running.Add(1)
+		go func() {
+			defer s.running.Done()
+			defer e.running.Store(false)
+			s.run(ctx, e, scheduled)
+		}()
+	}
+}
+
+// run claims the tick in task_runs, runs the task and records the outcome.
+func (s *Scheduler) run(ctx context.Context, e *entry, scheduled time.Time) {
+	logger := s.logger.With(zap.String("task", e.name), zap.Time("scheduled_for", scheduled))
+	// ticks are stored in UTC so every replica writes the same key
+	run := &models.TaskRun{Task: e.name, ScheduledFor: scheduled.UTC(), Holder: s.holder, StartedAt: s.now()}
+	claimed, err := s.repo.WithContext(ctx).StartRun(run)
+	if err != nil {
+		logger.Error("skipping scheduled task; failed to claim its run", zap.Error(err))
+		return
+	}
+	if !claimed {
+		// another replica already claimed this tick
+		return
+	}
+
+	ctx, span := tracer.Start(log.WithLogger(ctx, logger), "task "+e.name)
+	start := time.Now()
+	rows, err := call(ctx, e.task)
+	duration := time.Since(start)
+	s.metrics.TaskDuration.WithLabelValues(e.name).Observe(duration.Seconds())
+	tracing.End(span, &err)
+
+	finished := s.now()
+	run.FinishedAt = &finished
+	run.DurationMs = duration.Milliseconds()
+	run.RowsAffected = rows
+	if err != nil {
+		run.Error = err.Error()
+		s.metrics.TaskRuns.WithLabelValues(e.name, "error").Inc()
+		logger.Error("scheduled task failed", zap.Duration("duration", duration), zap.Error(err))
+	} else {
+		s.metrics.TaskRuns.WithLabelValues(e.name, "success").Inc()
+		logger.Info("scheduled task completed", zap.Duration("duration", duration), zap.Int64("rows_affected", rows))
+	}
+	// the outcome is recorded even when shutdown cancelled ctx
+	if err := s.repo.WithContext(context.WithoutCancel(ctx)).FinishRun(run); err != nil {
+		logger.Error("failed to record scheduled task run", zap.Error(err))
+	}
+}
+
+// pruneRuns drops run history older than a month.
+func (s *Scheduler) pruneRuns(ctx context.Context) (int64, error) {
+	return s.repo.WithContext(ctx).DeleteRunsBefore(s.now().AddDate(0, -1, 0))
+}
+
+// call runs task, turning a panic into an error so one bad task cannot take
+// the scheduler down.
+func call(ctx context.Context, task Task) (rows int64, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("task panicked: %v", r)
+		}
+	}()
+	return task(ctx)
+}
+
+// every fires on multiples of its interval. cron's own @every counts from
+// when it is asked, which would give each replica different ticks.
+type every time.Duration
+
+func (e every) Next(t time.Time) time.Time {
+	interval := time.Duration(e)
+	return t.Truncate(interval).Add(interval)
+}
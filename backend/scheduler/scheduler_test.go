@@ -0,0 +1,199 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"flower-backend/metrics"
+	"flower-backend/models"
+	"flower-backend/testutil"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"gorm.io/gorm"
+)
+
+// start is where the database clock stands when a test begins; the scheduler
+// reads it through NowFunc.
+var start = time.Date(2025, 6, 1, 12, 0, 30, 0, time.UTC)
+
+func newTestScheduler(t *testing.T, db *gorm.DB) *Scheduler {
+	t.Helper()
+	cfg := testutil.Config()
+	cfg.SchedulerLeaseTTL = 3 * time.Minute
+	s := New(db, cfg, zap.NewNop(), metrics.New())
+	s.tick = 10 * time.Millisecond
+	t.Cleanup(func() { s.Shutdown(context.Background()) })
+	return s
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// finishedRuns returns the recorded runs of task that have completed.
+func finishedRuns(db *gorm.DB, task string) []models.TaskRun {
+	var runs []models.TaskRun
+	db.Where("task = ? AND finished_at IS NOT NULL", task).Order("scheduled_for").Find(&runs)
+	return runs
+}
+
+func TestOnlyTheLeaderRunsTasks(t *testing.T) {
+	db, clk := testutil.NewDBWithClock(t, start)
+	var runs atomic.Int32
+	task := func(context.Context) (int64, error) {
+		runs.Add(1)
+		return 3, nil
+	}
+
+	leader := newTestScheduler(t, db)
+	if err := leader.Add("count", "* * * * *", task); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	leader.Start()
+	waitFor(t, "a leader", leader.Leader)
+
+	follower := newTestScheduler(t, db)
+	follower.Add("count", "* * * * *", task)
+	follower.Start()
+	time.Sleep(50 * time.Millisecond)
+	if follower.Leader() {
+		t.Fatal("both instances hold the lease")
+	}
+
+	clk.Advance(time.Minute)
+	waitFor(t, "the run", func() bool { return len(finishedRuns(db, "count")) == 1 })
+	// give the follower time to run it too, were it going to
+	time.Sleep(100 * time.Millisecond)
+
+	if n := runs.Load(); n != 1 {
+		t.Errorf("task ran %d times, want once", n)
+	}
+	run := finishedRuns(db, "count")[0]
+	if want := time.Date(2025, 6, 1, 12, 1, 0, 0, time.UTC); !run.ScheduledFor.Equal(want) {
+		t.Errorf("scheduled_for = %s, want %s", run.ScheduledFor, want)
+	}
+	if run.Holder != leader.holder || run.RowsAffected != 3 || run.Error != "" {
+		t.Errorf("run = %+v, want held by the leader with 3 rows and no error", run)
+	}
+}
+
+func TestFollowerTakesOverAfterShutdown(t *testing.T) {
+	db, clk := testutil.NewDBWithClock(t, start)
+	first := newTestScheduler(t, db)
+	first.Start()
+	waitFor(t, "a leader", first.Leader)
+
+	second := newTestScheduler(t, db)
+	ran := make(chan struct{}, 1)
+	second.Add("count", "* * * * *", func(context.Context) (int64, error) {
+		ran <- struct{}{}
+		return 0, nil
+	})
+	second.Start()
+
+	if err := first.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	// past the follower's next renewal, but well within the old lease's TTL
+	clk.Advance(time.Minute)
+	waitFor(t, "the follower to lead", second.Leader)
+	select {
+	case <-ran:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the new leader did not run the task")
+	}
+}
+
+func TestRunRecordsFailures(t *testing.T) {
+	db, clk := testutil.NewDBWithClock(t, start)
+	s := newTestScheduler(t, db)
+	s.Add("fails", "* * * * *", func(context.Context) (int64, error) {
+		return 0, errors.New("database is read-only")
+	})
+	s.Add("panics", "* * * * *", func(context.Context) (int64, error) {
+		panic("nil map")
+	})
+	s.Start()
+	clk.Advance(time.Minute)
+
+	for task, want := range map[string]string{
+		"fails":  "database is read-only",
+		"panics": "task panicked: nil map",
+	} {
+		waitFor(t, task+" to finish", func() bool { return len(finishedRuns(db, task)) == 1 })
+		if got := finishedRuns(db, task)[0].Error; got != want {
+			t.Errorf("%s error = %q, want %q", task, got, want)
+		}
+	}
+}
+
+func TestRunLogsUnrecordedOutcome(t *testing.T) {
+	db, clk := testutil.NewDBWithClock(t, start)
+	s := newTestScheduler(t, db)
+	core, logs := observer.New(zapcore.ErrorLevel)
+	s.logger = zap.New(core)
+	var ran atomic.Bool
+	s.Add("drops-history", "* * * * *", func(context.Context) (int64, error) {
+		ran.Store(true)
+		return 0, db.Migrator().DropTable(&models.TaskRun{})
+	})
+	s.Start()
+	clk.Advance(time.Minute)
+
+	waitFor(t, "the failed outcome to be logged", func() bool {
+		return logs.FilterMessage("failed to record scheduled task run").Len() == 1
+	})
+	if !ran.Load() {
+		t.Error("task did not run")
+	}
+}
+
+func TestShutdownCancelsRunningTasks(t *testing.T) {
+	db, clk := testutil.NewDBWithClock(t, start)
+	s := newTestScheduler(t, db)
+	started := make(chan struct{})
+	s.Add("slow", "* * * * *", func(ctx context.Context) (int64, error) {
+		close(started)
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+	s.Start()
+	clk.Advance(time.Minute)
+	<-started
+
+	if err := s.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	runs := finishedRuns(db, "slow")
+	if len(runs) != 1 || runs[0].Error != context.Canceled.Error() {
+		t.Errorf("runs = %+v, want one cancelled run", runs)
+	}
+	var lease models.SchedulerLease
+	db.First(&lease, "name = ?", leaseName)
+	if lease.ExpiresAt.After(clk.Now()) {
+		t.Errorf("lease expires at %s, want released at %s", lease.ExpiresAt, clk.Now())
+	}
+}
+
+func TestEveryIsAlignedAcrossReplicas(t *testing.T) {
+	schedule := every(5 * time.Minute)
+	for _, start := range []time.Time{
+		time.Date(2025, 6, 1, 12, 0, 30, 0, time.UTC),
+		time.Date(2025, 6, 1, 12, 4, 59, 0, time.UTC),
+	} {
+		if got, want := schedule.Next(start), time.Date(2025, 6, 1, 12, 5, 0, 0, time.UTC); !got.Equal(want) {
+			t.Errorf("Next(%s) = %s, want %s", start, got, want)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package feed_services
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor pins a feed session to the time its first page was ranked, so later
+// pages are scored against the same clock and seen-set, and records the
+// position of the last post returned.
+type Cursor struct {
+	Now    int64   `json:"n"`
+	Score  float64 `json:"s"`
+	PostID uint    `json:"id"`
+}
+
+func (c Cursor) Time() time.Time {
+	return time.Unix(0, c.Now)
+}
+
+func EncodeCursor(c Cursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeCursor(s string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.Now <= 0 {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
@@ -0,0 +1,44 @@
+package feed_services
+
+import (
+	"context"
+	"flower-backend/config"
+	"flower-backend/log"
+	"flower-backend/models"
+	feed_repository "flower-backend/repositories/v1/feed"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type FeedService interface {
+	// WithContext returns a service whose calls run under ctx
+	WithContext(ctx context.Context) FeedService
+	GetFeed(userID uint, cursor string, limit int) ([]models.Post, string, error)
+}
+
+type feedService struct {
+	repo    feed_repository.FeedRepository
+	cfg     *config.Config
+	logger  *zap.SugaredLogger
+	weights Weights
+	// now reads the database's clock so the candidate window agrees with created_at
+	now func() time.Time
+}
+
+func NewFeedService(db *gorm.DB, cfg *config.Config, logger *zap.SugaredLogger) FeedService {
+	return newTracedFeedService(newFeedService(db, cfg, logger))
+}
+
+func newFeedService(db *gorm.DB, cfg *config.Config, logger *zap.SugaredLogger) *feedService {
+	repo := feed_repository.NewFeedRepository(db, cfg, logger)
+	return &feedService{repo: repo, cfg: cfg, logger: logger, weights: WeightsFromConfig(cfg), now: db.NowFunc}
+}
+
+func (s *feedService) WithContext(ctx context.Context) FeedService {
+	clone := *s
+	clone.logger = log.SugarFromContext(ctx, s.logger)
+	clone.repo = s.repo.WithContext(ctx)
+	return &clone
+}
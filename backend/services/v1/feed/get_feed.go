@@ -0,0 +1,138 @@
+package feed_services
+
+import (
+	"flower-backend/models"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// GetFeed
+func (s *feedService) GetFeed(userID uint, cursor string, limit int) ([]models.Post, string, error) {
+	var after *Cursor
+	now := s.now()
+	if cursor != "" {
+		decoded, err := DecodeCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		after = decoded
+		now = decoded.Time()
+	}
+
+	candidates, err := s.loadCandidates(userID, now)
+	if err != nil {
+		return nil, "", err
+	}
+
+	ranked := Rank(candidates, s.weights, now)
+	page, hasMore := Paginate(ranked, after, limit)
+
+	ids := make([]uint, 0, len(page))
+	for _, post := range page {
+		ids = append(ids, post.PostID)
+	}
+	loaded, err := s.repo.GetPostsByIDs(ids)
+	if err != nil {
+		s.logger.Error("failed to load feed posts", zap.Error(err))
+		return nil, "", err
+	}
+	byID := make(map[uint]models.Post, len(loaded))
+	for _, post := range loaded {
+		byID[post.ID] = post
+	}
+	posts := make([]models.Post, 0, len(page))
+	for _, id := range ids {
+		if post, ok := byID[id]; ok {
+			posts = append(posts, post)
+		}
+	}
+
+	// Views are stamped after the session clock, so they only demote these
+	// posts in future sessions and never reshuffle the pages of this one.
+	if err := s.repo.RecordViews(userID, ids, s.now()); err != nil {
+		s.logger.Error("failed to record feed views", zap.Error(err))
+	}
+
+	nextCursor := ""
+	if hasMore && len(page) > 0 {
+		last := page[len(page)-1]
+		nextCursor = EncodeCursor(Cursor{Now: now.UnixNano(), Score: last.Score, PostID: last.PostID})
+	}
+
+	s.logger.Info("feed fetched successfully", zap.Uint("user_id", userID), zap.Int("posts_count", len(posts)))
+	return posts, nextCursor, nil
+}
+
+// loadCandidates gathers followed and popular posts created up to now and
+// collects the ranking signals for each of them.
+func (s *feedService) loadCandidates(userID uint, now time.Time) ([]Signals, error) {
+	since := now.Add(-s.cfg.FeedCandidateWindow)
+
+	followed, err := s.repo.GetFollowedPosts(userID, since, s.cfg.FeedMaxCandidates)
+	if err != nil {
+		s.logger.Error("failed to get followed posts", zap.Error(err))
+		return nil, err
+	}
+	popular, err := s.repo.GetPopularPosts(userID, since, s.cfg.FeedMaxCandidates)
+	if err != nil {
+		s.logger.Error("failed to get popular posts", zap.Error(err))
+		return nil, err
+	}
+	followingIDs, err := s.repo.GetFollowingIDs(userID)
+	if err != nil {
+		s.logger.Error("failed to get following ids", zap.Error(err))
+		return nil, err
+	}
+	following := make(map[uint]bool, len(followingIDs))
+	for _, id := range followingIDs {
+		following[id] = true
+	}
+
+	seenPosts := make(map[uint]bool)
+	seenAuthors := make(map[uint]bool)
+	var posts []models.Post
+	var postIDs, authorIDs []uint
+	for _, post := range append(followed, popular...) {
+		// Posts created after the session started would shift later pages
+		if seenPosts[post.ID] || post.CreatedAt.After(now) {
+			continue
+		}
+		seenPosts[post.ID] = true
+		posts = append(posts, post)
+		postIDs = append(postIDs, post.ID)
+		if !seenAuthors[post.UserID] {
+			seenAuthors[post.UserID] = true
+			authorIDs = append(authorIDs, post.UserID)
+		}
+	}
+
+	recentLikes, err := s.repo.CountLikesBetween(postIDs, now.Add(-s.weights.VelocityWindow), now)
+	if err != nil {
+		s.logger.Error("failed to count recent likes", zap.Error(err))
+		return nil, err
+	}
+	affinity, err := s.repo.CountAuthorAffinity(userID, authorIDs, now.Add(-s.cfg.FeedAffinityWindow))
+	if err != nil {
+		s.logger.Error("failed to count author affinity", zap.Error(err))
+		return nil, err
+	}
+	viewed, err := s.repo.GetSeenPostIDs(userID, postIDs, now)
+	if err != nil {
+		s.logger.Error("failed to get seen posts", zap.Error(err))
+		return nil, err
+	}
+
+	candidates := make([]Signals, 0, len(posts))
+	for _, post := range posts {
+		candidates = append(candidates, Signals{
+			PostID:         post.ID,
+			CreatedAt:      post.CreatedAt,
+			RecentLikes:    recentLikes[post.ID],
+			AuthorAffinity: affinity[post.UserID],
+			Followed:       following[post.UserID],
+			Seen:           viewed[post.ID],
+		})
+	}
+	return candidates, nil
+}
@@ -0,0 +1,74 @@
+package feed_services
+
+import (
+	"flower-backend/models"
+	"flower-backend/testutil"
+	"testing"
+	"time"
+)
+
+func TestGetFeed(t *testing.T) {
+	db := testutil.NewDB(t)
+	svc := newFeedService(db, testutil.Config(), testutil.Logger())
+	svc.now = func() time.Time { return testNow }
+
+	reader := testutil.CreateUser(t, db)
+	followed := testutil.CreateUser(t, db)
+	stranger := testutil.CreateUser(t, db)
+	testutil.CreateFollow(t, db, reader.ID, followed.ID)
+
+	var all []uint
+	for i := 0; i < 3; i++ {
+		post := testutil.CreatePost(t, db, followed.ID, func(p *models.Post) { p.CreatedAt = testNow.Add(-time.Duration(i+1) * time.Hour) })
+		all = append(all, post.ID)
+	}
+	for i := 0; i < 2; i++ {
+		post := testutil.CreatePost(t, db, stranger.ID, func(p *models.Post) { p.CreatedAt = testNow.Add(-time.Duration(i+1) * time.Hour) })
+		all = append(all, post.ID)
+	}
+	testutil.CreatePost(t, db, reader.ID, func(p *models.Post) { p.CreatedAt = testNow.Add(-time.Hour) })
+	testutil.CreatePost(t, db, followed.ID, func(p *models.Post) { p.CreatedAt = testNow.Add(time.Hour) })
+
+	first, cursor, err := svc.GetFeed(reader.ID, "", 3)
+	if err != nil {
+		t.Fatalf("GetFeed() error = %v", err)
+	}
+	if len(first) != 3 || cursor == "" {
+		t.Fatalf("first page = %d posts, cursor %q, want 3 and a cursor", len(first), cursor)
+	}
+	if first[0].UserID != followed.ID {
+		t.Errorf("first post is by %d, want a followed author to rank first", first[0].UserID)
+	}
+
+	// Posts shown on the first page are now seen, which must not reshuffle the session
+	second, next, err := svc.GetFeed(reader.ID, cursor, 3)
+	if err != nil {
+		t.Fatalf("GetFeed(cursor) error = %v", err)
+	}
+	if len(second) != 2 || next != "" {
+		t.Errorf("second page = %d posts, cursor %q, want 2 and no cursor", len(second), next)
+	}
+
+	got := map[uint]bool{}
+	for _, post := range append(first, second...) {
+		if got[post.ID] {
+			t.Errorf("post %d returned twice", post.ID)
+		}
+		got[post.ID] = true
+	}
+	for _, id := range all {
+		if !got[id] {
+			t.Errorf("post %d missing from the feed", id)
+		}
+	}
+
+	var views int64
+	db.Model(&models.PostView{}).Where("user_id = ?", reader.ID).Count(&views)
+	if views != 5 {
+		t.Errorf("recorded %d views, want 5", views)
+	}
+
+	if _, _, err := svc.GetFeed(reader.ID, "not-a-cursor", 3); err == nil {
+		t.Error("GetFeed() with a malformed cursor succeeded, want error")
+	}
+}
@@ -0,0 +1,115 @@
+package feed_services
+
+import (
+	"flower-backend/config"
+	"math"
+	"sort"
+	"time"
+)
+
+// Weights controls how much each signal contributes to a post's feed score.
+type Weights struct {
+	Recency  float64
+	Velocity float64
+	Affinity float64
+	Followed float64
+	// SeenFactor multiplies the score of posts the viewer has already been shown.
+	SeenFactor float64
+	// HalfLife is the age at which the recency signal drops to half.
+	HalfLife time.Duration
+	// VelocityWindow is the period over which recent likes are counted.
+	VelocityWindow time.Duration
+}
+
+func WeightsFromConfig(cfg *config.Config) Weights {
+	return Weights{
+		Recency:        cfg.FeedWeightRecency,
+		Velocity:       cfg.FeedWeightVelocity,
+		Affinity:       cfg.FeedWeightAffinity,
+		Followed:       cfg.FeedWeightFollowed,
+		SeenFactor:     cfg.FeedSeenFactor,
+		HalfLife:       cfg.FeedRecencyHalfLife,
+		VelocityWindow: cfg.FeedVelocityWindow,
+	}
+}
+
+// Signals are the per-post inputs to Score.
+type Signals struct {
+	PostID         uint
+	CreatedAt      time.Time
+	RecentLikes    int64 // likes received within the velocity window
+	AuthorAffinity int64 // likes the viewer gave the author's posts recently
+	Followed       bool  // the viewer follows the author
+	Seen           bool  // the post was shown to the viewer before this session
+}
+
+type RankedPost struct {
+	PostID uint
+	Score  float64
+}
+
+// Score combines recency decay, like velocity and author affinity into a
+// single value. It is a pure function of its inputs so rankings are reproducible.
+func Score(s Signals, w Weights, now time.Time) float64 {
+	recency := 0.0
+	if w.HalfLife > 0 {
+		age := now.Sub(s.CreatedAt)
+		if age < 0 {
+			age = 0
+		}
+		recency = math.Exp(-math.Ln2 * age.Hours() / w.HalfLife.Hours())
+	}
+
+	windowHours := w.VelocityWindow.Hours()
+	if windowHours < 1 {
+		windowHours = 1
+	}
+	velocity := math.Log1p(float64(s.RecentLikes) / windowHours)
+	affinity := math.Log1p(float64(s.AuthorAffinity))
+
+	score := w.Recency*recency + w.Velocity*velocity + w.Affinity*affinity
+	if s.Followed {
+		score += w.Followed
+	}
+	if s.Seen {
+		score *= w.SeenFactor
+	}
+	return score
+}
+
+// Rank scores every candidate and orders them by score, highest first. Ties
+// are broken by post ID descending so the order is total and stable.
+func Rank(candidates []Signals, w Weights, now time.Time) []RankedPost {
+	ranked := make([]RankedPost, 0, len(candidates))
+	for _, candidate := range candidates {
+		ranked = append(ranked, RankedPost{PostID: candidate.PostID, Score: Score(candidate, w, now)})
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return ranked[i].PostID > ranked[j].PostID
+	})
+	return ranked
+}
+
+// Paginate returns up to limit posts ranked strictly after the cursor
+// position, and whether more posts remain.
+func Paginate(ranked []RankedPost, after *Cursor, limit int) ([]RankedPost, bool) {
+	start := 0
+	if after != nil && after.PostID != 0 {
+		start = len(ranked)
+		for i, post := range ranked {
+			if post.Score < after.Score || (post.Score == after.Score && post.PostID < after.PostID) {
+				start = i
+				break
+			}
+		}
+	}
+
+	end := start + limit
+	if end >= len(ranked) {
+		return ranked[start:], false
+	}
+	return ranked[start:end], true
+}
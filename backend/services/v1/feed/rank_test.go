@@ -0,0 +1,242 @@
+package feed_services
+
+import (
+	"math"
+	"slices"
+	"testing"
+	"time"
+)
+
+var testNow = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func testWeights() Weights {
+	return Weights{
+		Recency:        1.0,
+		Velocity:       0.5,
+		Affinity:       0.3,
+		Followed:       0.5,
+		SeenFactor:     0.3,
+		HalfLife:       24 * time.Hour,
+		VelocityWindow: 24 * time.Hour,
+	}
+}
+
+func rankedIDs(ranked []RankedPost) []uint {
+	ids := make([]uint, 0, len(ranked))
+	for _, post := range ranked {
+		ids = append(ids, post.PostID)
+	}
+	return ids
+}
+
+func TestScore(t *testing.T) {
+	w := Weights{Recency: 1, HalfLife: 24 * time.Hour, VelocityWindow: 24 * time.Hour, SeenFactor: 0.5}
+
+	tests := []struct {
+		name    string
+		signals Signals
+		weights Weights
+		want    float64
+	}{
+		{
+			name:    "brand new post has full recency",
+			signals: Signals{CreatedAt: testNow},
+			weights: w,
+			want:    1,
+		},
+		{
+			name:    "post one half-life old has half recency",
+			signals: Signals{CreatedAt: testNow.Add(-24 * time.Hour)},
+			weights: w,
+			want:    0.5,
+		},
+		{
+			name:    "future timestamps are clamped to now",
+			signals: Signals{CreatedAt: testNow.Add(time.Hour)},
+			weights: w,
+			want:    1,
+		},
+		{
+			name:    "seen posts are scaled by the seen factor",
+			signals: Signals{CreatedAt: testNow, Seen: true},
+			weights: w,
+			want:    0.5,
+		},
+		{
+			name:    "followed bonus is additive",
+			signals: Signals{CreatedAt: testNow, Followed: true},
+			weights: Weights{Recency: 1, Followed: 0.25, HalfLife: 24 * time.Hour},
+			want:    1.25,
+		},
+		{
+			name:    "velocity is log-scaled likes per hour",
+			signals: Signals{RecentLikes: 24},
+			weights: Weights{Velocity: 1, VelocityWindow: 24 * time.Hour},
+			want:    math.Log1p(1),
+		},
+		{
+			name:    "affinity is log-scaled",
+			signals: Signals{AuthorAffinity: 3},
+			weights: Weights{Affinity: 2},
+			want:    2 * math.Log1p(3),
+		},
+		{
+			name:    "zero half-life disables recency",
+			signals: Signals{CreatedAt: testNow},
+			weights: Weights{Recency: 1},
+			want:    0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Score(tt.signals, tt.weights, testNow)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Score() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRank(t *testing.T) {
+	tests := []struct {
+		name       string
+		candidates []Signals
+		weights    Weights
+		want       []uint
+	}{
+		{
+			name: "newer posts rank first when other signals are equal",
+			candidates: []Signals{
+				{PostID: 1, CreatedAt: testNow.Add(-48 * time.Hour)},
+				{PostID: 2, CreatedAt: testNow.Add(-1 * time.Hour)},
+				{PostID: 3, CreatedAt: testNow.Add(-12 * time.Hour)},
+			},
+			weights: testWeights(),
+			want:    []uint{2, 3, 1},
+		},
+		{
+			name: "followed authors outrank strangers of the same age",
+			candidates: []Signals{
+				{PostID: 1, CreatedAt: testNow.Add(-2 * time.Hour)},
+				{PostID: 2, CreatedAt: testNow.Add(-2 * time.Hour), Followed: true},
+			},
+			weights: testWeights(),
+			want:    []uint{2, 1},
+		},
+		{
+			name: "seen posts are pushed below unseen ones",
+			candidates: []Signals{
+				{PostID: 1, CreatedAt: testNow, Followed: true, Seen: true},
+				{PostID: 2, CreatedAt: testNow.Add(-6 * time.Hour)},
+			},
+			weights: testWeights(),
+			want:    []uint{2, 1},
+		},
+		{
+			name: "high like velocity lifts an older post",
+			candidates: []Signals{
+				{PostID: 1, CreatedAt: testNow.Add(-1 * time.Hour)},
+				{PostID: 2, CreatedAt: testNow.Add(-36 * time.Hour), RecentLikes: 2400},
+			},
+			weights: testWeights(),
+			want:    []uint{2, 1},
+		},
+		{
+			name: "raising the recency weight changes the order",
+			candidates: []Signals{
+				{PostID: 1, CreatedAt: testNow.Add(-1 * time.Hour)},
+				{PostID: 2, CreatedAt: testNow.Add(-36 * time.Hour), RecentLikes: 2400},
+			},
+			weights: func() Weights {
+				w := testWeights()
+				w.Recency = 10
+				return w
+			}(),
+			want: []uint{1, 2},
+		},
+		{
+			name: "author affinity breaks otherwise equal posts",
+			candidates: []Signals{
+				{PostID: 1, CreatedAt: testNow, AuthorAffinity: 0},
+				{PostID: 2, CreatedAt: testNow, AuthorAffinity: 5},
+			},
+			weights: testWeights(),
+			want:    []uint{2, 1},
+		},
+		{
+			name: "ties are broken by post ID descending",
+			candidates: []Signals{
+				{PostID: 3, CreatedAt: testNow},
+				{PostID: 7, CreatedAt: testNow},
+				{PostID: 5, CreatedAt: testNow},
+			},
+			weights: testWeights(),
+			want:    []uint{7, 5, 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := rankedIDs(Rank(tt.candidates, tt.weights, testNow))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Rank() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	var candidates []Signals
+	for i := uint(1); i <= 7; i++ {
+		candidates = append(candidates, Signals{PostID: i, CreatedAt: testNow.Add(-time.Duration(i%3) * time.Hour)})
+	}
+	ranked := Rank(candidates, testWeights(), testNow)
+
+	var got []uint
+	var after *Cursor
+	for pages := 0; ; pages++ {
+		if pages > len(ranked) {
+			t.Fatal("pagination did not terminate")
+		}
+		page, hasMore := Paginate(ranked, after, 3)
+		got = append(got, rankedIDs(page)...)
+		if !hasMore {
+			break
+		}
+		last := page[len(page)-1]
+		after = &Cursor{Now: testNow.UnixNano(), Score: last.Score, PostID: last.PostID}
+	}
+
+	if want := rankedIDs(ranked); !slices.Equal(got, want) {
+		t.Errorf("paged ids = %v, want %v", got, want)
+	}
+}
+
+func TestPaginateAfterLastPost(t *testing.T) {
+	ranked := []RankedPost{{PostID: 2, Score: 2}, {PostID: 1, Score: 1}}
+	page, hasMore := Paginate(ranked, &Cursor{Now: 1, Score: 1, PostID: 1}, 10)
+	if len(page) != 0 || hasMore {
+		t.Errorf("Paginate() = %v, %v, want empty page", page, hasMore)
+	}
+}
+
+func TestCursor(t *testing.T) {
+	want := Cursor{Now: testNow.UnixNano(), Score: 1.0 / 3.0, PostID: 42}
+	got, err := DecodeCursor(EncodeCursor(want))
+	if err != nil {
+		t.Fatalf("DecodeCursor() error = %v", err)
+	}
+	if *got != want {
+		t.Errorf("DecodeCursor() = %+v, want %+v", *got, want)
+	}
+	if !got.Time().Equal(testNow) {
+		t.Errorf("Cursor.Time() = %v, want %v", got.Time(), testNow)
+	}
+
+	for _, invalid := range []string{"not base64!", "e30", "bnVsbA"} {
+		if _, err := DecodeCursor(invalid); err != ErrInvalidCursor {
+			t.Errorf("DecodeCursor(%q) error = %v, want ErrInvalidCursor", invalid, err)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package feed_services
+
+import (
+	"context"
+	"flower-backend/models"
+	"flower-backend/tracing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("flower-backend/services/v1/feed")
+
+// tracedFeedService starts a span around every call and runs the wrapped
+// service under it, so repository and storage spans nest beneath.
+type tracedFeedService struct {
+	FeedService
+	ctx context.Context
+}
+
+func newTracedFeedService(svc FeedService) FeedService {
+	return &tracedFeedService{FeedService: svc, ctx: context.Background()}
+}
+
+func (s *tracedFeedService) WithContext(ctx context.Context) FeedService {
+	return &tracedFeedService{FeedService: s.FeedService, ctx: ctx}
+}
+
+// start opens the span for method and returns the wrapped service bound to it.
+func (s *tracedFeedService) start(method string) (FeedService, trace.Span) {
+	ctx, span := tracer.Start(s.ctx, "FeedService."+method)
+	return s.FeedService.WithContext(ctx), span
+}
+
+func (s *tracedFeedService) GetFeed(userID uint, cursor string, limit int) (_ []models.Post, _ string, err error) {
+	svc, span := s.start("GetFeed")
+	defer tracing.End(span, &err)
+	return svc.GetFeed(userID, cursor, limit)
+}
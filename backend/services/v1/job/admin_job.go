@@ -0,0 +1,46 @@
+package job_services
+
+import (
+	"errors"
+	"flower-backend/models"
+	job_repository "flower-backend/repositories/v1/job"
+
+	"go.uber.org/zap"
+)
+
+var ErrJobNotDead = errors.New("only dead jobs can be retried")
+
+func (s *jobService) GetJobs(filter job_repository.JobFilter, page, limit int) ([]models.Job, int64, error) {
+	return s.repo.GetAllWithFilter(filter, page, limit)
+}
+
+func (s *jobService) GetJobByID(id uint) (*models.Job, error) {
+	return s.repo.GetByID(id)
+}
+
+// RetryJob gives a dead job a fresh set of attempts and returns it as queued.
+func (s *jobService) RetryJob(id uint) (*models.Job, error) {
+	retried, err := s.repo.Retry(id, s.now())
+	if err != nil {
+		return nil, err
+	}
+	job, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if !retried {
+		return nil, ErrJobNotDead
+	}
+	s.logger.Info("job retried", zap.Uint("job_id", id), zap.String("job_type", job.Type))
+	return job, nil
+}
+
+// RetryDeadJobs requeues every dead job, or only those of jobType.
+func (s *jobService) RetryDeadJobs(jobType string) (int64, error) {
+	retried, err := s.repo.RetryDead(jobType, s.now())
+	if err != nil {
+		return 0, err
+	}
+	s.logger.Info("dead jobs retried", zap.String("job_type", jobType), zap.Int64("count", retried))
+	return retried, nil
+}
@@ -0,0 +1,41 @@
+package job_services
+
+import (
+	"context"
+	"flower-backend/config"
+	"flower-backend/log"
+	"flower-backend/models"
+	job_repository "flower-backend/repositories/v1/job"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type JobService interface {
+	// WithContext returns a service whose calls run under ctx
+	WithContext(ctx context.Context) JobService
+	GetJobs(filter job_repository.JobFilter, page, limit int) ([]models.Job, int64, error)
+	GetJobByID(id uint) (*models.Job, error)
+	RetryJob(id uint) (*models.Job, error)
+	RetryDeadJobs(jobType string) (int64, error)
+}
+
+type jobService struct {
+	repo   job_repository.JobRepository
+	cfg    *config.Config
+	logger *zap.SugaredLogger
+	now    func() time.Time
+}
+
+func NewJobService(db *gorm.DB, cfg *config.Config, logger *zap.SugaredLogger) JobService {
+	repo := job_repository.NewJobRepository(db, cfg, logger)
+	return newTracedJobService(&jobService{repo: repo, cfg: cfg, logger: logger, now: db.NowFunc})
+}
+
+func (s *jobService) WithContext(ctx context.Context) JobService {
+	clone := *s
+	clone.logger = log.SugarFromContext(ctx, s.logger)
+	clone.repo = s.repo.WithContext(ctx)
+	return &clone
+}
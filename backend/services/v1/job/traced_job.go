@@ -0,0 +1,58 @@
+package job_services
+
+import (
+	"context"
+	"flower-backend/models"
+	job_repository "flower-backend/repositories/v1/job"
+	"flower-backend/tracing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("flower-backend/services/v1/job")
+
+// tracedJobService starts a span around every call and runs the wrapped
+// service under it, so repository and storage spans nest beneath.
+type tracedJobService struct {
+	JobService
+	ctx context.Context
+}
+
+func newTracedJobService(svc JobService) JobService {
+	return &tracedJobService{JobService: svc, ctx: context.Background()}
+}
+
+func (s *tracedJobService) WithContext(ctx context.Context) JobService {
+	return &tracedJobService{JobService: s.JobService, ctx: ctx}
+}
+
+// start opens the span for method and returns the wrapped service bound to it.
+func (s *tracedJobService) start(method string) (JobService, trace.Span) {
+	ctx, span := tracer.Start(s.ctx, "JobService."+method)
+	return s.JobService.WithContext(ctx), span
+}
+
+func (s *tracedJobService) GetJobs(filter job_repository.JobFilter, page, limit int) (_ []models.Job, _ int64, err error) {
+	svc, span := s.start("GetJobs")
+	defer tracing.End(span, &err)
+	return svc.GetJobs(filter, page, limit)
+}
+
+func (s *tracedJobService) GetJobByID(id uint) (_ *models.Job, err error) {
+	svc, span := s.start("GetJobByID")
+	defer tracing.End(span, &err)
+	return svc.GetJobByID(id)
+}
+
+func (s *tracedJobService) RetryJob(id uint) (_ *models.Job, err error) {
+	svc, span := s.start("RetryJob")
+	defer tracing.End(span, &err)
+	return svc.RetryJob(id)
+}
+
+func (s *tracedJobService) RetryDeadJobs(jobType string) (_ int64, err error) {
+	svc, span := s.start("RetryDeadJobs")
+	defer tracing.End(span, &err)
+	return svc.RetryDeadJobs(jobType)
+}
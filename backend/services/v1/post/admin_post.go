@@ -0,0 +1,91 @@
+package post_services
+
+import (
+	"flower-backend/models"
+	post_repository "flower-backend/repositories/v1/post"
+	"flower-backend/utils"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// The admin methods below act on behalf of an administrator and deliberately
+// skip CheckPostOwnership; routes exposing them must be behind Authorize("admin").
+
+// AdminGetPosts
+func (s *postService) AdminGetPosts(filter post_repository.PostFilter, page, limit int) ([]models.Post, int64, error) {
+	posts, total, err := s.repo.GetAllWithFilter(filter, page, limit)
+	if err != nil {
+		s.logger.Error("failed to get filtered posts", zap.Error(err))
+		return nil, 0, err
+	}
+	s.logger.Info("filtered posts fetched successfully", zap.Int("page", page), zap.Int("limit", limit))
+	return posts, total, nil
+}
+
+// AdminSetPostsHidden
+func (s *postService) AdminSetPostsHidden(postIDs []uint, hidden bool) (int64, error) {
+	affected, err := s.repo.SetHidden(postIDs, hidden)
+	if err != nil {
+		s.logger.Error("failed to update post visibility", zap.Error(err))
+		return 0, err
+	}
+	s.logger.Info("post visibility updated successfully", zap.Bool("hidden", hidden), zap.Int64("count", affected))
+	return affected, nil
+}
+
+// AdminDeletePosts deletes each post independently and reports the IDs that
+// could not be deleted, so one missing post does not abort the whole batch.
+func (s *postService) AdminDeletePosts(postIDs []uint) ([]uint, map[uint]string) {
+	deleted := make([]uint, 0, len(postIDs))
+	failed := make(map[uint]string)
+	for _, postID := range postIDs {
+		if err := s.repo.AdminDeleteByID(postID); err != nil {
+			if err == gorm.ErrRecordNotFound {
+				failed[postID] = "Post not found"
+				continue
+			}
+			s.logger.Error("failed to delete post", zap.Uint("post_id", postID), zap.Error(err))
+			failed[postID] = "Failed to delete post"
+			continue
+		}
+		deleted = append(deleted, postID)
+	}
+	s.logger.Info("posts deleted by admin", zap.Int("deleted", len(deleted)), zap.Int("failed", len(failed)))
+	return deleted, failed
+}
+
+// AdminUpdatePost
+func (s *postService) AdminUpdatePost(postID uint, updates map[string]any) (*models.Post, error) {
+	if title, ok := updates["title"].(string); ok {
+		updates["title"] = utils.SanitizeString(title)
+	}
+	if content, ok := updates["content"].(string); ok {
+		updates["content"] = utils.SanitizeHTML(content)
+	}
+
+	post, err := s.repo.UpdateByIDWithSelect(postID, updates, []string{"title", "content"})
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, gorm.ErrRecordNotFound
+		}
+		s.logger.Error("failed to update post", zap.Error(err))
+		return nil, err
+	}
+	s.logger.Info("post updated by admin", zap.Uint("id", postID))
+	return post, nil
+}
+
+// TransferPostOwnership
+func (s *postService) TransferPostOwnership(postID, newUserID uint) (*models.Post, error) {
+	post, err := s.repo.TransferOwnership(postID, newUserID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, gorm.ErrRecordNotFound
+		}
+		s.logger.Error("failed to transfer post ownership", zap.Error(err))
+		return nil, err
+	}
+	s.logger.Info("post ownership transferred", zap.Uint("post_id", postID), zap.Uint("user_id", newUserID))
+	return post, nil
+}
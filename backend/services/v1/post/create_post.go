@@ -1,7 +1,6 @@
 package post_services
 
 import (
-	"flower-backend/libs"
 	"flower-backend/models"
 	"flower-backend/utils"
 	"fmt"
@@ -12,30 +11,67 @@ import (
 
 // CreatePost
 func (s *postService) CreatePost(post models.Post) (*models.Post, error) {
-	post.Title = utils.SanitizeString(post.Title)
-	post.Content = utils.SanitizeHTML(post.Content)
-
+	tagNames := s.prepare(&post)
 	if err := s.repo.Create(&post); err != nil {
 		s.logger.Error("failed to create post", zap.Error(err))
 		return nil, err
 	}
+	if err := s.attachTags(&post, tagNames); err != nil {
+		return nil, err
+	}
 	s.logger.Info("post created successfully", zap.String("title", post.Title))
 	return &post, nil
 }
 
+// prepare sanitizes a new post and takes its tags off it, returning their
+// names. Tags are linked after the post exists so existing tag rows are reused.
+func (s *postService) prepare(post *models.Post) []string {
+	post.Title = utils.SanitizeString(post.Title)
+	post.Content = utils.SanitizeHTML(post.Content)
+
+	tagNames := make([]string, 0, len(post.Tags))
+	for _, tag := range post.Tags {
+		tagNames = append(tagNames, tag.Name)
+	}
+	post.Tags = nil
+	return tagNames
+}
+
+// attachTags links the named tags to a newly created post.
+func (s *postService) attachTags(post *models.Post, tagNames []string) error {
+	if len(tagNames) == 0 {
+		return nil
+	}
+	tags, err := s.setPostTags(post.ID, tagNames)
+	if err != nil {
+		return err
+	}
+	post.Tags = tags
+	return nil
+}
+
 // upload image
 func (s *postService) UploadImage(buffer []byte, postID uint) (string, error) {
-	cld, err := libs.NewCloudinary(s.cfg)
+
+	publicId := fmt.Sprintf("post_image_%d_%d", postID, time.Now().Unix())
+	imageURL, err := s.storage.Upload(s.ctx, buffer, publicId)
 	if err != nil {
-		s.logger.Error("failed to create cloudinary client", zap.Error(err))
+		s.logger.Error("failed to upload image", zap.Error(err))
 		return "", err
 	}
+	return imageURL, nil
+}
 
-	publicId := fmt.Sprintf("post_image_%d_%d", postID, time.Now().Unix())
-	uploadResult, err := libs.UploadToCloudinary(cld, buffer, publicId)
+// setPostTags replaces the tags of a post with the given names.
+func (s *postService) setPostTags(postID uint, names []string) ([]models.Tag, error) {
+	tags, err := s.repo.FindOrCreateTags(names)
 	if err != nil {
-		s.logger.Error("failed to upload image to cloudinary", zap.Error(err))
-		return "", err
+		s.logger.Error("failed to find or create tags", zap.Error(err))
+		return nil, err
+	}
+	if err := s.repo.ReplaceTags(postID, tags); err != nil {
+		s.logger.Error("failed to set post tags", zap.Error(err))
+		return nil, err
 	}
-	return uploadResult.SecureURL, nil
+	return tags, nil
 }
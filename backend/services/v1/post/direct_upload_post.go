@@ -0,0 +1,112 @@
+package post_services
+
+import (
+	"errors"
+	"flower-backend/libs"
+	"flower-backend/models"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Errors returned when creating a post from a directly uploaded image. An
+// upload signed for another user is reported as not found.
+var (
+	ErrAssetNotFound    = errors.New("asset not found")
+	ErrAssetNotUploaded = errors.New("asset has not been uploaded")
+	ErrAssetInUse       = errors.New("asset is already used by a post")
+	ErrAssetInvalid     = errors.New("asset is not an accepted image")
+)
+
+// imageFormats are the formats a directly uploaded image may have, as
+// storage reports them.
+var imageFormats = []string{"png", "jpg", "jpeg", "webp"}
+
+// maxDirectUploadSize returns the largest directly uploaded image accepted.
+func (s *postService) maxDirectUploadSize() int64 {
+	if s.cfg.DirectUploadMaxSize > 0 {
+		return s.cfg.DirectUploadMaxSize
+	}
+	return 10 << 20
+}
+
+// SignImageUpload records a new asset for the user and signs its upload so
+// the client can send the image straight to storage.
+func (s *postService) SignImageUpload(userID uint) (*models.DirectUpload, *libs.SignedUpload, error) {
+	expiry := s.cfg.DirectUploadExpiry
+	if expiry <= 0 {
+		expiry = 15 * time.Minute
+	}
+	upload := &models.DirectUpload{
+		AssetID:   fmt.Sprintf("post_direct_%d_%s", userID, uuid.NewString()),
+		UserID:    userID,
+		ExpiresAt: time.Now().Add(expiry),
+	}
+	signed, err := s.storage.SignUpload(s.ctx, upload.AssetID, upload.ExpiresAt)
+	if err != nil {
+		s.logger.Error("failed to sign image upload", zap.Error(err))
+		return nil, nil, err
+	}
+	if err := s.repo.CreateDirectUpload(upload); err != nil {
+		return nil, nil, err
+	}
+	s.logger.Info("image upload signed", zap.String("asset_id", upload.AssetID))
+	return upload, signed, nil
+}
+
+// CreatePostFromAsset creates post with the image the author uploaded
+// directly as assetID, after checking the upload was signed for them and
+// the image is in storage. Anything else they uploaded, a file of another
+// type or one over the size limit, is deleted.
+func (s *postService) CreatePostFromAsset(post models.Post, assetID string) (*models.Post, error) {
+	upload, err := s.repo.GetDirectUpload(assetID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAssetNotFound
+		}
+		return nil, err
+	}
+	if upload.UserID != post.UserID {
+		return nil, ErrAssetNotFound
+	}
+	if upload.PostID != nil {
+		return nil, ErrAssetInUse
+	}
+	asset, err := s.storage.Stat(s.ctx, assetID)
+	if err != nil {
+		if errors.Is(err, libs.ErrAssetNotFound) {
+			return nil, ErrAssetNotUploaded
+		}
+		s.logger.Error("failed to look up uploaded image", zap.Error(err))
+		return nil, err
+	}
+	if !slices.Contains(imageFormats, strings.ToLower(asset.Format)) || asset.Bytes > s.maxDirectUploadSize() {
+		s.logger.Warn("rejecting uploaded asset", zap.String("asset_id", assetID),
+			zap.String("format", asset.Format), zap.Int64("bytes", asset.Bytes))
+		if err := s.storage.Delete(s.ctx, libs.ExtractPublicId(asset.URL)); err != nil {
+			s.logger.Error("failed to delete rejected asset", zap.Error(err))
+		}
+		return nil, ErrAssetInvalid
+	}
+
+	tagNames := s.prepare(&post)
+	post.ImageURL = asset.URL
+	created, err := s.repo.CreateWithAsset(&post, assetID)
+	if err != nil {
+		s.logger.Error("failed to create post", zap.Error(err))
+		return nil, err
+	}
+	if !created {
+		return nil, ErrAssetInUse
+	}
+	if err := s.attachTags(&post, tagNames); err != nil {
+		return nil, err
+	}
+	s.logger.Info("post created from uploaded image", zap.Uint("id", post.ID), zap.String("asset_id", assetID))
+	return &post, nil
+}
@@ -0,0 +1,108 @@
+package post_services
+
+import (
+	"errors"
+	"flower-backend/models"
+	"flower-backend/utils"
+	"slices"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ErrPostNotDraft is returned when editing or publishing a post that is no
+// longer a draft or scheduled.
+var ErrPostNotDraft = errors.New("post is not a draft")
+
+// GetDrafts returns the user's drafts and scheduled posts.
+func (s *postService) GetDrafts(userID uint, page, limit int) ([]models.Post, int64, error) {
+	posts, total, err := s.repo.GetDraftsByUserID(userID, page, limit)
+	if err != nil {
+		s.logger.Error("failed to get drafts", zap.Error(err))
+		return nil, 0, err
+	}
+	return posts, total, nil
+}
+
+// GetDraft returns a draft or scheduled post to its author. Other users, and
+// posts already published, get gorm.ErrRecordNotFound.
+func (s *postService) GetDraft(postID, userID uint) (*models.Post, error) {
+	post, err := s.repo.GetByID(postID)
+	if err != nil {
+		return nil, err
+	}
+	if post.UserID != userID || (post.Status != models.PostDraft && post.Status != models.PostScheduled) {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return post, nil
+}
+
+// UpdateDraft edits a draft or scheduled post of the user. updates may hold
+// "title", "content", "tags" ([]string) and "publish_at" (*time.Time): a
+// time schedules the post, nil turns it back into a draft.
+func (s *postService) UpdateDraft(postID, userID uint, updates map[string]any) (*models.Post, error) {
+	if _, err := s.GetDraft(postID, userID); err != nil {
+		return nil, err
+	}
+
+	columns := map[string]any{}
+	if title, ok := updates["title"].(string); ok {
+		columns["title"] = utils.SanitizeString(title)
+	}
+	if content, ok := updates["content"].(string); ok {
+		columns["content"] = utils.SanitizeHTML(content)
+	}
+	if publishAt, ok := updates["publish_at"]; ok {
+		if at, _ := publishAt.(*time.Time); at != nil {
+			columns["status"] = models.PostScheduled
+			columns["publish_at"] = *at
+		} else {
+			columns["status"] = models.PostDraft
+			columns["publish_at"] = nil
+		}
+	}
+	if len(columns) > 0 {
+		columns["updated_at"] = s.now()
+		updated, err := s.repo.UpdateDraft(postID, columns)
+		if err != nil {
+			return nil, err
+		}
+		if !updated {
+			return nil, ErrPostNotDraft
+		}
+	}
+	if tagNames, ok := updates["tags"].([]string); ok {
+		if _, err := s.setPostTags(postID, slices.Clone(tagNames)); err != nil {
+			return nil, err
+		}
+	}
+	s.logger.Info("draft updated", zap.Uint("id", postID))
+	return s.repo.GetByID(postID)
+}
+
+// PublishDraft publishes a draft or scheduled post of the user now.
+func (s *postService) PublishDraft(postID, userID uint) (*models.Post, error) {
+	if _, err := s.GetDraft(postID, userID); err != nil {
+		return nil, err
+	}
+	published, err := s.repo.PublishDraft(postID, s.now())
+	if err != nil {
+		return nil, err
+	}
+	if !published {
+		return nil, ErrPostNotDraft
+	}
+	s.logger.Info("draft published", zap.Uint("id", postID))
+	return s.repo.GetByID(postID)
+}
+
+// NotifyFollowers tells the author's followers that the post is published.
+func (s *postService) NotifyFollowers(postID uint) error {
+	notified, err := s.repo.NotifyFollowers(postID)
+	if err != nil {
+		return err
+	}
+	s.logger.Info("followers notified of post", zap.Uint("id", postID), zap.Int64("count", notified))
+	return nil
+}
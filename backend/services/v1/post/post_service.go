@@ -1,18 +1,38 @@
 package post_services
 
 import (
+	"context"
 	"flower-backend/config"
+	"flower-backend/jobs"
+	"flower-backend/libs"
+	"flower-backend/log"
 	"flower-backend/models"
+	job_repository "flower-backend/repositories/v1/job"
 	post_repository "flower-backend/repositories/v1/post"
 	"mime/multipart"
+	"time"
 
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
 type PostService interface {
+	// WithContext returns a service whose calls run under ctx
+	WithContext(ctx context.Context) PostService
 	CreatePost(post models.Post) (*models.Post, error)
 	UploadImage(buffer []byte, postID uint) (string, error)
+	CreatePostAsync(post models.Post, image []byte) (*models.Post, error)
+	GetUploadStatus(postID, userID uint) (*models.Post, error)
+	RetryUpload(postID, userID uint, image []byte) (*models.Post, error)
+	ProcessUpload(postID uint) error
+	FailUpload(postID uint, reason string) error
+	SignImageUpload(userID uint) (*models.DirectUpload, *libs.SignedUpload, error)
+	CreatePostFromAsset(post models.Post, assetID string) (*models.Post, error)
+	GetDrafts(userID uint, page, limit int) ([]models.Post, int64, error)
+	GetDraft(postID, userID uint) (*models.Post, error)
+	UpdateDraft(postID, userID uint, updates map[string]any) (*models.Post, error)
+	PublishDraft(postID, userID uint) (*models.Post, error)
+	NotifyFollowers(postID uint) error
 	GetPostByID(id uint) (*models.Post, error)
 	GetPostAllByUserID(userID uint) ([]models.Post, error)
 	GetPostAll() ([]models.Post, error)
@@ -25,15 +45,60 @@ type PostService interface {
 	DislikePost(postID, userID uint) error
 	GetPostLikes(postID uint) (int64, error)
 	GetUserLikedPosts(userID uint, page, limit int) ([]models.Post, int64, error)
+	ReportPost(postID, userID uint, reason string) error
+	AdminGetPosts(filter post_repository.PostFilter, page, limit int) ([]models.Post, int64, error)
+	AdminSetPostsHidden(postIDs []uint, hidden bool) (int64, error)
+	AdminDeletePosts(postIDs []uint) ([]uint, map[uint]string)
+	AdminUpdatePost(postID uint, updates map[string]any) (*models.Post, error)
+	TransferPostOwnership(postID, newUserID uint) (*models.Post, error)
+	GetTrendingPosts(hours int, tag string, page, limit int) ([]models.Post, map[uint]int64, int64, error)
+	GetPopularPosts(window string, tag string, page, limit int) ([]models.Post, map[uint]int64, int64, error)
 }
 
 type postService struct {
-	repo   post_repository.PostRepository
-	cfg    *config.Config
-	logger *zap.SugaredLogger
+	ctx     context.Context
+	repo    post_repository.PostRepository
+	cfg     *config.Config
+	logger  *zap.SugaredLogger
+	storage libs.Storage
+	jobRepo job_repository.JobRepository
+	// now reads the database's clock, which decides when scheduled posts are due
+	now func() time.Time
 }
 
-func NewPostService(db *gorm.DB, cfg *config.Config, logger *zap.SugaredLogger) PostService {
-	repo := post_repository.NewPostRepository(db, cfg, logger)
-	return &postService{repo: repo, cfg: cfg, logger: logger}
+func NewPostService(db *gorm.DB, cfg *config.Config, repo post_repository.PostRepository, logger *zap.SugaredLogger, storage libs.Storage) PostService {
+	return newTracedPostService(newPostService(db, cfg, repo, logger, storage))
+}
+
+func newPostService(db *gorm.DB, cfg *config.Config, repo post_repository.PostRepository, logger *zap.SugaredLogger, storage libs.Storage) *postService {
+	jobRepo := job_repository.NewJobRepository(db, cfg, logger)
+	return &postService{ctx: context.Background(), repo: repo, cfg: cfg, logger: logger, storage: storage, jobRepo: jobRepo, now: db.NowFunc}
+}
+
+func (s *postService) WithContext(ctx context.Context) PostService {
+	clone := *s
+	clone.logger = log.SugarFromContext(ctx, s.logger)
+	clone.ctx = ctx
+	clone.repo = s.repo.WithContext(ctx)
+	clone.jobRepo = s.jobRepo.WithContext(ctx)
+	return &clone
+}
+
+// deleteImageLater queues removal of an image nothing references any more.
+// A failure is only logged: the change that replaced the image succeeded.
+func (s *postService) deleteImageLater(publicId string) {
+	job, err := jobs.NewJob(jobs.TypeDeleteAsset, jobs.DeleteAsset{PublicID: publicId})
+	if err == nil {
+		_, err = s.jobRepo.Create(job)
+	}
+	if err != nil {
+		s.logger.Error("failed to enqueue image deletion", zap.String("public_id", publicId), zap.Error(err))
+	}
+}
+
+// deleteImagesLater queues removal of an image and its renditions.
+func (s *postService) deleteImagesLater(images models.PostImages) {
+	for _, url := range images.URLs() {
+		s.deleteImageLater(libs.ExtractPublicId(url))
+	}
 }
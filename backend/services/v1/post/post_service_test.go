@@ -0,0 +1,262 @@
+package post_services
+
+import (
+	"flower-backend/models"
+	post_repository "flower-backend/repositories/v1/post"
+	"flower-backend/testutil"
+	"sync"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func newTestService(t *testing.T) (*postService, *gorm.DB) {
+	t.Helper()
+	db := testutil.NewDB(t)
+	cfg := testutil.Config()
+	repo := post_repository.NewPostRepository(db, cfg, nil, testutil.Logger())
+	return newPostService(db, cfg, repo, testutil.Logger(), nil), db
+}
+
+func TestCreatePost(t *testing.T) {
+	svc, db := newTestService(t)
+	author := testutil.CreateUser(t, db)
+
+	post, err := svc.CreatePost(models.Post{
+		Title:   "<b>Roses</b>",
+		Content: "<p>Red</p><script>alert(1)</script>",
+		UserID:  author.ID,
+		Tags:    []models.Tag{{Name: "roses"}, {Name: "red"}},
+	})
+	if err != nil {
+		t.Fatalf("CreatePost() error = %v", err)
+	}
+	if post.Title != "&lt;b&gt;Roses&lt;/b&gt;" {
+		t.Errorf("title = %q, want markup escaped", post.Title)
+	}
+	if post.Content != "<p>Red</p>" {
+		t.Errorf("content = %q, want scripts removed", post.Content)
+	}
+	if len(post.Tags) != 2 {
+		t.Errorf("post has %d tags, want 2", len(post.Tags))
+	}
+}
+
+func TestGetPost(t *testing.T) {
+	svc, db := newTestService(t)
+	author := testutil.CreateUser(t, db)
+	post := testutil.CreatePost(t, db, author.ID, func(p *models.Post) { p.Title = "Peony" })
+
+	if got, err := svc.GetPostByID(post.ID); err != nil || got.ID != post.ID {
+		t.Errorf("GetPostByID() = %v, %v", got, err)
+	}
+	if _, err := svc.GetPostByID(post.ID + 100); err != gorm.ErrRecordNotFound {
+		t.Errorf("GetPostByID(missing) error = %v, want ErrRecordNotFound", err)
+	}
+	if posts, err := svc.GetPostAllByUserID(author.ID); err != nil || len(posts) != 1 {
+		t.Errorf("GetPostAllByUserID() = %d posts, %v, want 1", len(posts), err)
+	}
+	if posts, err := svc.GetPostAll(); err != nil || len(posts) != 1 {
+		t.Errorf("GetPostAll() = %d posts, %v, want 1", len(posts), err)
+	}
+	if posts, err := svc.SearchPosts("peony"); err != nil || len(posts) != 1 {
+		t.Errorf("SearchPosts() = %d posts, %v, want 1", len(posts), err)
+	}
+	if posts, total, err := svc.GetPostWithPagination(0, 0); err != nil || total != 1 || len(posts) != 1 {
+		t.Errorf("GetPostWithPagination(0, 0) = %d posts of %d, %v, want 1 of 1", len(posts), total, err)
+	}
+}
+
+func TestCheckPostOwnership(t *testing.T) {
+	svc, db := newTestService(t)
+	owner := testutil.CreateUser(t, db)
+	other := testutil.CreateUser(t, db)
+	admin := testutil.CreateUser(t, db, func(u *models.User) { u.Role = "admin" })
+	post := testutil.CreatePost(t, db, owner.ID)
+	adminPost := testutil.CreatePost(t, db, admin.ID)
+
+	tests := []struct {
+		name    string
+		postID  uint
+		userID  uint
+		want    bool
+		wantErr bool
+	}{
+		{name: "owner", postID: post.ID, userID: owner.ID, want: true},
+		{name: "someone else", postID: post.ID, userID: other.ID, wantErr: true},
+		{name: "post written by an admin", postID: adminPost.ID, userID: other.ID, want: true},
+		{name: "missing post", postID: post.ID + 100, userID: owner.ID, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.CheckPostOwnership(tt.postID, tt.userID)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckPostOwnership() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("CheckPostOwnership() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLikePost(t *testing.T) {
+	svc, db := newTestService(t)
+	author := testutil.CreateUser(t, db)
+	fan := testutil.CreateUser(t, db)
+	post := testutil.CreatePost(t, db, author.ID)
+
+	tests := []struct {
+		name    string
+		postID  uint
+		wantErr string
+	}{
+		{name: "missing post", postID: post.ID + 100, wantErr: gorm.ErrRecordNotFound.Error()},
+		{name: "first like", postID: post.ID},
+		{name: "already liked", postID: post.ID, wantErr: "post already liked"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := svc.LikePost(tt.postID, fan.ID)
+			if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Fatalf("LikePost() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+	if count, err := svc.GetPostLikes(post.ID); err != nil || count != 1 {
+		t.Errorf("GetPostLikes() = %d, %v, want 1", count, err)
+	}
+	if posts, total, err := svc.GetUserLikedPosts(fan.ID, 1, 10); err != nil || total != 1 || len(posts) != 1 {
+		t.Errorf("GetUserLikedPosts() = %d posts of %d, %v, want 1 of 1", len(posts), total, err)
+	}
+
+	if err := svc.DislikePost(post.ID, fan.ID); err != nil {
+		t.Fatalf("DislikePost() error = %v", err)
+	}
+	if err := svc.DislikePost(post.ID+100, fan.ID); err != gorm.ErrRecordNotFound {
+		t.Errorf("DislikePost(missing) error = %v, want ErrRecordNotFound", err)
+	}
+	if count, _ := svc.GetPostLikes(post.ID); count != 0 {
+		t.Errorf("GetPostLikes() after dislike = %d, want 0", count)
+	}
+}
+
+func TestLikePostConcurrent(t *testing.T) {
+	svc, db := newTestService(t)
+	author := testutil.CreateUser(t, db)
+	fan := testutil.CreateUser(t, db)
+	post := testutil.CreatePost(t, db, author.ID)
+
+	const attempts = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, attempts)
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- svc.LikePost(post.ID, fan.ID)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	// Callers that lose the race either see "already liked" or succeed as a no-op
+	for err := range errs {
+		if err != nil && err.Error() != "post already liked" {
+			t.Errorf("concurrent LikePost() error = %v", err)
+		}
+	}
+	if count, _ := svc.GetPostLikes(post.ID); count != 1 {
+		t.Errorf("likes_count = %d, want 1", count)
+	}
+}
+
+func TestReportPost(t *testing.T) {
+	svc, db := newTestService(t)
+	author := testutil.CreateUser(t, db)
+	reporter := testutil.CreateUser(t, db)
+	post := testutil.CreatePost(t, db, author.ID)
+
+	if err := svc.ReportPost(post.ID, reporter.ID, "<i>spam</i>"); err != nil {
+		t.Fatalf("ReportPost() error = %v", err)
+	}
+	var report models.PostReport
+	db.First(&report)
+	if report.Reason != "&lt;i&gt;spam&lt;/i&gt;" {
+		t.Errorf("reason = %q, want markup escaped", report.Reason)
+	}
+	if err := svc.ReportPost(post.ID+100, reporter.ID, "spam"); err != gorm.ErrRecordNotFound {
+		t.Errorf("ReportPost(missing) error = %v, want ErrRecordNotFound", err)
+	}
+}
+
+func TestAdminPosts(t *testing.T) {
+	svc, db := newTestService(t)
+	alice := testutil.CreateUser(t, db)
+	bob := testutil.CreateUser(t, db)
+	first := testutil.CreatePost(t, db, alice.ID)
+	second := testutil.CreatePost(t, db, alice.ID)
+
+	if affected, err := svc.AdminSetPostsHidden([]uint{first.ID}, true); err != nil || affected != 1 {
+		t.Errorf("AdminSetPostsHidden() = %d, %v, want 1", affected, err)
+	}
+	hidden := true
+	if posts, total, err := svc.AdminGetPosts(post_repository.PostFilter{Hidden: &hidden}, 1, 10); err != nil || total != 1 || posts[0].ID != first.ID {
+		t.Errorf("AdminGetPosts(hidden) = %d posts, %v, want only %d", total, err, first.ID)
+	}
+
+	updated, err := svc.AdminUpdatePost(second.ID, map[string]any{"title": "<b>Edited</b>", "user_id": bob.ID})
+	if err != nil {
+		t.Fatalf("AdminUpdatePost() error = %v", err)
+	}
+	if updated.Title != "&lt;b&gt;Edited&lt;/b&gt;" || updated.UserID != alice.ID {
+		t.Errorf("AdminUpdatePost() = title %q owner %d, want escaped title and unchanged owner", updated.Title, updated.UserID)
+	}
+	if _, err := svc.AdminUpdatePost(second.ID+100, map[string]any{"title": "x"}); err != gorm.ErrRecordNotFound {
+		t.Errorf("AdminUpdatePost(missing) error = %v, want ErrRecordNotFound", err)
+	}
+
+	if moved, err := svc.TransferPostOwnership(second.ID, bob.ID); err != nil || moved.UserID != bob.ID {
+		t.Errorf("TransferPostOwnership() = %v, %v, want owner %d", moved, err, bob.ID)
+	}
+	if _, err := svc.TransferPostOwnership(second.ID+100, bob.ID); err != gorm.ErrRecordNotFound {
+		t.Errorf("TransferPostOwnership(missing) error = %v, want ErrRecordNotFound", err)
+	}
+
+	deleted, failed := svc.AdminDeletePosts([]uint{first.ID, second.ID + 100})
+	if len(deleted) != 1 || deleted[0] != first.ID {
+		t.Errorf("AdminDeletePosts() deleted = %v, want [%d]", deleted, first.ID)
+	}
+	if failed[second.ID+100] != "Post not found" || len(failed) != 1 {
+		t.Errorf("AdminDeletePosts() failed = %v, want only the missing post", failed)
+	}
+	if err := svc.DeletePostByID(second.ID, bob.ID); err != nil {
+		t.Errorf("DeletePostByID() error = %v", err)
+	}
+}
+
+func TestTrendingAndPopularPosts(t *testing.T) {
+	svc, db := newTestService(t)
+	author := testutil.CreateUser(t, db)
+	fan := testutil.CreateUser(t, db)
+	post := testutil.CreatePost(t, db, author.ID)
+	testutil.CreateLike(t, db, post.ID, fan.ID)
+	if err := svc.repo.RebuildLikeBuckets(time.Time{}); err != nil {
+		t.Fatalf("RebuildLikeBuckets() error = %v", err)
+	}
+
+	posts, likes, total, err := svc.GetTrendingPosts(24, "", 1, 10)
+	if err != nil || total != 1 || posts[0].ID != post.ID || likes[post.ID] != 1 {
+		t.Errorf("GetTrendingPosts() = %d posts, likes %v, %v, want post %d with 1 like", total, likes, err, post.ID)
+	}
+
+	for _, window := range []string{"day", "week", "month", "all"} {
+		if _, _, total, err := svc.GetPopularPosts(window, "", 1, 10); err != nil || total != 1 {
+			t.Errorf("GetPopularPosts(%q) total = %d, %v, want 1", window, total, err)
+		}
+	}
+	if _, _, _, err := svc.GetPopularPosts("decade", "", 1, 10); err != ErrInvalidWindow {
+		t.Errorf("GetPopularPosts(decade) error = %v, want ErrInvalidWindow", err)
+	}
+}
@@ -0,0 +1,28 @@
+package post_services
+
+import (
+	"flower-backend/models"
+	"flower-backend/utils"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ReportPost
+func (s *postService) ReportPost(postID, userID uint, reason string) error {
+	report := models.PostReport{
+		PostID: postID,
+		UserID: userID,
+		Reason: utils.SanitizeString(reason),
+	}
+	if err := s.repo.CreateReport(&report); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			s.logger.Error("post not found", zap.Uint("id", postID))
+			return gorm.ErrRecordNotFound
+		}
+		s.logger.Error("failed to report post", zap.Error(err))
+		return err
+	}
+	s.logger.Info("post reported successfully", zap.Uint("post_id", postID), zap.Uint("user_id", userID))
+	return nil
+}
@@ -0,0 +1,240 @@
+package post_services
+
+import (
+	"context"
+	"flower-backend/libs"
+	"flower-backend/models"
+	post_repository "flower-backend/repositories/v1/post"
+	"flower-backend/tracing"
+	"mime/multipart"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("flower-backend/services/v1/post")
+
+// tracedPostService starts a span around every call and runs the wrapped
+// service under it, so repository and storage spans nest beneath.
+type tracedPostService struct {
+	PostService
+	ctx context.Context
+}
+
+func newTracedPostService(svc PostService) PostService {
+	return &tracedPostService{PostService: svc, ctx: context.Background()}
+}
+
+func (s *tracedPostService) WithContext(ctx context.Context) PostService {
+	return &tracedPostService{PostService: s.PostService, ctx: ctx}
+}
+
+// start opens the span for method and returns the wrapped service bound to it.
+func (s *tracedPostService) start(method string) (PostService, trace.Span) {
+	ctx, span := tracer.Start(s.ctx, "PostService."+method)
+	return s.PostService.WithContext(ctx), span
+}
+
+func (s *tracedPostService) CreatePost(post models.Post) (_ *models.Post, err error) {
+	svc, span := s.start("CreatePost")
+	defer tracing.End(span, &err)
+	return svc.CreatePost(post)
+}
+
+func (s *tracedPostService) UploadImage(buffer []byte, postID uint) (_ string, err error) {
+	svc, span := s.start("UploadImage")
+	defer tracing.End(span, &err)
+	return svc.UploadImage(buffer, postID)
+}
+
+func (s *tracedPostService) CreatePostAsync(post models.Post, image []byte) (_ *models.Post, err error) {
+	svc, span := s.start("CreatePostAsync")
+	defer tracing.End(span, &err)
+	return svc.CreatePostAsync(post, image)
+}
+
+func (s *tracedPostService) GetUploadStatus(postID, userID uint) (_ *models.Post, err error) {
+	svc, span := s.start("GetUploadStatus")
+	defer tracing.End(span, &err)
+	return svc.GetUploadStatus(postID, userID)
+}
+
+func (s *tracedPostService) RetryUpload(postID, userID uint, image []byte) (_ *models.Post, err error) {
+	svc, span := s.start("RetryUpload")
+	defer tracing.End(span, &err)
+	return svc.RetryUpload(postID, userID, image)
+}
+
+func (s *tracedPostService) ProcessUpload(postID uint) (err error) {
+	svc, span := s.start("ProcessUpload")
+	defer tracing.End(span, &err)
+	return svc.ProcessUpload(postID)
+}
+
+func (s *tracedPostService) FailUpload(postID uint, reason string) (err error) {
+	svc, span := s.start("FailUpload")
+	defer tracing.End(span, &err)
+	return svc.FailUpload(postID, reason)
+}
+
+func (s *tracedPostService) SignImageUpload(userID uint) (_ *models.DirectUpload, _ *libs.SignedUpload, err error) {
+	svc, span := s.start("SignImageUpload")
+	defer tracing.End(span, &err)
+	return svc.SignImageUpload(userID)
+}
+
+func (s *tracedPostService) CreatePostFromAsset(post models.Post, assetID string) (_ *models.Post, err error) {
+	svc, span := s.start("CreatePostFromAsset")
+	defer tracing.End(span, &err)
+	return svc.CreatePostFromAsset(post, assetID)
+}
+
+func (s *tracedPostService) GetDrafts(userID uint, page, limit int) (_ []models.Post, _ int64, err error) {
+	svc, span := s.start("GetDrafts")
+	defer tracing.End(span, &err)
+	return svc.GetDrafts(userID, page, limit)
+}
+
+func (s *tracedPostService) GetDraft(postID, userID uint) (_ *models.Post, err error) {
+	svc, span := s.start("GetDraft")
+	defer tracing.End(span, &err)
+	return svc.GetDraft(postID, userID)
+}
+
+func (s *tracedPostService) UpdateDraft(postID, userID uint, updates map[string]any) (_ *models.Post, err error) {
+	svc, span := s.start("UpdateDraft")
+	defer tracing.End(span, &err)
+	return svc.UpdateDraft(postID, userID, updates)
+}
+
+func (s *tracedPostService) PublishDraft(postID, userID uint) (_ *models.Post, err error) {
+	svc, span := s.start("PublishDraft")
+	defer tracing.End(span, &err)
+	return svc.PublishDraft(postID, userID)
+}
+
+func (s *tracedPostService) NotifyFollowers(postID uint) (err error) {
+	svc, span := s.start("NotifyFollowers")
+	defer tracing.End(span, &err)
+	return svc.NotifyFollowers(postID)
+}
+
+func (s *tracedPostService) GetPostByID(id uint) (_ *models.Post, err error) {
+	svc, span := s.start("GetPostByID")
+	defer tracing.End(span, &err)
+	return svc.GetPostByID(id)
+}
+
+func (s *tracedPostService) GetPostAllByUserID(userID uint) (_ []models.Post, err error) {
+	svc, span := s.start("GetPostAllByUserID")
+	defer tracing.End(span, &err)
+	return svc.GetPostAllByUserID(userID)
+}
+
+func (s *tracedPostService) GetPostAll() (_ []models.Post, err error) {
+	svc, span := s.start("GetPostAll")
+	defer tracing.End(span, &err)
+	return svc.GetPostAll()
+}
+
+func (s *tracedPostService) SearchPosts(query string) (_ []models.Post, err error) {
+	svc, span := s.start("SearchPosts")
+	defer tracing.End(span, &err)
+	return svc.SearchPosts(query)
+}
+
+func (s *tracedPostService) GetPostWithPagination(page, limit int) (_ []models.Post, _ int64, err error) {
+	svc, span := s.start("GetPostWithPagination")
+	defer tracing.End(span, &err)
+	return svc.GetPostWithPagination(page, limit)
+}
+
+func (s *tracedPostService) CheckPostOwnership(postID, userID uint) (_ bool, err error) {
+	svc, span := s.start("CheckPostOwnership")
+	defer tracing.End(span, &err)
+	return svc.CheckPostOwnership(postID, userID)
+}
+
+func (s *tracedPostService) UpdatePostByID(postId uint, userId uint, imageFile *multipart.FileHeader, updates map[string]any, selectFields []string) (_ *models.Post, err error) {
+	svc, span := s.start("UpdatePostByID")
+	defer tracing.End(span, &err)
+	return svc.UpdatePostByID(postId, userId, imageFile, updates, selectFields)
+}
+
+func (s *tracedPostService) DeletePostByID(postID, userID uint) (err error) {
+	svc, span := s.start("DeletePostByID")
+	defer tracing.End(span, &err)
+	return svc.DeletePostByID(postID, userID)
+}
+
+func (s *tracedPostService) LikePost(postID, userID uint) (err error) {
+	svc, span := s.start("LikePost")
+	defer tracing.End(span, &err)
+	return svc.LikePost(postID, userID)
+}
+
+func (s *tracedPostService) DislikePost(postID, userID uint) (err error) {
+	svc, span := s.start("DislikePost")
+	defer tracing.End(span, &err)
+	return svc.DislikePost(postID, userID)
+}
+
+func (s *tracedPostService) GetPostLikes(postID uint) (_ int64, err error) {
+	svc, span := s.start("GetPostLikes")
+	defer tracing.End(span, &err)
+	return svc.GetPostLikes(postID)
+}
+
+func (s *tracedPostService) GetUserLikedPosts(userID uint, page, limit int) (_ []models.Post, _ int64, err error) {
+	svc, span := s.start("GetUserLikedPosts")
+	defer tracing.End(span, &err)
+	return svc.GetUserLikedPosts(userID, page, limit)
+}
+
+func (s *tracedPostService) ReportPost(postID, userID uint, reason string) (err error) {
+	svc, span := s.start("ReportPost")
+	defer tracing.End(span, &err)
+	return svc.ReportPost(postID, userID, reason)
+}
+
+func (s *tracedPostService) AdminGetPosts(filter post_repository.PostFilter, page, limit int) (_ []models.Post, _ int64, err error) {
+	svc, span := s.start("AdminGetPosts")
+	defer tracing.End(span, &err)
+	return svc.AdminGetPosts(filter, page, limit)
+}
+
+func (s *tracedPostService) AdminSetPostsHidden(postIDs []uint, hidden bool) (_ int64, err error) {
+	svc, span := s.start("AdminSetPostsHidden")
+	defer tracing.End(span, &err)
+	return svc.AdminSetPostsHidden(postIDs, hidden)
+}
+
+func (s *tracedPostService) AdminDeletePosts(postIDs []uint) ([]uint, map[uint]string) {
+	svc, span := s.start("AdminDeletePosts")
+	defer span.End()
+	return svc.AdminDeletePosts(postIDs)
+}
+
+func (s *tracedPostService) AdminUpdatePost(postID uint, updates map[string]any) (_ *models.Post, err error) {
+	svc, span := s.start("AdminUpdatePost")
+	defer tracing.End(span, &err)
+	return svc.AdminUpdatePost(postID, updates)
+}
+
+func (s *tracedPostService) TransferPostOwnership(postID, newUserID uint) (_ *models.Post, err error) {
+	svc, span := s.start("TransferPostOwnership")
+	defer tracing.End(span, &err)
+	return svc.TransferPostOwnership(postID, newUserID)
+}
+
+func (s *tracedPostService) GetTrendingPosts(hours int, tag string, page, limit int) (_ []models.Post, _ map[uint]int64, _ int64, err error) {
+	svc, span := s.start("GetTrendingPosts")
+	defer tracing.End(span, &err)
+	return svc.GetTrendingPosts(hours, tag, page, limit)
+}
+
+func (s *tracedPostService) GetPopularPosts(window string, tag string, page, limit int) (_ []models.Post, _ map[uint]int64, _ int64, err error) {
+	svc, span := s.start("GetPopularPosts")
+	defer tracing.End(span, &err)
+	return svc.GetPopularPosts(window, tag, page, limit)
+}
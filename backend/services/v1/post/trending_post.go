@@ -0,0 +1,55 @@
+package post_services
+
+import (
+	"errors"
+	"flower-backend/models"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var ErrInvalidWindow = errors.New("invalid window")
+
+// popularWindows maps the popular window names to their length; "all" has no bound.
+var popularWindows = map[string]time.Duration{
+	"day":   24 * time.Hour,
+	"week":  7 * 24 * time.Hour,
+	"month": 30 * 24 * time.Hour,
+	"all":   0,
+}
+
+// GetTrendingPosts
+func (s *postService) GetTrendingPosts(hours int, tag string, page, limit int) ([]models.Post, map[uint]int64, int64, error) {
+	// Buckets are hourly, so the window starts at the top of the hour
+	since := time.Now().Add(-time.Duration(hours) * time.Hour).Truncate(time.Hour)
+
+	posts, likes, total, err := s.repo.GetTopLikedPosts(&since, tag, page, limit)
+	if err != nil {
+		s.logger.Error("failed to get trending posts", zap.Error(err))
+		return nil, nil, 0, err
+	}
+	s.logger.Info("trending posts fetched successfully", zap.Int("hours", hours), zap.String("tag", tag))
+	return posts, likes, total, nil
+}
+
+// GetPopularPosts
+func (s *postService) GetPopularPosts(window string, tag string, page, limit int) ([]models.Post, map[uint]int64, int64, error) {
+	length, ok := popularWindows[window]
+	if !ok {
+		return nil, nil, 0, ErrInvalidWindow
+	}
+
+	var since *time.Time
+	if length > 0 {
+		start := time.Now().Add(-length).Truncate(time.Hour)
+		since = &start
+	}
+
+	posts, likes, total, err := s.repo.GetTopLikedPosts(since, tag, page, limit)
+	if err != nil {
+		s.logger.Error("failed to get popular posts", zap.Error(err))
+		return nil, nil, 0, err
+	}
+	s.logger.Info("popular posts fetched successfully", zap.String("window", window), zap.String("tag", tag))
+	return posts, likes, total, nil
+}
@@ -1,12 +1,12 @@
 package post_services
 
 import (
-	"flower-backend/libs"
 	"flower-backend/models"
 	"flower-backend/utils"
 	"fmt"
 	"io"
 	"mime/multipart"
+	"slices"
 	"time"
 
 	"go.uber.org/zap"
@@ -21,23 +21,26 @@ func (s *postService) UpdatePostByID(postId uint, userId uint, imageFile *multip
 		updates["content"] = utils.SanitizeHTML(content)
 	}
 
+	// Tags live in a join table, so they are applied separately from the column updates
+	tagNames, updateTags := updates["tags"].([]string)
+	delete(updates, "tags")
+
 	post, err := s.repo.UpdateByIDWithSelect(postId, updates, selectFields)
 	if err != nil {
 		s.logger.Error("failed to update post", zap.Error(err))
 		return nil, err
 	}
 
-	if imageFile != nil {
-		cld, err := libs.NewCloudinary(s.cfg)
+	var tags []models.Tag
+	if updateTags && slices.Contains(selectFields, "tags") {
+		tags, err = s.setPostTags(postId, tagNames)
 		if err != nil {
-			s.logger.Error("failed to create cloudinary client", zap.Error(err))
-			return nil, err
-		}
-		oldPublicId := libs.ExtractPublicId(post.ImageURL)
-		if err := libs.DeleteFromCloudinary(cld, oldPublicId); err != nil {
-			s.logger.Error("failed to delete old image from cloudinary", zap.Error(err))
 			return nil, err
 		}
+	}
+
+	if imageFile != nil {
+		oldImages := post.Images()
 
 		src, err := imageFile.Open()
 		if err != nil {
@@ -53,22 +56,33 @@ func (s *postService) UpdatePostByID(postId uint, userId uint, imageFile *multip
 		}
 
 		newPublicId := fmt.Sprintf("post_image_%d_%d", postId, time.Now().Unix())
-		uploadResult, err := libs.UploadToCloudinary(cld, buffer, newPublicId)
+		imageURL, err := s.storage.Upload(s.ctx, buffer, newPublicId)
 		if err != nil {
-			s.logger.Error("failed to upload image to cloudinary", zap.Error(err))
+			s.logger.Error("failed to upload image", zap.Error(err))
 			return nil, err
 		}
-		post.ImageURL = uploadResult.SecureURL
-		post.UpdatedAt = time.Now()
-
-		if err := s.repo.Update(post); err != nil {
+		// Only the image columns are written: the post read above is seconds
+		// old by now, and its counters may have moved. Renditions are made by
+		// background uploads only, so the old image's are dropped.
+		post, err = s.repo.UpdateByIDWithSelect(postId,
+			map[string]any{"image_url": imageURL, "thumbnail_url": "", "medium_url": ""},
+			[]string{"image_url", "thumbnail_url", "medium_url"})
+		if err != nil {
 			s.logger.Error("failed to update post", zap.Error(err))
 			return nil, err
 		}
+		// The old images go only once the post points at the new one
+		s.deleteImagesLater(oldImages)
+		if tags != nil {
+			post.Tags = tags
+		}
 		s.logger.Info("post updated successfully", zap.Uint("id", postId))
 		return post, nil
 	}
 
+	if tags != nil {
+		post.Tags = tags
+	}
 	s.logger.Info("post updated successfully", zap.Uint("id", postId))
 	return post, nil
 }
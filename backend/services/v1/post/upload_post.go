@@ -0,0 +1,167 @@
+package post_services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flower-backend/config"
+	"flower-backend/jobs"
+	"flower-backend/libs"
+	"flower-backend/models"
+	post_repository "flower-backend/repositories/v1/post"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ErrUploadNotFailed is returned when retrying the upload of a post that is
+// not in the failed state.
+var ErrUploadNotFailed = errors.New("post upload has not failed")
+
+// RegisterJobs registers the background image upload and follower
+// notifications on q. An upload job that runs out of attempts marks its post
+// as failed.
+func RegisterJobs(q *jobs.Queue, db *gorm.DB, cfg *config.Config, repo post_repository.PostRepository, logger *zap.SugaredLogger, storage libs.Storage) {
+	svc := NewPostService(db, cfg, repo, logger, storage)
+	jobs.Handle(q, jobs.TypeNotifyFollowers, func(ctx context.Context, payload jobs.NotifyFollowers) error {
+		return svc.WithContext(ctx).NotifyFollowers(payload.PostID)
+	})
+	jobs.Handle(q, jobs.TypeUploadPostImage, func(ctx context.Context, payload jobs.UploadPostImage) error {
+		return svc.WithContext(ctx).ProcessUpload(payload.PostID)
+	})
+	q.OnDead(jobs.TypeUploadPostImage, func(ctx context.Context, job *models.Job, err error) {
+		var payload jobs.UploadPostImage
+		if json.Unmarshal([]byte(job.Payload), &payload) == nil {
+			svc.WithContext(ctx).FailUpload(payload.PostID, err.Error())
+		}
+	})
+}
+
+// CreatePostAsync creates post in the processing state and leaves the
+// image upload to a background job.
+func (s *postService) CreatePostAsync(post models.Post, image []byte) (*models.Post, error) {
+	tagNames := s.prepare(&post)
+	if err := s.repo.CreateWithUpload(&post, image); err != nil {
+		s.logger.Error("failed to create post", zap.Error(err))
+		return nil, err
+	}
+	if err := s.attachTags(&post, tagNames); err != nil {
+		return nil, err
+	}
+	s.logger.Info("post created, image upload queued", zap.Uint("id", post.ID))
+	return &post, nil
+}
+
+// GetUploadStatus returns the post so its author can follow the upload.
+// Other users get gorm.ErrRecordNotFound, as for any unpublished post.
+func (s *postService) GetUploadStatus(postID, userID uint) (*models.Post, error) {
+	post, err := s.repo.GetByID(postID)
+	if err != nil {
+		return nil, err
+	}
+	if post.UserID != userID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return post, nil
+}
+
+// RetryUpload queues the upload of a failed post again, with image when
+// given or the image sent originally otherwise.
+func (s *postService) RetryUpload(postID, userID uint, image []byte) (*models.Post, error) {
+	if _, err := s.GetUploadStatus(postID, userID); err != nil {
+		return nil, err
+	}
+	retried, err := s.repo.RetryUpload(postID, image)
+	if err != nil {
+		return nil, err
+	}
+	if !retried {
+		return nil, ErrUploadNotFailed
+	}
+	s.logger.Info("post image upload queued again", zap.Uint("id", postID))
+	return s.repo.GetByID(postID)
+}
+
+// renditions are the scaled-down copies ProcessUpload stores next to an
+// image: a thumbnail for grids and a medium size for the post page.
+var renditions = []struct {
+	suffix string
+	width  int
+	url    func(*models.PostImages) *string
+}{
+	{"thumb", 320, func(i *models.PostImages) *string { return &i.ThumbnailURL }},
+	{"medium", 1080, func(i *models.PostImages) *string { return &i.MediumURL }},
+}
+
+// ProcessUpload stores the staged image of a post with its renditions and
+// publishes it.
+func (s *postService) ProcessUpload(postID uint) error {
+	upload, err := s.repo.GetUpload(postID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// the post was deleted, or an earlier attempt already finished
+			return nil
+		}
+		return err
+	}
+	images, err := s.uploadWithRenditions(upload.Data, fmt.Sprintf("post_image_%d_%d", postID, time.Now().Unix()))
+	if err != nil {
+		return err
+	}
+	completed, err := s.repo.CompleteUpload(postID, images)
+	if err != nil || !completed {
+		// nothing points at the images, e.g. because the post was deleted meanwhile
+		s.deleteImagesLater(images)
+		return err
+	}
+	s.logger.Info("post image uploaded, post published", zap.Uint("id", postID))
+	return nil
+}
+
+// uploadWithRenditions stores image under publicId and its renditions
+// under publicId with their suffix. Renditions the image is too small for,
+// or that cannot be made because its format is not decodable, get the
+// original's URL.
+func (s *postService) uploadWithRenditions(image []byte, publicId string) (models.PostImages, error) {
+	imageURL, err := s.storage.Upload(s.ctx, image, publicId)
+	if err != nil {
+		s.logger.Error("failed to upload image", zap.Error(err))
+		return models.PostImages{}, err
+	}
+	images := models.PostImages{ImageURL: imageURL, ThumbnailURL: imageURL, MediumURL: imageURL}
+	decoded, err := libs.DecodeImage(image)
+	if err != nil {
+		s.logger.Warn("image format not supported for renditions", zap.String("public_id", publicId), zap.Error(err))
+		return images, nil
+	}
+	for _, rendition := range renditions {
+		scaled, ok, err := libs.ScaleJPEG(decoded, rendition.width)
+		if err != nil {
+			s.logger.Warn("failed to scale image", zap.String("rendition", rendition.suffix), zap.Error(err))
+			continue
+		}
+		if !ok {
+			continue
+		}
+		url, err := s.storage.Upload(s.ctx, scaled, publicId+"_"+rendition.suffix)
+		if err != nil {
+			s.logger.Error("failed to upload image rendition", zap.String("rendition", rendition.suffix), zap.Error(err))
+			// a retry uploads under a new public ID, so drop this attempt's copies
+			s.deleteImagesLater(images)
+			return models.PostImages{}, err
+		}
+		*rendition.url(&images) = url
+	}
+	return images, nil
+}
+
+// FailUpload marks the post's upload as failed so its author can retry it.
+func (s *postService) FailUpload(postID uint, reason string) error {
+	if err := s.repo.FailUpload(postID, reason); err != nil {
+		return err
+	}
+	s.logger.Warn("post image upload failed", zap.Uint("id", postID), zap.String("reason", reason))
+	return nil
+}
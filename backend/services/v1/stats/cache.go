@@ -0,0 +1,35 @@
+package stats_services
+
+import "flower-backend/cache"
+
+// ownCacheSize bounds the store the service keeps for itself when the
+// shared cache is disabled.
+const ownCacheSize = 256
+
+// keyPrefix keeps dashboard aggregates apart from the other entries of the
+// shared cache.
+const keyPrefix = "stats:"
+
+// load reads the aggregate cached under key into v, so repeated dashboard
+// refreshes do not re-run the same GROUP BY queries.
+func (s *statsService) load(key string, v any) bool {
+	return cache.Load(s.store, keyPrefix+key, v)
+}
+
+// save caches v under key for cfg.StatsCacheTTL. A TTL of zero disables
+// caching.
+func (s *statsService) save(key string, v any) {
+	if s.cfg.StatsCacheTTL <= 0 {
+		return
+	}
+	cache.Save(s.store, keyPrefix+key, v, s.cfg.StatsCacheTTL)
+}
+
+// orEmpty turns the nil slice gob decodes an empty one to back into an
+// empty slice, so it is still sent as [] rather than null.
+func orEmpty[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
@@ -0,0 +1,289 @@
+package stats_services
+
+import (
+	admin_dto "flower-backend/dto/admin"
+	public_dto "flower-backend/dto/public"
+	stats_repository "flower-backend/repositories/v1/stats"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const dayLayout = "2006-01-02"
+
+// providers lists the auth providers that are always present in results,
+// so the dashboard can render a stable set of series.
+var providers = []string{"local", "google", "github"}
+
+// GetOverview
+func (s *statsService) GetOverview() (*admin_dto.StatsOverviewDTO, error) {
+	const key = "overview"
+	var cached admin_dto.StatsOverviewDTO
+	if s.load(key, &cached) {
+		return &cached, nil
+	}
+
+	overview := &admin_dto.StatsOverviewDTO{GeneratedAt: time.Now()}
+	totals := []struct {
+		metric string
+		target *admin_dto.MetricTotal
+	}{
+		{stats_repository.MetricSignups, &overview.Signups},
+		{stats_repository.MetricPosts, &overview.Posts},
+		{stats_repository.MetricLikes, &overview.Likes},
+		{stats_repository.MetricFollows, &overview.Follows},
+	}
+	for _, t := range totals {
+		rows, err := s.repo.CountByProvider(t.metric)
+		if err != nil {
+			s.logger.Error("failed to get stats totals", zap.String("metric", t.metric), zap.Error(err))
+			return nil, err
+		}
+		*t.target = toMetricTotal(rows)
+	}
+
+	now := time.Now()
+	windows := []struct {
+		days   int
+		target *admin_dto.MetricTotal
+	}{
+		{1, &overview.ActiveUsers1d},
+		{7, &overview.ActiveUsers7d},
+		{30, &overview.ActiveUsers30d},
+	}
+	for _, w := range windows {
+		rows, err := s.repo.CountActiveUsersByProvider(now.AddDate(0, 0, -w.days), now)
+		if err != nil {
+			s.logger.Error("failed to get active users", zap.Int("days", w.days), zap.Error(err))
+			return nil, err
+		}
+		*w.target = toMetricTotal(rows)
+	}
+
+	s.save(key, overview)
+	s.logger.Info("stats overview computed successfully")
+	return overview, nil
+}
+
+// GetTimeSeries
+func (s *statsService) GetTimeSeries(metric, interval string, from, to time.Time) (*admin_dto.TimeSeriesDTO, error) {
+	key := fmt.Sprintf("timeseries:%s:%s:%s:%s", metric, interval, from.Format(dayLayout), to.Format(dayLayout))
+	var cached admin_dto.TimeSeriesDTO
+	if s.load(key, &cached) {
+		cached.Points = orEmpty(cached.Points)
+		return &cached, nil
+	}
+
+	buckets, index := buildBuckets(interval, from, to)
+
+	if metric == stats_repository.MetricActiveUsers {
+		rows, err := s.repo.GetActiveUsersByDay(from, to)
+		if err != nil {
+			s.logger.Error("failed to get active users by day", zap.Error(err))
+			return nil, err
+		}
+		// Users are counted once per bucket, so weekly actives are not a sum of dailies
+		seen := make(map[string]struct{}, len(rows))
+		for _, row := range rows {
+			bucket := bucketStart(interval, parseDay(row.Day)).Format(dayLayout)
+			i, ok := index[bucket]
+			if !ok {
+				continue
+			}
+			seenKey := fmt.Sprintf("%s:%d", bucket, row.UserID)
+			if _, dup := seen[seenKey]; dup {
+				continue
+			}
+			seen[seenKey] = struct{}{}
+			buckets[i].Total++
+			buckets[i].ByProvider[normalizeProvider(row.Provider)]++
+		}
+	} else {
+		rows, err := s.repo.CountByDay(metric, from, to)
+		if err != nil {
+			s.logger.Error("failed to get stats time series", zap.String("metric", metric), zap.Error(err))
+			return nil, err
+		}
+		for _, row := range rows {
+			bucket := bucketStart(interval, parseDay(row.Day)).Format(dayLayout)
+			i, ok := index[bucket]
+			if !ok {
+				continue
+			}
+			buckets[i].Total += row.Count
+			buckets[i].ByProvider[normalizeProvider(row.Provider)] += row.Count
+		}
+	}
+
+	series := &admin_dto.TimeSeriesDTO{
+		Metric:      metric,
+		Interval:    interval,
+		From:        from.Format(dayLayout),
+		To:          to.AddDate(0, 0, -1).Format(dayLayout),
+		Points:      buckets,
+		GeneratedAt: time.Now(),
+	}
+	s.save(key, series)
+	s.logger.Info("stats time series computed successfully", zap.String("metric", metric), zap.String("interval", interval))
+	return series, nil
+}
+
+// GetTopPosts
+func (s *statsService) GetTopPosts(from, to time.Time, limit int) ([]admin_dto.TopPostDTO, error) {
+	key := fmt.Sprintf("top-posts:%s:%s:%d", from.Format(dayLayout), to.Format(dayLayout), limit)
+	var cached []admin_dto.TopPostDTO
+	if s.load(key, &cached) {
+		return orEmpty(cached), nil
+	}
+
+	rows, err := s.repo.GetTopPosts(from, to, limit)
+	if err != nil {
+		s.logger.Error("failed to get top posts", zap.Error(err))
+		return nil, err
+	}
+
+	ids := make([]uint, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.PostID)
+	}
+	posts, err := s.repo.GetPostsByIDs(ids)
+	if err != nil {
+		s.logger.Error("failed to load top posts", zap.Error(err))
+		return nil, err
+	}
+	byID := make(map[uint]public_dto.PublicPostDTO, len(posts))
+	for i := range posts {
+		byID[posts[i].ID] = public_dto.ToPublicPost(&posts[i])
+	}
+
+	result := make([]admin_dto.TopPostDTO, 0, len(rows))
+	for _, row := range rows {
+		post, ok := byID[row.PostID]
+		if !ok {
+			continue
+		}
+		result = append(result, admin_dto.TopPostDTO{Post: post, Likes: row.Likes})
+	}
+
+	s.save(key, result)
+	s.logger.Info("top posts computed successfully", zap.Int("count", len(result)))
+	return result, nil
+}
+
+// GetTopCreators
+func (s *statsService) GetTopCreators(from, to time.Time, limit int) ([]admin_dto.TopCreatorDTO, error) {
+	key := fmt.Sprintf("top-creators:%s:%s:%d", from.Format(dayLayout), to.Format(dayLayout), limit)
+	var cached []admin_dto.TopCreatorDTO
+	if s.load(key, &cached) {
+		return orEmpty(cached), nil
+	}
+
+	rows, err := s.repo.GetTopCreators(from, to, limit)
+	if err != nil {
+		s.logger.Error("failed to get top creators", zap.Error(err))
+		return nil, err
+	}
+
+	ids := make([]uint, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.UserID)
+	}
+	users, err := s.repo.GetUsersByIDs(ids)
+	if err != nil {
+		s.logger.Error("failed to load top creators", zap.Error(err))
+		return nil, err
+	}
+	postCounts, err := s.repo.CountPostsByUsers(ids, from, to)
+	if err != nil {
+		s.logger.Error("failed to count creator posts", zap.Error(err))
+		return nil, err
+	}
+
+	usersByID := make(map[uint]public_dto.PublicUserDTO, len(users))
+	for i := range users {
+		usersByID[users[i].ID] = public_dto.ToPublicUser(&users[i])
+	}
+	postsByID := make(map[uint]int64, len(postCounts))
+	for _, row := range postCounts {
+		postsByID[row.UserID] = row.Count
+	}
+
+	result := make([]admin_dto.TopCreatorDTO, 0, len(rows))
+	for _, row := range rows {
+		user, ok := usersByID[row.UserID]
+		if !ok {
+			continue
+		}
+		result = append(result, admin_dto.TopCreatorDTO{User: user, Likes: row.Likes, Posts: postsByID[row.UserID]})
+	}
+
+	s.save(key, result)
+	s.logger.Info("top creators computed successfully", zap.Int("count", len(result)))
+	return result, nil
+}
+
+func toMetricTotal(rows []stats_repository.ProviderCount) admin_dto.MetricTotal {
+	total := admin_dto.MetricTotal{ByProvider: emptyProviderMap()}
+	for _, row := range rows {
+		total.Total += row.Count
+		total.ByProvider[normalizeProvider(row.Provider)] += row.Count
+	}
+	return total
+}
+
+// buildBuckets returns zero-filled buckets covering [from, to) and an index by bucket start
+func buildBuckets(interval string, from, to time.Time) ([]admin_dto.TimeSeriesPointDTO, map[string]int) {
+	buckets := make([]admin_dto.TimeSeriesPointDTO, 0)
+	index := make(map[string]int)
+	for t := bucketStart(interval, from); t.Before(to); t = nextBucket(interval, t) {
+		key := t.Format(dayLayout)
+		index[key] = len(buckets)
+		buckets = append(buckets, admin_dto.TimeSeriesPointDTO{Bucket: key, ByProvider: emptyProviderMap()})
+	}
+	return buckets, index
+}
+
+// bucketStart truncates a day to the start of its bucket; weeks start on Monday
+func bucketStart(interval string, t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	if interval == IntervalWeek {
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset)
+	}
+	return day
+}
+
+func nextBucket(interval string, t time.Time) time.Time {
+	if interval == IntervalWeek {
+		return t.AddDate(0, 0, 7)
+	}
+	return t.AddDate(0, 0, 1)
+}
+
+// parseDay accepts both "2006-01-02" and full timestamps, depending on the driver
+func parseDay(day string) time.Time {
+	if len(day) > len(dayLayout) {
+		day = day[:len(dayLayout)]
+	}
+	t, err := time.ParseInLocation(dayLayout, day, time.Local)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+func normalizeProvider(provider string) string {
+	if provider == "" {
+		return "local"
+	}
+	return provider
+}
+
+func emptyProviderMap() map[string]int64 {
+	m := make(map[string]int64, len(providers))
+	for _, p := range providers {
+		m[p] = 0
+	}
+	return m
+}
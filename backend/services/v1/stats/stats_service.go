@@ -0,0 +1,52 @@
+package stats_services
+
+import (
+	"context"
+	"flower-backend/cache"
+	"flower-backend/config"
+	admin_dto "flower-backend/dto/admin"
+	"flower-backend/log"
+	stats_repository "flower-backend/repositories/v1/stats"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	IntervalDay  = "day"
+	IntervalWeek = "week"
+)
+
+type StatsService interface {
+	// WithContext returns a service whose calls run under ctx
+	WithContext(ctx context.Context) StatsService
+	GetOverview() (*admin_dto.StatsOverviewDTO, error)
+	GetTimeSeries(metric, interval string, from, to time.Time) (*admin_dto.TimeSeriesDTO, error)
+	GetTopPosts(from, to time.Time, limit int) ([]admin_dto.TopPostDTO, error)
+	GetTopCreators(from, to time.Time, limit int) ([]admin_dto.TopCreatorDTO, error)
+}
+
+type statsService struct {
+	repo   stats_repository.StatsRepository
+	store  cache.Cache
+	cfg    *config.Config
+	logger *zap.SugaredLogger
+}
+
+// NewStatsService builds the service, caching aggregates in store. When the
+// shared cache is disabled and store is nil, it keeps a small one of its own.
+func NewStatsService(db *gorm.DB, cfg *config.Config, store cache.Cache, logger *zap.SugaredLogger) StatsService {
+	repo := stats_repository.NewStatsRepository(db, cfg, logger)
+	if store == nil {
+		store = cache.NewMemoryCache(ownCacheSize)
+	}
+	return newTracedStatsService(&statsService{repo: repo, store: store, cfg: cfg, logger: logger})
+}
+
+func (s *statsService) WithContext(ctx context.Context) StatsService {
+	clone := *s
+	clone.logger = log.SugarFromContext(ctx, s.logger)
+	clone.repo = s.repo.WithContext(ctx)
+	return &clone
+}
@@ -0,0 +1,86 @@
+package stats_services
+
+import (
+	"flower-backend/cache"
+	"flower-backend/models"
+	stats_repository "flower-backend/repositories/v1/stats"
+	"flower-backend/testutil"
+	"testing"
+	"time"
+)
+
+func TestStatsService(t *testing.T) {
+	db := testutil.NewDB(t)
+	store := cache.NewMemoryCache(100)
+	svc := NewStatsService(db, testutil.Config(), store, testutil.Logger())
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	from, to := today.AddDate(0, 0, -6), today.AddDate(0, 0, 1)
+
+	alice := testutil.CreateUser(t, db)
+	bob := testutil.CreateUser(t, db, func(u *models.User) { u.Provider = "google" })
+	post := testutil.CreatePost(t, db, alice.ID)
+	testutil.CreateLike(t, db, post.ID, bob.ID)
+	testutil.CreateFollow(t, db, bob.ID, alice.ID)
+	// Drafts and hidden posts are not counted, nor are likes they got
+	testutil.CreatePost(t, db, alice.ID, func(p *models.Post) { p.Status = models.PostDraft })
+	hidden := testutil.CreatePost(t, db, bob.ID, func(p *models.Post) { p.Hidden = true })
+	testutil.CreateLike(t, db, hidden.ID, alice.ID)
+
+	t.Run("GetOverview", func(t *testing.T) {
+		overview, err := svc.GetOverview()
+		if err != nil {
+			t.Fatalf("GetOverview() error = %v", err)
+		}
+		if overview.Signups.Total != 2 || overview.Signups.ByProvider["google"] != 1 {
+			t.Errorf("signups = %+v, want 2 with 1 from google", overview.Signups)
+		}
+		if overview.ActiveUsers7d.Total != 2 {
+			t.Errorf("7-day active users = %d, want 2", overview.ActiveUsers7d.Total)
+		}
+		if _, ok := overview.Signups.ByProvider["github"]; !ok {
+			t.Error("providers without users must still be present")
+		}
+		if overview.Posts.Total != 1 {
+			t.Errorf("posts = %d, want only the published, visible one", overview.Posts.Total)
+		}
+		if _, ok := store.Get(keyPrefix + "overview"); !ok {
+			t.Error("overview not cached in the shared store")
+		}
+	})
+
+	t.Run("GetTimeSeries", func(t *testing.T) {
+		for _, metric := range []string{stats_repository.MetricPosts, stats_repository.MetricActiveUsers} {
+			series, err := svc.GetTimeSeries(metric, IntervalDay, from, to)
+			if err != nil {
+				t.Fatalf("GetTimeSeries(%s) error = %v", metric, err)
+			}
+			if len(series.Points) != 7 {
+				t.Fatalf("GetTimeSeries(%s) has %d points, want 7", metric, len(series.Points))
+			}
+			last := series.Points[len(series.Points)-1]
+			if last.Total == 0 {
+				t.Errorf("GetTimeSeries(%s) today = 0, want activity in the last bucket", metric)
+			}
+		}
+	})
+
+	t.Run("GetTopPosts and GetTopCreators", func(t *testing.T) {
+		posts, err := svc.GetTopPosts(from, to, 5)
+		if err != nil || len(posts) != 1 || posts[0].Post.ID != post.ID || posts[0].Likes != 1 {
+			t.Errorf("GetTopPosts() = %+v, %v, want post %d with 1 like", posts, err, post.ID)
+		}
+		creators, err := svc.GetTopCreators(from, to, 5)
+		if err != nil || len(creators) != 1 || creators[0].User.ID != alice.ID || creators[0].Posts != 1 {
+			t.Errorf("GetTopCreators() = %+v, %v, want alice with 1 post", creators, err)
+		}
+
+		// An empty range is served from cache as [] too
+		for range 2 {
+			if posts, err := svc.GetTopPosts(to, to.AddDate(0, 0, 1), 5); err != nil || posts == nil || len(posts) != 0 {
+				t.Errorf("GetTopPosts(empty range) = %#v, %v, want an empty slice", posts, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,58 @@
+package stats_services
+
+import (
+	"context"
+	admin_dto "flower-backend/dto/admin"
+	"flower-backend/tracing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("flower-backend/services/v1/stats")
+
+// tracedStatsService starts a span around every call and runs the wrapped
+// service under it, so repository and storage spans nest beneath.
+type tracedStatsService struct {
+	StatsService
+	ctx context.Context
+}
+
+func newTracedStatsService(svc StatsService) StatsService {
+	return &tracedStatsService{StatsService: svc, ctx: context.Background()}
+}
+
+func (s *tracedStatsService) WithContext(ctx context.Context) StatsService {
+	return &tracedStatsService{StatsService: s.StatsService, ctx: ctx}
+}
+
+// start opens the span for method and returns the wrapped service bound to it.
+func (s *tracedStatsService) start(method string) (StatsService, trace.Span) {
+	ctx, span := tracer.Start(s.ctx, "StatsService."+method)
+	return s.StatsService.WithContext(ctx), span
+}
+
+func (s *tracedStatsService) GetOverview() (_ *admin_dto.StatsOverviewDTO, err error) {
+	svc, span := s.start("GetOverview")
+	defer tracing.End(span, &err)
+	return svc.GetOverview()
+}
+
+func (s *tracedStatsService) GetTimeSeries(metric, interval string, from, to time.Time) (_ *admin_dto.TimeSeriesDTO, err error) {
+	svc, span := s.start("GetTimeSeries")
+	defer tracing.End(span, &err)
+	return svc.GetTimeSeries(metric, interval, from, to)
+}
+
+func (s *tracedStatsService) GetTopPosts(from, to time.Time, limit int) (_ []admin_dto.TopPostDTO, err error) {
+	svc, span := s.start("GetTopPosts")
+	defer tracing.End(span, &err)
+	return svc.GetTopPosts(from, to, limit)
+}
+
+func (s *tracedStatsService) GetTopCreators(from, to time.Time, limit int) (_ []admin_dto.TopCreatorDTO, err error) {
+	svc, span := s.start("GetTopCreators")
+	defer tracing.End(span, &err)
+	return svc.GetTopCreators(from, to, limit)
+}
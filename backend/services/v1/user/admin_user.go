@@ -0,0 +1,75 @@
+package user_services
+
+import (
+	"errors"
+	"flower-backend/models"
+	user_repository "flower-backend/repositories/v1/user"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrInvalidRole     = errors.New("invalid role")
+	ErrSelfModifyAdmin = errors.New("cannot change your own role or suspension")
+)
+
+// Roles understood by Authorize; keep in sync with the route definitions.
+var validRoles = map[string]bool{"user": true, "admin": true}
+
+func isValidRole(role string) bool {
+	return validRoles[role]
+}
+
+func containsID(ids []uint, id uint) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+// ExportUsers
+func (s *userService) ExportUsers(filter user_repository.UserFilter) ([]models.User, error) {
+	users, err := s.repo.GetAllWithFilter(filter)
+	if err != nil {
+		s.logger.Error("failed to export users", zap.Error(err))
+		return nil, err
+	}
+	s.logger.Info("users exported successfully", zap.Int("count", len(users)))
+	return users, nil
+}
+
+// BulkUpdateUserRole
+func (s *userService) BulkUpdateUserRole(ids []uint, role string, actorID uint) (int64, error) {
+	if !isValidRole(role) {
+		return 0, ErrInvalidRole
+	}
+	// An admin demoting themselves would lock them out of this endpoint
+	if containsID(ids, actorID) {
+		return 0, ErrSelfModifyAdmin
+	}
+
+	affected, err := s.repo.UpdateRoleByIDs(ids, role)
+	if err != nil {
+		s.logger.Error("failed to update user roles", zap.Error(err))
+		return 0, err
+	}
+	s.logger.Info("user roles updated successfully", zap.String("role", role), zap.Int64("count", affected))
+	return affected, nil
+}
+
+// BulkSetUsersSuspended
+func (s *userService) BulkSetUsersSuspended(ids []uint, suspended bool, actorID uint) (int64, error) {
+	if containsID(ids, actorID) {
+		return 0, ErrSelfModifyAdmin
+	}
+
+	affected, err := s.repo.SetSuspended(ids, suspended)
+	if err != nil {
+		s.logger.Error("failed to update user suspension", zap.Error(err))
+		return 0, err
+	}
+	s.logger.Info("user suspension updated successfully", zap.Bool("suspended", suspended), zap.Int64("count", affected))
+	return affected, nil
+}
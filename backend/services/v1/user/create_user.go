@@ -1,7 +1,6 @@
 package user_services
 
 import (
-	"flower-backend/libs"
 	"flower-backend/models"
 	"flower-backend/utils"
 	"fmt"
@@ -23,19 +22,14 @@ func (s *userService) CreateUser(user models.User) (*models.User, error) {
 
 // upload avatar
 func (s *userService) UploadAvatar(buffer []byte, userID uint) (string, error) {
-	cld, err := libs.NewCloudinary(s.cfg)
-	if err != nil {
-		s.logger.Error("failed to create cloudinary client", zap.Error(err))
-		return "", err
-	}
 	publicId := fmt.Sprintf("avatar_%d_%d", userID, time.Now().Unix())
 
-	uploadResult, err := libs.UploadToCloudinary(cld, buffer, publicId)
+	avatarURL, err := s.storage.Upload(s.ctx, buffer, publicId)
 	if err != nil {
-		s.logger.Error("failed to upload avatar to cloudinary", zap.Error(err))
+		s.logger.Error("failed to upload avatar", zap.Error(err))
 		return "", err
 	}
-	return uploadResult.SecureURL, nil
+	return avatarURL, nil
 }
 
 // register user
@@ -59,18 +53,12 @@ func (s *userService) RegisterUser(username, email, password string, avatarFile
 			s.logger.Error("failed to read avatar file", zap.Error(err))
 			return nil, err
 		}
-		cld, err := libs.NewCloudinary(s.cfg)
-		if err != nil {
-			s.logger.Error("failed to create cloudinary client", zap.Error(err))
-			return nil, err
-		}
 		publicId := fmt.Sprintf("avatar_%d", time.Now().Unix())
-		uploadResult, err := libs.UploadToCloudinary(cld, buffer, publicId)
+		avatarURL, err = s.storage.Upload(s.ctx, buffer, publicId)
 		if err != nil {
-			s.logger.Error("failed to upload avatar to cloudinary", zap.Error(err))
+			s.logger.Error("failed to upload avatar", zap.Error(err))
 			return nil, err
 		}
-		avatarURL = uploadResult.SecureURL
 	} else {
 		// Generate default avatar URL based on first character of username
 		firstChar := "A"
@@ -114,3 +102,13 @@ func (s *userService) CreateToken(token *models.Token) error {
 	}
 	return nil
 }
+
+// GetToken looks up a stored refresh token
+func (s *userService) GetToken(token string) (*models.Token, error) {
+	return s.repo.GetToken(token)
+}
+
+// DeleteToken revokes a stored refresh token
+func (s *userService) DeleteToken(token string) error {
+	return s.repo.DeleteToken(token)
+}
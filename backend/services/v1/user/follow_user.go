@@ -8,8 +8,14 @@ import (
 	"gorm.io/gorm"
 )
 
+var ErrCannotFollowSelf = errors.New("cannot follow yourself")
+
 // FollowUser
 func (s *userService) FollowUser(followerID, followingID uint) error {
+	if followerID == followingID {
+		return ErrCannotFollowSelf
+	}
+
 	exists, err := s.repo.CheckFollowExists(followerID, followingID)
 	if err != nil {
 		s.logger.Error("failed to check if user is followed", zap.Error(err))
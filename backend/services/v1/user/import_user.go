@@ -0,0 +1,213 @@
+package user_services
+
+import (
+	"errors"
+	"flower-backend/jobs"
+	"flower-backend/libs"
+	"flower-backend/models"
+	user_repository "flower-backend/repositories/v1/user"
+	"flower-backend/utils"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// ImportUserRow is a single account parsed from an import file. Row is the
+// 1-based position in the file, used to point admins at the failing line.
+type ImportUserRow struct {
+	Row      int
+	Username string
+	Email    string
+	Password string
+	Role     string
+}
+
+type ImportRowError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+type ImportResult struct {
+	DryRun  bool             `json:"dry_run"`
+	Total   int              `json:"total"`
+	Valid   int              `json:"valid"`
+	Created int              `json:"created"`
+	Invited int              `json:"invited"`
+	Errors  []ImportRowError `json:"errors"`
+}
+
+// ImportUsers validates every row and, unless dryRun is set, creates the valid
+// accounts in a single transaction. Invalid rows are reported and skipped.
+// Rows without a password get a random one nobody knows, so they are only
+// accepted when sendInvites is set: the invite links to a page where the user
+// chooses their own. Invites are queued with the accounts and mailed by a
+// background job, so Invited counts queued invites.
+func (s *userService) ImportUsers(rows []ImportUserRow, dryRun, sendInvites bool) (*ImportResult, error) {
+	result := &ImportResult{
+		DryRun: dryRun,
+		Total:  len(rows),
+		Errors: []ImportRowError{},
+	}
+
+	emails := make([]string, 0, len(rows))
+	usernames := make([]string, 0, len(rows))
+	for i := range rows {
+		rows[i].Username = strings.TrimSpace(rows[i].Username)
+		rows[i].Email = strings.TrimSpace(rows[i].Email)
+		rows[i].Role = strings.ToLower(strings.TrimSpace(rows[i].Role))
+		emails = append(emails, rows[i].Email)
+		usernames = append(usernames, rows[i].Username)
+	}
+
+	existing, err := s.repo.GetExisting(emails, usernames)
+	if err != nil {
+		s.logger.Error("failed to check existing users", zap.Error(err))
+		return nil, err
+	}
+	takenEmails := make(map[string]bool, len(existing))
+	takenUsernames := make(map[string]bool, len(existing))
+	for _, user := range existing {
+		takenEmails[strings.ToLower(user.Email)] = true
+		takenUsernames[strings.ToLower(user.Username)] = true
+	}
+
+	seenEmails := make(map[string]int, len(rows))
+	seenUsernames := make(map[string]int, len(rows))
+	valid := make([]ImportUserRow, 0, len(rows))
+	for _, row := range rows {
+		rowErrors := s.validateImportRow(row, sendInvites)
+
+		emailKey := strings.ToLower(row.Email)
+		usernameKey := strings.ToLower(row.Username)
+		if takenEmails[emailKey] {
+			rowErrors = append(rowErrors, ImportRowError{Row: row.Row, Field: "email", Message: "Email already exists"})
+		} else if first, ok := seenEmails[emailKey]; ok && row.Email != "" {
+			rowErrors = append(rowErrors, ImportRowError{Row: row.Row, Field: "email", Message: fmt.Sprintf("Duplicate email, first seen on row %d", first)})
+		}
+		if takenUsernames[usernameKey] {
+			rowErrors = append(rowErrors, ImportRowError{Row: row.Row, Field: "username", Message: "Username already exists"})
+		} else if first, ok := seenUsernames[usernameKey]; ok && row.Username != "" {
+			rowErrors = append(rowErrors, ImportRowError{Row: row.Row, Field: "username", Message: fmt.Sprintf("Duplicate username, first seen on row %d", first)})
+		}
+		if _, ok := seenEmails[emailKey]; !ok {
+			seenEmails[emailKey] = row.Row
+		}
+		if _, ok := seenUsernames[usernameKey]; !ok {
+			seenUsernames[usernameKey] = row.Row
+		}
+
+		if len(rowErrors) > 0 {
+			result.Errors = append(result.Errors, rowErrors...)
+			continue
+		}
+		valid = append(valid, row)
+	}
+	result.Valid = len(valid)
+
+	if dryRun || len(valid) == 0 {
+		s.logger.Info("user import validated", zap.Bool("dry_run", dryRun), zap.Int("total", result.Total), zap.Int("valid", result.Valid))
+		return result, nil
+	}
+
+	users := make([]models.User, 0, len(valid))
+	for _, row := range valid {
+		password := row.Password
+		if password == "" {
+			password = libs.GenerateRandomString(16)
+		}
+		hashedPassword, err := utils.HashPassword(password)
+		if err != nil {
+			s.logger.Error("failed to hash password", zap.Error(err))
+			return nil, err
+		}
+
+		role := row.Role
+		if role == "" {
+			role = "user"
+		}
+		for _, adminEmail := range s.cfg.WhiteListAdminEmails {
+			if row.Email == adminEmail {
+				role = "admin"
+				break
+			}
+		}
+
+		users = append(users, models.User{
+			Username: utils.SanitizeUsername(row.Username),
+			Email:    utils.SanitizeEmail(row.Email),
+			Password: hashedPassword,
+			Avatar:   fmt.Sprintf("https://ui-avatars.com/api/?name=%s&background=random&size=200", string([]rune(row.Username)[0])),
+			Role:     role,
+			Provider: "local",
+		})
+	}
+
+	var invites []user_repository.Invite
+	if sendInvites {
+		invites = make([]user_repository.Invite, 0, len(users))
+		for _, user := range users {
+			invite, err := s.newInvite(&user)
+			if err != nil {
+				s.logger.Error("failed to build invite", zap.String("email", user.Email), zap.Error(err))
+				return nil, err
+			}
+			invites = append(invites, invite)
+		}
+	}
+
+	if err := s.repo.CreateBatch(users, invites); err != nil {
+		s.logger.Error("failed to import users", zap.Error(err))
+		return nil, err
+	}
+	result.Created = len(users)
+	result.Invited = len(invites)
+
+	s.logger.Info("users imported successfully", zap.Int("created", result.Created), zap.Int("invited", result.Invited), zap.Int("errors", len(result.Errors)))
+	return result, nil
+}
+
+func (s *userService) validateImportRow(row ImportUserRow, sendInvites bool) []ImportRowError {
+	var rowErrors []ImportRowError
+	if !utils.ValidateUsername(row.Username) {
+		rowErrors = append(rowErrors, ImportRowError{Row: row.Row, Field: "username", Message: "Invalid username"})
+	}
+	if !utils.ValidateEmail(row.Email) {
+		rowErrors = append(rowErrors, ImportRowError{Row: row.Row, Field: "email", Message: "Invalid email"})
+	}
+	if row.Password == "" {
+		if !sendInvites {
+			rowErrors = append(rowErrors, ImportRowError{Row: row.Row, Field: "password", Message: "Password is required unless invites are sent"})
+		}
+	} else if !utils.ValidatePassword(row.Password) {
+		rowErrors = append(rowErrors, ImportRowError{Row: row.Row, Field: "password", Message: "Invalid password"})
+	}
+	if row.Role != "" && !isValidRole(row.Role) {
+		rowErrors = append(rowErrors, ImportRowError{Row: row.Row, Field: "role", Message: "Invalid role"})
+	}
+	return rowErrors
+}
+
+// newInvite builds the mail inviting user to choose their password. The link
+// carries a single-use token that expires after cfg.InviteExpiry.
+func (s *userService) newInvite(user *models.User) (user_repository.Invite, error) {
+	token := libs.GenerateRandomString(32)
+	if token == "" {
+		return user_repository.Invite{}, errors.New("failed to generate password token")
+	}
+	expiresAt := s.now().Add(s.cfg.InviteExpiry)
+	body := fmt.Sprintf(
+		"Hi %s,\n\nAn account has been created for you on Flower Sharing with the email %s.\n\nChoose your password at %s/set-password?token=%s\n\nThe link works once and expires on %s.\n",
+		user.Username, user.Email, s.cfg.FrontendURL, url.QueryEscape(token), expiresAt.UTC().Format("2 Jan 2006 15:04 MST"),
+	)
+	mail, err := jobs.NewJob(jobs.TypeSendMail, jobs.SendMail{To: user.Email, Subject: "You're invited to Flower Sharing", Body: body})
+	if err != nil {
+		return user_repository.Invite{}, err
+	}
+	return user_repository.Invite{
+		Token: &models.PasswordToken{TokenHash: hashPasswordToken(token), ExpiresAt: expiresAt},
+		Mail:  mail,
+	}, nil
+}
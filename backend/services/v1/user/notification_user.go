@@ -0,0 +1,17 @@
+package user_services
+
+import (
+	"flower-backend/models"
+
+	"go.uber.org/zap"
+)
+
+// GetNotifications
+func (s *userService) GetNotifications(userID uint, page, limit int) ([]models.Notification, int64, error) {
+	notifications, total, err := s.repo.GetNotifications(userID, page, limit)
+	if err != nil {
+		s.logger.Error("failed to get notifications", zap.Error(err))
+		return nil, 0, err
+	}
+	return notifications, total, nil
+}
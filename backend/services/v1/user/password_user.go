@@ -0,0 +1,30 @@
+package user_services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalidPasswordToken is returned for a set-password token that is
+// unknown, expired or already used.
+var ErrInvalidPasswordToken = errors.New("invalid or expired password token")
+
+// SetPassword stores passwordHash for the user a set-password token was
+// issued to, and uses the token up.
+func (s *userService) SetPassword(token, passwordHash string) error {
+	_, err := s.repo.SetPasswordWithToken(hashPasswordToken(token), s.now(), passwordHash)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrInvalidPasswordToken
+	}
+	return err
+}
+
+// hashPasswordToken is how password tokens are stored, so reading the table
+// does not let anyone set a password.
+func hashPasswordToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
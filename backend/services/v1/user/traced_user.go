@@ -0,0 +1,197 @@
+package user_services
+
+import (
+	"context"
+	"flower-backend/models"
+	user_repository "flower-backend/repositories/v1/user"
+	"flower-backend/tracing"
+	"mime/multipart"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("flower-backend/services/v1/user")
+
+// tracedUserService starts a span around every call and runs the wrapped
+// service under it, so repository and storage spans nest beneath.
+type tracedUserService struct {
+	UserService
+	ctx context.Context
+}
+
+func newTracedUserService(svc UserService) UserService {
+	return &tracedUserService{UserService: svc, ctx: context.Background()}
+}
+
+func (s *tracedUserService) WithContext(ctx context.Context) UserService {
+	return &tracedUserService{UserService: s.UserService, ctx: ctx}
+}
+
+// start opens the span for method and returns the wrapped service bound to it.
+func (s *tracedUserService) start(method string) (UserService, trace.Span) {
+	ctx, span := tracer.Start(s.ctx, "UserService."+method)
+	return s.UserService.WithContext(ctx), span
+}
+
+func (s *tracedUserService) CreateUser(user models.User) (_ *models.User, err error) {
+	svc, span := s.start("CreateUser")
+	defer tracing.End(span, &err)
+	return svc.CreateUser(user)
+}
+
+func (s *tracedUserService) CreateToken(token *models.Token) (err error) {
+	svc, span := s.start("CreateToken")
+	defer tracing.End(span, &err)
+	return svc.CreateToken(token)
+}
+
+func (s *tracedUserService) GetToken(token string) (_ *models.Token, err error) {
+	svc, span := s.start("GetToken")
+	defer tracing.End(span, &err)
+	return svc.GetToken(token)
+}
+
+func (s *tracedUserService) DeleteToken(token string) (err error) {
+	svc, span := s.start("DeleteToken")
+	defer tracing.End(span, &err)
+	return svc.DeleteToken(token)
+}
+
+func (s *tracedUserService) SetPassword(token, passwordHash string) (err error) {
+	svc, span := s.start("SetPassword")
+	defer tracing.End(span, &err)
+	return svc.SetPassword(token, passwordHash)
+}
+
+func (s *tracedUserService) RegisterUser(username, email, password string, avatarFile *multipart.FileHeader) (_ *models.User, err error) {
+	svc, span := s.start("RegisterUser")
+	defer tracing.End(span, &err)
+	return svc.RegisterUser(username, email, password, avatarFile)
+}
+
+func (s *tracedUserService) UploadAvatar(buffer []byte, userID uint) (_ string, err error) {
+	svc, span := s.start("UploadAvatar")
+	defer tracing.End(span, &err)
+	return svc.UploadAvatar(buffer, userID)
+}
+
+func (s *tracedUserService) GetUserByID(id uint) (_ *models.User, err error) {
+	svc, span := s.start("GetUserByID")
+	defer tracing.End(span, &err)
+	return svc.GetUserByID(id)
+}
+
+func (s *tracedUserService) GetUserByEmail(email string) (_ *models.User, err error) {
+	svc, span := s.start("GetUserByEmail")
+	defer tracing.End(span, &err)
+	return svc.GetUserByEmail(email)
+}
+
+func (s *tracedUserService) GetUserByUsername(username string) (_ *models.User, err error) {
+	svc, span := s.start("GetUserByUsername")
+	defer tracing.End(span, &err)
+	return svc.GetUserByUsername(username)
+}
+
+func (s *tracedUserService) GetUserByIDWithSelect(id uint, selectFields []string) (_ *models.User, err error) {
+	svc, span := s.start("GetUserByIDWithSelect")
+	defer tracing.End(span, &err)
+	return svc.GetUserByIDWithSelect(id, selectFields)
+}
+
+func (s *tracedUserService) GetUserAll() (_ []models.User, err error) {
+	svc, span := s.start("GetUserAll")
+	defer tracing.End(span, &err)
+	return svc.GetUserAll()
+}
+
+func (s *tracedUserService) UpdateUserByIDWithSelect(id uint, updates map[string]any, imageFile *multipart.FileHeader, selectFields []string) (_ *models.User, err error) {
+	svc, span := s.start("UpdateUserByIDWithSelect")
+	defer tracing.End(span, &err)
+	return svc.UpdateUserByIDWithSelect(id, updates, imageFile, selectFields)
+}
+
+func (s *tracedUserService) DeleteUserByID(id uint) (err error) {
+	svc, span := s.start("DeleteUserByID")
+	defer tracing.End(span, &err)
+	return svc.DeleteUserByID(id)
+}
+
+func (s *tracedUserService) FollowUser(followerID, followingID uint) (err error) {
+	svc, span := s.start("FollowUser")
+	defer tracing.End(span, &err)
+	return svc.FollowUser(followerID, followingID)
+}
+
+func (s *tracedUserService) UnfollowUser(followerID, followingID uint) (err error) {
+	svc, span := s.start("UnfollowUser")
+	defer tracing.End(span, &err)
+	return svc.UnfollowUser(followerID, followingID)
+}
+
+func (s *tracedUserService) GetUserFollowers(userID uint) (_ []models.User, err error) {
+	svc, span := s.start("GetUserFollowers")
+	defer tracing.End(span, &err)
+	return svc.GetUserFollowers(userID)
+}
+
+func (s *tracedUserService) GetUserFollowing(userID uint) (_ []models.User, err error) {
+	svc, span := s.start("GetUserFollowing")
+	defer tracing.End(span, &err)
+	return svc.GetUserFollowing(userID)
+}
+
+func (s *tracedUserService) GetUserFollowersCount(userID uint) (_ int64, err error) {
+	svc, span := s.start("GetUserFollowersCount")
+	defer tracing.End(span, &err)
+	return svc.GetUserFollowersCount(userID)
+}
+
+func (s *tracedUserService) GetUserFollowingCount(userID uint) (_ int64, err error) {
+	svc, span := s.start("GetUserFollowingCount")
+	defer tracing.End(span, &err)
+	return svc.GetUserFollowingCount(userID)
+}
+
+func (s *tracedUserService) GetUserFollowingPosts(userID uint, page, limit int) (_ []models.Post, _ int64, err error) {
+	svc, span := s.start("GetUserFollowingPosts")
+	defer tracing.End(span, &err)
+	return svc.GetUserFollowingPosts(userID, page, limit)
+}
+
+func (s *tracedUserService) GetNotifications(userID uint, page, limit int) (_ []models.Notification, _ int64, err error) {
+	svc, span := s.start("GetNotifications")
+	defer tracing.End(span, &err)
+	return svc.GetNotifications(userID, page, limit)
+}
+
+func (s *tracedUserService) CheckUserOwnership(id uint, userID uint) (_ bool, err error) {
+	svc, span := s.start("CheckUserOwnership")
+	defer tracing.End(span, &err)
+	return svc.CheckUserOwnership(id, userID)
+}
+
+func (s *tracedUserService) ImportUsers(rows []ImportUserRow, dryRun, sendInvites bool) (_ *ImportResult, err error) {
+	svc, span := s.start("ImportUsers")
+	defer tracing.End(span, &err)
+	return svc.ImportUsers(rows, dryRun, sendInvites)
+}
+
+func (s *tracedUserService) ExportUsers(filter user_repository.UserFilter) (_ []models.User, err error) {
+	svc, span := s.start("ExportUsers")
+	defer tracing.End(span, &err)
+	return svc.ExportUsers(filter)
+}
+
+func (s *tracedUserService) BulkUpdateUserRole(ids []uint, role string, actorID uint) (_ int64, err error) {
+	svc, span := s.start("BulkUpdateUserRole")
+	defer tracing.End(span, &err)
+	return svc.BulkUpdateUserRole(ids, role, actorID)
+}
+
+func (s *tracedUserService) BulkSetUsersSuspended(ids []uint, suspended bool, actorID uint) (_ int64, err error) {
+	svc, span := s.start("BulkSetUsersSuspended")
+	defer tracing.End(span, &err)
+	return svc.BulkSetUsersSuspended(ids, suspended, actorID)
+}
@@ -28,19 +28,7 @@ func (s *userService) UpdateUserByIDWithSelect(id uint, updates map[string]any,
 	}
 
 	if imageFile != nil {
-		cld, err := libs.NewCloudinary(s.cfg)
-		if err != nil {
-			s.logger.Error("failed to create cloudinary client", zap.Error(err))
-			return nil, err
-		}
-
-		oldPublicId := libs.ExtractPublicId(user.Avatar)
-		if oldPublicId != "" {
-			if err := libs.DeleteFromCloudinary(cld, oldPublicId); err != nil {
-				s.logger.Error("failed to delete old image from cloudinary", zap.Error(err))
-				return nil, err
-			}
-		}
+		oldAvatar := user.Avatar
 
 		src, err := imageFile.Open()
 		if err != nil {
@@ -56,17 +44,22 @@ func (s *userService) UpdateUserByIDWithSelect(id uint, updates map[string]any,
 		}
 
 		newPublicId := fmt.Sprintf("avatar_%d_%d", id, time.Now().Unix())
-		uploadResult, err := libs.UploadToCloudinary(cld, buffer, newPublicId)
+		avatarURL, err := s.storage.Upload(s.ctx, buffer, newPublicId)
 		if err != nil {
-			s.logger.Error("failed to upload image to cloudinary", zap.Error(err))
+			s.logger.Error("failed to upload avatar", zap.Error(err))
 			return nil, err
 		}
-		user.Avatar = uploadResult.SecureURL
-
-		if err := s.repo.Update(user); err != nil {
+		// Only the avatar column is written: the user read above is seconds
+		// old by now, and its counters may have moved
+		user, err = s.repo.UpdateByIDWithSelect(id, map[string]any{"avatar": avatarURL}, []string{"avatar"})
+		if err != nil {
 			s.logger.Error("failed to update user avatar", zap.Error(err))
 			return nil, err
 		}
+		// The old avatar goes only once the user points at the new one
+		if oldAvatar != "" {
+			s.deleteImageLater(libs.ExtractPublicId(oldAvatar))
+		}
 	}
 	s.logger.Info("user updated successfully", zap.Uint("id", id))
 	return user, nil
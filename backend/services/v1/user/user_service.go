@@ -1,18 +1,29 @@
 package user_services
 
 import (
+	"context"
 	"flower-backend/config"
+	"flower-backend/jobs"
+	"flower-backend/libs"
+	"flower-backend/log"
 	"flower-backend/models"
+	job_repository "flower-backend/repositories/v1/job"
 	user_repository "flower-backend/repositories/v1/user"
 	"mime/multipart"
+	"time"
 
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
 type UserService interface {
+	// WithContext returns a service whose calls run under ctx
+	WithContext(ctx context.Context) UserService
 	CreateUser(user models.User) (*models.User, error)
 	CreateToken(token *models.Token) error
+	GetToken(token string) (*models.Token, error)
+	DeleteToken(token string) error
+	SetPassword(token, passwordHash string) error
 	RegisterUser(username, email, password string, avatarFile *multipart.FileHeader) (*models.User, error)
 	UploadAvatar(buffer []byte, userID uint) (string, error)
 	GetUserByID(id uint) (*models.User, error)
@@ -29,16 +40,51 @@ type UserService interface {
 	GetUserFollowersCount(userID uint) (int64, error)
 	GetUserFollowingCount(userID uint) (int64, error)
 	GetUserFollowingPosts(userID uint, page, limit int) ([]models.Post, int64, error)
+	GetNotifications(userID uint, page, limit int) ([]models.Notification, int64, error)
 	CheckUserOwnership(id uint, userID uint) (bool, error)
+	ImportUsers(rows []ImportUserRow, dryRun, sendInvites bool) (*ImportResult, error)
+	ExportUsers(filter user_repository.UserFilter) ([]models.User, error)
+	BulkUpdateUserRole(ids []uint, role string, actorID uint) (int64, error)
+	BulkSetUsersSuspended(ids []uint, suspended bool, actorID uint) (int64, error)
 }
 
 type userService struct {
-	repo   user_repository.UserRepository
-	cfg    *config.Config
-	logger *zap.SugaredLogger
+	ctx     context.Context
+	repo    user_repository.UserRepository
+	cfg     *config.Config
+	logger  *zap.SugaredLogger
+	storage libs.Storage
+	jobRepo job_repository.JobRepository
+	// now reads the database's clock, which password tokens expire against
+	now func() time.Time
 }
 
-func NewUserService(db *gorm.DB, cfg *config.Config, logger *zap.SugaredLogger) UserService {
-	repo := user_repository.NewUserRepository(db, cfg, logger)
-	return &userService{repo: repo, cfg: cfg, logger: logger}
+func NewUserService(db *gorm.DB, cfg *config.Config, repo user_repository.UserRepository, logger *zap.SugaredLogger, storage libs.Storage) UserService {
+	return newTracedUserService(newUserService(db, cfg, repo, logger, storage))
+}
+
+func newUserService(db *gorm.DB, cfg *config.Config, repo user_repository.UserRepository, logger *zap.SugaredLogger, storage libs.Storage) *userService {
+	jobRepo := job_repository.NewJobRepository(db, cfg, logger)
+	return &userService{ctx: context.Background(), repo: repo, cfg: cfg, logger: logger, storage: storage, jobRepo: jobRepo, now: db.NowFunc}
+}
+
+func (s *userService) WithContext(ctx context.Context) UserService {
+	clone := *s
+	clone.logger = log.SugarFromContext(ctx, s.logger)
+	clone.ctx = ctx
+	clone.repo = s.repo.WithContext(ctx)
+	clone.jobRepo = s.jobRepo.WithContext(ctx)
+	return &clone
+}
+
+// deleteImageLater queues removal of an image nothing references any more.
+// A failure is only logged: the change that replaced the image succeeded.
+func (s *userService) deleteImageLater(publicId string) {
+	job, err := jobs.NewJob(jobs.TypeDeleteAsset, jobs.DeleteAsset{PublicID: publicId})
+	if err == nil {
+		_, err = s.jobRepo.Create(job)
+	}
+	if err != nil {
+		s.logger.Error("failed to enqueue image deletion", zap.String("public_id", publicId), zap.Error(err))
+	}
 }
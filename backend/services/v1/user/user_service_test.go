@@ -0,0 +1,337 @@
+package user_services
+
+import (
+	"encoding/json"
+	"flower-backend/jobs"
+	"flower-backend/models"
+	user_repository "flower-backend/repositories/v1/user"
+	"flower-backend/testutil"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func newTestService(t *testing.T) (*userService, *gorm.DB) {
+	t.Helper()
+	db := testutil.NewDB(t)
+	cfg := testutil.Config()
+	cfg.WhiteListAdminEmails = []string{"boss@example.com"}
+	repo := user_repository.NewUserRepository(db, cfg, nil, testutil.Logger())
+	return newUserService(db, cfg, repo, testutil.Logger(), nil), db
+}
+
+func TestRegisterUser(t *testing.T) {
+	svc, _ := newTestService(t)
+
+	tests := []struct {
+		name     string
+		username string
+		email    string
+		wantRole string
+		wantErr  bool
+	}{
+		{name: "regular user", username: "lily", email: "lily@example.com", wantRole: "user"},
+		{name: "whitelisted admin", username: "boss", email: "boss@example.com", wantRole: "admin"},
+		{name: "duplicate email", username: "lily2", email: "lily@example.com", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, err := svc.RegisterUser(tt.username, tt.email, "hashed", nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RegisterUser() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if user.Role != tt.wantRole {
+				t.Errorf("role = %q, want %q", user.Role, tt.wantRole)
+			}
+			if user.Avatar == "" {
+				t.Error("RegisterUser() left the default avatar empty")
+			}
+		})
+	}
+}
+
+func TestGetUser(t *testing.T) {
+	svc, db := newTestService(t)
+	user := testutil.CreateUser(t, db)
+
+	if got, err := svc.GetUserByID(user.ID); err != nil || got.ID != user.ID {
+		t.Errorf("GetUserByID() = %v, %v", got, err)
+	}
+	if _, err := svc.GetUserByID(user.ID + 100); err != gorm.ErrRecordNotFound {
+		t.Errorf("GetUserByID(missing) error = %v, want ErrRecordNotFound", err)
+	}
+	if got, err := svc.GetUserByEmail(user.Email); err != nil || got.ID != user.ID {
+		t.Errorf("GetUserByEmail() = %v, %v", got, err)
+	}
+	if got, err := svc.GetUserByUsername(user.Username); err != nil || got.ID != user.ID {
+		t.Errorf("GetUserByUsername() = %v, %v", got, err)
+	}
+	if users, err := svc.GetUserAll(); err != nil || len(users) != 1 {
+		t.Errorf("GetUserAll() = %d users, %v, want 1", len(users), err)
+	}
+}
+
+func TestCheckUserOwnership(t *testing.T) {
+	svc, db := newTestService(t)
+	user := testutil.CreateUser(t, db)
+	other := testutil.CreateUser(t, db)
+
+	if ok, err := svc.CheckUserOwnership(user.ID, user.ID); err != nil || !ok {
+		t.Errorf("CheckUserOwnership(self) = %v, %v, want true", ok, err)
+	}
+	if ok, err := svc.CheckUserOwnership(user.ID, other.ID); err == nil || ok {
+		t.Errorf("CheckUserOwnership(other) = %v, %v, want an error", ok, err)
+	}
+	if _, err := svc.CheckUserOwnership(user.ID+100, user.ID); err != gorm.ErrRecordNotFound {
+		t.Errorf("CheckUserOwnership(missing) error = %v, want ErrRecordNotFound", err)
+	}
+}
+
+func TestFollowUser(t *testing.T) {
+	svc, db := newTestService(t)
+	alice := testutil.CreateUser(t, db)
+	bob := testutil.CreateUser(t, db)
+
+	tests := []struct {
+		name        string
+		followerID  uint
+		followingID uint
+		wantErr     string
+	}{
+		{name: "self follow", followerID: alice.ID, followingID: alice.ID, wantErr: ErrCannotFollowSelf.Error()},
+		{name: "missing follower", followerID: alice.ID + 100, followingID: bob.ID, wantErr: gorm.ErrRecordNotFound.Error()},
+		{name: "missing followed user", followerID: alice.ID, followingID: bob.ID + 100, wantErr: gorm.ErrRecordNotFound.Error()},
+		{name: "first follow", followerID: alice.ID, followingID: bob.ID},
+		{name: "already followed", followerID: alice.ID, followingID: bob.ID, wantErr: "user already followed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := svc.FollowUser(tt.followerID, tt.followingID)
+			if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Fatalf("FollowUser() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	if count, err := svc.GetUserFollowersCount(bob.ID); err != nil || count != 1 {
+		t.Errorf("GetUserFollowersCount() = %d, %v, want 1", count, err)
+	}
+	if count, err := svc.GetUserFollowingCount(alice.ID); err != nil || count != 1 {
+		t.Errorf("GetUserFollowingCount() = %d, %v, want 1", count, err)
+	}
+	if followers, err := svc.GetUserFollowers(bob.ID); err != nil || len(followers) != 1 {
+		t.Errorf("GetUserFollowers() = %d users, %v, want 1", len(followers), err)
+	}
+	if following, err := svc.GetUserFollowing(alice.ID); err != nil || len(following) != 1 {
+		t.Errorf("GetUserFollowing() = %d users, %v, want 1", len(following), err)
+	}
+
+	testutil.CreatePost(t, db, bob.ID)
+	if posts, total, err := svc.GetUserFollowingPosts(alice.ID, 1, 10); err != nil || total != 1 || len(posts) != 1 {
+		t.Errorf("GetUserFollowingPosts() = %d posts of %d, %v, want 1 of 1", len(posts), total, err)
+	}
+
+	if err := svc.UnfollowUser(alice.ID, bob.ID); err != nil {
+		t.Fatalf("UnfollowUser() error = %v", err)
+	}
+	if err := svc.UnfollowUser(alice.ID+100, bob.ID); err != gorm.ErrRecordNotFound {
+		t.Errorf("UnfollowUser(missing) error = %v, want ErrRecordNotFound", err)
+	}
+	if count, _ := svc.GetUserFollowersCount(bob.ID); count != 0 {
+		t.Errorf("GetUserFollowersCount() after unfollow = %d, want 0", count)
+	}
+}
+
+func TestFollowUserConcurrent(t *testing.T) {
+	svc, db := newTestService(t)
+	alice := testutil.CreateUser(t, db)
+	bob := testutil.CreateUser(t, db)
+
+	const attempts = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, attempts)
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- svc.FollowUser(alice.ID, bob.ID)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	// Callers that lose the race either see "already followed" or succeed as a no-op
+	for err := range errs {
+		if err != nil && err.Error() != "user already followed" {
+			t.Errorf("concurrent FollowUser() error = %v", err)
+		}
+	}
+	if count, _ := svc.GetUserFollowersCount(bob.ID); count != 1 {
+		t.Errorf("followers_count = %d, want 1", count)
+	}
+	if count, _ := svc.GetUserFollowingCount(alice.ID); count != 1 {
+		t.Errorf("following_count = %d, want 1", count)
+	}
+}
+
+func TestUpdateAndDeleteUser(t *testing.T) {
+	svc, db := newTestService(t)
+	user := testutil.CreateUser(t, db)
+
+	updated, err := svc.UpdateUserByIDWithSelect(user.ID, map[string]any{"username": "renamed", "role": "admin"}, nil, []string{"username"})
+	if err != nil {
+		t.Fatalf("UpdateUserByIDWithSelect() error = %v", err)
+	}
+	if updated.Username != "renamed" || updated.Role != "user" {
+		t.Errorf("got username=%q role=%q, want renamed and unchanged role", updated.Username, updated.Role)
+	}
+
+	db.Model(&models.User{}).Where("id = ?", user.ID).UpdateColumn("avatar", "")
+	if err := svc.DeleteUserByID(user.ID); err != nil {
+		t.Fatalf("DeleteUserByID() error = %v", err)
+	}
+	if err := svc.DeleteUserByID(user.ID); err != gorm.ErrRecordNotFound {
+		t.Errorf("second DeleteUserByID() error = %v, want ErrRecordNotFound", err)
+	}
+}
+
+func TestBulkAdminActions(t *testing.T) {
+	svc, db := newTestService(t)
+	admin := testutil.CreateUser(t, db, func(u *models.User) { u.Role = "admin" })
+	first := testutil.CreateUser(t, db)
+	second := testutil.CreateUser(t, db)
+
+	roleTests := []struct {
+		name    string
+		ids     []uint
+		role    string
+		want    int64
+		wantErr error
+	}{
+		{name: "invalid role", ids: []uint{first.ID}, role: "superuser", wantErr: ErrInvalidRole},
+		{name: "includes the acting admin", ids: []uint{first.ID, admin.ID}, role: "user", wantErr: ErrSelfModifyAdmin},
+		{name: "promote", ids: []uint{first.ID, second.ID}, role: "admin", want: 2},
+	}
+	for _, tt := range roleTests {
+		t.Run("role "+tt.name, func(t *testing.T) {
+			got, err := svc.BulkUpdateUserRole(tt.ids, tt.role, admin.ID)
+			if err != tt.wantErr {
+				t.Fatalf("BulkUpdateUserRole() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("BulkUpdateUserRole() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := svc.BulkSetUsersSuspended([]uint{admin.ID}, true, admin.ID); err != ErrSelfModifyAdmin {
+		t.Errorf("BulkSetUsersSuspended(self) error = %v, want ErrSelfModifyAdmin", err)
+	}
+	if got, err := svc.BulkSetUsersSuspended([]uint{first.ID}, true, admin.ID); err != nil || got != 1 {
+		t.Errorf("BulkSetUsersSuspended() = %d, %v, want 1", got, err)
+	}
+
+	users, err := svc.ExportUsers(user_repository.UserFilter{Role: "admin"})
+	if err != nil || len(users) != 3 {
+		t.Errorf("ExportUsers(admin) = %d users, %v, want 3", len(users), err)
+	}
+}
+
+func TestImportUsersInvites(t *testing.T) {
+	svc, db := newTestService(t)
+
+	result, err := svc.ImportUsers([]ImportUserRow{{Row: 1, Username: "reed", Email: "reed@example.com"}}, false, true)
+	if err != nil {
+		t.Fatalf("ImportUsers() error = %v", err)
+	}
+	if result.Created != 1 || result.Invited != 1 {
+		t.Errorf("created=%d invited=%d, want 1 and 1", result.Created, result.Invited)
+	}
+	reed, _ := svc.GetUserByUsername("reed")
+	var mail models.Job
+	if err := db.Where("type = ?", jobs.TypeSendMail).First(&mail).Error; err != nil {
+		t.Fatalf("no mail job queued for the invitee: %v", err)
+	}
+	var payload jobs.SendMail
+	json.Unmarshal([]byte(mail.Payload), &payload)
+	if payload.To != "reed@example.com" || !strings.Contains(payload.Body, "/set-password?token=") {
+		t.Errorf("invite mail = %+v, want a set-password link to reed", payload)
+	}
+	var token models.PasswordToken
+	if err := db.Where("user_id = ?", reed.ID).First(&token).Error; err != nil {
+		t.Fatalf("no password token for the invitee: %v", err)
+	}
+	if !token.ExpiresAt.After(time.Now().Add(svc.cfg.InviteExpiry - time.Minute)) {
+		t.Errorf("token expires at %v, want about %v from now", token.ExpiresAt, svc.cfg.InviteExpiry)
+	}
+}
+
+func TestSetPassword(t *testing.T) {
+	svc, db := newTestService(t)
+	user := testutil.CreateUser(t, db)
+	db.Create(&models.PasswordToken{TokenHash: hashPasswordToken("invite-token"), UserID: user.ID, ExpiresAt: time.Now().Add(time.Hour)})
+
+	if err := svc.SetPassword("wrong-token", "new-hash"); err != ErrInvalidPasswordToken {
+		t.Errorf("SetPassword(wrong token) error = %v, want ErrInvalidPasswordToken", err)
+	}
+	if err := svc.SetPassword("invite-token", "new-hash"); err != nil {
+		t.Fatalf("SetPassword() error = %v", err)
+	}
+	var stored models.User
+	db.First(&stored, user.ID)
+	if stored.Password != "new-hash" {
+		t.Errorf("password = %q, want new-hash", stored.Password)
+	}
+	if err := svc.SetPassword("invite-token", "other-hash"); err != ErrInvalidPasswordToken {
+		t.Errorf("SetPassword(used token) error = %v, want ErrInvalidPasswordToken", err)
+	}
+}
+
+func TestImportUsers(t *testing.T) {
+	svc, db := newTestService(t)
+	existing := testutil.CreateUser(t, db)
+
+	rows := []ImportUserRow{
+		{Row: 1, Username: "fern", Email: "fern@example.com", Password: "Passw0rd!"},
+		{Row: 2, Username: "moss", Email: "boss@example.com", Password: "Passw0rd!"},
+		{Row: 3, Username: existing.Username, Email: "taken@example.com", Password: "Passw0rd!"},
+		{Row: 4, Username: "ivy", Email: "fern@example.com", Password: "Passw0rd!"},
+		{Row: 5, Username: "Bad Name", Email: "not-an-email", Password: "weak"},
+		{Row: 6, Username: "reed", Email: "reed@example.com"},
+	}
+
+	dry, err := svc.ImportUsers(append([]ImportUserRow(nil), rows...), true, false)
+	if err != nil {
+		t.Fatalf("ImportUsers(dry run) error = %v", err)
+	}
+	if dry.Valid != 2 || dry.Created != 0 {
+		t.Errorf("dry run valid=%d created=%d, want 2 and 0", dry.Valid, dry.Created)
+	}
+	failedRows := map[int]bool{}
+	for _, rowErr := range dry.Errors {
+		failedRows[rowErr.Row] = true
+	}
+	for _, row := range []int{3, 4, 5, 6} {
+		if !failedRows[row] {
+			t.Errorf("row %d was not reported as invalid", row)
+		}
+	}
+
+	result, err := svc.ImportUsers(rows, false, false)
+	if err != nil {
+		t.Fatalf("ImportUsers() error = %v", err)
+	}
+	if result.Created != 2 {
+		t.Errorf("created = %d, want 2", result.Created)
+	}
+	moss, err := svc.GetUserByUsername("moss")
+	if err != nil || moss.Role != "admin" {
+		t.Errorf("whitelisted import = %v, %v, want an admin", moss, err)
+	}
+}
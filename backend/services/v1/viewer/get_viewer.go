@@ -0,0 +1,57 @@
+package viewer_services
+
+import (
+	public_dto "flower-backend/dto/public"
+	"flower-backend/models"
+
+	"go.uber.org/zap"
+)
+
+// ForPosts
+func (s *viewerService) ForPosts(viewerID uint, posts []models.Post) (*public_dto.ViewerState, error) {
+	if viewerID == 0 {
+		return nil, nil
+	}
+
+	postIDs := make([]uint, 0, len(posts))
+	authorIDs := make([]uint, 0, len(posts))
+	seenAuthors := make(map[uint]bool, len(posts))
+	for _, post := range posts {
+		postIDs = append(postIDs, post.ID)
+		if !seenAuthors[post.UserID] {
+			seenAuthors[post.UserID] = true
+			authorIDs = append(authorIDs, post.UserID)
+		}
+	}
+
+	liked, err := s.postRepo.GetLikedPostIDs(viewerID, postIDs)
+	if err != nil {
+		s.logger.Error("failed to get liked posts for viewer", zap.Error(err))
+		return nil, err
+	}
+	following, followedBy, err := s.userRepo.GetFollowRelations(viewerID, authorIDs)
+	if err != nil {
+		s.logger.Error("failed to get follow relations for viewer", zap.Error(err))
+		return nil, err
+	}
+	return &public_dto.ViewerState{LikedPosts: liked, Following: following, FollowedBy: followedBy}, nil
+}
+
+// ForUsers
+func (s *viewerService) ForUsers(viewerID uint, users []models.User) (*public_dto.ViewerState, error) {
+	if viewerID == 0 {
+		return nil, nil
+	}
+
+	userIDs := make([]uint, 0, len(users))
+	for _, user := range users {
+		userIDs = append(userIDs, user.ID)
+	}
+
+	following, followedBy, err := s.userRepo.GetFollowRelations(viewerID, userIDs)
+	if err != nil {
+		s.logger.Error("failed to get follow relations for viewer", zap.Error(err))
+		return nil, err
+	}
+	return &public_dto.ViewerState{Following: following, FollowedBy: followedBy}, nil
+}
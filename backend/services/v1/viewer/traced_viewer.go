@@ -0,0 +1,46 @@
+package viewer_services
+
+import (
+	"context"
+	public_dto "flower-backend/dto/public"
+	"flower-backend/models"
+	"flower-backend/tracing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("flower-backend/services/v1/viewer")
+
+// tracedViewerService starts a span around every call and runs the wrapped
+// service under it, so repository and storage spans nest beneath.
+type tracedViewerService struct {
+	ViewerService
+	ctx context.Context
+}
+
+func newTracedViewerService(svc ViewerService) ViewerService {
+	return &tracedViewerService{ViewerService: svc, ctx: context.Background()}
+}
+
+func (s *tracedViewerService) WithContext(ctx context.Context) ViewerService {
+	return &tracedViewerService{ViewerService: s.ViewerService, ctx: ctx}
+}
+
+// start opens the span for method and returns the wrapped service bound to it.
+func (s *tracedViewerService) start(method string) (ViewerService, trace.Span) {
+	ctx, span := tracer.Start(s.ctx, "ViewerService."+method)
+	return s.ViewerService.WithContext(ctx), span
+}
+
+func (s *tracedViewerService) ForPosts(viewerID uint, posts []models.Post) (_ *public_dto.ViewerState, err error) {
+	svc, span := s.start("ForPosts")
+	defer tracing.End(span, &err)
+	return svc.ForPosts(viewerID, posts)
+}
+
+func (s *tracedViewerService) ForUsers(viewerID uint, users []models.User) (_ *public_dto.ViewerState, err error) {
+	svc, span := s.start("ForUsers")
+	defer tracing.End(span, &err)
+	return svc.ForUsers(viewerID, users)
+}
@@ -0,0 +1,41 @@
+package viewer_services
+
+import (
+	"context"
+	"flower-backend/config"
+	public_dto "flower-backend/dto/public"
+	"flower-backend/log"
+	"flower-backend/models"
+	post_repository "flower-backend/repositories/v1/post"
+	user_repository "flower-backend/repositories/v1/user"
+
+	"go.uber.org/zap"
+)
+
+// ViewerService loads the viewer-relative flags for a page of posts or users.
+// Each call issues at most one query per relation, whatever the page size.
+type ViewerService interface {
+	// WithContext returns a service whose calls run under ctx
+	WithContext(ctx context.Context) ViewerService
+	ForPosts(viewerID uint, posts []models.Post) (*public_dto.ViewerState, error)
+	ForUsers(viewerID uint, users []models.User) (*public_dto.ViewerState, error)
+}
+
+type viewerService struct {
+	postRepo post_repository.PostRepository
+	userRepo user_repository.UserRepository
+	cfg      *config.Config
+	logger   *zap.SugaredLogger
+}
+
+func NewViewerService(cfg *config.Config, postRepo post_repository.PostRepository, userRepo user_repository.UserRepository, logger *zap.SugaredLogger) ViewerService {
+	return newTracedViewerService(&viewerService{postRepo: postRepo, userRepo: userRepo, cfg: cfg, logger: logger})
+}
+
+func (s *viewerService) WithContext(ctx context.Context) ViewerService {
+	clone := *s
+	clone.logger = log.SugarFromContext(ctx, s.logger)
+	clone.postRepo = s.postRepo.WithContext(ctx)
+	clone.userRepo = s.userRepo.WithContext(ctx)
+	return &clone
+}
@@ -0,0 +1,63 @@
+package viewer_services
+
+import (
+	"flower-backend/models"
+	post_repository "flower-backend/repositories/v1/post"
+	user_repository "flower-backend/repositories/v1/user"
+	"flower-backend/testutil"
+	"testing"
+)
+
+func TestViewerService(t *testing.T) {
+	db := testutil.NewDB(t)
+	cfg := testutil.Config()
+	svc := NewViewerService(cfg,
+		post_repository.NewPostRepository(db, cfg, nil, testutil.Logger()),
+		user_repository.NewUserRepository(db, cfg, nil, testutil.Logger()),
+		testutil.Logger())
+
+	viewer := testutil.CreateUser(t, db)
+	friend := testutil.CreateUser(t, db)
+	stranger := testutil.CreateUser(t, db)
+	liked := testutil.CreatePost(t, db, friend.ID)
+	other := testutil.CreatePost(t, db, stranger.ID)
+	testutil.CreateLike(t, db, liked.ID, viewer.ID)
+	testutil.CreateFollow(t, db, viewer.ID, friend.ID)
+	testutil.CreateFollow(t, db, stranger.ID, viewer.ID)
+
+	t.Run("anonymous viewer", func(t *testing.T) {
+		state, err := svc.ForPosts(0, []models.Post{*liked})
+		if err != nil || state != nil {
+			t.Errorf("ForPosts(0) = %v, %v, want nil state", state, err)
+		}
+	})
+
+	t.Run("ForPosts", func(t *testing.T) {
+		state, err := svc.ForPosts(viewer.ID, []models.Post{*liked, *other})
+		if err != nil {
+			t.Fatalf("ForPosts() error = %v", err)
+		}
+		if !state.LikedPosts[liked.ID] || state.LikedPosts[other.ID] {
+			t.Errorf("LikedPosts = %v, want only %d", state.LikedPosts, liked.ID)
+		}
+		if !state.Following[friend.ID] || state.Following[stranger.ID] {
+			t.Errorf("Following = %v, want only %d", state.Following, friend.ID)
+		}
+		if !state.FollowedBy[stranger.ID] || state.FollowedBy[friend.ID] {
+			t.Errorf("FollowedBy = %v, want only %d", state.FollowedBy, stranger.ID)
+		}
+	})
+
+	t.Run("ForUsers", func(t *testing.T) {
+		state, err := svc.ForUsers(viewer.ID, []models.User{*friend, *stranger})
+		if err != nil {
+			t.Fatalf("ForUsers() error = %v", err)
+		}
+		if !state.Following[friend.ID] || !state.FollowedBy[stranger.ID] {
+			t.Errorf("ForUsers() = following %v followed by %v", state.Following, state.FollowedBy)
+		}
+		if state.LikedPosts != nil {
+			t.Errorf("ForUsers() LikedPosts = %v, want nil", state.LikedPosts)
+		}
+	})
+}
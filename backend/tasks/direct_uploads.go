@@ -0,0 +1,19 @@
+package tasks
+
+import (
+	"context"
+	post_repository "flower-backend/repositories/v1/post"
+	"time"
+)
+
+// directUploadRetention is how long a signed direct upload can still be
+// turned into a post.
+const directUploadRetention = 24 * time.Hour
+
+// PruneDirectUploads returns the task that forgets old direct uploads and
+// deletes the images no post ended up using.
+func PruneDirectUploads(repo post_repository.PostRepository) func(ctx context.Context) (int64, error) {
+	return func(ctx context.Context) (int64, error) {
+		return repo.WithContext(ctx).DeleteDirectUploadsBefore(time.Now().Add(-directUploadRetention))
+	}
+}
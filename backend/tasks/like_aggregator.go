@@ -0,0 +1,26 @@
+package tasks
+
+import (
+	"context"
+	post_repository "flower-backend/repositories/v1/post"
+	"time"
+)
+
+// AggregateLikes returns the task that keeps the hourly like buckets behind
+// the trending and popular endpoints up to date. It runs every interval and
+// recomputes the buckets of that interval plus the current hour, so likes
+// committed late are still counted. The window is measured back from now, the
+// clock the likes were stamped with.
+func AggregateLikes(repo post_repository.PostRepository, interval time.Duration, now func() time.Time) func(ctx context.Context) (int64, error) {
+	return func(ctx context.Context) (int64, error) {
+		return 0, repo.WithContext(ctx).RebuildLikeBuckets(now().Add(-interval - time.Hour))
+	}
+}
+
+// RebuildLikes returns the task that rebuilds every like bucket, so likes
+// removed from older posts drop out of them.
+func RebuildLikes(repo post_repository.PostRepository) func(ctx context.Context) (int64, error) {
+	return func(ctx context.Context) (int64, error) {
+		return 0, repo.WithContext(ctx).RebuildLikeBuckets(time.Time{})
+	}
+}
@@ -0,0 +1,21 @@
+package tasks
+
+import (
+	"context"
+	post_repository "flower-backend/repositories/v1/post"
+	"time"
+)
+
+// publishBatch caps how many scheduled posts one run publishes; the rest wait
+// for the next minute.
+const publishBatch = 100
+
+// PublishScheduledPosts returns the task that publishes the scheduled posts
+// whose publish time has passed by now. Their authors' followers are notified
+// by a job queued with each post.
+func PublishScheduledPosts(repo post_repository.PostRepository, now func() time.Time) func(ctx context.Context) (int64, error) {
+	return func(ctx context.Context) (int64, error) {
+		published, err := repo.WithContext(ctx).PublishDue(now(), publishBatch)
+		return int64(len(published)), err
+	}
+}
@@ -0,0 +1,26 @@
+package tasks
+
+import (
+	post_repository "flower-backend/repositories/v1/post"
+	user_repository "flower-backend/repositories/v1/user"
+
+	"go.uber.org/zap"
+)
+
+// RecountCounters recomputes the denormalized like, follower, following and
+// post counters from their join tables. Use it to repair drift, e.g. after
+// manual edits to the database or when the counter columns are first added.
+func RecountCounters(postRepo post_repository.PostRepository, userRepo user_repository.UserRepository, logger *zap.Logger) error {
+	posts, err := postRepo.RecountLikes()
+	if err != nil {
+		logger.Error("recount of post likes failed", zap.Error(err))
+		return err
+	}
+	users, err := userRepo.RecountCounters()
+	if err != nil {
+		logger.Error("recount of user counters failed", zap.Error(err))
+		return err
+	}
+	logger.Info("counters recomputed", zap.Int64("posts", posts), zap.Int64("users", users))
+	return nil
+}
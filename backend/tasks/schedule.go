@@ -0,0 +1,36 @@
+package tasks
+
+import (
+	"flower-backend/config"
+	"flower-backend/metrics"
+	post_repository "flower-backend/repositories/v1/post"
+	user_repository "flower-backend/repositories/v1/user"
+	"flower-backend/scheduler"
+	"time"
+)
+
+// Schedule adds the periodic maintenance tasks to s.
+func Schedule(s *scheduler.Scheduler, cfg *config.Config, m *metrics.Metrics, postRepo post_repository.PostRepository, userRepo user_repository.UserRepository) error {
+	interval := cfg.LikeAggregateInterval
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+
+	for _, t := range []struct {
+		name string
+		spec string
+		task scheduler.Task
+	}{
+		// expired refresh tokens would otherwise pile up
+		{"tokens.cleanup", "0 * * * *", CleanupExpiredTokens(userRepo, m)},
+		{"likes.aggregate", "@every " + interval.String(), AggregateLikes(postRepo, interval, s.Now)},
+		{"likes.rebuild", "15 4 * * *", RebuildLikes(postRepo)},
+		{"uploads.prune", "30 * * * *", PruneDirectUploads(postRepo)},
+		{"posts.publish", "* * * * *", PublishScheduledPosts(postRepo, s.Now)},
+	} {
+		if err := s.Add(t.name, t.spec, t.task); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,26 +1,23 @@
 package tasks
 
 import (
+	"context"
+	"flower-backend/metrics"
 	user_repository "flower-backend/repositories/v1/user"
 	"time"
-
-	"go.uber.org/zap"
 )
 
-// StartTokenCleanup launches a background ticker to prune expired refresh tokens.
-// It runs every hour and logs the count of deleted tokens.
-func StartTokenCleanup(repo user_repository.UserRepository, logger *zap.Logger) {
-	go func() {
-		ticker := time.NewTicker(1 * time.Hour)
-		defer ticker.Stop()
-
-		for range ticker.C {
-			now := time.Now()
-			if deleted, err := repo.DeleteExpiredTokens(now); err != nil {
-				logger.Error("token cleanup failed", zap.Error(err))
-			} else if deleted > 0 {
-				logger.Info("token cleanup removed expired tokens", zap.Int64("count", deleted))
-			}
+// CleanupExpiredTokens returns the task that prunes expired refresh tokens.
+// Each run is also recorded in the token cleanup metrics.
+func CleanupExpiredTokens(repo user_repository.UserRepository, m *metrics.Metrics) func(ctx context.Context) (int64, error) {
+	return func(ctx context.Context) (int64, error) {
+		deleted, err := repo.WithContext(ctx).DeleteExpiredTokens(time.Now())
+		if err != nil {
+			m.TokenCleanupRuns.WithLabelValues("error").Inc()
+			return 0, err
 		}
-	}()
+		m.TokenCleanupRuns.WithLabelValues("success").Inc()
+		m.TokenCleanupDeleted.Add(float64(deleted))
+		return deleted, nil
+	}
 }
@@ -0,0 +1,44 @@
+package testutil
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Clock is a settable time source for code that takes a clock, or reads the
+// database's through NowFunc.
+type Clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewClock returns a clock stopped at now.
+func NewClock(now time.Time) *Clock {
+	return &Clock{now: now}
+}
+
+// Now returns the clock's current time.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Advance moves the clock forward by d.
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// NewDBWithClock is NewDB with its NowFunc reading a clock stopped at now.
+func NewDBWithClock(t testing.TB, now time.Time) (*gorm.DB, *Clock) {
+	t.Helper()
+	db := NewDB(t)
+	clock := NewClock(now)
+	db.Config.NowFunc = clock.Now
+	return db, clock
+}
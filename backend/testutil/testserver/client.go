@@ -0,0 +1,246 @@
+package testserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"testing"
+)
+
+// Client is one browser session against a Server. It keeps cookies, echoes
+// the CSRF cookie in the X-CSRF-Token header and sends the access token from
+// the last Register or Login.
+type Client struct {
+	t      *testing.T
+	server *Server
+	http   *http.Client
+
+	AccessToken string
+	UserID      uint
+	// Header is added to every request
+	Header http.Header
+}
+
+// Response is a recorded HTTP response.
+type Response struct {
+	*http.Response
+	Body []byte
+}
+
+// JSON decodes the body into a map, failing the test if it is not JSON.
+func (r *Response) JSON(t *testing.T) map[string]any {
+	t.Helper()
+	var body map[string]any
+	if err := json.Unmarshal(r.Body, &body); err != nil {
+		t.Fatalf("decode %s response: %v: %s", r.Request.URL.Path, err, r.Body)
+	}
+	return body
+}
+
+// Decode unmarshals the body into v, failing the test on error.
+func (r *Response) Decode(t *testing.T, v any) {
+	t.Helper()
+	if err := json.Unmarshal(r.Body, v); err != nil {
+		t.Fatalf("decode %s response: %v: %s", r.Request.URL.Path, err, r.Body)
+	}
+}
+
+// NewClient returns a client with an empty cookie jar. Redirects are not
+// followed so OAuth redirects can be inspected.
+func (s *Server) NewClient(t *testing.T) *Client {
+	t.Helper()
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatalf("create cookie jar: %v", err)
+	}
+	return &Client{
+		t:      t,
+		server: s,
+		http: &http.Client{
+			Jar: jar,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		Header: http.Header{},
+	}
+}
+
+// Cookie returns the value of the named cookie held for the server.
+func (c *Client) Cookie(name string) string {
+	u, _ := url.Parse(c.server.URL)
+	for _, cookie := range c.http.Jar.Cookies(u) {
+		if cookie.Name == name {
+			return cookie.Value
+		}
+	}
+	return ""
+}
+
+// SetCookie stores a cookie for the server, as a browser would after a redirect.
+func (c *Client) SetCookie(name, value string) {
+	u, _ := url.Parse(c.server.URL)
+	c.http.Jar.SetCookies(u, []*http.Cookie{{Name: name, Value: value, Path: "/"}})
+}
+
+// CSRFToken returns the session's CSRF token, fetching one on first use.
+func (c *Client) CSRFToken() string {
+	c.t.Helper()
+	if token := c.Cookie("csrf_token"); token != "" {
+		return token
+	}
+	c.Get("/api/v1/health")
+	token := c.Cookie("csrf_token")
+	if token == "" {
+		c.t.Fatal("server did not issue a csrf_token cookie")
+	}
+	return token
+}
+
+// Do sends a request with the given body and content type.
+func (c *Client) Do(method, path string, body io.Reader, contentType string) *Response {
+	c.t.Helper()
+	req, err := http.NewRequest(method, c.server.URL+path, body)
+	if err != nil {
+		c.t.Fatalf("build %s %s: %v", method, path, err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if method != http.MethodGet && method != http.MethodHead && method != http.MethodOptions {
+		req.Header.Set("X-CSRF-Token", c.CSRFToken())
+	}
+	if c.AccessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+	}
+	for name, values := range c.Header {
+		req.Header[name] = values
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		c.t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		c.t.Fatalf("read %s %s: %v", method, path, err)
+	}
+	return &Response{Response: resp, Body: data}
+}
+
+// Get sends a GET request.
+func (c *Client) Get(path string) *Response {
+	c.t.Helper()
+	return c.Do(http.MethodGet, path, nil, "")
+}
+
+// PostJSON sends v as a JSON body; a nil v sends no body.
+func (c *Client) PostJSON(path string, v any) *Response {
+	c.t.Helper()
+	return c.sendJSON(http.MethodPost, path, v)
+}
+
+// Delete sends a DELETE request.
+func (c *Client) Delete(path string) *Response {
+	c.t.Helper()
+	return c.Do(http.MethodDelete, path, nil, "")
+}
+
+func (c *Client) sendJSON(method, path string, v any) *Response {
+	c.t.Helper()
+	if v == nil {
+		return c.Do(method, path, nil, "")
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		c.t.Fatalf("encode %s body: %v", path, err)
+	}
+	return c.Do(method, path, bytes.NewReader(data), "application/json")
+}
+
+// PostForm sends a multipart form. Each entry in files is uploaded under its
+// field name with the key as filename.
+func (c *Client) PostForm(path string, fields map[string]string, files map[string][]byte) *Response {
+	c.t.Helper()
+	return c.sendForm(http.MethodPost, path, fields, files)
+}
+
+// PutForm is PostForm with PUT.
+func (c *Client) PutForm(path string, fields map[string]string, files map[string][]byte) *Response {
+	c.t.Helper()
+	return c.sendForm(http.MethodPut, path, fields, files)
+}
+
+func (c *Client) sendForm(method, path string, fields map[string]string, files map[string][]byte) *Response {
+	c.t.Helper()
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	for name, value := range fields {
+		if err := w.WriteField(name, value); err != nil {
+			c.t.Fatalf("write field %s: %v", name, err)
+		}
+	}
+	for name, content := range files {
+		part, err := w.CreateFormFile(name, name+".png")
+		if err != nil {
+			c.t.Fatalf("create file %s: %v", name, err)
+		}
+		part.Write(content)
+	}
+	if err := w.Close(); err != nil {
+		c.t.Fatalf("close form: %v", err)
+	}
+	return c.Do(method, path, &body, w.FormDataContentType())
+}
+
+// Register signs up a new account and keeps its access token.
+func (c *Client) Register(username, email, password string) *Response {
+	c.t.Helper()
+	resp := c.PostJSON("/api/v1/auth/register", map[string]string{
+		"username": username,
+		"email":    email,
+		"password": password,
+	})
+	c.keepSession(resp)
+	return resp
+}
+
+// Login signs in and keeps the access token.
+func (c *Client) Login(email, password string) *Response {
+	c.t.Helper()
+	resp := c.PostJSON("/api/v1/auth/login", map[string]string{
+		"email":    email,
+		"password": password,
+	})
+	c.keepSession(resp)
+	return resp
+}
+
+// MustRegister registers an account and fails the test unless it succeeds.
+func (c *Client) MustRegister(username, email, password string) {
+	c.t.Helper()
+	if resp := c.Register(username, email, password); resp.StatusCode != http.StatusOK {
+		c.t.Fatalf("register %s: status %d: %s", username, resp.StatusCode, resp.Body)
+	}
+}
+
+func (c *Client) keepSession(resp *Response) {
+	c.t.Helper()
+	if resp.StatusCode != http.StatusOK {
+		return
+	}
+	var body struct {
+		AccessToken string `json:"accessToken"`
+		User        struct {
+			ID uint `json:"id"`
+		} `json:"user"`
+	}
+	resp.Decode(c.t, &body)
+	c.AccessToken = body.AccessToken
+	c.UserID = body.User.ID
+}
@@ -0,0 +1,161 @@
+package testserver
+
+import (
+	"context"
+	"flower-backend/libs"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// StorageURL is the base of URLs handed out by Storage. Its last path
+// segment mirrors the Cloudinary folder so libs.ExtractPublicId round-trips.
+const StorageURL = "https://images.test/flower-sharing/"
+
+// Storage is an in-memory libs.Storage.
+type Storage struct {
+	mu        sync.Mutex
+	objects   map[string][]byte
+	pingErr   error
+	uploadErr error
+	onUpload  func()
+}
+
+// NewStorage returns an empty store.
+func NewStorage() *Storage {
+	return &Storage{objects: map[string][]byte{}}
+}
+
+func (s *Storage) Upload(_ context.Context, buffer []byte, publicId string) (string, error) {
+	s.mu.Lock()
+	onUpload := s.onUpload
+	s.mu.Unlock()
+	if onUpload != nil {
+		onUpload()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.uploadErr != nil {
+		return "", s.uploadErr
+	}
+	s.objects["flower-sharing/"+publicId] = append([]byte(nil), buffer...)
+	return StorageURL + publicId + ".png", nil
+}
+
+func (s *Storage) Delete(_ context.Context, publicId string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.objects, publicId)
+	return nil
+}
+
+// SignUpload hands out a PUT to the fake upload endpoint. Tests stand in for
+// the client by calling Upload with the same public ID.
+func (s *Storage) SignUpload(_ context.Context, publicId string, expiresAt time.Time) (*libs.SignedUpload, error) {
+	return &libs.SignedUpload{
+		URL:       StorageURL + publicId + "?expires=" + fmt.Sprint(expiresAt.Unix()),
+		Method:    "PUT",
+		Headers:   map[string]string{"Content-Type": "image/png"},
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+func (s *Storage) Stat(_ context.Context, publicId string) (*libs.Asset, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	object, ok := s.objects["flower-sharing/"+publicId]
+	if !ok {
+		return nil, libs.ErrAssetNotFound
+	}
+	// like a real backend, the format comes from the stored bytes
+	format := strings.TrimPrefix(http.DetectContentType(object), "image/")
+	return &libs.Asset{PublicID: publicId, URL: StorageURL + publicId + ".png", Bytes: int64(len(object)), Format: format}, nil
+}
+
+func (s *Storage) Ping(context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.pingErr
+}
+
+// OnUpload runs fn at the start of every Upload, standing in for writes
+// other requests make while an upload is in flight.
+func (s *Storage) OnUpload(fn func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onUpload = fn
+}
+
+// SetUnreachable makes Ping fail with err, or succeed again when err is nil.
+func (s *Storage) SetUnreachable(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pingErr = err
+}
+
+// FailUploads makes Upload fail with err, or succeed again when err is nil.
+func (s *Storage) FailUploads(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.uploadErr = err
+}
+
+// Len reports how many objects are stored.
+func (s *Storage) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.objects)
+}
+
+// OAuth hands out stub providers whose authorization codes map to profiles
+// registered with AddProfile.
+type OAuth struct {
+	mu       sync.Mutex
+	profiles map[string]libs.OAuthProfile
+}
+
+// NewOAuth returns a stub with no known codes.
+func NewOAuth() *OAuth {
+	return &OAuth{profiles: map[string]libs.OAuthProfile{}}
+}
+
+// AddProfile makes code exchange to profile for every provider.
+func (o *OAuth) AddProfile(code string, profile libs.OAuthProfile) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.profiles[code] = profile
+}
+
+func (o *OAuth) provider(name string) (libs.OAuthProvider, error) {
+	return &oauthProvider{name: name, stub: o}, nil
+}
+
+type oauthProvider struct {
+	name string
+	stub *OAuth
+}
+
+func (p *oauthProvider) AuthCodeURL(state string) string {
+	return fmt.Sprintf("https://oauth.test/%s/authorize?state=%s", p.name, url.QueryEscape(state))
+}
+
+func (p *oauthProvider) Exchange(_ context.Context, code string) (*oauth2.Token, error) {
+	p.stub.mu.Lock()
+	defer p.stub.mu.Unlock()
+	if _, ok := p.stub.profiles[code]; !ok {
+		return nil, fmt.Errorf("unknown authorization code %q", code)
+	}
+	return &oauth2.Token{AccessToken: code}, nil
+}
+
+func (p *oauthProvider) FetchProfile(_ context.Context, token *oauth2.Token) (*libs.OAuthProfile, error) {
+	p.stub.mu.Lock()
+	defer p.stub.mu.Unlock()
+	profile := p.stub.profiles[token.AccessToken]
+	return &profile, nil
+}
@@ -0,0 +1,147 @@
+// Package testserver runs the v1 router over httptest with a SQLite
+// database, an in-memory image store, a fixed clock and stub OAuth providers,
+// so HTTP flows can be exercised end to end.
+package testserver
+
+import (
+	"flower-backend/app"
+	"flower-backend/cache"
+	"flower-backend/config"
+	"flower-backend/health"
+	"flower-backend/jobs"
+	"flower-backend/libs"
+	"flower-backend/metrics"
+	"flower-backend/middlewares"
+	"flower-backend/migrations"
+	post_repository "flower-backend/repositories/v1/post"
+	user_repository "flower-backend/repositories/v1/user"
+	v1Routes "flower-backend/routes/v1"
+	post_services "flower-backend/services/v1/post"
+	"flower-backend/testutil"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Server is a running test instance of the API.
+type Server struct {
+	*httptest.Server
+
+	DB      *gorm.DB
+	Config  *config.Config
+	Clock   *testutil.Clock
+	Storage *Storage
+	OAuth   *OAuth
+	Health  *health.Checker
+	// Cache is nil unless WithCache is given, so tests see their writes
+	// without invalidation
+	Cache cache.Cache
+	// Jobs is registered but not started; tests start it when they need it
+	Jobs *jobs.Queue
+	// Metrics records only this server's requests and jobs
+	Metrics *metrics.Metrics
+
+	// start is where Clock begins
+	start time.Time
+}
+
+// Option adjusts how New builds a server.
+type Option func(*Server)
+
+// WithDB serves from db instead of a fresh migrated SQLite database. The
+// server's clock becomes db's NowFunc.
+func WithDB(db *gorm.DB) Option {
+	return func(s *Server) { s.DB = db }
+}
+
+// WithCache serves post and user reads through store.
+func WithCache(store cache.Cache) Option {
+	return func(s *Server) { s.Cache = store }
+}
+
+// WithClock starts the server's clock at now.
+func WithClock(now time.Time) Option {
+	return func(s *Server) { s.start = now }
+}
+
+// New starts a server for the test and shuts it down when the test ends.
+// Every dependency hangs off the server's own app.App, so tests may run in
+// parallel.
+func New(t *testing.T, opts ...Option) *Server {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	s := &Server{
+		start:   time.Now(),
+		Storage: NewStorage(),
+		OAuth:   NewOAuth(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	// GORM stamps created_at itself, so it must read the same clock
+	if s.DB == nil {
+		s.DB, s.Clock = testutil.NewDBWithClock(t, s.start)
+	} else {
+		s.Clock = testutil.NewClock(s.start)
+		s.DB.Config.NowFunc = s.Clock.Now
+	}
+	s.Config = testutil.Config()
+	s.Config.CloudinaryFolder = "flower-sharing"
+	s.Config.WhiteListAdminEmails = []string{"admin@example.com"}
+	s.Config.AllowOrigins = []string{"http://localhost:3000"}
+
+	logger := zap.NewNop()
+	migrator, err := migrations.NewMigrator(s.DB, logger)
+	if err != nil {
+		t.Fatalf("load migrations: %v", err)
+	}
+	s.Health = health.NewChecker(
+		health.Database(s.DB),
+		health.Pool(s.DB),
+		health.Storage(s.Storage, 0),
+		health.Migrations(migrator),
+	)
+	s.Metrics = metrics.New()
+	s.Jobs = jobs.New(s.DB, s.Config, logger, s.Metrics)
+	if err := jobs.RegisterDefaults(s.Jobs, s.Config, s.Storage); err != nil {
+		t.Fatalf("register jobs: %v", err)
+	}
+	posts := post_repository.NewPostRepository(s.DB, s.Config, s.Cache, logger.Sugar())
+	post_services.RegisterJobs(s.Jobs, s.DB, s.Config, posts, logger.Sugar(), s.Storage)
+	a := &app.App{
+		Config:        s.Config,
+		Logger:        logger,
+		DB:            s.DB,
+		Cache:         s.Cache,
+		JWT:           libs.NewJWT(s.Config, logger.Sugar(), s.Clock.Now),
+		Storage:       s.Storage,
+		OAuthProvider: s.OAuth.provider,
+		Users:         user_repository.NewUserRepository(s.DB, s.Config, s.Cache, logger.Sugar()),
+		Posts:         posts,
+		Now:           s.Clock.Now,
+		Health:        s.Health,
+		Jobs:          s.Jobs,
+		Metrics:       s.Metrics,
+	}
+
+	// Mirrors the request pipeline in main, without rate limiting and access logs
+	r := gin.New()
+	r.Use(middlewares.Tracing(s.Config.TracingServiceName))
+	r.Use(middlewares.RequestID(logger))
+	r.Use(middlewares.Metrics(s.Metrics))
+	r.Use(middlewares.Helmet(s.Config))
+	r.Use(middlewares.XSSProtection(logger))
+	r.Use(middlewares.ValidateFormInput())
+	r.Use(middlewares.CSRFProtection(s.Config, logger, s.Metrics))
+	r.Use(middlewares.TraceController())
+	v1Routes.Routes(r, a)
+
+	s.Server = httptest.NewServer(r)
+	t.Cleanup(s.Close)
+	return s
+}
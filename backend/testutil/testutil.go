@@ -0,0 +1,173 @@
+// Package testutil provides a migrated SQLite database and fixtures for
+// repository and service integration tests.
+package testutil
+
+import (
+	"flower-backend/config"
+	"flower-backend/database"
+	"flower-backend/migrations"
+	"flower-backend/models"
+	"fmt"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// NewDB opens a fresh SQLite database in the test's temp directory and
+// applies every migration, so tests run against the production schema.
+// The database is closed when the test ends.
+func NewDB(t testing.TB) *gorm.DB {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "test.db")
+	db, err := gorm.Open(sqlite.Open(database.SQLiteDSN(path)), &gorm.Config{
+		Logger: gormlogger.Default.LogMode(gormlogger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("open test database: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	migrator, err := migrations.NewMigrator(db, zap.NewNop())
+	if err != nil {
+		t.Fatalf("load migrations: %v", err)
+	}
+	if _, err := migrator.Up(); err != nil {
+		t.Fatalf("apply migrations: %v", err)
+	}
+	return db
+}
+
+// Config returns the defaults LoadConfig would produce, without reading the
+// environment. Tests may adjust fields before passing it on.
+func Config() *config.Config {
+	return &config.Config{
+		GO_ENV:              "test",
+		DBDriver:            database.DriverSQLite,
+		JWTSecret:           "test-secret",
+		JWTRefreshSecret:    "test-refresh-secret",
+		JWTExpiry:           15 * time.Minute,
+		JWTRefreshExpiry:    7 * 24 * time.Hour,
+		FrontendURL:         "http://localhost:3000",
+		InviteExpiry:        72 * time.Hour,
+		StatsCacheTTL:       time.Minute,
+		CacheTTL:            time.Minute,
+		FeedWeightRecency:   1.0,
+		FeedWeightVelocity:  0.5,
+		FeedWeightAffinity:  0.3,
+		FeedWeightFollowed:  0.5,
+		FeedSeenFactor:      0.3,
+		FeedRecencyHalfLife: 24 * time.Hour,
+		FeedCandidateWindow: 168 * time.Hour,
+		FeedVelocityWindow:  24 * time.Hour,
+		FeedAffinityWindow:  720 * time.Hour,
+		FeedMaxCandidates:   300,
+		// jobs are picked up within a test's patience, not a second later
+		JobPollInterval: 10 * time.Millisecond,
+	}
+}
+
+// Logger returns a logger that discards everything.
+func Logger() *zap.SugaredLogger {
+	return zap.NewNop().Sugar()
+}
+
+var sequence atomic.Int64
+
+// CreateUser inserts a user with a unique username and email.
+func CreateUser(t testing.TB, db *gorm.DB, modify ...func(*models.User)) *models.User {
+	t.Helper()
+
+	n := sequence.Add(1)
+	user := &models.User{
+		Username: fmt.Sprintf("user%d", n),
+		Email:    fmt.Sprintf("user%d@example.com", n),
+		Password: "hashed",
+		Role:     "user",
+		Provider: "local",
+	}
+	for _, fn := range modify {
+		fn(user)
+	}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatalf("create user fixture: %v", err)
+	}
+	return user
+}
+
+// CreatePost inserts a post by userID and, when it is published, bumps the
+// author's posts_count the way PostRepository.Create does.
+func CreatePost(t testing.TB, db *gorm.DB, userID uint, modify ...func(*models.Post)) *models.Post {
+	t.Helper()
+
+	n := sequence.Add(1)
+	post := &models.Post{
+		Title:   fmt.Sprintf("Post %d", n),
+		Content: fmt.Sprintf("Content of post %d", n),
+		UserID:  userID,
+	}
+	for _, fn := range modify {
+		fn(post)
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(post).Error; err != nil {
+			return err
+		}
+		// the column defaults to published
+		if post.Status != "" && post.Status != models.PostPublished {
+			return nil
+		}
+		return tx.Model(&models.User{}).Where("id = ?", userID).
+			UpdateColumn("posts_count", gorm.Expr("posts_count + ?", 1)).Error
+	})
+	if err != nil {
+		t.Fatalf("create post fixture: %v", err)
+	}
+	return post
+}
+
+// CreateLike records a like and keeps likes_count in step.
+func CreateLike(t testing.TB, db *gorm.DB, postID, userID uint) {
+	t.Helper()
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&models.PostLike{PostID: postID, UserID: userID, CreatedAt: time.Now()}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Post{}).Where("id = ?", postID).
+			UpdateColumn("likes_count", gorm.Expr("likes_count + ?", 1)).Error
+	})
+	if err != nil {
+		t.Fatalf("create like fixture: %v", err)
+	}
+}
+
+// CreateFollow records a follow and keeps both users' counters in step.
+func CreateFollow(t testing.TB, db *gorm.DB, followerID, followingID uint) {
+	t.Helper()
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&models.UserFollow{FollowerID: followerID, FollowingID: followingID}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.User{}).Where("id = ?", followerID).
+			UpdateColumn("following_count", gorm.Expr("following_count + ?", 1)).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.User{}).Where("id = ?", followingID).
+			UpdateColumn("followers_count", gorm.Expr("followers_count + ?", 1)).Error
+	})
+	if err != nil {
+		t.Fatalf("create follow fixture: %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+package tracing_test
+
+import (
+	"flower-backend/testutil"
+	"flower-backend/testutil/testserver"
+	"flower-backend/tracing"
+	"net/http"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestRequestSpansNest(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	if _, err := tracing.Setup(t.Context(), testutil.Config()); err != nil {
+		t.Fatalf("Setup: %v", err)
+	}
+
+	srv := testserver.New(t)
+	if err := tracing.InstrumentDB(srv.DB); err != nil {
+		t.Fatalf("InstrumentDB: %v", err)
+	}
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/api/v1/post/all", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET /post/all: %v", err)
+	}
+	resp.Body.Close()
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		if span.SpanContext().TraceID().String() != traceID {
+			t.Errorf("span %q has trace %s, want the incoming %s", span.Name(), span.SpanContext().TraceID(), traceID)
+		}
+		spans[span.Name()] = span
+	}
+
+	find := func(prefix string) sdktrace.ReadOnlySpan {
+		for name, span := range spans {
+			if strings.HasPrefix(name, prefix) {
+				return span
+			}
+		}
+		t.Fatalf("no span starting with %q among %v", prefix, names(spans))
+		return nil
+	}
+	server := find("GET /api/v1/post/all")
+	controller := find("post.PostController.GetPostAll")
+	service := find("PostService.GetPostAll")
+	repo := find("PostRepository.GetAll")
+	query := find("select posts")
+
+	for _, link := range []struct{ child, parent sdktrace.ReadOnlySpan }{
+		{controller, server}, {service, controller}, {repo, service}, {query, repo},
+	} {
+		if link.child.Parent().SpanID() != link.parent.SpanContext().SpanID() {
+			t.Errorf("span %q is not a child of %q", link.child.Name(), link.parent.Name())
+		}
+	}
+}
+
+func names(spans map[string]sdktrace.ReadOnlySpan) []string {
+	var out []string
+	for name := range spans {
+		out = append(out, name)
+	}
+	return out
+}
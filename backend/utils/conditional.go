@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ETag returns a strong ETag hashed from the JSON encoding of payload.
+func ETag(payload any) string {
+	return `"` + hashPayload(payload) + `"`
+}
+
+// WeakETag returns a weak ETag hashed from the JSON encoding of payload.
+// List endpoints use it since their order and membership shift as posts
+// are created, so only semantic equivalence is promised.
+func WeakETag(payload any) string {
+	return `W/"` + hashPayload(payload) + `"`
+}
+
+func hashPayload(payload any) string {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:16])
+}
+
+// NotModified sets the ETag and Last-Modified validators and answers the
+// request with 304 Not Modified when the client's copy is still current,
+// reporting whether it did. If-None-Match takes precedence over
+// If-Modified-Since, and a zero lastModified disables the latter.
+func NotModified(c *gin.Context, etag string, lastModified time.Time) bool {
+	c.Header("ETag", etag)
+	if !lastModified.IsZero() {
+		c.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if ifNoneMatch := c.GetHeader("If-None-Match"); ifNoneMatch != "" {
+		if !etagMatches(ifNoneMatch, etag) {
+			return false
+		}
+	} else {
+		ifModifiedSince := c.GetHeader("If-Modified-Since")
+		if ifModifiedSince == "" || lastModified.IsZero() {
+			return false
+		}
+		since, err := http.ParseTime(ifModifiedSince)
+		if err != nil || lastModified.Truncate(time.Second).After(since) {
+			return false
+		}
+	}
+
+	c.AbortWithStatus(http.StatusNotModified)
+	return true
+}
+
+// etagMatches applies the weak comparison GET requests use.
+func etagMatches(header, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -95,6 +95,30 @@ func ValidateUsername(username string) bool {
 	return re.MatchString(username)
 }
 
+// NormalizeTags parses a comma-separated tag list into lowercase, de-duplicated
+// names without a leading '#'. It reports false if any tag is invalid or there
+// are more than 10 tags.
+func NormalizeTags(raw string) ([]string, bool) {
+	re := regexp.MustCompile(`^[a-z0-9_-]{1,30}$`)
+	tags := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(raw, ",") {
+		tag := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(part), "#"))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if !re.MatchString(tag) {
+			return nil, false
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	if len(tags) > 10 {
+		return nil, false
+	}
+	return tags, true
+}
+
 func ValidatePassword(password string) bool {
 	// Check minimum length
 	if len(password) < 8 {
@@ -149,6 +173,16 @@ func ParseInt(s string) int {
 	return num
 }
 
+func ParseFloat(s string) float64 {
+	num, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		logger, _ := zap.NewProduction()
+		logger.Fatal("failed to parse float", zap.String("value", s), zap.Error(err))
+		return 0
+	}
+	return num
+}
+
 /**
  * Updates Swagger documentation host dynamically based on the API base URL.
  *